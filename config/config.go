@@ -3,16 +3,24 @@
 package config
 
 import (
+	"encoding"
 	"errors"
 	"fmt"
+	"maps"
 	"os"
+	"reflect"
+	"slices"
+	"strconv"
 	"strings"
+	"time"
 
+	"github.com/mitchellh/mapstructure"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/viper"
+	"gopkg.in/yaml.v3"
 
 	"github.com/tkrop/go-config/info"
-	"github.com/tkrop/go-config/internal/reflect"
+	intreflect "github.com/tkrop/go-config/internal/reflect"
 	"github.com/tkrop/go-config/log"
 )
 
@@ -25,6 +33,17 @@ func NewErrConfig(message, context string, err error) error {
 	return fmt.Errorf("%w - %s [%s]: %w", ErrConfig, message, context, err)
 }
 
+// ErrArrayLength is a common error to indicate an array value with invalid
+// length.
+var ErrArrayLength = errors.New("invalid array length")
+
+// NewErrArrayLength creates a new error to indicate that the given array value
+// does not provide the expected number of elements.
+func NewErrArrayLength(value string, expect, actual int) error {
+	return fmt.Errorf("%w [%s]: expected %d, got %d",
+		ErrArrayLength, value, expect, actual)
+}
+
 // Config common application configuration.
 type Config struct {
 	// Env contains the execution environment, e.g. local, prod, test.
@@ -35,9 +54,26 @@ type Config struct {
 	Log *log.Config
 }
 
+// Validate validates the config returning an error for invalid values.
+func (c *Config) Validate() error {
+	if c.Log != nil {
+		return c.Log.Validate()
+	}
+	return nil
+}
+
+// Validator is the interface implemented by configs supporting validation.
+type Validator interface {
+	// Validate validates the config returning an error for invalid values.
+	Validate() error
+}
+
 // Reader common config reader based on viper.
 type Reader[C any] struct {
 	*viper.Viper
+	// templates contains the default values of template paths for map
+	// entries, e.g. `servers.*.port`.
+	templates map[string]any
 }
 
 // GetEnvName returns the environment specific configuration file name using
@@ -97,7 +133,9 @@ func (r *Reader[C]) SetDefaults(
 // updated.
 //
 // Depending on the `zero` flag the default values are either include setting
-// zero values or ignoring them.
+// zero values or ignoring them. Errors while scanning the config struct, e.g.
+// exceeding the maximum depth of nested values, are logged, or raised as
+// panic if the flag `viper.panic.defaults` is set.
 func (r *Reader[C]) SetDefaultConfig(
 	key string, config any, zero bool,
 ) *Reader[C] {
@@ -112,12 +150,73 @@ func (r *Reader[C]) SetDefaultConfig(
 	r.SetDefault("info.platform", info.Platform)
 	r.SetDefault("info.compiler", info.Compiler)
 
-	reflect.NewTagWalker("default", "mapstructure", zero).
-		Walk(key, config, r.SetDefault)
+	err := intreflect.NewTagWalkerE("default", "mapstructure", zero,
+		r.setDefault, FallbackTags...).Walk(key, config, nil)
+	if err != nil {
+		err := NewErrConfig("default config", key, err)
+		logrus.WithFields(logrus.Fields{
+			"key": key,
+		}).WithError(err).Error("default config")
+		if r.GetBool("viper.panic.defaults") {
+			panic(err)
+		}
+	}
 
 	return r
 }
 
+// setDefault sets the default value for the given key in the config reader,
+// or registers the default value for template paths of map entries to be
+// applied to the available map entries on `GetConfig`.
+func (r *Reader[C]) setDefault(key string, value any) error {
+	if !slices.Contains(strings.Split(key, "."), intreflect.TemplateKey) {
+		r.SetDefault(key, value)
+	} else if r.templates == nil {
+		r.templates = map[string]any{key: value}
+	} else {
+		r.templates[key] = value
+	}
+	return nil
+}
+
+// applyTemplates applies the default values of the template paths to all
+// map entries available in the config reader, that do not provide a value
+// or default value yet.
+func (r *Reader[C]) applyTemplates() {
+	keys := slices.Sorted(maps.Keys(r.templates))
+	for _, key := range keys {
+		r.applyTemplate("", strings.Split(key, "."), r.templates[key])
+	}
+}
+
+// applyTemplate applies the given default value to the template path given
+// by its parts relative to the given prefix path by expanding the first
+// template key to all available map entries.
+func (r *Reader[C]) applyTemplate(prefix string, parts []string, value any) {
+	index := slices.Index(parts, intreflect.TemplateKey)
+	if index < 0 {
+		if key := joinKey(prefix, parts...); !r.IsSet(key) {
+			r.SetDefault(key, value)
+		}
+		return
+	}
+
+	base := joinKey(prefix, parts[:index]...)
+	for name := range r.GetStringMap(base) {
+		r.applyTemplate(joinKey(base, name), parts[index+1:], value)
+	}
+}
+
+// joinKey joins the given prefix and key parts using dots `.`.
+func joinKey(prefix string, parts ...string) string {
+	if prefix == "" {
+		return strings.Join(parts, ".")
+	} else if len(parts) == 0 {
+		return prefix
+	}
+	return prefix + "." + strings.Join(parts, ".")
+}
+
 // SetDefault is a convenience method to set the default value for the given
 // key in the config reader and return the config reader.
 //
@@ -147,10 +246,14 @@ func (r *Reader[C]) ReadConfig(context string) *Reader[C] {
 // GetConfig is a convenience method to return the config without loading the
 // environment specific config file. The context is used to distinguish
 // different calls in case of a panic created by failures while unmarschalling
-// the config.
+// or validating the config. For the standard config, the build information
+// read from the config is merged over the default build information. Default
+// tags of map value struct types are applied to all available map entries.
 func (r *Reader[C]) GetConfig(context string) *C {
+	r.applyTemplates()
+
 	config := new(C)
-	if err := r.Unmarshal(config); err != nil {
+	if err := r.Unmarshal(config, viper.DecodeHook(DecodeHook())); err != nil {
 		err := NewErrConfig("unmarshal config", context, err)
 		logrus.WithFields(logrus.Fields{
 			"context": context,
@@ -160,6 +263,39 @@ func (r *Reader[C]) GetConfig(context string) *C {
 		}
 	}
 
+	if config, ok := any(config).(*Config); ok && config.Info != nil {
+		// Applies an explicitly configured dirty flag after merging, since
+		// the non-zero override semantics of merging cannot clear the flag.
+		dirty, set := config.Info.Dirty, r.GetString("info.dirty") != ""
+		config.Info = info.GetDefault().Merge(config.Info)
+		if set {
+			config.Info.Dirty = dirty
+		}
+		config.Info.AdjustVersion()
+	}
+
+	if errs := info.GetDefault().Errors; len(errs) != 0 {
+		err := NewErrConfig("build info", context, errors.Join(errs...))
+		logrus.WithFields(logrus.Fields{
+			"context": context,
+		}).WithError(err).Warn("build info")
+		if r.GetBool("viper.panic.info") {
+			panic(err)
+		}
+	}
+
+	if validator, ok := any(config).(Validator); ok {
+		if err := validator.Validate(); err != nil {
+			err := NewErrConfig("validate config", context, err)
+			logrus.WithFields(logrus.Fields{
+				"context": context,
+			}).WithError(err).Warn("validate config")
+			if r.GetBool("viper.panic.validate") {
+				panic(err)
+			}
+		}
+	}
+
 	logrus.WithFields(logrus.Fields{
 		"context": context,
 		"config":  config,
@@ -168,6 +304,237 @@ func (r *Reader[C]) GetConfig(context string) *C {
 	return config
 }
 
+// DecodeHook returns the decode hook used for unmarshalling the config. Besides
+// the default hook for slices, it supports decoding times from RFC3339
+// strings, durations from go duration strings and bare numbers as
+// nanoseconds, types implementing the text unmarshaler interface, maps
+// from YAML strings as provided via `default`-tags or environment variables,
+// as well as flattening nested maps split by viper at dots in keys, and
+// mapping keys named by fallback tags to their fields.
+func DecodeHook() mapstructure.DecodeHookFunc {
+	return mapstructure.ComposeDecodeHookFunc(
+		StringToDurationHookFunc(),
+		StringToTimeHookFunc(),
+		TextUnmarshalerHookFunc(),
+		StringToArrayHookFunc(","),
+		mapstructure.StringToSliceHookFunc(","),
+		StringToMapHookFunc(),
+		FlattenMapHookFunc(),
+		FallbackTagsHookFunc(FallbackTags...),
+	)
+}
+
+// FallbackTags contains the tag names used in priority order for naming
+// config fields without `mapstructure` tag name.
+var FallbackTags = []string{"json", "yaml"}
+
+// FallbackTagsHookFunc returns a decode hook that maps the keys of maps
+// decoded into structs, that are named by the given fallback tags, to the
+// names of their fields, if the fields provide no `mapstructure` tag name.
+// This way fields are decoded using the same names as the default values are
+// set up.
+func FallbackTagsHookFunc(tags ...string) mapstructure.DecodeHookFuncType {
+	return func(from, to reflect.Type, data any) (any, error) {
+		if from.Kind() != reflect.Map || from.Key().Kind() != reflect.String ||
+			to.Kind() != reflect.Struct {
+			return data, nil
+		}
+
+		value := reflect.ValueOf(data)
+		result := make(map[string]any, value.Len())
+		for _, key := range value.MapKeys() {
+			result[key.String()] = value.MapIndex(key).Interface()
+		}
+
+		for index := 0; index < to.NumField(); index++ {
+			field := to.Field(index)
+			name := fallbackName(field, tags)
+			if name == "" || strings.EqualFold(name, field.Name) {
+				continue
+			}
+			for key, value := range result {
+				if strings.EqualFold(key, name) {
+					delete(result, key)
+					result[field.Name] = value
+				}
+			}
+		}
+		return result, nil
+	}
+}
+
+// fallbackName returns the name of the given field provided by the first of
+// the given fallback tags, if the field provides no `mapstructure` tag name.
+// If the field is ignored, an empty string is returned.
+func fallbackName(field reflect.StructField, tags []string) string {
+	if !field.IsExported() {
+		return ""
+	} else if name, _, _ := strings.Cut(
+		field.Tag.Get("mapstructure"), ","); name != "" {
+		return ""
+	}
+
+	for _, tag := range tags {
+		if name, _, _ := strings.Cut(field.Tag.Get(tag), ","); name == "-" {
+			return ""
+		} else if name != "" {
+			return name
+		}
+	}
+	return ""
+}
+
+// StringToDurationHookFunc returns a decode hook that converts strings to
+// durations by parsing the string as go duration, or as bare number of
+// nanoseconds.
+func StringToDurationHookFunc() mapstructure.DecodeHookFuncType {
+	return func(from, to reflect.Type, data any) (any, error) {
+		if from.Kind() != reflect.String ||
+			to != reflect.TypeOf(time.Duration(0)) {
+			return data, nil
+		}
+
+		value := strings.TrimSpace(data.(string))
+		if nanos, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return time.Duration(nanos), nil
+		}
+		return time.ParseDuration(value)
+	}
+}
+
+// StringToTimeHookFunc returns a decode hook that converts strings to times
+// by parsing the string using RFC3339 format. Empty strings are converted to
+// zero times.
+func StringToTimeHookFunc() mapstructure.DecodeHookFuncType {
+	return func(from, to reflect.Type, data any) (any, error) {
+		if from.Kind() != reflect.String || to != reflect.TypeOf(time.Time{}) {
+			return data, nil
+		}
+
+		value := strings.TrimSpace(data.(string))
+		if value == "" {
+			return time.Time{}, nil
+		}
+		return time.Parse(time.RFC3339, value)
+	}
+}
+
+// TextUnmarshalerHookFunc returns a decode hook that converts strings to
+// types implementing the text unmarshaler interface by calling
+// `UnmarshalText` with the string. Empty strings are converted to zero values
+// to support fields without default tags.
+func TextUnmarshalerHookFunc() mapstructure.DecodeHookFuncType {
+	return func(from, to reflect.Type, data any) (any, error) {
+		if from.Kind() != reflect.String || to.Kind() == reflect.Ptr ||
+			!reflect.PointerTo(to).Implements(textUnmarshalerType) {
+			return data, nil
+		}
+
+		result := reflect.New(to)
+		if value := data.(string); value != "" {
+			unmarshaler := result.Interface().(encoding.TextUnmarshaler)
+			if err := unmarshaler.UnmarshalText([]byte(value)); err != nil {
+				return nil, err
+			}
+		}
+		return result.Elem().Interface(), nil
+	}
+}
+
+// textUnmarshalerType is the type of the text unmarshaler interface.
+var textUnmarshalerType = reflect.TypeOf(
+	(*encoding.TextUnmarshaler)(nil)).Elem()
+
+// StringToArrayHookFunc returns a decode hook that converts strings to fixed
+// size arrays by decoding the string as YAML sequence, if it starts with `[`,
+// or else by splitting the string using the given separator. Empty strings are
+// converted to zero arrays. If the number of elements does not match the
+// length of the array, an error is returned.
+func StringToArrayHookFunc(sep string) mapstructure.DecodeHookFuncType {
+	return func(from, to reflect.Type, data any) (any, error) {
+		if from.Kind() != reflect.String || to.Kind() != reflect.Array {
+			return data, nil
+		}
+
+		value := strings.TrimSpace(data.(string))
+		if value == "" {
+			return reflect.New(to).Elem().Interface(), nil
+		}
+
+		result := []any{}
+		if strings.HasPrefix(value, "[") {
+			if err := yaml.Unmarshal([]byte(value), &result); err != nil {
+				return nil, err
+			}
+		} else {
+			for _, elem := range strings.Split(value, sep) {
+				result = append(result, strings.TrimSpace(elem))
+			}
+		}
+
+		if len(result) != to.Len() {
+			return nil, NewErrArrayLength(value, to.Len(), len(result))
+		}
+		return result, nil
+	}
+}
+
+// StringToMapHookFunc returns a decode hook that converts strings to maps by
+// decoding the string as YAML. Empty strings are converted to empty maps.
+func StringToMapHookFunc() mapstructure.DecodeHookFuncType {
+	return func(from, to reflect.Type, data any) (any, error) {
+		if from.Kind() != reflect.String || to.Kind() != reflect.Map {
+			return data, nil
+		}
+
+		result := map[string]any{}
+		if value := strings.TrimSpace(data.(string)); value != "" {
+			if err := yaml.Unmarshal([]byte(value), &result); err != nil {
+				return nil, err
+			}
+		}
+		return result, nil
+	}
+}
+
+// FlattenMapHookFunc returns a decode hook that flattens nested maps into maps
+// with string values by joining the nested keys using dots. This restores keys
+// containing dots, e.g. package names, that are split into nested maps by
+// viper.
+func FlattenMapHookFunc() mapstructure.DecodeHookFuncType {
+	return func(from, to reflect.Type, data any) (any, error) {
+		if from.Kind() != reflect.Map || to.Kind() != reflect.Map ||
+			to.Elem().Kind() != reflect.String {
+			return data, nil
+		}
+
+		result := map[string]any{}
+		flattenMap("", reflect.ValueOf(data), result)
+		return result, nil
+	}
+}
+
+// flattenMap flattens the given nested map value into the given result map
+// using the given prefix for constructing the keys.
+func flattenMap(prefix string, value reflect.Value, result map[string]any) {
+	for _, key := range value.MapKeys() {
+		name := fmt.Sprint(key.Interface())
+		if prefix != "" {
+			name = prefix + "." + name
+		}
+
+		elem := value.MapIndex(key)
+		for elem.Kind() == reflect.Interface && !elem.IsNil() {
+			elem = elem.Elem()
+		}
+		if elem.Kind() == reflect.Map {
+			flattenMap(name, elem, result)
+		} else {
+			result[name] = elem.Interface()
+		}
+	}
+}
+
 // LoadConfig is a convenience method to load the environment specific config
 // file and returns the config. The context is used to distinguish different
 // calls in case of a panic created by failures loading the config file or
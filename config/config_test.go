@@ -1,21 +1,45 @@
 package config_test
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/mitchellh/mapstructure"
+	"github.com/sirupsen/logrus"
 	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/tkrop/go-config/config"
+	"github.com/tkrop/go-config/info"
 	"github.com/tkrop/go-config/internal/filepath"
+	intreflect "github.com/tkrop/go-config/internal/reflect"
+	"github.com/tkrop/go-config/log"
 	"github.com/tkrop/go-testing/mock"
 	"github.com/tkrop/go-testing/test"
 )
 
 var configPaths = []string{filepath.Normalize(".")}
 
+// setBuildInfo sets up a default build information with an invalid build
+// time and restores the default build information after the test.
+func setBuildInfo(t test.Test) {
+	orig := info.GetDefault()
+	t.Cleanup(func() { info.SetDefault(orig) })
+	info.SetDefault(info.New("", "", "", "x", "", "false", "", ""))
+}
+
+// timeErr returns the error of parsing the given time string.
+func timeErr(t string) error {
+	_, err := info.ParseTime(t)
+	return err
+}
+
 type testConfigParam struct {
 	setenv         func(test.Test)
 	setup          func(*config.Reader[config.Config])
@@ -80,6 +104,38 @@ var testConfigParams = map[string]testConfigParam{
 					"strconv.ParseBool: parsing \"5s\": invalid syntax"},
 			})),
 	},
+
+	"panic after validation failure": {
+		setup: func(r *config.Reader[config.Config]) {
+			r.SetDefault("viper.panic.validate", true)
+			r.SetDefault("log.level", "inf0")
+		},
+		expect: test.Panic(config.NewErrConfig("validate config",
+			"test", errors.Join(log.NewErrLevel("inf0")))),
+	},
+
+	"panic after build info failure": {
+		setenv: setBuildInfo,
+		setup: func(r *config.Reader[config.Config]) {
+			r.SetDefault("viper.panic.info", true)
+		},
+		expect: test.Panic(config.NewErrConfig("build info", "test",
+			errors.Join(info.NewErrParse("build", "x", timeErr("x"))))),
+	},
+
+	"warning after build info failure": {
+		setenv:         setBuildInfo,
+		expectEnv:      "prod",
+		expectLogLevel: "info",
+	},
+
+	"warning after validation failure": {
+		setup: func(r *config.Reader[config.Config]) {
+			r.SetDefault("log.level", "inf0")
+		},
+		expectEnv:      "prod",
+		expectLogLevel: "inf0",
+	},
 }
 
 func TestConfig(t *testing.T) {
@@ -101,3 +157,788 @@ func TestConfig(t *testing.T) {
 			assert.Equal(t, param.expectLogLevel, reader.GetString("log.level"))
 		})
 }
+
+type testConfigInfoParam struct {
+	setenv        func(test.Test)
+	setup         func(*config.Reader[config.Config])
+	expectPath    string
+	expectRepo    string
+	expectVersion string
+	expectDirty   bool
+}
+
+var testConfigInfoParams = map[string]testConfigInfoParam{
+	"info from file": {
+		setup: func(r *config.Reader[config.Config]) {
+			r.AddConfigPath("fixtures")
+		},
+		expectPath:  "github.com/tkrop/go-config",
+		expectRepo:  "git@github.com:tkrop/go-config",
+		expectDirty: true,
+	},
+
+	"info path from env": {
+		setenv: func(t test.Test) {
+			t.Setenv("TC_INFO_PATH", "github.com/tkrop/go-make/cmd/go-make")
+		},
+		setup: func(r *config.Reader[config.Config]) {
+			r.AddConfigPath("fixtures")
+		},
+		expectPath:  "github.com/tkrop/go-make/cmd/go-make",
+		expectRepo:  "git@github.com:tkrop/go-make",
+		expectDirty: true,
+	},
+
+	"info tag from env": {
+		setenv: func(t test.Test) {
+			t.Setenv("TC_INFO_TAG", "v1.2.3")
+		},
+		setup: func(r *config.Reader[config.Config]) {
+			r.AddConfigPath("fixtures")
+		},
+		expectPath:    "github.com/tkrop/go-config",
+		expectRepo:    "git@github.com:tkrop/go-config",
+		expectVersion: "v1.2.3",
+		expectDirty:   true,
+	},
+
+	"info dirty cleared from env": {
+		setenv: func(t test.Test) {
+			t.Setenv("TC_INFO_DIRTY", "false")
+		},
+		setup: func(r *config.Reader[config.Config]) {
+			r.AddConfigPath("fixtures")
+		},
+		expectPath: "github.com/tkrop/go-config",
+		expectRepo: "git@github.com:tkrop/go-config",
+	},
+}
+
+func TestConfigInfo(t *testing.T) {
+	test.Map(t, testConfigInfoParams).
+		RunSeq(func(t test.Test, param testConfigInfoParam) {
+			// Given
+			if param.setenv != nil {
+				param.setenv(t)
+			}
+			orig := info.GetDefault()
+			t.Cleanup(func() { info.SetDefault(orig) })
+			info.SetDefault(&info.Info{Go: "1.23.5", Dirty: true})
+			reader := config.NewReader[config.Config]("TC", "test").
+				SetDefaults(param.setup)
+
+			// When
+			config := reader.LoadConfig("test")
+
+			// Then
+			assert.Equal(t, param.expectPath, config.Info.Path)
+			assert.Equal(t, param.expectRepo, config.Info.Repo)
+			assert.Equal(t, param.expectVersion, config.Info.Version)
+			assert.Equal(t, "1.23.5", config.Info.Go)
+			assert.Equal(t, param.expectDirty, config.Info.Dirty)
+		})
+}
+
+type testStringToDurationHookParam struct {
+	from        any
+	to          any
+	expect      any
+	expectError bool
+}
+
+var testStringToDurationHookParams = map[string]testStringToDurationHookParam{
+	"string to duration": {
+		from:   "1m30s",
+		to:     time.Duration(0),
+		expect: 90 * time.Second,
+	},
+	"string to duration nanos": {
+		from:   "30",
+		to:     time.Duration(0),
+		expect: 30 * time.Nanosecond,
+	},
+	"string to duration invalid": {
+		from:        "30x",
+		to:          time.Duration(0),
+		expectError: true,
+	},
+	"string to int64": {
+		from:   "30",
+		to:     int64(0),
+		expect: "30",
+	},
+	"int to duration": {
+		from:   30,
+		to:     time.Duration(0),
+		expect: 30,
+	},
+}
+
+func TestStringToDurationHook(t *testing.T) {
+	test.Map(t, testStringToDurationHookParams).
+		Run(func(t test.Test, param testStringToDurationHookParam) {
+			// Given
+			hook := config.StringToDurationHookFunc()
+
+			// When
+			result, err := hook(reflect.TypeOf(param.from),
+				reflect.TypeOf(param.to), param.from)
+
+			// Then
+			if param.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, param.expect, result)
+			}
+		})
+}
+
+type testStringToTimeHookParam struct {
+	from        any
+	to          any
+	expect      any
+	expectError bool
+}
+
+var testStringToTimeHookParams = map[string]testStringToTimeHookParam{
+	"string to time": {
+		from:   "2024-10-01T12:00:00Z",
+		to:     time.Time{},
+		expect: time.Date(2024, 10, 1, 12, 0, 0, 0, time.UTC),
+	},
+	"string to time empty": {
+		from:   "",
+		to:     time.Time{},
+		expect: time.Time{},
+	},
+	"string to time invalid": {
+		from:        "2024-10-01",
+		to:          time.Time{},
+		expectError: true,
+	},
+	"string to string": {
+		from:   "2024-10-01T12:00:00Z",
+		to:     "",
+		expect: "2024-10-01T12:00:00Z",
+	},
+}
+
+func TestStringToTimeHook(t *testing.T) {
+	test.Map(t, testStringToTimeHookParams).
+		Run(func(t test.Test, param testStringToTimeHookParam) {
+			// Given
+			hook := config.StringToTimeHookFunc()
+
+			// When
+			result, err := hook(reflect.TypeOf(param.from),
+				reflect.TypeOf(param.to), param.from)
+
+			// Then
+			if param.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, param.expect, result)
+			}
+		})
+}
+
+// Level is an enum type implementing the text unmarshaler interface.
+type Level int
+
+// Levels of the enum type.
+const (
+	LevelLow Level = iota
+	LevelHigh
+)
+
+// UnmarshalText unmarshals the level from the given text.
+func (l *Level) UnmarshalText(text []byte) error {
+	switch string(text) {
+	case "low":
+		*l = LevelLow
+	case "high":
+		*l = LevelHigh
+	default:
+		return fmt.Errorf("invalid level [%s]", text)
+	}
+	return nil
+}
+
+type testTextUnmarshalerHookParam struct {
+	from        any
+	to          any
+	expect      any
+	expectError bool
+}
+
+var testTextUnmarshalerHookParams = map[string]testTextUnmarshalerHookParam{
+	"string to level": {
+		from:   "high",
+		to:     LevelLow,
+		expect: LevelHigh,
+	},
+	"string to level empty": {
+		from:   "",
+		to:     LevelHigh,
+		expect: LevelLow,
+	},
+	"string to level invalid": {
+		from:        "medium",
+		to:          LevelLow,
+		expectError: true,
+	},
+	"string to int": {
+		from:   "high",
+		to:     0,
+		expect: "high",
+	},
+	"int to level": {
+		from:   1,
+		to:     LevelLow,
+		expect: 1,
+	},
+}
+
+func TestTextUnmarshalerHook(t *testing.T) {
+	test.Map(t, testTextUnmarshalerHookParams).
+		Run(func(t test.Test, param testTextUnmarshalerHookParam) {
+			// Given
+			hook := config.TextUnmarshalerHookFunc()
+
+			// When
+			result, err := hook(reflect.TypeOf(param.from),
+				reflect.TypeOf(param.to), param.from)
+
+			// Then
+			if param.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, param.expect, result)
+			}
+		})
+}
+
+// levelConfig is a config with text unmarshaler default tags.
+type levelConfig struct {
+	Level   Level   `default:"high"`
+	Pointer *Level  `default:"high"`
+	Levels  []Level `default:"low,high"`
+	None    Level
+}
+
+func TestTextUnmarshalerDefaults(t *testing.T) {
+	// Given
+	reader := config.NewReader[levelConfig]("TC", "test")
+
+	// When
+	config := reader.GetConfig("test")
+
+	// Then
+	level := LevelHigh
+	assert.Equal(t, &levelConfig{
+		Level:   LevelHigh,
+		Pointer: &level,
+		Levels:  []Level{LevelLow, LevelHigh},
+	}, config)
+}
+
+// fallbackConfig is a config with fallback tags.
+type fallbackConfig struct {
+	TimeoutMs int    `json:"timeoutMs" default:"5"`
+	Name      string `yaml:"displayName" default:"name"`
+	Mapped    string `mapstructure:"mapped" json:"other" default:"mapped"`
+	Skipped   string `json:"-" default:"skipped"`
+}
+
+type testFallbackTagsParam struct {
+	setenv func(test.Test)
+	expect *fallbackConfig
+}
+
+var testFallbackTagsParams = map[string]testFallbackTagsParam{
+	"defaults": {
+		expect: &fallbackConfig{
+			TimeoutMs: 5, Name: "name", Mapped: "mapped",
+		},
+	},
+	"env": {
+		setenv: func(t test.Test) {
+			t.Setenv("TC_TIMEOUTMS", "7")
+			t.Setenv("TC_DISPLAYNAME", "other")
+			t.Setenv("TC_MAPPED", "env")
+		},
+		expect: &fallbackConfig{
+			TimeoutMs: 7, Name: "other", Mapped: "env",
+		},
+	},
+}
+
+func TestFallbackTags(t *testing.T) {
+	test.Map(t, testFallbackTagsParams).
+		RunSeq(func(t test.Test, param testFallbackTagsParam) {
+			// Given
+			if param.setenv != nil {
+				param.setenv(t)
+			}
+			reader := config.NewReader[fallbackConfig]("TC", "test")
+
+			// When
+			config := reader.GetConfig("test")
+
+			// Then
+			assert.Equal(t, param.expect, config)
+		})
+}
+
+// server is a map value struct type with default tags.
+type server struct {
+	Host string `default:"localhost"`
+	Port int    `default:"8080"`
+}
+
+// templateConfig is a config with maps of struct types with default tags.
+type templateConfig struct {
+	Servers map[string]server
+	Proxies map[string]*server
+	Regions map[string]map[string]server
+}
+
+type testTemplateDefaultsParam struct {
+	config string
+	setup  func(*config.Reader[templateConfig])
+	expect *templateConfig
+}
+
+var testTemplateDefaultsParams = map[string]testTemplateDefaultsParam{
+	"defaults": {
+		expect: &templateConfig{
+			Servers: map[string]server{},
+			Proxies: map[string]*server{},
+			Regions: map[string]map[string]server{},
+		},
+	},
+	"config-entries": {
+		config: "servers: {a: {port: 9000}, b: {host: other}}\n" +
+			"proxies: {c: {host: proxy}}\n" +
+			"regions: {eu: {d: {port: 1}}}",
+		expect: &templateConfig{
+			Servers: map[string]server{
+				"a": {Host: "localhost", Port: 9000},
+				"b": {Host: "other", Port: 8080},
+			},
+			Proxies: map[string]*server{
+				"c": {Host: "proxy", Port: 8080},
+			},
+			Regions: map[string]map[string]server{
+				"eu": {"d": {Host: "localhost", Port: 1}},
+			},
+		},
+	},
+	"set-entries": {
+		setup: func(r *config.Reader[templateConfig]) {
+			r.Set("servers.a.host", "set")
+		},
+		expect: &templateConfig{
+			Servers: map[string]server{
+				"a": {Host: "set", Port: 8080},
+			},
+			Proxies: map[string]*server{},
+			Regions: map[string]map[string]server{},
+		},
+	},
+	"default-entries": {
+		setup: func(r *config.Reader[templateConfig]) {
+			r.SetDefaultConfig("", templateConfig{
+				Servers: map[string]server{"a": {Port: 9000}},
+			}, false)
+		},
+		expect: &templateConfig{
+			Servers: map[string]server{
+				"a": {Host: "localhost", Port: 9000},
+			},
+			Proxies: map[string]*server{},
+			Regions: map[string]map[string]server{},
+		},
+	},
+}
+
+func TestTemplateDefaults(t *testing.T) {
+	test.Map(t, testTemplateDefaultsParams).
+		Run(func(t test.Test, param testTemplateDefaultsParam) {
+			// Given
+			reader := config.NewReader[templateConfig]("TC", "test")
+			if param.config != "" {
+				reader.SetConfigType("yaml")
+				require.NoError(t, reader.Viper.ReadConfig(
+					strings.NewReader(param.config)))
+			}
+			if param.setup != nil {
+				param.setup(reader)
+			}
+
+			// When
+			config := reader.GetConfig("test")
+
+			// Then
+			assert.Equal(t, param.expect, config)
+		})
+}
+
+// nestedMap creates a nested map with the given depth using the key `m` for
+// each level.
+func nestedMap(depth int) any {
+	var value any = "value"
+	for ; depth > 0; depth-- {
+		value = map[string]any{"m": value}
+	}
+	return value
+}
+
+type testDefaultConfigDepthParam struct {
+	panic       bool
+	expectValue any
+}
+
+var testDefaultConfigDepthParams = map[string]testDefaultConfigDepthParam{
+	"log error": {
+		expectValue: "value",
+	},
+	"panic error": {
+		panic: true,
+	},
+}
+
+func TestDefaultConfigDepth(t *testing.T) {
+	test.Map(t, testDefaultConfigDepthParams).
+		Run(func(t test.Test, param testDefaultConfigDepthParam) {
+			// Given
+			reader := config.NewReader[config.Config]("TC", "test")
+			reader.Set("viper.panic.defaults", param.panic)
+			value := map[string]any{"within": nestedMap(2),
+				"beyond": nestedMap(intreflect.DefaultMaxDepth)}
+			err := config.NewErrConfig("default config", "deep",
+				errors.Join(intreflect.NewErrTagWalker("deep.beyond"+
+					strings.Repeat(".m", intreflect.DefaultMaxDepth),
+					intreflect.NewErrMaxDepth(intreflect.DefaultMaxDepth))))
+
+			// When
+			if param.panic {
+				assert.PanicsWithError(t, err.Error(), func() {
+					reader.SetDefaultConfig("deep", value, false)
+				})
+				return
+			}
+			reader.SetDefaultConfig("deep", value, false)
+
+			// Then
+			assert.Equal(t, param.expectValue, reader.Get("deep.within.m.m"))
+			assert.Nil(t, reader.Get("deep.beyond"+
+				strings.Repeat(".m", intreflect.DefaultMaxDepth)))
+		})
+}
+
+// timeConfig is a config with time default tags.
+type timeConfig struct {
+	Timeout  time.Duration   `default:"30s"`
+	Interval *time.Duration  `default:"1000"`
+	Delays   []time.Duration `default:"1s,2s"`
+	Start    time.Time       `default:"2024-10-01T12:00:00Z"`
+	Stop     *time.Time      `default:"2024-10-02T12:00:00Z"`
+}
+
+func TestTimeDefaults(t *testing.T) {
+	// Given
+	reader := config.NewReader[timeConfig]("TC", "test")
+
+	// When
+	config := reader.GetConfig("test")
+
+	// Then
+	interval := time.Microsecond
+	stop := time.Date(2024, 10, 2, 12, 0, 0, 0, time.UTC)
+	assert.Equal(t, &timeConfig{
+		Timeout:  30 * time.Second,
+		Interval: &interval,
+		Delays:   []time.Duration{time.Second, 2 * time.Second},
+		Start:    time.Date(2024, 10, 1, 12, 0, 0, 0, time.UTC),
+		Stop:     &stop,
+	}, config)
+}
+
+type testStringToMapHookParam struct {
+	from        any
+	to          any
+	expect      any
+	expectError bool
+}
+
+var testStringToMapHookParams = map[string]testStringToMapHookParam{
+	"string to map empty": {
+		from:   "",
+		to:     map[string]string{},
+		expect: map[string]any{},
+	},
+	"string to map yaml": {
+		from:   "{key: value, other: 1}",
+		to:     map[string]string{},
+		expect: map[string]any{"key": "value", "other": 1},
+	},
+	"string to map invalid": {
+		from:        "{key",
+		to:          map[string]string{},
+		expectError: true,
+	},
+	"string to string": {
+		from:   "value",
+		to:     "",
+		expect: "value",
+	},
+	"int to map": {
+		from:   1,
+		to:     map[string]string{},
+		expect: 1,
+	},
+}
+
+func TestStringToMapHook(t *testing.T) {
+	test.Map(t, testStringToMapHookParams).
+		Run(func(t test.Test, param testStringToMapHookParam) {
+			// Given
+			hook := config.StringToMapHookFunc()
+
+			// When
+			result, err := hook(reflect.TypeOf(param.from),
+				reflect.TypeOf(param.to), param.from)
+
+			// Then
+			if param.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, param.expect, result)
+			}
+		})
+}
+
+type testStringToArrayHookParam struct {
+	from        any
+	to          any
+	expect      any
+	expectError bool
+}
+
+var testStringToArrayHookParams = map[string]testStringToArrayHookParam{
+	"string to array empty": {
+		from:   "",
+		to:     [3]float64{},
+		expect: [3]float64{},
+	},
+	"string to array yaml": {
+		from:   "[0.5, 0.9, 0.99]",
+		to:     [3]float64{},
+		expect: []any{0.5, 0.9, 0.99},
+	},
+	"string to array separated": {
+		from:   "80, 443",
+		to:     [2]int{},
+		expect: []any{"80", "443"},
+	},
+	"string to array invalid": {
+		from:        "[0.5",
+		to:          [3]float64{},
+		expectError: true,
+	},
+	"string to array too short": {
+		from:        "[0.5, 0.9]",
+		to:          [3]float64{},
+		expect:      config.NewErrArrayLength("[0.5, 0.9]", 3, 2),
+		expectError: true,
+	},
+	"string to array too long": {
+		from:        "80,443,8080",
+		to:          [2]int{},
+		expect:      config.NewErrArrayLength("80,443,8080", 2, 3),
+		expectError: true,
+	},
+	"string to slice": {
+		from:   "80,443",
+		to:     []int{},
+		expect: "80,443",
+	},
+}
+
+func TestStringToArrayHook(t *testing.T) {
+	test.Map(t, testStringToArrayHookParams).
+		Run(func(t test.Test, param testStringToArrayHookParam) {
+			// Given
+			hook := config.StringToArrayHookFunc(",")
+
+			// When
+			result, err := hook(reflect.TypeOf(param.from),
+				reflect.TypeOf(param.to), param.from)
+
+			// Then
+			if param.expectError && param.expect != nil {
+				assert.Equal(t, param.expect, err)
+			} else if param.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, param.expect, result)
+			}
+		})
+}
+
+// arrayConfig is a config with array default tags.
+type arrayConfig struct {
+	Thresholds [3]float64 `default:"[0.5, 0.9, 0.99]"`
+	Ports      *[2]int    `default:"80,443"`
+	Weights    [2]int
+}
+
+type testArrayDefaultsParam struct {
+	setenv func(test.Test)
+	expect *arrayConfig
+}
+
+var testArrayDefaultsParams = map[string]testArrayDefaultsParam{
+	"defaults": {
+		expect: &arrayConfig{
+			Thresholds: [3]float64{0.5, 0.9, 0.99},
+			Ports:      &[2]int{80, 443},
+		},
+	},
+	"env": {
+		setenv: func(t test.Test) {
+			t.Setenv("TC_THRESHOLDS", "0.1,0.2,0.3")
+			t.Setenv("TC_PORTS", "[8080, 8443]")
+			t.Setenv("TC_WEIGHTS", "1,2")
+		},
+		expect: &arrayConfig{
+			Thresholds: [3]float64{0.1, 0.2, 0.3},
+			Ports:      &[2]int{8080, 8443},
+			Weights:    [2]int{1, 2},
+		},
+	},
+}
+
+func TestArrayDefaults(t *testing.T) {
+	test.Map(t, testArrayDefaultsParams).
+		RunSeq(func(t test.Test, param testArrayDefaultsParam) {
+			// Given
+			if param.setenv != nil {
+				param.setenv(t)
+			}
+			reader := config.NewReader[arrayConfig]("TC", "test")
+
+			// When
+			config := reader.GetConfig("test")
+
+			// Then
+			assert.Equal(t, param.expect, config)
+		})
+}
+
+type testFlattenMapHookParam struct {
+	from   any
+	to     any
+	expect any
+}
+
+var testFlattenMapHookParams = map[string]testFlattenMapHookParam{
+	"flatten map flat": {
+		from:   map[string]any{"key": "value"},
+		to:     map[string]string{},
+		expect: map[string]any{"key": "value"},
+	},
+	"flatten map nested": {
+		from: map[string]any{
+			"github":   map[string]any{"com/org/pkg": "debug"},
+			"net/http": "warn",
+		},
+		to: map[string]string{},
+		expect: map[string]any{
+			"github.com/org/pkg": "debug",
+			"net/http":           "warn",
+		},
+	},
+	"flatten map any": {
+		from:   map[string]any{"key": map[string]any{"sub": "value"}},
+		to:     map[string]any{},
+		expect: map[string]any{"key": map[string]any{"sub": "value"}},
+	},
+	"flatten string": {
+		from:   "value",
+		to:     map[string]string{},
+		expect: "value",
+	},
+}
+
+func TestFlattenMapHook(t *testing.T) {
+	test.Map(t, testFlattenMapHookParams).
+		Run(func(t test.Test, param testFlattenMapHookParam) {
+			// Given
+			hook := config.FlattenMapHookFunc()
+
+			// When
+			result, err := hook(reflect.TypeOf(param.from),
+				reflect.TypeOf(param.to), param.from)
+
+			// Then
+			assert.NoError(t, err)
+			assert.Equal(t, param.expect, result)
+		})
+}
+
+type testLogFieldsParam struct {
+	setenv func(test.Test)
+	setup  func(*config.Reader[config.Config])
+}
+
+var testLogFieldsParams = map[string]testLogFieldsParam{
+	"fields from defaults": {
+		setup: func(r *config.Reader[config.Config]) {
+			r.SetDefault("log.fields.service", "checkout")
+			r.SetDefault("log.fields.env", "prod")
+		},
+	},
+	"fields from env": {
+		setenv: func(t test.Test) {
+			t.Setenv("TC_LOG_FIELDS", "{service: checkout, env: prod}")
+		},
+	},
+}
+
+func TestLogFields(t *testing.T) {
+	test.Map(t, testLogFieldsParams).
+		RunSeq(func(t test.Test, param testLogFieldsParam) {
+			// Given
+			if param.setenv != nil {
+				param.setenv(t)
+			}
+			reader := config.NewReader[config.Config]("TC", "test").
+				SetDefaults(param.setup)
+			reader.SetDefault("log.timeformat", log.TimeFormatNone)
+			reader.SetDefault("log.colormode", log.ColorModeOff)
+			config := reader.GetConfig("test")
+			rus, zero := &bytes.Buffer{}, &bytes.Buffer{}
+			rlogger := config.Log.SetupRus(rus, logrus.New())
+			zlogger := config.Log.SetupZero(zero).ZeroLogger()
+
+			// When
+			rlogger.Info("first message")
+			rlogger.WithField("key", "value").Warn("second message")
+			zlogger.Info().Msg("first message")
+			zlogger.Warn().Str("key", "value").Msg("second message")
+
+			// Then
+			expect := "INFO first message env=\"prod\" service=\"checkout\"\n" +
+				"WARN second message env=\"prod\" key=\"value\" " +
+				"service=\"checkout\"\n"
+			assert.Equal(t, expect, rus.String())
+			assert.Equal(t, expect, zero.String())
+		})
+}
@@ -0,0 +1,91 @@
+package info
+
+import (
+	"net/http"
+	"path"
+	"strings"
+)
+
+// UserAgentRevisionLen is the length of the revision in the user agent.
+const UserAgentRevisionLen = 8
+
+// UserAgent returns the user agent of a command or module composed from the
+// build information, e.g. `myapp/v1.4.2 (+github.com/org/myapp; rev
+// 1b66f320)`. The product name and version are sanitized to be valid tokens
+// following RFC 7231. If the path is missing, the product name falls back to
+// `unknown`, while missing versions and comment details are omitted.
+func (info *Info) UserAgent() string {
+	product := "unknown"
+	if info.Path != "" {
+		product = token(path.Base(info.Path))
+	}
+	if info.Version != "" {
+		product += "/" + token(info.Version)
+	}
+
+	details := []string{}
+	if info.Path != "" {
+		details = append(details, "+"+comment(info.Path))
+	}
+	if revision := info.ShortRevision(UserAgentRevisionLen); revision != "" {
+		details = append(details, "rev "+comment(revision))
+	}
+	if len(details) > 0 {
+		return product + " (" + strings.Join(details, "; ") + ")"
+	}
+	return product
+}
+
+// token sanitizes the given string to be a valid token following RFC 7231 by
+// replacing all invalid characters with `-`.
+func token(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		case strings.ContainsRune("!#$%&'*+-.^_`|~", r):
+			return r
+		}
+		return '-'
+	}, s)
+}
+
+// comment sanitizes the given string to be valid comment text following RFC
+// 7231 by replacing parentheses, backslashes, and non-printable characters
+// with `-`.
+func comment(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r < ' ' || r > '~' || strings.ContainsRune(`()\`, r) {
+			return '-'
+		}
+		return r
+	}, s)
+}
+
+// transport is the http round tripper injecting the user agent.
+type transport struct {
+	// base is the http round tripper used to send the requests.
+	base http.RoundTripper
+	// agent is the user agent injected into the requests.
+	agent string
+}
+
+// Transport creates a http round tripper injecting the user agent of the
+// default build information into all requests sent via the given round
+// tripper, if the requests do not provide a user agent yet. If no round
+// tripper is given, the `http.DefaultTransport` is used.
+func Transport(rt http.RoundTripper) http.RoundTripper {
+	if rt == nil {
+		rt = http.DefaultTransport
+	}
+	return &transport{base: rt, agent: GetDefault().UserAgent()}
+}
+
+// RoundTrip sends the given request injecting the user agent if absent.
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Header.Get("User-Agent") == "" {
+		req = req.Clone(req.Context())
+		req.Header.Set("User-Agent", t.agent)
+	}
+	return t.base.RoundTrip(req)
+}
@@ -0,0 +1,119 @@
+package info_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tkrop/go-testing/test"
+
+	"github.com/tkrop/go-config/info"
+)
+
+type testUserAgentParam struct {
+	info   *info.Info
+	expect string
+}
+
+var testUserAgentParams = map[string]testUserAgentParam{
+	"full": {
+		info: &info.Info{
+			Path:     "github.com/org/myapp",
+			Version:  "v1.4.2",
+			Revision: "1b66f320c950b25fa63b81fd4e660c5d1f9d758e",
+		},
+		expect: "myapp/v1.4.2 (+github.com/org/myapp; rev 1b66f320)",
+	},
+	"pseudo version": {
+		info: &info.Info{
+			Path:     "github.com/org/myapp",
+			Version:  "v0.0.0-20241001120000-1b66f320c950",
+			Revision: "1b66f320c950",
+		},
+		expect: "myapp/v0.0.0-20241001120000-1b66f320c950 " +
+			"(+github.com/org/myapp; rev 1b66f320)",
+	},
+	"no version": {
+		info:   &info.Info{Path: "github.com/org/myapp"},
+		expect: "myapp (+github.com/org/myapp)",
+	},
+	"no path": {
+		info:   &info.Info{Version: "v1.4.2", Revision: "1b66f320"},
+		expect: "unknown/v1.4.2 (rev 1b66f320)",
+	},
+	"empty": {
+		info:   &info.Info{},
+		expect: "unknown",
+	},
+	"sanitized": {
+		info: &info.Info{
+			Path:     "github.com/org/my app(1)",
+			Version:  "v1.4.2+build/1",
+			Revision: "rev\n(1)",
+		},
+		expect: "my-app-1-/v1.4.2+build-1 " +
+			"(+github.com/org/my app-1-; rev rev--1-)",
+	},
+}
+
+func TestUserAgent(t *testing.T) {
+	test.Map(t, testUserAgentParams).
+		Run(func(t test.Test, param testUserAgentParam) {
+			// When
+			agent := param.info.UserAgent()
+
+			// Then
+			assert.Equal(t, param.expect, agent)
+		})
+}
+
+type testTransportParam struct {
+	agent  string
+	expect string
+}
+
+var testTransportParams = map[string]testTransportParam{
+	"inject": {
+		expect: "myapp/v1.4.2 (+github.com/org/myapp)",
+	},
+	"keep": {
+		agent:  "custom/v1.0.0",
+		expect: "custom/v1.0.0",
+	},
+}
+
+func TestTransport(t *testing.T) {
+	test.Map(t, testTransportParams).
+		RunSeq(func(t test.Test, param testTransportParam) {
+			// Given
+			orig := info.GetDefault()
+			defer info.SetDefault(orig)
+			info.SetDefault(&info.Info{
+				Path:    "github.com/org/myapp",
+				Version: "v1.4.2",
+			})
+			agent := ""
+			server := httptest.NewServer(http.HandlerFunc(
+				func(_ http.ResponseWriter, r *http.Request) {
+					agent = r.Header.Get("User-Agent")
+				}))
+			defer server.Close()
+			client := &http.Client{Transport: info.Transport(nil)}
+			req, err := http.NewRequest(http.MethodGet, server.URL, nil)
+			require.NoError(t, err)
+			if param.agent != "" {
+				req.Header.Set("User-Agent", param.agent)
+			}
+
+			// When
+			resp, err := client.Do(req)
+
+			// Then
+			require.NoError(t, err)
+			defer resp.Body.Close()
+			assert.Equal(t, param.expect, agent)
+			assert.Equal(t, param.agent, req.Header.Get("User-Agent"))
+		})
+}
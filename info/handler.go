@@ -0,0 +1,91 @@
+package info
+
+import (
+	"hash/fnv"
+	"net/http"
+	"strconv"
+
+	"github.com/tkrop/go-config/internal/coding"
+)
+
+// Content types of the build information served by the info handler.
+const (
+	// ContentTypeJSON is the content type of the JSON build information.
+	ContentTypeJSON = "application/json"
+	// ContentTypeYAML is the content type of the YAML build information.
+	ContentTypeYAML = "application/yaml"
+)
+
+// handler is the http handler serving the build information.
+type handler struct {
+	// info provides the build information served by the handler.
+	info func() *Info
+}
+
+// Handler creates a http handler serving the given build information of a
+// command or module as JSON, or as YAML if requested via `?format=yaml`. The
+// handler supports `GET` and `HEAD` requests and sets an `ETag` derived from
+// the version and revision to allow proxies to cache the build information.
+// If no build information is given, the default build information is served
+// the same way as by `DefaultHandler`.
+func Handler(info *Info) http.Handler {
+	if info == nil {
+		return DefaultHandler()
+	}
+	return &handler{info: func() *Info { return info }}
+}
+
+// DefaultHandler creates a http handler serving the default build information
+// of a command or module the same way as `Handler`.
+func DefaultHandler() http.Handler {
+	return &handler{info: GetDefault}
+}
+
+// ServeHTTP serves the http request for the build information.
+func (h *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	ctype, content := FormatJSON, ContentTypeJSON
+	if r.URL.Query().Get("format") == "yaml" {
+		ctype, content = FormatYAML, ContentTypeYAML
+	}
+
+	info := h.info()
+	body, err := info.Format(ctype)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	tag := etag(info, ctype)
+	w.Header().Set("Content-Type", content)
+	w.Header().Set("ETag", tag)
+	if r.Header.Get("If-None-Match") == tag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+	w.WriteHeader(http.StatusOK)
+	if r.Method == http.MethodGet {
+		_, _ = w.Write([]byte(body))
+	}
+}
+
+// etag returns the entity tag of the given build information in the given
+// coding derived from the version and revision, as well as the hostname and
+// process id of the runtime environment, if present.
+func etag(info *Info, ctype coding.Type) string {
+	hash := fnv.New64a()
+	_, _ = hash.Write([]byte(info.Version + "\x00" + info.Revision +
+		"\x00" + string(ctype)))
+	if info.Runtime != nil {
+		_, _ = hash.Write([]byte("\x00" + info.Runtime.Hostname +
+			"\x00" + strconv.Itoa(info.Runtime.PID)))
+	}
+	return `"` + strconv.FormatUint(hash.Sum64(), 16) + `"`
+}
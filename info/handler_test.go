@@ -0,0 +1,139 @@
+package info_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tkrop/go-testing/test"
+
+	"github.com/tkrop/go-config/info"
+	"github.com/tkrop/go-config/internal/coding"
+)
+
+// handlerInfo is the build information served by the info handler.
+var handlerInfo = &info.Info{
+	Path:     buildPath,
+	Version:  "v1.2.3",
+	Revision: revisionHead,
+	Go:       "1.23.0",
+	Platform: "linux/amd64",
+}
+
+type testHandlerParam struct {
+	method        string
+	target        string
+	expectStatus  int
+	expectType    string
+	expectBody    string
+	expectAllowed string
+}
+
+var testHandlerParams = map[string]testHandlerParam{
+	"get json": {
+		method:       http.MethodGet,
+		target:       "/info",
+		expectStatus: http.StatusOK,
+		expectType:   info.ContentTypeJSON,
+		expectBody:   coding.ToString(coding.TypeJSON, handlerInfo),
+	},
+	"get yaml": {
+		method:       http.MethodGet,
+		target:       "/info?format=yaml",
+		expectStatus: http.StatusOK,
+		expectType:   info.ContentTypeYAML,
+		expectBody:   coding.ToString(coding.TypeYAML, handlerInfo),
+	},
+	"head json": {
+		method:       http.MethodHead,
+		target:       "/info",
+		expectStatus: http.StatusOK,
+		expectType:   info.ContentTypeJSON,
+	},
+	"head yaml": {
+		method:       http.MethodHead,
+		target:       "/info?format=yaml",
+		expectStatus: http.StatusOK,
+		expectType:   info.ContentTypeYAML,
+	},
+	"post": {
+		method:        http.MethodPost,
+		target:        "/info",
+		expectStatus:  http.StatusMethodNotAllowed,
+		expectAllowed: "GET, HEAD",
+	},
+}
+
+func TestHandler(t *testing.T) {
+	test.Map(t, testHandlerParams).
+		Run(func(t test.Test, param testHandlerParam) {
+			// Given
+			handler := info.Handler(handlerInfo)
+			request := httptest.NewRequest(param.method, param.target, nil)
+			recorder := httptest.NewRecorder()
+
+			// When
+			handler.ServeHTTP(recorder, request)
+
+			// Then
+			assert.Equal(t, param.expectStatus, recorder.Code)
+			assert.Equal(t, param.expectType,
+				recorder.Header().Get("Content-Type"))
+			assert.Equal(t, param.expectBody, recorder.Body.String())
+			assert.Equal(t, param.expectAllowed,
+				recorder.Header().Get("Allow"))
+		})
+}
+
+func TestHandlerETag(t *testing.T) {
+	// Given
+	handler := info.Handler(handlerInfo)
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/info", nil))
+	etag := recorder.Header().Get("ETag")
+	request := httptest.NewRequest(http.MethodGet, "/info", nil)
+	request.Header.Set("If-None-Match", etag)
+	recorder = httptest.NewRecorder()
+
+	// When
+	handler.ServeHTTP(recorder, request)
+
+	// Then
+	assert.NotEmpty(t, etag)
+	assert.Equal(t, http.StatusNotModified, recorder.Code)
+	assert.Equal(t, etag, recorder.Header().Get("ETag"))
+	assert.Empty(t, recorder.Body.String())
+}
+
+type testDefaultHandlerParam struct {
+	handler func() http.Handler
+}
+
+var testDefaultHandlerParams = map[string]testDefaultHandlerParam{
+	"default handler": {
+		handler: info.DefaultHandler,
+	},
+	"nil info handler": {
+		handler: func() http.Handler { return info.Handler(nil) },
+	},
+}
+
+func TestDefaultHandler(t *testing.T) {
+	test.Map(t, testDefaultHandlerParams).
+		RunSeq(func(t test.Test, param testDefaultHandlerParam) {
+			// Given
+			defaultInfo := info.GetDefault()
+			defer info.SetDefault(defaultInfo)
+			info.SetDefault(handlerInfo)
+			recorder := httptest.NewRecorder()
+
+			// When
+			param.handler().ServeHTTP(recorder,
+				httptest.NewRequest(http.MethodGet, "/info", nil))
+
+			// Then
+			assert.Equal(t, http.StatusOK, recorder.Code)
+			assert.Equal(t, handlerInfo.String(), recorder.Body.String())
+		})
+}
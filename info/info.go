@@ -2,26 +2,54 @@
 package info
 
 import (
+	"cmp"
+	"errors"
 	"fmt"
+	"io"
+	"path"
 	"regexp"
 	"runtime"
 	"runtime/debug"
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/rs/zerolog"
 	log "github.com/sirupsen/logrus"
 	"github.com/tkrop/go-config/internal/coding"
 )
 
 const (
-	// RepoPathSepNum is the number of the repository path separator.
+	// RepoPathSepNum is the number of the repository path separator for
+	// hosts with fixed repository path depth, e.g. `github.com`.
 	RepoPathSepNum = 3
-	// DebugRevisionLen is the length of the debug revision.
+	// DebugRevisionLen is the default length of short revisions.
 	DebugRevisionLen = 12
 )
 
+// ErrParse is a common error to indicate an invalid custom value of the
+// build information.
+var ErrParse = errors.New("invalid build info")
+
+// NewErrParse creates a new error to indicate that the given custom value of
+// the build information with the given key could not be parsed.
+func NewErrParse(key, value string, err error) error {
+	return fmt.Errorf("%w [%s=%s]: %w", ErrParse, key, value, err)
+}
+
+// ErrRelease is a common error to indicate that the build information does
+// not describe a clean release build.
+var ErrRelease = errors.New("no release")
+
+// NewErrRelease creates a new error to indicate that the build information
+// with the given version does not describe a clean release build for the
+// given reason.
+func NewErrRelease(reason, version string) error {
+	return fmt.Errorf("%w - %s [%s]", ErrRelease, reason, version)
+}
+
 var (
 	// Regexp for semantic versioning as supported by go as tag.
 	semVersionTagRegex = regexp.MustCompile(
@@ -29,24 +57,67 @@ var (
 			`(?:-(?P<prerelease>(?:0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*)` +
 			`(?:\.(?:0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*))*))?` +
 			`(?:\+(?P<buildmetadata>[0-9a-zA-Z-]+(?:\.[0-9a-zA-Z-]+)*))?$`)
+	// Regexp for pseudo versions prerelease as generated by go and by
+	// `AdjustVersion` using the commit time and the revision.
+	pseudoVersionRegex = regexp.MustCompile(
+		`(?:^|\.)\d{14}-[0-9a-zA-Z-]+(?:\.[0-9a-zA-Z-]+)*$`)
 	// Default build information filled from context.
-	defaultInfo = New("", "", "", "", "", "true")
+	defaultInfo = NewCached("", "", "", "", "", "true", "", "")
+	// Regexp for hexadecimal commit hash revisions.
+	hashRevisionRegex = regexp.MustCompile(`^[0-9a-fA-F]+$`)
+	// Default length of short revisions.
+	shortRevisionLen = newAtomicInt64(DebugRevisionLen)
+	// Regexp for the module major version suffix of a package path.
+	majorVersionRegex = regexp.MustCompile(`/v(?:[2-9]|[1-9]\d+)$`)
+	// Number of repository path separators of hosts with fixed repository
+	// path depth. For other hosts the full package path is used.
+	repoPathSepNums = map[string]int{
+		"github.com":    RepoPathSepNum,
+		"bitbucket.org": RepoPathSepNum,
+	}
 	// Mutex to prevent race condition.
 	mutex = sync.Mutex{}
+
+	// Cached debug build information read once via `readDebugCached`.
+	debugBuild *debug.BuildInfo
+	// Cached flag whether the debug build information is available.
+	debugOK bool
+	// Once to read the debug build information only once.
+	debugOnce = sync.Once{}
 )
 
-// SetDefault sets the default build information of a command or module.
+// newAtomicInt64 creates a new atomic integer with the given value.
+func newAtomicInt64(value int64) *atomic.Int64 {
+	result := &atomic.Int64{}
+	result.Store(value)
+	return result
+}
+
+// SetDefault sets the default build information of a command or module. The
+// build information is copied to prevent callers from mutating the shared
+// default build information.
 func SetDefault(info *Info) {
 	mutex.Lock()
 	defer mutex.Unlock()
-	defaultInfo = info
+	defaultInfo = info.clone()
 }
 
-// GetDefault returns the default build information of a command or module.
+// GetDefault returns a copy of the default build information of a command or
+// module to prevent callers from mutating the shared default build
+// information.
 func GetDefault() *Info {
 	mutex.Lock()
 	defer mutex.Unlock()
-	return defaultInfo
+	return defaultInfo.clone()
+}
+
+// readDebugCached reads the debug build information only once and returns
+// the cached debug build information on subsequent calls.
+func readDebugCached() (*debug.BuildInfo, bool) {
+	debugOnce.Do(func() {
+		debugBuild, debugOK = debug.ReadBuildInfo()
+	})
+	return debugBuild, debugOK
 }
 
 // Info provides the build information of a command or module.
@@ -60,6 +131,12 @@ type Info struct {
 	// Revision contains the revision of the command or module from version
 	// control system.
 	Revision string `yaml:"revision,omitempty" json:"revision,omitempty"`
+	// Branch contains the branch of the command or module from the version
+	// control system.
+	Branch string `yaml:"branch,omitempty" json:"branch,omitempty"`
+	// Tag contains the exact tag of the command or module from the version
+	// control system, if the build is based on a tagged revision.
+	Tag string `yaml:"tag,omitempty" json:"tag,omitempty"`
 	// Build contains the build time of the command or module.
 	Build time.Time `yaml:"build,omitempty" json:"build,omitempty"`
 	// Commit contains the last commit time of the command or module from the
@@ -79,6 +156,40 @@ type Info struct {
 	// Compiler contains the actual compiler the command or module was build
 	// with.
 	Compiler string `yaml:"compiler,omitempty" json:"compiler,omitempty"`
+	// CGO contains the `CGO_ENABLED` build setting of the command or module,
+	// i.e. `1` if CGO was enabled and `0` if not.
+	CGO string `yaml:"cgo,omitempty" json:"cgo,omitempty"`
+	// Tags contains the build tags the command or module was build with.
+	Tags string `yaml:"tags,omitempty" json:"tags,omitempty"`
+	// GOARM contains the `GOARM` build setting of the command or module.
+	GOARM string `yaml:"goarm,omitempty" json:"goarm,omitempty"`
+	// GOAMD64 contains the `GOAMD64` build setting of the command or module.
+	GOAMD64 string `yaml:"goamd64,omitempty" json:"goamd64,omitempty"`
+	// GOFLAGS contains the `GOFLAGS` build setting of the command or module.
+	GOFLAGS string `yaml:"goflags,omitempty" json:"goflags,omitempty"`
+
+	// Deps contains the module dependencies the command or module was build
+	// with, if captured via `UseDebugDeps`.
+	Deps []Module `yaml:"deps,omitempty" json:"deps,omitempty"`
+	// Runtime contains the runtime environment of the process of the command
+	// or module, if captured via `WithRuntime`.
+	Runtime *Runtime `yaml:"runtime,omitempty" json:"runtime,omitempty"`
+
+	// Errors contains the errors of parsing the custom values of the build
+	// information, that are left to the caller to report.
+	Errors []error `yaml:"-" json:"-" mapstructure:"-"`
+}
+
+// Module provides the build information of a module dependency.
+type Module struct {
+	// Path contains the module path of the dependency.
+	Path string `yaml:"path,omitempty" json:"path,omitempty"`
+	// Version contains the module version of the dependency.
+	Version string `yaml:"version,omitempty" json:"version,omitempty"`
+	// Sum contains the check sum of the dependency.
+	Sum string `yaml:"sum,omitempty" json:"sum,omitempty"`
+	// Replace contains the module replacing the dependency, if any.
+	Replace *Module `yaml:"replace,omitempty" json:"replace,omitempty"`
 }
 
 // New returns the build information of a command or module using given custom
@@ -87,31 +198,87 @@ type Info struct {
 // must be the revision of the command or module from the version control
 // system. The build and commit time must be provided using RFC3339 format.
 // The dirty flag must be set if the build of the command or module is based.
-// on a dirty local repository state.
+// on a dirty local repository state. The branch and the exact tag must be the
+// branch and tag of the command or module from the version control system.
 //
 // If no custom values are provided the build information is enriched using
 // the build information of the command or module and the debug build
 // information if available. The version is adjusted if it does not follow
 // semantic versioning as supported by go.
 func New(
-	path, version, revision, build, commit, dirty string,
+	path, version, revision, build, commit, dirty, branch, tag string,
 ) *Info {
-	return (&Info{
+	return newInfo(path, version, revision, build, commit, dirty, branch,
+		tag).UseDebug(debug.ReadBuildInfo()).AdjustVersion()
+}
+
+// NewCached returns the build information of a command or module the same
+// way as `New`, but reads the debug build information only once and reuses
+// the cached debug build information on subsequent calls.
+func NewCached(
+	path, version, revision, build, commit, dirty, branch, tag string,
+) *Info {
+	return newInfo(path, version, revision, build, commit, dirty, branch,
+		tag).UseDebug(readDebugCached()).AdjustVersion()
+}
+
+// newInfo returns the build information of a command or module using given
+// custom values without enriching it.
+func newInfo(
+	path, version, revision, build, commit, dirty, branch, tag string,
+) *Info {
+	info := &Info{
 		Path:     path,
 		Version:  version,
 		Revision: revision,
-		Build:    TimeRFC3339Parse(build),
-		Commit:   TimeRFC3339Parse(commit),
-		Dirty:    DirtyParse(dirty),
+		Branch:   branch,
+		Tag:      tag,
 		Go:       runtime.Version()[2:],
 		Compiler: runtime.Compiler,
 		Platform: fmt.Sprintf("%s/%s", runtime.GOOS, runtime.GOARCH),
-	}).UseDebug(debug.ReadBuildInfo()).AdjustVersion()
+	}
+
+	var err error
+	if info.Build, err = ParseTime(build); err != nil {
+		info.Errors = append(info.Errors, NewErrParse("build", build, err))
+	}
+	if info.Commit, err = ParseTime(commit); err != nil {
+		info.Errors = append(info.Errors, NewErrParse("commit", commit, err))
+	}
+	if info.Dirty, err = ParseDirty(dirty); err != nil {
+		info.Errors = append(info.Errors, NewErrParse("dirty", dirty, err))
+	}
+	return info
+}
+
+// clone returns a deep copy of the build information of a command or module.
+func (info *Info) clone() *Info {
+	if info == nil {
+		return nil
+	}
+
+	clone := *info
+	if info.Deps != nil {
+		clone.Deps = make([]Module, len(info.Deps))
+		for index := range info.Deps {
+			clone.Deps[index] = *info.Deps[index].clone()
+		}
+	}
+	if info.Errors != nil {
+		clone.Errors = append([]error{}, info.Errors...)
+	}
+	if info.Runtime != nil {
+		rt := *info.Runtime
+		clone.Runtime = &rt
+	}
+	return &clone
 }
 
 // UseDebug enriches the build information of a command or module using the
-// given debug build information. If the debug build information is not
-// available the build information is not changed.
+// given debug build information including the version control settings as
+// well as the `CGO_ENABLED`, `-tags`, `GOARM`, `GOAMD64`, and `GOFLAGS` build
+// settings. If the debug build information is not available the build
+// information is not changed.
 func (info *Info) UseDebug(build *debug.BuildInfo, ok bool) *Info {
 	if ok && build != nil {
 		if info.Path == "" {
@@ -133,6 +300,20 @@ func (info *Info) UseDebug(build *debug.BuildInfo, ok bool) *Info {
 				info.Commit, _ = time.Parse(time.RFC3339, kv.Value)
 			case "vcs.modified":
 				info.Dirty = kv.Value == "true"
+			case "vcs.branch":
+				info.Branch = kv.Value
+			case "vcs.tag":
+				info.Tag = kv.Value
+			case "CGO_ENABLED":
+				info.CGO = kv.Value
+			case "-tags":
+				info.Tags = kv.Value
+			case "GOARM":
+				info.GOARM = kv.Value
+			case "GOAMD64":
+				info.GOAMD64 = kv.Value
+			case "GOFLAGS":
+				info.GOFLAGS = kv.Value
 			}
 		}
 	}
@@ -140,34 +321,435 @@ func (info *Info) UseDebug(build *debug.BuildInfo, ok bool) *Info {
 	return info
 }
 
+// UseDebugDeps enriches the build information of a command or module using
+// the given debug build information the same way as `UseDebug` and captures
+// additionally the module dependencies including their replacements. If the
+// debug build information is not available the build information is not
+// changed.
+func (info *Info) UseDebugDeps(build *debug.BuildInfo, ok bool) *Info {
+	info.UseDebug(build, ok)
+	if ok && build != nil {
+		info.Deps = make([]Module, 0, len(build.Deps))
+		for _, dep := range build.Deps {
+			if dep != nil {
+				info.Deps = append(info.Deps, *newModule(dep))
+			}
+		}
+	}
+
+	return info
+}
+
+// newModule creates the build information of the given debug module
+// dependency including its replacement.
+func newModule(dep *debug.Module) *Module {
+	module := &Module{Path: dep.Path, Version: dep.Version, Sum: dep.Sum}
+	if dep.Replace != nil {
+		module.Replace = newModule(dep.Replace)
+	}
+	return module
+}
+
+// clone returns a deep copy of the module dependency including its
+// replacement.
+func (module *Module) clone() *Module {
+	clone := *module
+	if module.Replace != nil {
+		clone.Replace = module.Replace.clone()
+	}
+	return &clone
+}
+
+// Dependency returns the module dependency with the given module path, if
+// the module dependencies were captured via `UseDebugDeps`.
+func (info *Info) Dependency(path string) (Module, bool) {
+	for _, dep := range info.Deps {
+		if dep.Path == path {
+			return dep, true
+		}
+	}
+	return Module{}, false
+}
+
+// Merge merges the given build information of a command or module into the
+// build information using field-wise non-zero override semantics, i.e. all
+// non-zero attributes of the given build information override the attributes
+// of the build information. Parse errors are accumulated. The version is not
+// adjusted, this needs to be done via `AdjustVersion`. Since the dirty flag is
+// only overridden if set, clearing it requires setting it explicitly.
+func (info *Info) Merge(other *Info) *Info {
+	if other == nil {
+		return info
+	}
+
+	info.Path = cmp.Or(other.Path, info.Path)
+	info.Repo = cmp.Or(other.Repo, info.Repo)
+	info.Version = cmp.Or(other.Version, info.Version)
+	info.Revision = cmp.Or(other.Revision, info.Revision)
+	info.Branch = cmp.Or(other.Branch, info.Branch)
+	info.Tag = cmp.Or(other.Tag, info.Tag)
+	if !other.Build.IsZero() {
+		info.Build = other.Build
+	}
+	if !other.Commit.IsZero() {
+		info.Commit = other.Commit
+	}
+	info.Dirty = other.Dirty || info.Dirty
+	info.Checksum = cmp.Or(other.Checksum, info.Checksum)
+	info.Go = cmp.Or(other.Go, info.Go)
+	info.Platform = cmp.Or(other.Platform, info.Platform)
+	info.Compiler = cmp.Or(other.Compiler, info.Compiler)
+	info.CGO = cmp.Or(other.CGO, info.CGO)
+	info.Tags = cmp.Or(other.Tags, info.Tags)
+	info.GOARM = cmp.Or(other.GOARM, info.GOARM)
+	info.GOAMD64 = cmp.Or(other.GOAMD64, info.GOAMD64)
+	info.GOFLAGS = cmp.Or(other.GOFLAGS, info.GOFLAGS)
+	if other.Deps != nil {
+		info.Deps = other.Deps
+	}
+	if other.Runtime != nil {
+		info.Runtime = other.Runtime
+	}
+	info.Errors = append(info.Errors, other.Errors...)
+
+	return info
+}
+
+// SetShortRevisionLen sets the default length of short revisions used by
+// `ShortRevision`, if no custom length is requested.
+func SetShortRevisionLen(n int) {
+	shortRevisionLen.Store(int64(n))
+}
+
+// GetShortRevisionLen returns the default length of short revisions used by
+// `ShortRevision`, if no custom length is requested.
+func GetShortRevisionLen() int {
+	return int(shortRevisionLen.Load())
+}
+
+// ShortRevision returns the revision of the build information of a command
+// or module truncated to the given length. If the length is not positive,
+// the default length provided by `GetShortRevisionLen` is used. Revisions
+// that are no hexadecimal commit hashes or are not longer than the length
+// are returned unchanged.
+func (info *Info) ShortRevision(n int) string {
+	if n <= 0 {
+		n = GetShortRevisionLen()
+	}
+	if n <= 0 || len(info.Revision) <= n ||
+		!hashRevisionRegex.MatchString(info.Revision) {
+		return info.Revision
+	}
+	return info.Revision[0:n]
+}
+
 // AdjustVersion adjusts the version of the build information of a command or
 // module if the version does not follow semantic versioning as supported by
-// go. The version is adjusted using the revision and commit time of the build
-// information. If the revision is not available the version is not changed.
+// go. The version is adjusted using the exact tag, if it follows semantic
+// versioning, and else using the revision and commit time of the build
+// information, shortened via `ShortRevision` using the default length. If the
+// revision is not available the version is not changed.
 func (info *Info) AdjustVersion() *Info {
 	if info.Path != "" {
-		info.Repo = strings.Replace(splitRuneN(
-			"git@"+info.Path, '/', RepoPathSepNum), "/", ":", 1)
+		info.Repo = info.RepoURL(RepoSchemeSSH)
 	}
 
 	if !semVersionTagRegex.MatchString(info.Version) {
-		if info.Revision != "" && !info.Commit.Equal(time.Time{}) {
-			revision := info.Revision
-			if len(revision) > DebugRevisionLen {
-				info.Revision = revision[0:DebugRevisionLen]
-			}
+		if semVersionTagRegex.MatchString(info.Tag) {
+			info.Version = info.Tag
+		} else if info.Revision != "" && !info.Commit.Equal(time.Time{}) {
+			info.Revision = info.ShortRevision(0)
 			info.Version = fmt.Sprintf("v0.0.0-%s-%s",
-				info.Commit.UTC().Format("20060102150405"), revision)
+				info.Commit.UTC().Format("20060102150405"), info.Revision)
 		}
 	}
 
 	return info
 }
 
+// Repository URL schemes supported by `RepoURL`.
+const (
+	// RepoSchemeSSH is the scheme for `git@host:org/repo` repository URLs.
+	RepoSchemeSSH = "ssh"
+	// RepoSchemeHTTPS is the scheme for `https://host/org/repo` repository
+	// URLs.
+	RepoSchemeHTTPS = "https"
+)
+
+// RepoURL returns the repository URL of a command or module derived from the
+// package path using the given scheme, i.e. `ssh` or `https`. For hosts with
+// fixed repository path depth, e.g. `github.com`, the package path is cut
+// after the repository, while for other hosts, e.g. `gitlab.com` supporting
+// subgroups, the full package path is used. The module major version suffix
+// is stripped in both cases. If the path is missing or the scheme is not
+// supported, an empty string is returned.
+func (info *Info) RepoURL(scheme string) string {
+	if info.Path == "" {
+		return ""
+	}
+
+	repo := info.Path
+	host, _, _ := strings.Cut(repo, "/")
+	if num, ok := repoPathSepNums[host]; ok {
+		repo = splitRuneN(repo, '/', num)
+	}
+	repo = majorVersionRegex.ReplaceAllString(repo, "")
+
+	switch scheme {
+	case RepoSchemeSSH:
+		return "git@" + strings.Replace(repo, "/", ":", 1)
+	case RepoSchemeHTTPS:
+		return "https://" + repo
+	}
+	return ""
+}
+
+// Encoding types supported for formatting the build information.
+const (
+	// FormatJSON is the JSON encoding type.
+	FormatJSON = coding.TypeJSON
+	// FormatYAML is the YAML encoding type.
+	FormatYAML = coding.TypeYAML
+)
+
+// SemVer returns the semantic version parts of the version of the build
+// information of a command or module, i.e. the major, minor, and patch
+// version as well as the prerelease and build metadata. If the version does
+// not follow semantic versioning as supported by go, `ok` is `false`.
+func (info *Info) SemVer() (
+	major, minor, patch int, prerelease, metadata string, ok bool,
+) {
+	match := semVersionTagRegex.FindStringSubmatch(info.Version)
+	if match == nil {
+		return 0, 0, 0, "", "", false
+	}
+
+	group := func(name string) string {
+		return match[semVersionTagRegex.SubexpIndex(name)]
+	}
+	major, _ = strconv.Atoi(group("major"))
+	minor, _ = strconv.Atoi(group("minor"))
+	patch, _ = strconv.Atoi(group("patch"))
+	return major, minor, patch, group("prerelease"), group("buildmetadata"), true
+}
+
+// IsPrerelease returns whether the version of the build information of a
+// command or module is a semantic prerelease version including pseudo
+// versions.
+func (info *Info) IsPrerelease() bool {
+	_, _, _, prerelease, _, ok := info.SemVer()
+	return ok && prerelease != ""
+}
+
+// IsPseudoVersion returns whether the version of the build information of a
+// command or module is a pseudo version, i.e. a version constructed from the
+// commit time and the revision by go or by `AdjustVersion`.
+func (info *Info) IsPseudoVersion() bool {
+	_, _, _, prerelease, _, ok := info.SemVer()
+	return ok && pseudoVersionRegex.MatchString(prerelease)
+}
+
+// IsRelease returns whether the build information of a command or module
+// describes a clean release build, i.e. the version is a semantic version
+// that is neither a prerelease nor a pseudo version, the build is not dirty,
+// and the revision is present.
+func (info *Info) IsRelease() bool {
+	return info.ReleaseCheck() == nil
+}
+
+// ReleaseCheck checks whether the build information of a command or module
+// describes a clean release build the same way as `IsRelease`, returning an
+// error explaining each failed criterion.
+func (info *Info) ReleaseCheck() error {
+	errs := []error{}
+	if _, _, _, _, _, ok := info.SemVer(); !ok {
+		errs = append(errs, NewErrRelease("invalid version", info.Version))
+	} else if info.IsPseudoVersion() {
+		errs = append(errs, NewErrRelease("pseudo version", info.Version))
+	} else if info.IsPrerelease() {
+		errs = append(errs, NewErrRelease("prerelease version", info.Version))
+	}
+	if info.Dirty {
+		errs = append(errs, NewErrRelease("dirty build", info.Version))
+	}
+	if info.Revision == "" {
+		errs = append(errs, NewErrRelease("missing revision", info.Version))
+	}
+	return errors.Join(errs...)
+}
+
 // String returns the build information of a command or module as structured
-// JSON string.
+// JSON string. If the encoding fails, the error report is returned instead.
 func (info *Info) String() string {
-	return coding.ToString(coding.TypeJSON, info)
+	str, err := info.Format(FormatJSON)
+	if err != nil {
+		return err.Error()
+	}
+	return str
+}
+
+// Format returns the build information of a command or module as structured
+// string using the given encoding type, i.e. JSON or YAML. Zero build and
+// commit times are omitted in YAML. If the encoding fails or the encoding
+// type is unknown, the error is returned.
+func (info *Info) Format(ctype coding.Type) (string, error) {
+	bytes, err := coding.Marshal(ctype, info)
+	if err != nil {
+		return "", err
+	}
+	return string(bytes), nil
+}
+
+// Short returns the build information of a command or module as human
+// readable one-line summary, e.g. `myapp v1.4.2 (rev 1b66f320c950, built
+// 2024-10-01, go1.22.3 linux/amd64, dirty)`, omitting unset attributes. The
+// revision is truncated to the debug revision length.
+func (info *Info) Short() string {
+	parts := []string{}
+	if info.Path != "" {
+		parts = append(parts, path.Base(info.Path))
+	}
+	if info.Version != "" {
+		parts = append(parts, info.Version)
+	}
+
+	details := []string{}
+	if revision := info.ShortRevision(0); revision != "" {
+		details = append(details, "rev "+revision)
+	}
+	if !info.Build.IsZero() {
+		details = append(details, "built "+info.Build.UTC().Format(time.DateOnly))
+	}
+	if platform := strings.TrimSpace(
+		goVersion(info.Go) + " " + info.Platform); platform != "" {
+		details = append(details, platform)
+	}
+	if info.Dirty {
+		details = append(details, "dirty")
+	}
+	if len(details) > 0 {
+		parts = append(parts, "("+strings.Join(details, ", ")+")")
+	}
+	return strings.Join(parts, " ")
+}
+
+// goVersion returns the given go version with `go` prefix, if set.
+func goVersion(version string) string {
+	if version == "" {
+		return ""
+	}
+	return "go" + version
+}
+
+// PrintVersion prints the default build information of a command or module
+// as human readable one-line summary to the given writer, e.g. for the output
+// of a `--version` flag.
+func PrintVersion(w io.Writer) error {
+	_, err := fmt.Fprintln(w, GetDefault().Short())
+	return err
+}
+
+// Fields returns the non-zero attributes of the build information of a
+// command or module as field map using the stable keys `version`,
+// `revision`, `build`, `commit`, `dirty`, `go`, `platform`, `cgo`, `tags`,
+// `goarm`, `goamd64`, and `goflags`, as well as `hostname`, `pid`, `user`,
+// `numcpu`, and `start` of the runtime environment, if present. Zero times
+// are omitted. The field map is accepted by logrus via `WithFields` and
+// zerolog via `Fields`.
+func (info *Info) Fields() map[string]any {
+	fields := make(map[string]any, 17)
+	addField(fields, "version", info.Version, info.Version != "")
+	addField(fields, "revision", info.Revision, info.Revision != "")
+	addField(fields, "build", info.Build, !info.Build.IsZero())
+	addField(fields, "commit", info.Commit, !info.Commit.IsZero())
+	addField(fields, "dirty", info.Dirty, info.Dirty)
+	addField(fields, "go", info.Go, info.Go != "")
+	addField(fields, "platform", info.Platform, info.Platform != "")
+	addField(fields, "cgo", info.CGO, info.CGO != "")
+	addField(fields, "tags", info.Tags, info.Tags != "")
+	addField(fields, "goarm", info.GOARM, info.GOARM != "")
+	addField(fields, "goamd64", info.GOAMD64, info.GOAMD64 != "")
+	addField(fields, "goflags", info.GOFLAGS, info.GOFLAGS != "")
+	if rt := info.Runtime; rt != nil {
+		addField(fields, "hostname", rt.Hostname, rt.Hostname != "")
+		addField(fields, "pid", rt.PID, rt.PID != 0)
+		addField(fields, "user", rt.User, rt.User != "")
+		addField(fields, "numcpu", rt.NumCPU, rt.NumCPU != 0)
+		addField(fields, "start", rt.Start, !rt.Start.IsZero())
+	}
+	return fields
+}
+
+// addField adds the given field value with the given key to the given field
+// map, if the add flag is set.
+func addField(fields map[string]any, key string, value any, add bool) {
+	if add {
+		fields[key] = value
+	}
+}
+
+// LogStartup logs a single info entry with the build information fields of
+// the command or module via the given logrus logger on startup.
+func (info *Info) LogStartup(logger log.FieldLogger) {
+	logger.WithFields(info.Fields()).Info("starting")
+}
+
+// ZeroLogStartup logs a single info entry with the build information fields
+// of the command or module via the given zerolog logger on startup.
+func (info *Info) ZeroLogStartup(logger *zerolog.Logger) {
+	logger.Info().Fields(info.Fields()).Msg("starting")
+}
+
+// BuildAge returns the age of the build of a command or module at the given
+// time using the build time falling back to the commit time. If both times
+// are unknown, `false` is returned.
+func (info *Info) BuildAge(now time.Time) (time.Duration, bool) {
+	switch {
+	case !info.Build.IsZero():
+		return now.Sub(info.Build), true
+	case !info.Commit.IsZero():
+		return now.Sub(info.Commit), true
+	}
+	return 0, false
+}
+
+// staleFields returns the fields of the staleness warning, if the build of
+// the command or module is older than the given duration at the given time.
+func (info *Info) staleFields(d time.Duration, now time.Time) log.Fields {
+	age, ok := info.BuildAge(now)
+	if !ok || age <= d {
+		return nil
+	}
+	return log.Fields{
+		"age":     age.Round(time.Second).String(),
+		"version": info.Version,
+	}
+}
+
+// WarnIfOlderThan logs a single warning with the build age and version via
+// the given logrus logger, if the build of the command or module is older
+// than the given duration. Builds of unknown age never warn. The result is
+// whether the warning was logged.
+func (info *Info) WarnIfOlderThan(d time.Duration, logger log.FieldLogger) bool {
+	fields := info.staleFields(d, time.Now())
+	if fields == nil {
+		return false
+	}
+	logger.WithFields(fields).Warn("build outdated")
+	return true
+}
+
+// ZeroWarnIfOlderThan logs a single warning with the build age and version
+// via the given zerolog logger the same way as `WarnIfOlderThan`.
+func (info *Info) ZeroWarnIfOlderThan(
+	d time.Duration, logger *zerolog.Logger,
+) bool {
+	fields := info.staleFields(d, time.Now())
+	if fields == nil {
+		return false
+	}
+	logger.Warn().Fields(map[string]any(fields)).Msg("build outdated")
+	return true
 }
 
 // splitRuneN splits the string s at the `n`th occurrence of the rune ch.
@@ -186,27 +768,43 @@ func splitRuneN(s string, ch rune, n int) string {
 	return s
 }
 
+// ParseTime parses the given time string using RFC3339 format. An empty
+// time string is parsed as zero time without error.
+func ParseTime(t string) (time.Time, error) {
+	if t == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse(time.RFC3339, t)
+}
+
+// ParseDirty parses the given string as a boolean value. An empty string is
+// parsed as `false` without error. If the parsing fails, `true` is returned
+// together with the error to flag the build as dirty.
+func ParseDirty(str string) (bool, error) {
+	if str == "" {
+		return false, nil
+	}
+	dirty, err := strconv.ParseBool(str)
+	if err != nil {
+		return true, err
+	}
+	return dirty, nil
+}
+
 // TimeRFC3339Parse parses the given time string using RFC3339 format
 // swallowing errors.
+//
+// Deprecated: use `ParseTime` instead to handle errors.
 func TimeRFC3339Parse(t string) time.Time {
-	time, err := time.Parse(time.RFC3339, t)
-	if err != nil && t != "" {
-		log.WithFields(log.Fields{
-			"time": t,
-		}).WithError(err).Error("parsing time")
-	}
+	time, _ := ParseTime(t)
 	return time
 }
 
-// DirtyParse parses the given string as a boolean value and returns false if
+// DirtyParse parses the given string as a boolean value and returns true if
 // the parsing fails. Else the parsed boolean value is returned.
+//
+// Deprecated: use `ParseDirty` instead to handle errors.
 func DirtyParse(str string) bool {
-	dirty, err := strconv.ParseBool(str)
-	if err != nil && str != "" {
-		log.WithFields(log.Fields{
-			"bool": str,
-		}).WithError(err).Error("parsing bool")
-		return true
-	}
+	dirty, _ := ParseDirty(str)
 	return dirty
 }
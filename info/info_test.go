@@ -1,12 +1,23 @@
 package info_test
 
 import (
+	"bytes"
+	"os"
+	"path/filepath"
 	"runtime/debug"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/rs/zerolog"
+	"github.com/sirupsen/logrus"
+	logtest "github.com/sirupsen/logrus/hooks/test"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/tkrop/go-config/info"
+	"github.com/tkrop/go-config/internal/coding"
 	"github.com/tkrop/go-testing/test"
 )
 
@@ -27,73 +38,76 @@ type InfoParams struct {
 
 var testInfoParams = map[string]InfoParams{
 	"nil build info": {
-		info:       info.New("", "", "", "", "", ""),
-		expectInfo: info.New("", "", "", "", "", ""),
+		info:       info.New("", "", "", "", "", "", "", ""),
+		expectInfo: info.New("", "", "", "", "", "", "", ""),
 	},
 	"no build info": {
-		info:       info.New("", "", "", "", "", ""),
+		info:       info.New("", "", "", "", "", "", "", ""),
 		build:      &debug.BuildInfo{},
-		expectInfo: info.New("", "", "", "", "", ""),
+		expectInfo: info.New("", "", "", "", "", "", "", ""),
 	},
 	"invalid build info": {
-		info:       info.New("", "", "", "x", "x", "x"),
-		build:      &debug.BuildInfo{},
-		expectInfo: info.New("", "", "", "", "", "true"),
+		info:  info.New("", "", "", "x", "x", "x", "", ""),
+		build: &debug.BuildInfo{},
+		expectInfo: withErrors(info.New("", "", "", "", "", "true", "", ""),
+			info.NewErrParse("build", "x", timeErr("x")),
+			info.NewErrParse("commit", "x", timeErr("x")),
+			info.NewErrParse("dirty", "x", dirtyErr("x"))),
 	},
 
 	// Setup build info path.
 	"build info setup path": {
-		info: info.New(setupPath, "", "", "", "", ""),
+		info: info.New(setupPath, "", "", "", "", "", "", ""),
 		build: &debug.BuildInfo{
 			Main: debug.Module{Path: buildPath},
 		},
-		expectInfo: info.New(setupPath, "", "", "", "", ""),
+		expectInfo: info.New(setupPath, "", "", "", "", "", "", ""),
 	},
 	"build info build path": {
-		info: info.New("", "", "", "", "", ""),
+		info: info.New("", "", "", "", "", "", "", ""),
 		build: &debug.BuildInfo{
 			Main: debug.Module{Path: buildPath},
 		},
-		expectInfo: info.New(buildPath, "", "", "", "", ""),
+		expectInfo: info.New(buildPath, "", "", "", "", "", "", ""),
 	},
 
 	// Setup build info version.
 	"build info setup version": {
-		info: info.New("", "v2.3.4", "beta.1", "", "", ""),
+		info: info.New("", "v2.3.4", "beta.1", "", "", "", "", ""),
 		build: &debug.BuildInfo{
 			Main: debug.Module{Version: "v1.2.3-alpha.1"},
 		},
-		expectInfo: info.New("", "v1.2.3-alpha.1", "alpha.1", "", "", ""),
+		expectInfo: info.New("", "v1.2.3-alpha.1", "alpha.1", "", "", "", "", ""),
 	},
 	"build info build version": {
-		info: info.New("", "", "", "", "", ""),
+		info: info.New("", "", "", "", "", "", "", ""),
 		build: &debug.BuildInfo{
 			Main: debug.Module{Version: "v1.2.3-alpha.1"},
 		},
-		expectInfo: info.New("", "v1.2.3-alpha.1", "alpha.1", "", "", ""),
+		expectInfo: info.New("", "v1.2.3-alpha.1", "alpha.1", "", "", "", "", ""),
 	},
 
 	// Setup build info settings.
 	"build info revision": {
-		info: info.New("", "", "", "", "", ""),
+		info: info.New("", "", "", "", "", "", "", ""),
 		build: &debug.BuildInfo{
 			Settings: []debug.BuildSetting{
 				{Key: "vcs.revision", Value: "beta.2"},
 			},
 		},
-		expectInfo: info.New("", "", "beta.2", "", "", ""),
+		expectInfo: info.New("", "", "beta.2", "", "", "", "", ""),
 	},
 	"build info time": {
-		info: info.New("", "", "", "", "", ""),
+		info: info.New("", "", "", "", "", "", "", ""),
 		build: &debug.BuildInfo{
 			Settings: []debug.BuildSetting{
 				{Key: "vcs.time", Value: "2023-12-10T18:30:00Z"},
 			},
 		},
-		expectInfo: info.New("", "", "", "", "2023-12-10T18:30:00Z", ""),
+		expectInfo: info.New("", "", "", "", "2023-12-10T18:30:00Z", "", "", ""),
 	},
 	"build info time revision": {
-		info: info.New("", "", "", "", "", ""),
+		info: info.New("", "", "", "", "", "", "", ""),
 		build: &debug.BuildInfo{
 			Settings: []debug.BuildSetting{
 				{Key: "vcs.revision", Value: "beta.2"},
@@ -101,10 +115,10 @@ var testInfoParams = map[string]InfoParams{
 			},
 		},
 		expectInfo: info.New("", "", "beta.2", "",
-			"2023-12-10T18:30:00Z", ""),
+			"2023-12-10T18:30:00Z", "", "", ""),
 	},
 	"build info time hash": {
-		info: info.New("", "", "", "", "", ""),
+		info: info.New("", "", "", "", "", "", "", ""),
 		build: &debug.BuildInfo{
 			Settings: []debug.BuildSetting{{
 				Key:   "vcs.revision",
@@ -112,10 +126,10 @@ var testInfoParams = map[string]InfoParams{
 			}, {Key: "vcs.time", Value: "2023-12-10T18:30:00Z"}},
 		},
 		expectInfo: info.New("", "", revisionHead, "",
-			"2023-12-10T18:30:00Z", ""),
+			"2023-12-10T18:30:00Z", "", "", ""),
 	},
 	"build info time hash setup": {
-		info: info.New("", "v1.2.3", "", "", "", ""),
+		info: info.New("", "v1.2.3", "", "", "", "", "", ""),
 		build: &debug.BuildInfo{
 			Settings: []debug.BuildSetting{{
 				Key:   "vcs.revision",
@@ -123,28 +137,112 @@ var testInfoParams = map[string]InfoParams{
 			}, {Key: "vcs.time", Value: "2023-12-10T18:30:00Z"}},
 		},
 		expectInfo: info.New("", "v1.2.3", revisionHead, "",
-			"2023-12-10T18:30:00Z", ""),
+			"2023-12-10T18:30:00Z", "", "", ""),
+	},
+	"build info tag": {
+		info: info.New("", "", "", "", "", "", "", ""),
+		build: &debug.BuildInfo{
+			Settings: []debug.BuildSetting{{
+				Key:   "vcs.revision",
+				Value: revisionHead,
+			}, {Key: "vcs.time", Value: "2023-12-10T18:30:00Z"},
+				{Key: "vcs.tag", Value: "v1.2.3"}},
+		},
+		expectInfo: info.New("", "v1.2.3", revisionHead, "",
+			"2023-12-10T18:30:00Z", "", "", "v1.2.3"),
+	},
+	"build info setup tag": {
+		info:  info.New("", "", "", "", "", "", "main", "v1.2.3"),
+		build: &debug.BuildInfo{},
+		expectInfo: info.New("", "v1.2.3", "", "", "", "",
+			"main", "v1.2.3"),
+	},
+	"build info invalid tag": {
+		info: info.New("", "", "", "", "", "", "", ""),
+		build: &debug.BuildInfo{
+			Settings: []debug.BuildSetting{{
+				Key:   "vcs.revision",
+				Value: revisionHead,
+			}, {Key: "vcs.time", Value: "2023-12-10T18:30:00Z"},
+				{Key: "vcs.tag", Value: "latest"}},
+		},
+		expectInfo: info.New("", "", revisionHead, "",
+			"2023-12-10T18:30:00Z", "", "", "latest"),
+	},
+	"build info branch": {
+		info: info.New("", "", "", "", "", "", "", ""),
+		build: &debug.BuildInfo{
+			Settings: []debug.BuildSetting{{
+				Key:   "vcs.revision",
+				Value: revisionHead,
+			}, {Key: "vcs.time", Value: "2023-12-10T18:30:00Z"},
+				{Key: "vcs.branch", Value: "main"}},
+		},
+		expectInfo: info.New("", "", revisionHead, "",
+			"2023-12-10T18:30:00Z", "", "main", ""),
 	},
 	"build info modified": {
-		info: info.New("", "", "", "", "", ""),
+		info: info.New("", "", "", "", "", "", "", ""),
 		build: &debug.BuildInfo{
 			Settings: []debug.BuildSetting{
 				{Key: "vcs.modified", Value: "true"},
 			},
 		},
-		expectInfo: info.New("", "", "", "", "", "true"),
+		expectInfo: info.New("", "", "", "", "", "true", "", ""),
 	},
 	"build info unmodified": {
-		info: info.New("", "", "", "", "", ""),
+		info: info.New("", "", "", "", "", "", "", ""),
 		build: &debug.BuildInfo{
 			Settings: []debug.BuildSetting{
 				{Key: "vcs.modified", Value: "false"},
 			},
 		},
-		expectInfo: info.New("", "", "", "", "", "false"),
+		expectInfo: info.New("", "", "", "", "", "false", "", ""),
+	},
+	"build info settings": {
+		info: info.New("", "", "", "", "", "", "", ""),
+		build: &debug.BuildInfo{
+			Settings: []debug.BuildSetting{
+				{Key: "CGO_ENABLED", Value: "0"},
+				{Key: "-tags", Value: "netgo,osusergo"},
+				{Key: "GOARM", Value: "7"},
+				{Key: "GOAMD64", Value: "v3"},
+				{Key: "GOFLAGS", Value: "-mod=vendor"},
+			},
+		},
+		expectInfo: withSettings(info.New("", "", "", "", "", "", "", "")),
 	},
 }
 
+// withSettings sets the extended build settings of the given build
+// information as provided by the synthetic debug build information.
+func withSettings(build *info.Info) *info.Info {
+	build.CGO = "0"
+	build.Tags = "netgo,osusergo"
+	build.GOARM = "7"
+	build.GOAMD64 = "v3"
+	build.GOFLAGS = "-mod=vendor"
+	return build
+}
+
+// withErrors sets the given parse errors on the given build information.
+func withErrors(build *info.Info, errs ...error) *info.Info {
+	build.Errors = errs
+	return build
+}
+
+// timeErr returns the error of parsing the given time string.
+func timeErr(t string) error {
+	_, err := info.ParseTime(t)
+	return err
+}
+
+// dirtyErr returns the error of parsing the given dirty flag string.
+func dirtyErr(str string) error {
+	_, err := info.ParseDirty(str)
+	return err
+}
+
 func TestUseDebug(t *testing.T) {
 	test.Map(t, testInfoParams).
 		Run(func(t test.Test, param InfoParams) {
@@ -158,9 +256,308 @@ func TestUseDebug(t *testing.T) {
 		})
 }
 
+type testMergeParam struct {
+	info   *info.Info
+	other  *info.Info
+	expect *info.Info
+}
+
+var testMergeParams = map[string]testMergeParam{
+	"nil": {
+		info:   &info.Info{Path: "github.com/tkrop/go-config"},
+		expect: &info.Info{Path: "github.com/tkrop/go-config"},
+	},
+	"empty": {
+		info:   &info.Info{Path: "github.com/tkrop/go-config", Dirty: true},
+		other:  &info.Info{},
+		expect: &info.Info{Path: "github.com/tkrop/go-config", Dirty: true},
+	},
+	"override": {
+		info: &info.Info{
+			Path:    "github.com/tkrop/go-config",
+			Version: "v1.2.3",
+			Go:      "1.23.5",
+			CGO:     "1",
+		},
+		other: &info.Info{
+			Path:   "github.com/tkrop/go-make",
+			Build:  ageNow,
+			Dirty:  true,
+			CGO:    "0",
+			Tags:   "netgo",
+			Errors: []error{info.ErrParse},
+		},
+		expect: &info.Info{
+			Path:    "github.com/tkrop/go-make",
+			Version: "v1.2.3",
+			Build:   ageNow,
+			Dirty:   true,
+			Go:      "1.23.5",
+			CGO:     "0",
+			Tags:    "netgo",
+			Errors:  []error{info.ErrParse},
+		},
+	},
+}
+
+func TestMerge(t *testing.T) {
+	test.Map(t, testMergeParams).
+		Run(func(t test.Test, param testMergeParam) {
+			// When
+			build := param.info.Merge(param.other)
+
+			// Then
+			assert.Equal(t, param.expect, build)
+		})
+}
+
+type testRepoURLParam struct {
+	path        string
+	expectSSH   string
+	expectHTTPS string
+}
+
+var testRepoURLParams = map[string]testRepoURLParam{
+	"empty": {},
+	"github": {
+		path:        "github.com/tkrop/go-config",
+		expectSSH:   "git@github.com:tkrop/go-config",
+		expectHTTPS: "https://github.com/tkrop/go-config",
+	},
+	"github package": {
+		path:        "github.com/tkrop/go-make/cmd/go-make",
+		expectSSH:   "git@github.com:tkrop/go-make",
+		expectHTTPS: "https://github.com/tkrop/go-make",
+	},
+	"github major version": {
+		path:        "github.com/tkrop/go-config/v2",
+		expectSSH:   "git@github.com:tkrop/go-config",
+		expectHTTPS: "https://github.com/tkrop/go-config",
+	},
+	"gitlab subgroup": {
+		path:        "gitlab.com/group/sub/repo",
+		expectSSH:   "git@gitlab.com:group/sub/repo",
+		expectHTTPS: "https://gitlab.com/group/sub/repo",
+	},
+	"gitlab subgroup major version": {
+		path:        "gitlab.com/group/sub/repo/v12",
+		expectSSH:   "git@gitlab.com:group/sub/repo",
+		expectHTTPS: "https://gitlab.com/group/sub/repo",
+	},
+	"custom host": {
+		path:        "git.example.com/team/service/v1",
+		expectSSH:   "git@git.example.com:team/service/v1",
+		expectHTTPS: "https://git.example.com/team/service/v1",
+	},
+}
+
+func TestRepoURL(t *testing.T) {
+	test.Map(t, testRepoURLParams).
+		Run(func(t test.Test, param testRepoURLParam) {
+			// Given
+			build := &info.Info{Path: param.path}
+
+			// When
+			ssh := build.RepoURL(info.RepoSchemeSSH)
+			https := build.RepoURL(info.RepoSchemeHTTPS)
+
+			// Then
+			assert.Equal(t, param.expectSSH, ssh)
+			assert.Equal(t, param.expectHTTPS, https)
+			assert.Empty(t, build.RepoURL("ftp"))
+			assert.Equal(t, param.expectSSH, build.AdjustVersion().Repo)
+		})
+}
+
+type testShortRevisionParam struct {
+	revision string
+	n        int
+	len      int
+	expect   string
+}
+
+var testShortRevisionParams = map[string]testShortRevisionParam{
+	"hash 7": {
+		revision: revisionHead,
+		n:        7,
+		expect:   "1b66f32",
+	},
+	"hash 12": {
+		revision: revisionHead,
+		n:        12,
+		expect:   "1b66f320c950",
+	},
+	"hash full": {
+		revision: revisionHead,
+		n:        len(revisionHead),
+		expect:   revisionHead,
+	},
+	"hash longer": {
+		revision: "1b66f32",
+		n:        12,
+		expect:   "1b66f32",
+	},
+	"hash default": {
+		revision: revisionHead,
+		expect:   "1b66f320c950",
+	},
+	"hash default 7": {
+		revision: revisionHead,
+		len:      7,
+		expect:   "1b66f32",
+	},
+	"hash default full": {
+		revision: revisionHead,
+		len:      -1,
+		expect:   revisionHead,
+	},
+	"non-hash 7": {
+		revision: "release-2024-10-01",
+		n:        7,
+		expect:   "release-2024-10-01",
+	},
+	"non-hash 12": {
+		revision: "release-2024-10-01",
+		n:        12,
+		expect:   "release-2024-10-01",
+	},
+	"non-hash full": {
+		revision: "release-2024-10-01",
+		n:        len("release-2024-10-01"),
+		expect:   "release-2024-10-01",
+	},
+	"empty": {
+		n: 7,
+	},
+}
+
+func TestShortRevision(t *testing.T) {
+	test.Map(t, testShortRevisionParams).
+		RunSeq(func(t test.Test, param testShortRevisionParam) {
+			// Given
+			if param.len != 0 {
+				defer info.SetShortRevisionLen(info.GetShortRevisionLen())
+				info.SetShortRevisionLen(param.len)
+			}
+			build := &info.Info{Revision: param.revision}
+
+			// When
+			revision := build.ShortRevision(param.n)
+
+			// Then
+			assert.Equal(t, param.expect, revision)
+			if param.n == 0 && param.revision != "" {
+				assert.Contains(t, build.Short(), "rev "+param.expect)
+			}
+		})
+}
+
+var testAdjustVersionShortRevisionParams = map[string]testShortRevisionParam{
+	"hash default": {
+		revision: revisionHead,
+		expect:   "1b66f320c950",
+	},
+	"hash default 7": {
+		revision: revisionHead,
+		len:      7,
+		expect:   "1b66f32",
+	},
+	"hash default full": {
+		revision: revisionHead,
+		len:      -1,
+		expect:   revisionHead,
+	},
+	"non-hash": {
+		revision: "release-2024-10-01",
+		expect:   "release-2024-10-01",
+	},
+}
+
+func TestAdjustVersionShortRevision(t *testing.T) {
+	test.Map(t, testAdjustVersionShortRevisionParams).
+		RunSeq(func(t test.Test, param testShortRevisionParam) {
+			// Given
+			if param.len != 0 {
+				defer info.SetShortRevisionLen(info.GetShortRevisionLen())
+				info.SetShortRevisionLen(param.len)
+			}
+			build := &info.Info{
+				Revision: param.revision,
+				Commit:   time.Date(2023, 12, 10, 18, 30, 0, 0, time.UTC),
+			}
+
+			// When
+			build.AdjustVersion()
+
+			// Then
+			assert.Equal(t, param.expect, build.Revision)
+			assert.Equal(t, "v0.0.0-20231210183000-"+param.expect,
+				build.Version)
+		})
+}
+
+type testParseParam struct {
+	time        string
+	dirty       string
+	expectTime  time.Time
+	expectDirty bool
+	expectError bool
+}
+
+var testParseParams = map[string]testParseParam{
+	"empty": {},
+	"valid": {
+		time:        "2023-12-10T18:30:00Z",
+		dirty:       "true",
+		expectTime:  time.Date(2023, 12, 10, 18, 30, 0, 0, time.UTC),
+		expectDirty: true,
+	},
+	"invalid time": {
+		time:        "x",
+		dirty:       "false",
+		expectError: true,
+	},
+	"invalid dirty": {
+		dirty:       "x",
+		expectDirty: true,
+		expectError: true,
+	},
+}
+
+func TestParse(t *testing.T) {
+	test.Map(t, testParseParams).
+		Run(func(t test.Test, param testParseParam) {
+			// When
+			time, terr := info.ParseTime(param.time)
+			dirty, derr := info.ParseDirty(param.dirty)
+
+			// Then
+			assert.Equal(t, param.expectTime, time)
+			assert.Equal(t, param.expectDirty, dirty)
+			assert.Equal(t, param.expectError, terr != nil || derr != nil)
+			assert.Equal(t, time, info.TimeRFC3339Parse(param.time))
+			assert.Equal(t, dirty, info.DirtyParse(param.dirty))
+		})
+}
+
+func TestNewErrors(t *testing.T) {
+	// Given
+	hook := logtest.NewGlobal()
+	defer logrus.StandardLogger().ReplaceHooks(logrus.LevelHooks{})
+
+	// When
+	build := info.New("", "", "", "x", "", "false", "", "")
+
+	// Then
+	assert.Empty(t, hook.AllEntries())
+	require.Len(t, build.Errors, 1)
+	assert.ErrorIs(t, build.Errors[0], info.ErrParse)
+	assert.NotContains(t, build.String(), "error")
+}
+
 func TestDefault(t *testing.T) {
 	// Given
-	defaultInfo := info.New("", "", "", "", "", "false")
+	defaultInfo := info.New("", "", "", "", "", "false", "", "")
 
 	// When
 	info.SetDefault(defaultInfo)
@@ -168,3 +565,592 @@ func TestDefault(t *testing.T) {
 	// Then
 	assert.Equal(t, defaultInfo, info.GetDefault())
 }
+
+func TestDefaultCopy(t *testing.T) {
+	// Given
+	orig := info.GetDefault()
+	defer info.SetDefault(orig)
+	defaultInfo := &info.Info{
+		Version: "v1.2.3",
+		Deps: []info.Module{{
+			Path:    "github.com/tkrop/go-testing",
+			Replace: &info.Module{Path: "../go-testing"},
+		}},
+		Runtime: &info.Runtime{Hostname: "replica-1"},
+	}
+	info.SetDefault(defaultInfo)
+
+	// When
+	defaultInfo.Version = "v0.0.1"
+	build := info.GetDefault()
+	build.Deps[0].Replace.Path = "changed"
+	build.Runtime.Hostname = "changed"
+
+	// Then
+	expect := info.GetDefault()
+	assert.Equal(t, "v1.2.3", expect.Version)
+	assert.Equal(t, "../go-testing", expect.Deps[0].Replace.Path)
+	assert.Equal(t, "replica-1", expect.Runtime.Hostname)
+}
+
+func TestNewCached(t *testing.T) {
+	// When
+	build := info.NewCached("", "v1.2.3", "", "", "", "false", "", "")
+
+	// Then
+	assert.Equal(t, info.New("", "v1.2.3", "", "", "", "false", "", ""), build)
+}
+
+func TestConcurrentDefault(t *testing.T) {
+	// Given
+	orig := info.GetDefault()
+	defer info.SetDefault(orig)
+	group := sync.WaitGroup{}
+
+	// When
+	for index := 0; index < 10; index++ {
+		group.Add(1)
+		go func() {
+			defer group.Done()
+			build := info.NewCached("", "", "", "", "", "false", "", "")
+			info.SetDefault(info.New("", "", "", "", "", "false", "", ""))
+			info.GetDefault().Version = "changed"
+			build.Version = "changed"
+		}()
+	}
+	group.Wait()
+
+	// Then
+	assert.NotEqual(t, "changed", info.GetDefault().Version)
+}
+
+func BenchmarkNew(b *testing.B) {
+	b.Run("new", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			info.New("", "", "", "", "", "false", "", "")
+		}
+	})
+	b.Run("cached", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			info.NewCached("", "", "", "", "", "false", "", "")
+		}
+	})
+	b.Run("default", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			info.GetDefault()
+		}
+	})
+}
+
+type testFieldsParam struct {
+	info   *info.Info
+	expect map[string]any
+}
+
+var testFieldsParams = map[string]testFieldsParam{
+	"full info": {
+		info: &info.Info{
+			Path:     buildPath,
+			Version:  "v1.2.3",
+			Revision: revisionHead,
+			Build:    info.TimeRFC3339Parse("2023-12-10T18:30:00Z"),
+			Commit:   info.TimeRFC3339Parse("2023-12-10T18:00:00Z"),
+			Dirty:    true,
+			Go:       "1.23.0",
+			Platform: "linux/amd64",
+			Compiler: "gc",
+		},
+		expect: map[string]any{
+			"version":  "v1.2.3",
+			"revision": revisionHead,
+			"build":    info.TimeRFC3339Parse("2023-12-10T18:30:00Z"),
+			"commit":   info.TimeRFC3339Parse("2023-12-10T18:00:00Z"),
+			"dirty":    true,
+			"go":       "1.23.0",
+			"platform": "linux/amd64",
+		},
+	},
+	"settings info": {
+		info: withSettings(&info.Info{Version: "v1.2.3"}),
+		expect: map[string]any{
+			"version": "v1.2.3",
+			"cgo":     "0",
+			"tags":    "netgo,osusergo",
+			"goarm":   "7",
+			"goamd64": "v3",
+			"goflags": "-mod=vendor",
+		},
+	},
+	"sparse info": {
+		info: &info.Info{
+			Version: "v1.2.3",
+			Go:      "1.23.0",
+		},
+		expect: map[string]any{
+			"version": "v1.2.3",
+			"go":      "1.23.0",
+		},
+	},
+	"empty info": {
+		info:   &info.Info{},
+		expect: map[string]any{},
+	},
+}
+
+func TestFields(t *testing.T) {
+	test.Map(t, testFieldsParams).
+		Run(func(t test.Test, param testFieldsParam) {
+			// When
+			fields := param.info.Fields()
+
+			// Then
+			assert.Equal(t, param.expect, fields)
+		})
+}
+
+func TestLogStartup(t *testing.T) {
+	// Given
+	buffer := &bytes.Buffer{}
+	logger := logrus.New()
+	logger.SetOutput(buffer)
+	logger.SetFormatter(&logrus.JSONFormatter{DisableTimestamp: true})
+	info := &info.Info{Version: "v1.2.3", Dirty: true}
+
+	// When
+	info.LogStartup(logger)
+
+	// Then
+	assert.JSONEq(t, `{"level":"info","msg":"starting",`+
+		`"version":"v1.2.3","dirty":true}`, buffer.String())
+}
+
+func TestZeroLogStartup(t *testing.T) {
+	// Given
+	buffer := &bytes.Buffer{}
+	logger := zerolog.New(buffer)
+	info := &info.Info{Version: "v1.2.3", Dirty: true}
+
+	// When
+	info.ZeroLogStartup(&logger)
+
+	// Then
+	assert.JSONEq(t, `{"level":"info","message":"starting",`+
+		`"version":"v1.2.3","dirty":true}`, buffer.String())
+}
+
+// readGolden reads the golden file with the given name from the fixtures.
+func readGolden(t test.Test, name string) string {
+	golden, err := os.ReadFile(filepath.Join("fixtures", name))
+	require.NoError(t, err)
+	return string(golden)
+}
+
+// formatInfo is a fully populated build information.
+var formatInfo = &info.Info{
+	Path:     buildPath,
+	Repo:     "git@github.com:tkrop/go-config",
+	Version:  "v1.2.3",
+	Revision: revisionHead,
+	Build:    info.TimeRFC3339Parse("2023-12-10T18:30:00Z"),
+	Commit:   info.TimeRFC3339Parse("2023-12-10T18:00:00Z"),
+	Dirty:    true,
+	Checksum: "h1:abc=",
+	Go:       "1.23.5",
+	Platform: "linux/amd64",
+	Compiler: "gc",
+}
+
+type testFormatParam struct {
+	info        *info.Info
+	ctype       coding.Type
+	expect      string
+	expectError error
+}
+
+var testFormatParams = map[string]testFormatParam{
+	"json full": {
+		info:   formatInfo,
+		ctype:  info.FormatJSON,
+		expect: "info-full.json",
+	},
+	"yaml full": {
+		info:   formatInfo,
+		ctype:  info.FormatYAML,
+		expect: "info-full.yaml",
+	},
+	"yaml sparse": {
+		info:   &info.Info{Version: "v1.2.3", Go: "1.23.5"},
+		ctype:  info.FormatYAML,
+		expect: "info-sparse.yaml",
+	},
+	"yaml settings": {
+		info:   withSettings(&info.Info{Version: "v1.2.3", Go: "1.23.5"}),
+		ctype:  info.FormatYAML,
+		expect: "info-settings.yaml",
+	},
+	"unknown": {
+		info:  formatInfo,
+		ctype: coding.TypeUnkown,
+		expectError: coding.NewErrEncoding(formatInfo,
+			coding.NewErrCoding(coding.TypeUnkown)),
+	},
+}
+
+func TestFormat(t *testing.T) {
+	test.Map(t, testFormatParams).
+		Run(func(t test.Test, param testFormatParam) {
+			// When
+			str, err := param.info.Format(param.ctype)
+
+			// Then
+			assert.Equal(t, param.expectError, err)
+			if param.expect != "" {
+				assert.Equal(t, strings.TrimSuffix(
+					readGolden(t, param.expect), "\n"),
+					strings.TrimSuffix(str, "\n"))
+			} else {
+				assert.Empty(t, str)
+			}
+			if param.ctype == info.FormatJSON {
+				assert.Equal(t, str, param.info.String())
+			}
+		})
+}
+
+type testShortParam struct {
+	info   *info.Info
+	expect string
+}
+
+var testShortParams = map[string]testShortParam{
+	"full info": {
+		info: &info.Info{
+			Path:     "github.com/tkrop/myapp",
+			Version:  "v1.4.2",
+			Revision: revisionHead,
+			Build:    info.TimeRFC3339Parse("2024-10-01T18:30:00Z"),
+			Go:       "1.22.3",
+			Platform: "linux/amd64",
+		},
+		expect: "myapp v1.4.2 (rev 1b66f320c950, built 2024-10-01, " +
+			"go1.22.3 linux/amd64)",
+	},
+	"dirty info": {
+		info: &info.Info{
+			Path:     "github.com/tkrop/myapp",
+			Version:  "v1.4.2",
+			Revision: "1b66f320",
+			Dirty:    true,
+		},
+		expect: "myapp v1.4.2 (rev 1b66f320, dirty)",
+	},
+	"minimal info": {
+		info:   &info.Info{Version: "v1.4.2"},
+		expect: "v1.4.2",
+	},
+	"platform info": {
+		info:   &info.Info{Platform: "linux/amd64"},
+		expect: "(linux/amd64)",
+	},
+	"empty info": {
+		info:   &info.Info{},
+		expect: "",
+	},
+}
+
+func TestShort(t *testing.T) {
+	test.Map(t, testShortParams).
+		Run(func(t test.Test, param testShortParam) {
+			// When
+			short := param.info.Short()
+
+			// Then
+			assert.Equal(t, param.expect, short)
+		})
+}
+
+func TestPrintVersion(t *testing.T) {
+	// Given
+	defaultInfo := info.GetDefault()
+	defer info.SetDefault(defaultInfo)
+	info.SetDefault(&info.Info{Path: "github.com/tkrop/myapp", Version: "v1.4.2"})
+	buffer := &bytes.Buffer{}
+
+	// When
+	err := info.PrintVersion(buffer)
+
+	// Then
+	assert.NoError(t, err)
+	assert.Equal(t, "myapp v1.4.2\n", buffer.String())
+}
+
+// depsBuild is a synthetic debug build information with module dependencies.
+var depsBuild = &debug.BuildInfo{
+	Main: debug.Module{Path: buildPath},
+	Deps: []*debug.Module{{
+		Path: "github.com/rs/zerolog", Version: "v1.33.0", Sum: "h1:zero=",
+	}, {
+		Path: "github.com/sirupsen/logrus", Version: "v1.9.3",
+		Replace: &debug.Module{
+			Path: "github.com/fork/logrus", Version: "v1.9.4", Sum: "h1:fork=",
+		},
+	}, nil},
+}
+
+type testUseDebugDepsParam struct {
+	build      *debug.BuildInfo
+	ok         bool
+	expectDeps []info.Module
+}
+
+var testUseDebugDepsParams = map[string]testUseDebugDepsParam{
+	"build info deps": {
+		build: depsBuild,
+		ok:    true,
+		expectDeps: []info.Module{{
+			Path: "github.com/rs/zerolog", Version: "v1.33.0", Sum: "h1:zero=",
+		}, {
+			Path: "github.com/sirupsen/logrus", Version: "v1.9.3",
+			Replace: &info.Module{
+				Path: "github.com/fork/logrus", Version: "v1.9.4",
+				Sum: "h1:fork=",
+			},
+		}},
+	},
+	"build info no deps": {
+		build:      &debug.BuildInfo{},
+		ok:         true,
+		expectDeps: []info.Module{},
+	},
+	"build info missing": {
+		build: depsBuild,
+	},
+}
+
+func TestUseDebugDeps(t *testing.T) {
+	test.Map(t, testUseDebugDepsParams).
+		Run(func(t test.Test, param testUseDebugDepsParam) {
+			// Given
+			build := &info.Info{}
+
+			// When
+			build.UseDebugDeps(param.build, param.ok)
+
+			// Then
+			assert.Equal(t, param.expectDeps, build.Deps)
+		})
+}
+
+func TestDependency(t *testing.T) {
+	// Given
+	build := (&info.Info{}).UseDebugDeps(depsBuild, true)
+
+	// When
+	zerolog, zok := build.Dependency("github.com/rs/zerolog")
+	logrus, lok := build.Dependency("github.com/sirupsen/logrus")
+	missing, mok := build.Dependency("github.com/spf13/viper")
+
+	// Then
+	assert.True(t, zok)
+	assert.Equal(t, "v1.33.0", zerolog.Version)
+	assert.True(t, lok)
+	assert.Equal(t, "github.com/fork/logrus", logrus.Replace.Path)
+	assert.False(t, mok)
+	assert.Zero(t, missing)
+	assert.Contains(t, build.String(), `"deps":[{"path":"github.com/rs/zerolog"`)
+	assert.NotContains(t, (&info.Info{}).UseDebug(depsBuild, true).String(),
+		`"deps"`)
+}
+
+type testSemVerParam struct {
+	version          string
+	expectMajor      int
+	expectMinor      int
+	expectPatch      int
+	expectPrerelease string
+	expectMetadata   string
+	expectOk         bool
+	expectPre        bool
+	expectPseudo     bool
+}
+
+var testSemVerParams = map[string]testSemVerParam{
+	"release": {
+		version:     "v1.2.3",
+		expectMajor: 1, expectMinor: 2, expectPatch: 3,
+		expectOk: true,
+	},
+	"major release": {
+		version:     "v12.0.10",
+		expectMajor: 12, expectPatch: 10,
+		expectOk: true,
+	},
+	"prerelease": {
+		version:     "v1.2.3-alpha.1",
+		expectMajor: 1, expectMinor: 2, expectPatch: 3,
+		expectPrerelease: "alpha.1",
+		expectOk:         true,
+		expectPre:        true,
+	},
+	"build metadata": {
+		version:     "v1.2.3+build.5",
+		expectMajor: 1, expectMinor: 2, expectPatch: 3,
+		expectMetadata: "build.5",
+		expectOk:       true,
+	},
+	"prerelease build metadata": {
+		version:     "v1.2.3-rc.1+build.5",
+		expectMajor: 1, expectMinor: 2, expectPatch: 3,
+		expectPrerelease: "rc.1",
+		expectMetadata:   "build.5",
+		expectOk:         true,
+		expectPre:        true,
+	},
+	"pseudo version": {
+		version:          "v0.0.0-20231210183000-1b66f320c950",
+		expectPrerelease: "20231210183000-1b66f320c950",
+		expectOk:         true,
+		expectPre:        true,
+		expectPseudo:     true,
+	},
+	"pseudo version prerelease": {
+		version:     "v1.2.4-0.20231210183000-1b66f320c950",
+		expectMajor: 1, expectMinor: 2, expectPatch: 4,
+		expectPrerelease: "0.20231210183000-1b66f320c950",
+		expectOk:         true,
+		expectPre:        true,
+		expectPseudo:     true,
+	},
+	"pseudo version adjusted": {
+		version: info.New("", "", revisionHead, "",
+			"2023-12-10T18:30:00Z", "", "", "").Version,
+		expectPrerelease: "20231210183000-1b66f320c950",
+		expectOk:         true,
+		expectPre:        true,
+		expectPseudo:     true,
+	},
+	"garbage version": {
+		version: "latest",
+	},
+	"empty version": {},
+}
+
+func TestSemVer(t *testing.T) {
+	test.Map(t, testSemVerParams).
+		Run(func(t test.Test, param testSemVerParam) {
+			// Given
+			build := &info.Info{Version: param.version}
+
+			// When
+			major, minor, patch, prerelease, metadata, ok := build.SemVer()
+
+			// Then
+			assert.Equal(t, param.expectMajor, major)
+			assert.Equal(t, param.expectMinor, minor)
+			assert.Equal(t, param.expectPatch, patch)
+			assert.Equal(t, param.expectPrerelease, prerelease)
+			assert.Equal(t, param.expectMetadata, metadata)
+			assert.Equal(t, param.expectOk, ok)
+			assert.Equal(t, param.expectPre, build.IsPrerelease())
+			assert.Equal(t, param.expectPseudo, build.IsPseudoVersion())
+		})
+}
+
+// ageNow is the fixed time used to determine the build age.
+var ageNow = info.TimeRFC3339Parse("2024-10-31T12:00:00Z")
+
+type testBuildAgeParam struct {
+	info     *info.Info
+	expect   time.Duration
+	expectOk bool
+}
+
+var testBuildAgeParams = map[string]testBuildAgeParam{
+	"fresh build": {
+		info: &info.Info{
+			Build:  ageNow.Add(-time.Hour),
+			Commit: ageNow.Add(-48 * time.Hour),
+		},
+		expect:   time.Hour,
+		expectOk: true,
+	},
+	"stale build": {
+		info:     &info.Info{Build: ageNow.Add(-60 * 24 * time.Hour)},
+		expect:   60 * 24 * time.Hour,
+		expectOk: true,
+	},
+	"stale commit": {
+		info:     &info.Info{Commit: ageNow.Add(-31 * 24 * time.Hour)},
+		expect:   31 * 24 * time.Hour,
+		expectOk: true,
+	},
+	"unknown build": {
+		info: &info.Info{Version: "v1.2.3"},
+	},
+}
+
+func TestBuildAge(t *testing.T) {
+	test.Map(t, testBuildAgeParams).
+		Run(func(t test.Test, param testBuildAgeParam) {
+			// When
+			age, ok := param.info.BuildAge(ageNow)
+
+			// Then
+			assert.Equal(t, param.expect, age)
+			assert.Equal(t, param.expectOk, ok)
+		})
+}
+
+type testWarnIfOlderThanParam struct {
+	info   *info.Info
+	expect string
+}
+
+var testWarnIfOlderThanParams = map[string]testWarnIfOlderThanParam{
+	"fresh build": {
+		info: &info.Info{
+			Version: "v1.2.3", Build: time.Now().Add(-time.Hour),
+		},
+	},
+	"stale build": {
+		info: &info.Info{
+			Version: "v1.2.3", Build: time.Now().Add(-60 * 24 * time.Hour),
+		},
+		expect: `"age":"1440h0m0s","version":"v1.2.3"`,
+	},
+	"unknown build": {
+		info: &info.Info{Version: "v1.2.3"},
+	},
+}
+
+func TestWarnIfOlderThan(t *testing.T) {
+	test.Map(t, testWarnIfOlderThanParams).
+		Run(func(t test.Test, param testWarnIfOlderThanParam) {
+			// Given
+			rus, zero := &bytes.Buffer{}, &bytes.Buffer{}
+			rlogger := logrus.New()
+			rlogger.SetOutput(rus)
+			rlogger.SetFormatter(&logrus.JSONFormatter{DisableTimestamp: true})
+			zlogger := zerolog.New(zero)
+
+			// When
+			rwarn := param.info.WarnIfOlderThan(30*24*time.Hour, rlogger)
+			zwarn := param.info.ZeroWarnIfOlderThan(30*24*time.Hour, &zlogger)
+
+			// Then
+			assert.Equal(t, param.expect != "", rwarn)
+			assert.Equal(t, param.expect != "", zwarn)
+			if param.expect == "" {
+				assert.Empty(t, rus.String())
+				assert.Empty(t, zero.String())
+				return
+			}
+			assert.JSONEq(t, `{"level":"warning","msg":"build outdated",`+
+				param.expect+`}`, rus.String())
+			assert.JSONEq(t, `{"level":"warn","message":"build outdated",`+
+				param.expect+`}`, zero.String())
+		})
+}
@@ -0,0 +1,55 @@
+// Package ldflags provides exported build information variables to be set
+// via `-ldflags="-X ..."` during the build, e.g.:
+//
+//	-X github.com/tkrop/go-config/info/ldflags.Path=...
+//	-X github.com/tkrop/go-config/info/ldflags.Version=...
+//	-X github.com/tkrop/go-config/info/ldflags.Revision=...
+//	-X github.com/tkrop/go-config/info/ldflags.Build=...
+//	-X github.com/tkrop/go-config/info/ldflags.Commit=...
+//	-X github.com/tkrop/go-config/info/ldflags.Dirty=...
+//	-X github.com/tkrop/go-config/info/ldflags.Branch=...
+//	-X github.com/tkrop/go-config/info/ldflags.Tag=...
+//
+// Importing the package sets up the default build information from the
+// variables, so that applications do not need to declare them on their own.
+package ldflags
+
+import "github.com/tkrop/go-config/info"
+
+// Build information variables set via `-ldflags="-X ..."`.
+var (
+	// Path contains the package path.
+	Path string
+	// Version contains the custom version.
+	Version string
+	// Revision contains the custom revision.
+	Revision string
+	// Build contains the custom build time.
+	Build string
+	// Commit contains the custom commit time.
+	Commit string
+	// Dirty contains the custom dirty flag.
+	Dirty string // Bool not supported by ldflags `-X`.
+	// Branch contains the custom branch.
+	Branch string
+	// Tag contains the custom exact tag.
+	Tag string
+)
+
+// init sets up the default build information from the variables.
+func init() {
+	Setup()
+}
+
+// Setup sets up the default build information from the build information
+// variables. If none of the variables is set, the default build information
+// derived from the debug build information is kept unchanged.
+func Setup() {
+	if Path == "" && Version == "" && Revision == "" && Build == "" &&
+		Commit == "" && Dirty == "" && Branch == "" && Tag == "" {
+		return
+	}
+
+	info.SetDefault(info.NewCached(Path, Version, Revision, Build, Commit, Dirty,
+		Branch, Tag))
+}
@@ -0,0 +1,88 @@
+package ldflags_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tkrop/go-testing/test"
+
+	"github.com/tkrop/go-config/info"
+	"github.com/tkrop/go-config/info/ldflags"
+)
+
+// setVars sets the build information variables to the given values and
+// restores the variables and the default build information after the test.
+func setVars(t test.Test, vars [8]string) {
+	refs := []*string{
+		&ldflags.Path, &ldflags.Version, &ldflags.Revision, &ldflags.Build,
+		&ldflags.Commit, &ldflags.Dirty, &ldflags.Branch, &ldflags.Tag,
+	}
+
+	orig, prev := info.GetDefault(), [8]string{}
+	for index, ref := range refs {
+		prev[index], *ref = *ref, vars[index]
+	}
+	t.Cleanup(func() {
+		for index, ref := range refs {
+			*ref = prev[index]
+		}
+		info.SetDefault(orig)
+	})
+}
+
+type testSetupParam struct {
+	vars   [8]string
+	expect func(orig *info.Info) *info.Info
+}
+
+var testSetupParams = map[string]testSetupParam{
+	"empty": {
+		expect: func(orig *info.Info) *info.Info {
+			return orig
+		},
+	},
+	"version only": {
+		vars: [8]string{"", "v1.2.3"},
+		expect: func(orig *info.Info) *info.Info {
+			build := *info.New("", "", "", "", "", "", "", "")
+			build.Version = "v1.2.3"
+			return &build
+		},
+	},
+	"all": {
+		vars: [8]string{
+			"github.com/tkrop/go-config", "v1.2.3", "1b66f320c950",
+			"2024-10-01T12:00:00Z", "2024-09-30T10:00:00Z", "true",
+			"main", "v1.2.3",
+		},
+		expect: func(*info.Info) *info.Info {
+			build := *info.New("", "", "", "", "", "", "", "")
+			build.Path = "github.com/tkrop/go-config"
+			build.Repo = "git@github.com:tkrop/go-config"
+			build.Version = "v1.2.3"
+			build.Revision = "1b66f320c950"
+			build.Branch = "main"
+			build.Tag = "v1.2.3"
+			build.Build = time.Date(2024, 10, 1, 12, 0, 0, 0, time.UTC)
+			build.Commit = time.Date(2024, 9, 30, 10, 0, 0, 0, time.UTC)
+			build.Dirty = true
+			return &build
+		},
+	},
+}
+
+func TestSetup(t *testing.T) {
+	test.Map(t, testSetupParams).
+		RunSeq(func(t test.Test, param testSetupParam) {
+			// Given
+			setVars(t, param.vars)
+			orig := info.GetDefault()
+
+			// When
+			ldflags.Setup()
+
+			// Then
+			assert.Equal(t, param.expect(orig), info.GetDefault())
+		})
+}
@@ -0,0 +1,84 @@
+// Package metrics provides the build information of a command or module as
+// Prometheus `build_info` metric. The package is kept separate to avoid
+// pulling in the Prometheus dependency for users of the info package.
+package metrics
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/tkrop/go-config/info"
+)
+
+// MetricBuildInfo is the name of the build info metric.
+const MetricBuildInfo = "build_info"
+
+// Labels of the build info metric.
+const (
+	// LabelVersion is the label of the version.
+	LabelVersion = "version"
+	// LabelRevision is the label of the revision.
+	LabelRevision = "revision"
+	// LabelGoVersion is the label of the go version.
+	LabelGoVersion = "goversion"
+	// LabelPlatform is the label of the build platform.
+	LabelPlatform = "platform"
+	// LabelDirty is the label of the dirty flag.
+	LabelDirty = "dirty"
+)
+
+// collector is the collector of the build info metric.
+type collector struct {
+	// desc is the description of the build info metric.
+	desc *prometheus.Desc
+	// labels are the label values of the build info metric.
+	labels []string
+}
+
+// Collector creates a Prometheus collector providing the given build
+// information as `<namespace>_build_info` gauge with value 1 and the labels
+// `version`, `revision`, `goversion`, `platform`, and `dirty`.
+func Collector(namespace string, info *info.Info) prometheus.Collector {
+	return &collector{
+		desc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", MetricBuildInfo),
+			"A metric with a constant '1' value labeled by version, "+
+				"revision, goversion, platform, and dirty flag from which "+
+				"the command or module was built.",
+			[]string{
+				LabelVersion, LabelRevision, LabelGoVersion,
+				LabelPlatform, LabelDirty,
+			}, nil),
+		labels: []string{
+			info.Version, info.Revision, info.Go,
+			info.Platform, strconv.FormatBool(info.Dirty),
+		},
+	}
+}
+
+// Describe sends the description of the build info metric.
+func (c *collector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.desc
+}
+
+// Collect sends the build info metric.
+func (c *collector) Collect(ch chan<- prometheus.Metric) {
+	ch <- prometheus.MustNewConstMetric(c.desc,
+		prometheus.GaugeValue, 1, c.labels...)
+}
+
+// Register registers the build info collector for the given build
+// information with the given registerer. If the build info collector is
+// already registered, the registration is ignored, i.e. duplicate
+// registration attempts are safe.
+func Register(
+	registerer prometheus.Registerer, namespace string, info *info.Info,
+) error {
+	err := registerer.Register(Collector(namespace, info))
+	if are := (prometheus.AlreadyRegisteredError{}); errors.As(err, &are) {
+		return nil
+	}
+	return err
+}
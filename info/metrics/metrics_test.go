@@ -0,0 +1,100 @@
+package metrics_test
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tkrop/go-testing/test"
+
+	"github.com/tkrop/go-config/info"
+	"github.com/tkrop/go-config/info/metrics"
+)
+
+// labels returns the label values of the given metric by label name.
+func labels(metric *dto.Metric) map[string]string {
+	labels := map[string]string{}
+	for _, label := range metric.GetLabel() {
+		labels[label.GetName()] = label.GetValue()
+	}
+	return labels
+}
+
+type testCollectorParam struct {
+	namespace    string
+	info         *info.Info
+	expectName   string
+	expectLabels map[string]string
+}
+
+var testCollectorParams = map[string]testCollectorParam{
+	"full info": {
+		namespace: "service",
+		info: &info.Info{
+			Version:  "v1.2.3",
+			Revision: "1b66f320c950",
+			Go:       "1.23.5",
+			Platform: "linux/amd64",
+			Dirty:    true,
+		},
+		expectName: "service_build_info",
+		expectLabels: map[string]string{
+			"version": "v1.2.3", "revision": "1b66f320c950",
+			"goversion": "1.23.5", "platform": "linux/amd64",
+			"dirty": "true",
+		},
+	},
+	"empty info": {
+		info:       &info.Info{},
+		expectName: "build_info",
+		expectLabels: map[string]string{
+			"version": "", "revision": "", "goversion": "",
+			"platform": "", "dirty": "false",
+		},
+	},
+}
+
+func TestCollector(t *testing.T) {
+	test.Map(t, testCollectorParams).
+		Run(func(t test.Test, param testCollectorParam) {
+			// Given
+			registry := prometheus.NewPedanticRegistry()
+
+			// When
+			require.NoError(t, metrics.Register(registry,
+				param.namespace, param.info))
+			require.NoError(t, metrics.Register(registry,
+				param.namespace, param.info))
+
+			// Then
+			families, err := registry.Gather()
+			require.NoError(t, err)
+			require.Len(t, families, 1)
+			assert.Equal(t, param.expectName, families[0].GetName())
+			require.Len(t, families[0].GetMetric(), 1)
+			metric := families[0].GetMetric()[0]
+			assert.Equal(t, 1.0, metric.GetGauge().GetValue())
+			assert.Equal(t, param.expectLabels, labels(metric))
+		})
+}
+
+func TestRegisterOnce(t *testing.T) {
+	// Given
+	registry := prometheus.NewRegistry()
+	require.NoError(t, metrics.Register(registry, "service",
+		&info.Info{Version: "v1.2.3"}))
+
+	// When
+	err := metrics.Register(registry, "service",
+		&info.Info{Version: "v1.2.4"})
+
+	// Then
+	require.NoError(t, err)
+	families, err := registry.Gather()
+	require.NoError(t, err)
+	require.Len(t, families, 1)
+	require.Len(t, families[0].GetMetric(), 1)
+	assert.Equal(t, "v1.2.3", labels(families[0].GetMetric()[0])["version"])
+}
@@ -0,0 +1,67 @@
+package info
+
+import (
+	"os"
+	"os/user"
+	"runtime"
+	"time"
+)
+
+// startTime is the start time of the process.
+var startTime = time.Now()
+
+// Runtime provides the runtime environment of the process of a command or
+// module, e.g. for post-mortems.
+type Runtime struct {
+	// Hostname contains the hostname the process is running on.
+	Hostname string `yaml:"hostname,omitempty" json:"hostname,omitempty"`
+	// PID contains the process id of the process.
+	PID int `yaml:"pid,omitempty" json:"pid,omitempty"`
+	// User contains the name of the user the process is running as.
+	User string `yaml:"user,omitempty" json:"user,omitempty"`
+	// NumCPU contains the number of logical CPUs usable by the process.
+	NumCPU int `yaml:"numcpu,omitempty" json:"numcpu,omitempty"`
+	// Start contains the start time of the process.
+	Start time.Time `yaml:"start,omitempty" json:"start,omitempty"`
+}
+
+// NewRuntime returns the runtime environment of the process of a command or
+// module using the given functions to look up the hostname and the user name
+// falling back to `os.Hostname` and `user.Current`. If a lookup fails, the
+// attribute is omitted.
+func NewRuntime(hostname, username func() (string, error)) *Runtime {
+	if hostname == nil {
+		hostname = os.Hostname
+	}
+	if username == nil {
+		username = currentUser
+	}
+
+	host, _ := hostname()
+	name, _ := username()
+	return &Runtime{
+		Hostname: host,
+		PID:      os.Getpid(),
+		User:     name,
+		NumCPU:   runtime.NumCPU(),
+		Start:    startTime,
+	}
+}
+
+// currentUser returns the name of the user the process is running as.
+func currentUser() (string, error) {
+	current, err := user.Current()
+	if err != nil {
+		return "", err
+	}
+	return current.Username, nil
+}
+
+// WithRuntime extends the build information of a command or module with the
+// runtime environment of the process, i.e. hostname, process id, user,
+// number of CPUs, and start time. The runtime environment is only included
+// in the output if populated.
+func (info *Info) WithRuntime() *Info {
+	info.Runtime = NewRuntime(nil, nil)
+	return info
+}
@@ -0,0 +1,91 @@
+package info_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tkrop/go-testing/test"
+
+	"github.com/tkrop/go-config/info"
+)
+
+// errLookup is the error returned by failing lookups.
+var errLookup = errors.New("lookup")
+
+// lookup returns a lookup function returning the given value and error.
+func lookup(value string, err error) func() (string, error) {
+	return func() (string, error) { return value, err }
+}
+
+type testRuntimeParam struct {
+	hostname     func() (string, error)
+	username     func() (string, error)
+	expectHost   string
+	expectUser   string
+	expectFields []string
+}
+
+var testRuntimeParams = map[string]testRuntimeParam{
+	"injected": {
+		hostname:   lookup("replica-1", nil),
+		username:   lookup("service", nil),
+		expectHost: "replica-1",
+		expectUser: "service",
+		expectFields: []string{
+			"version", "hostname", "pid", "user", "numcpu", "start",
+		},
+	},
+	"failing lookups": {
+		hostname:     lookup("", errLookup),
+		username:     lookup("", errLookup),
+		expectFields: []string{"version", "pid", "numcpu", "start"},
+	},
+}
+
+func TestRuntime(t *testing.T) {
+	test.Map(t, testRuntimeParams).
+		Run(func(t test.Test, param testRuntimeParam) {
+			// Given
+			build := &info.Info{Version: "v1.2.3"}
+
+			// When
+			build.Runtime = info.NewRuntime(param.hostname, param.username)
+
+			// Then
+			assert.Equal(t, param.expectHost, build.Runtime.Hostname)
+			assert.Equal(t, param.expectUser, build.Runtime.User)
+			assert.Equal(t, os.Getpid(), build.Runtime.PID)
+			assert.Equal(t, runtime.NumCPU(), build.Runtime.NumCPU)
+			assert.False(t, build.Runtime.Start.IsZero())
+			fields := build.Fields()
+			assert.Len(t, fields, len(param.expectFields))
+			for _, key := range param.expectFields {
+				assert.Contains(t, fields, key)
+			}
+		})
+}
+
+func TestWithRuntime(t *testing.T) {
+	// Given
+	hostname, err := os.Hostname()
+	require.NoError(t, err)
+	build := &info.Info{Version: "v1.2.3"}
+	assert.NotContains(t, build.String(), `"runtime"`)
+
+	// When
+	build.WithRuntime()
+
+	// Then
+	assert.Equal(t, hostname, build.Runtime.Hostname)
+	assert.Contains(t, build.String(), `"runtime":{"hostname":"`+hostname)
+	recorder := httptest.NewRecorder()
+	info.Handler(build).ServeHTTP(recorder,
+		httptest.NewRequest(http.MethodGet, "/info", nil))
+	assert.Equal(t, build.String(), recorder.Body.String())
+}
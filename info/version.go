@@ -0,0 +1,149 @@
+package info
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ErrVersion is a common error to indicate an invalid semantic version.
+var ErrVersion = errors.New("invalid version")
+
+// NewErrVersion creates a new error to indicate that the given version does
+// not follow semantic versioning as supported by go.
+func NewErrVersion(version string) error {
+	return fmt.Errorf("%w [%s]", ErrVersion, version)
+}
+
+// semVer is a parsed semantic version.
+type semVer struct {
+	// core contains the major, minor, and patch version.
+	core [3]int
+	// prerelease contains the prerelease identifiers.
+	prerelease []string
+	// pseudo contains the commit timestamp of pseudo versions.
+	pseudo string
+}
+
+// parseSemVer parses the given semantic version. If the version does not
+// follow semantic versioning as supported by go, `false` is returned.
+func parseSemVer(version string) (*semVer, bool) {
+	info := &Info{Version: version}
+	major, minor, patch, prerelease, _, ok := info.SemVer()
+	if !ok {
+		return nil, false
+	}
+
+	parsed := &semVer{core: [3]int{major, minor, patch}}
+	if prerelease != "" {
+		parsed.prerelease = strings.Split(prerelease, ".")
+	}
+	if info.IsPseudoVersion() {
+		match := pseudoVersionRegex.FindString(prerelease)
+		parsed.pseudo = strings.TrimPrefix(match, ".")[0:14]
+	}
+	return parsed, true
+}
+
+// CompareVersions compares the given semantic versions following the
+// semantic versioning precedence rules, i.e. numeric identifiers are compared
+// numerically, prereleases have lower precedence than releases, and build
+// metadata is ignored. Pseudo versions of the same version are compared by
+// their commit timestamps. The result is `-1` if `a` precedes `b`, `1` if `b`
+// precedes `a`, and `0` if both have the same precedence. Invalid versions
+// precede all valid versions and are compared lexically.
+func CompareVersions(a, b string) int {
+	result, _ := CompareVersionsStrict(a, b)
+	return result
+}
+
+// CompareVersionsStrict compares the given semantic versions the same way as
+// `CompareVersions`, but additionally returns an error flagging invalid
+// versions.
+func CompareVersionsStrict(a, b string) (int, error) {
+	va, aok := parseSemVer(a)
+	vb, bok := parseSemVer(b)
+
+	errs := []error{}
+	if !aok {
+		errs = append(errs, NewErrVersion(a))
+	}
+	if !bok {
+		errs = append(errs, NewErrVersion(b))
+	}
+
+	switch {
+	case !aok && !bok:
+		return strings.Compare(a, b), errors.Join(errs...)
+	case !aok:
+		return -1, errors.Join(errs...)
+	case !bok:
+		return 1, errors.Join(errs...)
+	}
+	return va.compare(vb), nil
+}
+
+// compare compares the semantic version with the given semantic version.
+func (v *semVer) compare(o *semVer) int {
+	for index := range v.core {
+		if result := cmp.Compare(v.core[index], o.core[index]); result != 0 {
+			return result
+		}
+	}
+
+	if v.pseudo != "" && o.pseudo != "" {
+		if result := strings.Compare(v.pseudo, o.pseudo); result != 0 {
+			return result
+		}
+	}
+	return comparePrerelease(v.prerelease, o.prerelease)
+}
+
+// comparePrerelease compares the given prerelease identifiers following the
+// semantic versioning precedence rules.
+func comparePrerelease(a, b []string) int {
+	switch {
+	case len(a) == 0 && len(b) == 0:
+		return 0
+	case len(a) == 0:
+		return 1
+	case len(b) == 0:
+		return -1
+	}
+
+	for index := 0; index < len(a) && index < len(b); index++ {
+		if result := compareIdentifier(a[index], b[index]); result != 0 {
+			return result
+		}
+	}
+	return cmp.Compare(len(a), len(b))
+}
+
+// compareIdentifier compares the given prerelease identifiers, i.e. numeric
+// identifiers are compared numerically and have lower precedence than
+// alphanumeric identifiers, that are compared lexically.
+func compareIdentifier(a, b string) int {
+	na, aerr := strconv.ParseUint(a, 10, 64)
+	nb, berr := strconv.ParseUint(b, 10, 64)
+	switch {
+	case aerr == nil && berr == nil:
+		return cmp.Compare(na, nb)
+	case aerr == nil:
+		return -1
+	case berr == nil:
+		return 1
+	}
+	return strings.Compare(a, b)
+}
+
+// AtLeast returns whether the version of the build information of a command
+// or module has at least the precedence of the given semantic version. If
+// the version of the build information is invalid, `false` is returned.
+func (info *Info) AtLeast(version string) bool {
+	if _, ok := parseSemVer(info.Version); !ok {
+		return false
+	}
+	return CompareVersions(info.Version, version) >= 0
+}
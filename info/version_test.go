@@ -0,0 +1,236 @@
+package info_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tkrop/go-testing/test"
+
+	"github.com/tkrop/go-config/info"
+)
+
+// semverPrecedence are the versions of the semantic versioning spec examples
+// in ascending order of precedence.
+var semverPrecedence = []string{
+	"v1.0.0-alpha", "v1.0.0-alpha.1", "v1.0.0-alpha.beta", "v1.0.0-beta",
+	"v1.0.0-beta.2", "v1.0.0-beta.11", "v1.0.0-rc.1", "v1.0.0",
+	"v2.0.0", "v2.1.0", "v2.1.1",
+}
+
+func TestCompareVersionsPrecedence(t *testing.T) {
+	for i, a := range semverPrecedence {
+		for j, b := range semverPrecedence {
+			// When
+			result, err := info.CompareVersionsStrict(a, b)
+
+			// Then
+			assert.NoError(t, err)
+			assert.Equal(t, compare(i, j), result, "%s <=> %s", a, b)
+		}
+	}
+}
+
+// compare returns the expected comparison result of the given indexes.
+func compare(i, j int) int {
+	switch {
+	case i < j:
+		return -1
+	case i > j:
+		return 1
+	}
+	return 0
+}
+
+type testCompareVersionsParam struct {
+	a, b        string
+	expect      int
+	expectError error
+}
+
+var testCompareVersionsParams = map[string]testCompareVersionsParam{
+	"equal": {
+		a: "v1.2.3", b: "v1.2.3", expect: 0,
+	},
+	"numeric major": {
+		a: "v2.0.0", b: "v10.0.0", expect: -1,
+	},
+	"numeric minor": {
+		a: "v1.10.0", b: "v1.9.0", expect: 1,
+	},
+	"numeric patch": {
+		a: "v1.0.9", b: "v1.0.10", expect: -1,
+	},
+	"metadata ignored": {
+		a: "v1.0.0+build.1", b: "v1.0.0+build.2", expect: 0,
+	},
+	"metadata prerelease": {
+		a: "v1.0.0-rc.1+build.1", b: "v1.0.0", expect: -1,
+	},
+	"numeric before alphanumeric": {
+		a: "v1.0.0-1", b: "v1.0.0-alpha", expect: -1,
+	},
+	"pseudo timestamps": {
+		a:      "v0.0.0-20231210183000-ffffffffffff",
+		b:      "v0.0.0-20240101000000-000000000000",
+		expect: -1,
+	},
+	"pseudo prerelease timestamps": {
+		a:      "v1.2.4-0.20240101000000-000000000000",
+		b:      "v1.2.4-0.20231210183000-ffffffffffff",
+		expect: 1,
+	},
+	"pseudo before release": {
+		a:      "v1.2.4-0.20240101000000-000000000000",
+		b:      "v1.2.4",
+		expect: -1,
+	},
+	"pseudo after previous release": {
+		a:      "v1.2.4-0.20240101000000-000000000000",
+		b:      "v1.2.3",
+		expect: 1,
+	},
+	"invalid before valid": {
+		a: "latest", b: "v0.0.1", expect: -1,
+		expectError: errors.Join(info.NewErrVersion("latest")),
+	},
+	"valid after invalid": {
+		a: "v0.0.1", b: "", expect: 1,
+		expectError: errors.Join(info.NewErrVersion("")),
+	},
+	"invalid lexically": {
+		a: "latest", b: "main", expect: -1,
+		expectError: errors.Join(info.NewErrVersion("latest"),
+			info.NewErrVersion("main")),
+	},
+}
+
+func TestCompareVersions(t *testing.T) {
+	test.Map(t, testCompareVersionsParams).
+		Run(func(t test.Test, param testCompareVersionsParam) {
+			// When
+			result, err := info.CompareVersionsStrict(param.a, param.b)
+			inverse := info.CompareVersions(param.b, param.a)
+
+			// Then
+			assert.Equal(t, param.expect, result)
+			assert.Equal(t, param.expectError, err)
+			assert.Equal(t, -param.expect, inverse)
+		})
+}
+
+type testAtLeastParam struct {
+	version string
+	minimum string
+	expect  bool
+}
+
+var testAtLeastParams = map[string]testAtLeastParam{
+	"newer": {
+		version: "v1.4.2", minimum: "v1.4.0", expect: true,
+	},
+	"equal": {
+		version: "v1.4.2", minimum: "v1.4.2", expect: true,
+	},
+	"older": {
+		version: "v1.4.2", minimum: "v2.0.0",
+	},
+	"prerelease older": {
+		version: "v2.0.0-rc.1", minimum: "v2.0.0",
+	},
+	"invalid version": {
+		version: "latest", minimum: "v0.0.0",
+	},
+	"invalid minimum": {
+		version: "v0.0.0", minimum: "latest", expect: true,
+	},
+}
+
+func TestAtLeast(t *testing.T) {
+	test.Map(t, testAtLeastParams).
+		Run(func(t test.Test, param testAtLeastParam) {
+			// Given
+			build := &info.Info{Version: param.version}
+
+			// When
+			result := build.AtLeast(param.minimum)
+
+			// Then
+			assert.Equal(t, param.expect, result)
+		})
+}
+
+type testReleaseParam struct {
+	info   *info.Info
+	expect error
+}
+
+var testReleaseParams = map[string]testReleaseParam{
+	"tagged clean": {
+		info: &info.Info{Version: "v1.2.3", Revision: "1b66f320c950"},
+	},
+	"tagged clean metadata": {
+		info: &info.Info{Version: "v1.2.3+build.1", Revision: "1b66f320c950"},
+	},
+	"tagged dirty": {
+		info: &info.Info{
+			Version: "v1.2.3", Revision: "1b66f320c950", Dirty: true,
+		},
+		expect: errors.Join(info.NewErrRelease("dirty build", "v1.2.3")),
+	},
+	"tagged no revision": {
+		info:   &info.Info{Version: "v1.2.3"},
+		expect: errors.Join(info.NewErrRelease("missing revision", "v1.2.3")),
+	},
+	"pseudo version": {
+		info: &info.Info{
+			Version:  "v0.0.0-20241001120000-1b66f320c950",
+			Revision: "1b66f320c950",
+		},
+		expect: errors.Join(info.NewErrRelease("pseudo version",
+			"v0.0.0-20241001120000-1b66f320c950")),
+	},
+	"pseudo version dirty": {
+		info: &info.Info{
+			Version:  "v0.0.0-20241001120000-1b66f320c950",
+			Revision: "1b66f320c950",
+			Dirty:    true,
+		},
+		expect: errors.Join(info.NewErrRelease("pseudo version",
+			"v0.0.0-20241001120000-1b66f320c950"),
+			info.NewErrRelease("dirty build",
+				"v0.0.0-20241001120000-1b66f320c950")),
+	},
+	"prerelease": {
+		info:   &info.Info{Version: "v1.2.3-rc.1", Revision: "1b66f320c950"},
+		expect: errors.Join(info.NewErrRelease("prerelease version", "v1.2.3-rc.1")),
+	},
+	"prerelease dirty": {
+		info: &info.Info{
+			Version: "v1.2.3-rc.1", Revision: "1b66f320c950", Dirty: true,
+		},
+		expect: errors.Join(info.NewErrRelease("prerelease version", "v1.2.3-rc.1"),
+			info.NewErrRelease("dirty build", "v1.2.3-rc.1")),
+	},
+	"invalid version": {
+		info:   &info.Info{Version: "latest", Revision: "1b66f320c950"},
+		expect: errors.Join(info.NewErrRelease("invalid version", "latest")),
+	},
+	"empty": {
+		info: &info.Info{},
+		expect: errors.Join(info.NewErrRelease("invalid version", ""),
+			info.NewErrRelease("missing revision", "")),
+	},
+}
+
+func TestRelease(t *testing.T) {
+	test.Map(t, testReleaseParams).
+		Run(func(t test.Test, param testReleaseParam) {
+			// When
+			err := param.info.ReleaseCheck()
+
+			// Then
+			assert.Equal(t, param.expect, err)
+			assert.Equal(t, param.expect == nil, param.info.IsRelease())
+		})
+}
@@ -46,23 +46,33 @@ func FromString(ctype Type, s string, obj any) any {
 // the encoding fails or the encoder is unknown, no error is returned, but a
 // failure report encoded in a byte slice is returned for analysis.
 func ToBytes(ctype Type, obj any) []byte {
+	b, err := Marshal(ctype, obj)
+	if err != nil {
+		return []byte(err.Error())
+	}
+	return b
+}
+
+// Marshal encodes any object to a byte slice using the requested encoder. If
+// the encoding fails or the encoder is unknown, the error is returned.
+func Marshal(ctype Type, obj any) ([]byte, error) {
 	switch ctype {
 	case TypeJSON:
 		if b, err := json.Marshal(obj); err != nil {
-			return []byte(NewErrEncoding(obj, err).Error())
+			return nil, NewErrEncoding(obj, err)
 		} else {
-			return b
+			return b, nil
 		}
 	case TypeYAML:
 		if b, err := yaml.Marshal(obj); err != nil {
-			return []byte(NewErrEncoding(obj, err).Error())
+			return nil, NewErrEncoding(obj, err)
 		} else {
-			return b
+			return b, nil
 		}
 	case TypeUnkown:
 		fallthrough
 	default:
-		return []byte(NewErrEncoding(obj, NewErrCoding(ctype)).Error())
+		return nil, NewErrEncoding(obj, NewErrCoding(ctype))
 	}
 }
 
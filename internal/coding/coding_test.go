@@ -199,3 +199,51 @@ func TestToFromString(t *testing.T) {
 			}
 		})
 }
+
+type MarshalParams struct {
+	from        any
+	ctype       coding.Type
+	expect      string
+	expectError error
+}
+
+var testMarshalParams = map[string]MarshalParams{
+	"unknown object": {
+		ctype: coding.TypeUnkown,
+		from:  &object{},
+		expectError: coding.NewErrEncoding(&object{},
+			coding.NewErrCoding(coding.TypeUnkown)),
+	},
+	"json full object": {
+		ctype:  coding.TypeJSON,
+		from:   &object{Type: "object", Value: "string", Flag: true},
+		expect: `{"type":"object","value":"string","flag":true}`,
+	},
+	"json encode failure": {
+		ctype:       coding.TypeJSON,
+		from:        &object{Type: "encode"},
+		expectError: coding.NewErrEncoding(&object{}, errMarshalJSON),
+	},
+	"yaml full object": {
+		ctype:  coding.TypeYAML,
+		from:   &object{Type: "object", Value: "string", Flag: true},
+		expect: "type: object\nvalue: string\nflag: true\n",
+	},
+	"yaml encode failure": {
+		ctype:       coding.TypeYAML,
+		from:        &object{Type: "encode"},
+		expectError: coding.NewErrEncoding(&object{}, errMarshalYAML),
+	},
+}
+
+func TestMarshal(t *testing.T) {
+	test.Map(t, testMarshalParams).
+		Run(func(t test.Test, param MarshalParams) {
+			// When
+			bytes, err := coding.Marshal(param.ctype, param.from)
+
+			// Then
+			assert.Equal(t, param.expectError, err)
+			assert.Equal(t, param.expect, string(bytes))
+		})
+}
@@ -2,17 +2,68 @@
 package reflect
 
 import (
+	"encoding"
+	"errors"
+	"fmt"
 	"reflect"
 	"slices"
 	"strconv"
 	"strings"
 )
 
+// ErrStopWalk is a sentinel error returned by the walk function to stop the
+// walk immediately.
+var ErrStopWalk = errors.New("stop walk")
+
+// TemplateKey is the key used in template paths for map entries.
+const TemplateKey = "*"
+
+// DefaultMaxDepth is the default maximum depth of nested values the tag
+// walker descends into.
+const DefaultMaxDepth = 32
+
+// ErrTagWalker is a common error to indicate a tag walker error.
+var ErrTagWalker = errors.New("tag walker")
+
+// NewErrTagWalker creates a new tag walker error for the given path wrapping
+// the original error.
+func NewErrTagWalker(path string, err error) error {
+	return fmt.Errorf("%w [%s]: %w", ErrTagWalker, path, err)
+}
+
+// ErrMaxDepth is a common error to indicate that the maximum depth of nested
+// values is exceeded.
+var ErrMaxDepth = errors.New("max depth exceeded")
+
+// NewErrMaxDepth creates a new error to indicate that the given maximum depth
+// of nested values is exceeded.
+func NewErrMaxDepth(depth int) error {
+	return fmt.Errorf("%w [%d]", ErrMaxDepth, depth)
+}
+
 // TagWalker provides a way to walk through a struct and apply a function to
 // each field that is settable.
 type TagWalker struct {
 	dtag, mtag string
 	zero       bool
+	// tags contains the tag names used for naming fields in priority order.
+	tags []string
+	// call contains the function called by `Walk` if no function is given.
+	call func(path string, value any) error
+	// maxDepth contains the maximum depth of nested values.
+	maxDepth int
+	// depth contains the depth of the current walk path.
+	depth int
+	// types contains the struct types on the current walk path.
+	types map[reflect.Type]int
+	// skip flags whether zero values on the current walk path are skipped.
+	skip bool
+	// tag contains the struct tag of the current field.
+	tag reflect.StructTag
+	// stop flags whether the walk is stopped.
+	stop bool
+	// errs contains the errors returned by the walk function.
+	errs []error
 }
 
 // NewTagWalker creates a new TagWalker with the given default tag name and
@@ -21,29 +72,170 @@ type TagWalker struct {
 // reader. However, the implementation is not dependent on these packages and
 // can be used without them or similar packages.
 //
+// The optional fallback tag names, e.g. `json` and `yaml`, are used in the
+// given order for naming fields without map tag name.
+//
 // [go-defaults]: <https://github.com/mcuadros/go-defaults>
 // [mapstructure]: <https://github.com/go-viper/mapstructure>
-func NewTagWalker(dtag, mtag string, zero bool) *TagWalker {
-	return &TagWalker{dtag: dtag, mtag: mtag, zero: zero}
+func NewTagWalker(
+	dtag, mtag string, zero bool, tags ...string,
+) *TagWalker {
+	return &TagWalker{
+		dtag: dtag, mtag: mtag, zero: zero,
+		tags:     append([]string{mtag}, tags...),
+		maxDepth: DefaultMaxDepth,
+	}
+}
+
+// NewTagWalkerE creates a new TagWalker the same way as `NewTagWalker`, but
+// with the given function returning an error called by `Walk` for each
+// field. Returned errors are collected and returned joined after the walk,
+// while returning `ErrStopWalk` stops the walk immediately without reporting
+// an error.
+func NewTagWalkerE(
+	dtag, mtag string, zero bool,
+	call func(path string, value any) error, tags ...string,
+) *TagWalker {
+	walker := NewTagWalker(dtag, mtag, zero, tags...)
+	walker.call = call
+	return walker
+}
+
+// WithMaxDepth sets the maximum depth of nested values the walker descends
+// into. If the depth is exceeded, the walker reports an error and stops
+// descending into the branch. If the depth is not positive, the default
+// maximum depth is used.
+func (w *TagWalker) WithMaxDepth(depth int) *TagWalker {
+	if depth <= 0 {
+		depth = DefaultMaxDepth
+	}
+	w.maxDepth = depth
+	return w
 }
 
 // Walk walks through the fields of the given value and calls the given
-// function with the path and tag of each field that has a tag.
+// function with the path and tag of each field that has a tag. If no function
+// is given, the function of the walker created via `NewTagWalkerE` is called
+// instead. Nil pointers to struct types already on the current walk path are
+// not descended into to terminate on self-referential struct types. The
+// errors collected during the walk, e.g. on exceeding the maximum depth, are
+// returned joined.
 func (w *TagWalker) Walk(
 	key string, value any,
 	call func(path string, value any),
+) error {
+	ecall := w.call
+	if call != nil || ecall == nil {
+		ecall = func(path string, value any) error {
+			if call != nil {
+				call(path, value)
+			}
+			return nil
+		}
+	}
+	return w.walkE(key, value, func(pv PathValue) error {
+		return ecall(pv.Path, pv.Value)
+	})
+}
+
+// PathValue contains a path and the value collected by the walk.
+type PathValue struct {
+	// Path contains the path of the value.
+	Path string
+	// Value contains the current value, or the default tag if the current
+	// value is zero.
+	Value any
+	// Type contains the go type of the value.
+	Type reflect.Type
+	// Tag contains the struct tag of the originating field.
+	Tag reflect.StructTag
+}
+
+// Collect walks through the fields of the given value the same way as `Walk`,
+// but returns the collected paths and values in deterministic order, i.e.
+// struct fields in declaration order and map entries in key order.
+func (w *TagWalker) Collect(key string, value any) ([]PathValue, error) {
+	values := []PathValue{}
+	err := w.walkE(key, value, func(pv PathValue) error {
+		values = append(values, pv)
+		return nil
+	})
+	return values, err
+}
+
+// walkE walks through the fields of the given value using a fresh walk state
+// calling the given function with the collected path values.
+func (w *TagWalker) walkE(
+	key string, value any,
+	call func(pv PathValue) error,
+) error {
+	walker := *w
+	walker.depth = 0
+	walker.types = map[reflect.Type]int{}
+	walker.errs = []error{}
+	walker.walk(strings.ToLower(key), reflect.ValueOf(value), call)
+	return errors.Join(walker.errs...)
+}
+
+// descend increments the depth of the current walk path, if the maximum depth
+// is not exceeded. Else it collects a max depth error for the given path and
+// returns `false` to stop descending into the branch. The depth is increased
+// exactly once per nesting step, i.e. per struct and per slice, array, or map
+// entry, independent of the path taken to reach the nested value.
+func (w *TagWalker) descend(key string) bool {
+	if w.depth >= w.maxDepth {
+		w.errs = append(w.errs,
+			NewErrTagWalker(key, NewErrMaxDepth(w.maxDepth)))
+		return false
+	}
+	w.depth++
+	return true
+}
+
+// emit calls the given function with the given path, value, and type, unless
+// the walk is stopped, collecting the returned error.
+func (w *TagWalker) emit(
+	key string, value any, vtype reflect.Type,
+	call func(pv PathValue) error,
 ) {
-	w.walk(strings.ToLower(key), reflect.ValueOf(value), call)
+	if w.stop {
+		return
+	}
+
+	err := call(PathValue{Path: key, Value: value, Type: vtype, Tag: w.tag})
+	if errors.Is(err, ErrStopWalk) {
+		w.stop = true
+	} else if err != nil {
+		w.errs = append(w.errs, NewErrTagWalker(key, err))
+	}
 }
 
 // walk is the internal walker function that is called recursively for each
 // element of the given value. The function calls the given function for each
 // value to apply the path and tag of the field to ensure that all paths can be
-// provided via environment variables to the config reader.
+// provided via environment variables to the config reader. Non-empty maps
+// provided via interface values are descended into, while the depth of the
+// walk path is limited by the maximum depth.
 func (w *TagWalker) walk(
 	key string, value reflect.Value,
-	call func(path string, value any),
+	call func(pv PathValue) error,
 ) {
+	if w.stop {
+		return
+	}
+
+	if value.Kind() == reflect.Interface &&
+		value.Elem().Kind() == reflect.Map && value.Elem().Len() != 0 {
+		value = value.Elem()
+	}
+
+	if value.IsValid() && isText(value.Type()) {
+		if !value.IsZero() || w.zero && !w.skip {
+			w.emit(key, value.Interface(), value.Type(), call)
+		}
+		return
+	}
+
 	switch value.Kind() {
 	case reflect.Ptr:
 		// TODO: Find test case for this code!
@@ -54,34 +246,60 @@ func (w *TagWalker) walk(
 	case reflect.Slice, reflect.Array:
 		for index := 0; index < value.Len(); index++ {
 			nkey := w.key(key, strconv.Itoa(index))
-			w.walk(nkey, value.Index(index), call)
+			w.walkEntry(nkey, value.Index(index), call)
 		}
 	case reflect.Map:
-		for _, fkey := range value.MapKeys() {
+		keys := value.MapKeys()
+		slices.SortFunc(keys, func(a, b reflect.Value) int {
+			return strings.Compare(a.String(), b.String())
+		})
+		for _, fkey := range keys {
 			nkey := w.key(key, fkey.String())
-			w.walk(nkey, value.MapIndex(fkey), call)
+			w.walkEntry(nkey, value.MapIndex(fkey), call)
 		}
 	case reflect.Struct:
 		w.walkStruct(key, value, call)
 	default:
-		if value.IsValid() && (!value.IsZero() || w.zero) {
-			call(key, value.Interface())
+		if value.IsValid() && (!value.IsZero() || w.zero && !w.skip) {
+			w.emit(key, value.Interface(), value.Type(), call)
 		}
 	}
 }
 
+// walkEntry walks through the given slice, array, or map entry one nesting
+// step deeper, if the maximum depth is not exceeded.
+func (w *TagWalker) walkEntry(
+	key string, value reflect.Value,
+	call func(pv PathValue) error,
+) {
+	if !w.descend(key) {
+		return
+	}
+	defer func() { w.depth-- }()
+
+	w.walk(key, value, call)
+}
+
 // walkStruct walks through the fields of the given struct value and calls the
 // given function with the path and tag of each field that has a tag. On each
 // field it also calls recursively the `walk` function depth-first.
 func (w *TagWalker) walkStruct(
 	key string, value reflect.Value,
-	call func(path string, value any),
+	call func(pv PathValue) error,
 ) {
+	if !w.descend(key) {
+		return
+	}
+	defer func() { w.depth-- }()
+
 	vtype := value.Type()
+	w.types[vtype]++
+	defer func() { w.types[vtype]-- }()
+
 	num := value.NumField()
-	for index := 0; index < num; index++ {
+	for index := 0; index < num && !w.stop; index++ {
 		field := vtype.Field(index)
-		if field.IsExported() {
+		if field.IsExported() && !w.ignored(field) {
 			w.walkField(w.field(key, field),
 				value.Field(index), field, call)
 		}
@@ -92,35 +310,115 @@ func (w *TagWalker) walkStruct(
 // with the path and tag of the field. If the field is a struct, the function
 // calls the `walkStruct` function to walk through the struct fields. If the
 // field is a pointer, slice, array, or map, the function calls the `walk`
-// function to walk through the field elements.
+// function to walk through the field elements. Zero arrays are handled like
+// empty slices reporting the tag of the field.
 func (w *TagWalker) walkField(
 	key string, value reflect.Value,
 	field reflect.StructField,
-	call func(path string, value any),
+	call func(pv PathValue) error,
 ) {
+	tag := w.tag
+	w.tag = field.Tag
+	defer func() { w.tag = tag }()
+
+	if !w.skip && w.skipped(field) {
+		if !value.IsValid() || value.IsZero() {
+			return
+		}
+		w.skip = true
+		defer func() { w.skip = false }()
+	}
+
+	if value.IsValid() && isText(value.Type()) {
+		w.walkText(key, value, field, call)
+		return
+	}
+
 	switch value.Kind() {
 	case reflect.Struct:
 		w.walkStruct(key, value, call)
 	case reflect.Ptr:
 		if value.IsZero() {
+			if w.types[value.Type().Elem()] > 0 {
+				return
+			}
 			value = reflect.New(value.Type().Elem())
 		}
 		w.walkField(key, value.Elem(), field, call)
-	case reflect.Slice, reflect.Array, reflect.Map:
+	case reflect.Array:
+		if value.IsZero() {
+			w.callTag(key, value.Type(), field, call)
+		} else {
+			w.walk(key, value, call)
+		}
+	case reflect.Slice, reflect.Map:
 		if value.Len() == 0 {
-			call(key, field.Tag.Get(w.dtag))
+			w.callTag(key, value.Type(), field, call)
 		} else {
 			w.walk(key, value, call)
 		}
+		if value.Kind() == reflect.Map {
+			w.walkTemplate(key, value.Type().Elem(), call)
+		}
 	default:
 		if value.IsValid() && !value.IsZero() {
-			call(key, value.Interface())
+			w.emit(key, value.Interface(), value.Type(), call)
 		} else {
-			call(key, field.Tag.Get(w.dtag))
+			w.callTag(key, value.Type(), field, call)
 		}
 	}
 }
 
+// walkTemplate walks through the fields of the given map value type, if it
+// is a struct type, a pointer to a struct type, or a nested map type, using
+// the template key `<key>.*` for the map entries. This way, the default tags
+// of map value struct types are reported as template paths, e.g.
+// `servers.*.port`, that can be applied to all map entries.
+func (w *TagWalker) walkTemplate(
+	key string, vtype reflect.Type,
+	call func(pv PathValue) error,
+) {
+	if vtype.Kind() == reflect.Ptr {
+		vtype = vtype.Elem()
+	}
+
+	switch {
+	case isText(vtype):
+	case vtype.Kind() == reflect.Struct && w.types[vtype] == 0:
+		w.walkStruct(w.key(key, TemplateKey), reflect.New(vtype).Elem(), call)
+	case vtype.Kind() == reflect.Map:
+		w.walkTemplate(w.key(key, TemplateKey), vtype.Elem(), call)
+	}
+}
+
+// walkText calls the given function with the path and the given value of the
+// field, if the value is not zero. Else it calls the function with the path
+// and tag of the field, since times and values implementing the text
+// unmarshaler interface are handled as scalar values instead of structs,
+// slices, or maps.
+func (w *TagWalker) walkText(
+	key string, value reflect.Value,
+	field reflect.StructField,
+	call func(pv PathValue) error,
+) {
+	if !value.IsZero() {
+		w.emit(key, value.Interface(), value.Type(), call)
+	} else {
+		w.callTag(key, value.Type(), field, call)
+	}
+}
+
+// callTag calls the given function with the path, tag, and type of the given
+// field, unless zero values on the current walk path are skipped.
+func (w *TagWalker) callTag(
+	key string, vtype reflect.Type, field reflect.StructField,
+	call func(pv PathValue) error,
+) {
+	if !w.skip {
+		w.emit(key, field.Tag.Get(w.dtag), vtype, call)
+	}
+}
+
 // field returns the field key for the given field and whether it is squashed.
 // If the field has a tag, the tag is used as terminal field name. If the tag
 // is empty, the field name is used as terminal field name. If the tag contains
@@ -128,20 +426,39 @@ func (w *TagWalker) walkField(
 func (w *TagWalker) field(
 	key string, field reflect.StructField,
 ) string {
-	mtag := field.Tag.Get(w.mtag)
-	if mtag == "" {
-		return w.key(key, field.Name)
-	}
-
-	args := strings.Split(mtag, ",")
+	args := strings.Split(field.Tag.Get(w.mtag), ",")
 	if isStruct(field) && slices.Contains(args[1:], "squash") {
 		return key
-	} else if args[0] != "" {
-		return w.key(key, args[0])
+	} else if name := w.name(field); name != "" {
+		return w.key(key, name)
 	}
 	return w.key(key, field.Name)
 }
 
+// name returns the tag name of the given field using the first tag with a
+// non-empty tag name in priority order ignoring tag options. If no tag
+// provides a tag name, an empty string is returned.
+func (w *TagWalker) name(field reflect.StructField) string {
+	for _, tag := range w.tags {
+		if name, _, _ := strings.Cut(field.Tag.Get(tag), ","); name != "" {
+			return name
+		}
+	}
+	return ""
+}
+
+// ignored evaluates whether the given field is ignored by the tags, i.e.
+// the field has the tag name `-`.
+func (w *TagWalker) ignored(field reflect.StructField) bool {
+	return w.name(field) == "-"
+}
+
+// skipped evaluates whether zero values of the given field are skipped by the
+// default tag, i.e. the field has the default tag `-`.
+func (w *TagWalker) skipped(field reflect.StructField) bool {
+	return field.Tag.Get(w.dtag) == "-"
+}
+
 // isStruct evaluates whether the given field is a struct or a pointer to a
 // struct.
 func isStruct(field reflect.StructField) bool {
@@ -150,6 +467,17 @@ func isStruct(field reflect.StructField) bool {
 			field.Type.Elem().Kind() == reflect.Struct)
 }
 
+// textType is the text unmarshaler interface type of values handled as
+// scalar values.
+var textType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+
+// isText evaluates whether the given type is not a pointer and the pointer
+// to the type implements the text unmarshaler interface, e.g. `time.Time`.
+func isText(vtype reflect.Type) bool {
+	return vtype.Kind() != reflect.Ptr &&
+		reflect.PointerTo(vtype).Implements(textType)
+}
+
 // key is the default key building function. It concatenates the current key
 // with the field name separated by a dot `.`. If the key is empty, the field
 // name is used as base key.
@@ -1,8 +1,19 @@
 package reflect_test
 
 import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	goreflect "reflect"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tkrop/go-config/config"
 	"github.com/tkrop/go-config/internal/reflect"
 	"github.com/tkrop/go-testing/mock"
 	"github.com/tkrop/go-testing/test"
@@ -27,6 +38,38 @@ func Call(path string, value any) mock.SetupFunc {
 	}
 }
 
+// testTime is an arbitrary time used for testing time values.
+var testTime = time.Date(2024, 10, 1, 12, 0, 0, 0, time.UTC)
+
+// text is a struct implementing the text unmarshaler interface.
+type text struct {
+	Value string
+}
+
+// UnmarshalText unmarshals the text value from the given text.
+func (t *text) UnmarshalText(value []byte) error {
+	t.Value = string(value)
+	return nil
+}
+
+// node is a directly self-referential struct type.
+type node struct {
+	Next *node
+	Name string `tag:"x"`
+}
+
+// nodeA is a mutually self-referential struct type referencing `nodeB`.
+type nodeA struct {
+	B    *nodeB
+	Name string `tag:"a"`
+}
+
+// nodeB is a mutually self-referential struct type referencing `nodeA`.
+type nodeB struct {
+	A    *nodeA
+	Name string `tag:"b"`
+}
+
 // tagWalkerParam contains a value and the expected tags.
 type tagWalkerParam struct {
 	value  any
@@ -483,6 +526,119 @@ var testTagWalkerParams = map[string]tagWalkerParam{
 		),
 	},
 
+	// Test struct with nested arrays.
+	"array-int": {
+		value: [2]int{1, 2},
+		expect: mock.Chain(
+			Call("0", 1),
+			Call("1", 2),
+		),
+	},
+	"array-int-zero": {
+		value: [2]int{},
+	},
+	"array-int-zero-zero": {
+		value: [2]int{},
+		zero:  true,
+		expect: mock.Chain(
+			Call("0", 0),
+			Call("1", 0),
+		),
+	},
+	"struct-array-tag": {
+		value: struct {
+			A [3]float64 `tag:"[0.5, 0.9, 0.99]"`
+		}{},
+		expect: Call("a", "[0.5, 0.9, 0.99]"),
+	},
+	"struct-array-tag-zero": {
+		value: struct {
+			A [3]float64 `tag:"[0.5, 0.9, 0.99]"`
+		}{},
+		zero:   true,
+		expect: Call("a", "[0.5, 0.9, 0.99]"),
+	},
+	"struct-array-no-tag": {
+		value: struct {
+			A [2]int
+		}{},
+		expect: Call("a", ""),
+	},
+	"struct-array-no-tag-zero": {
+		value: struct {
+			A [2]int
+		}{},
+		zero:   true,
+		expect: Call("a", ""),
+	},
+	"struct-ptr-array-tag": {
+		value: struct {
+			A *[3]float64 `tag:"[0.5, 0.9, 0.99]"`
+		}{},
+		expect: Call("a", "[0.5, 0.9, 0.99]"),
+	},
+	"struct-array-value": {
+		value: struct {
+			A [3]float64 `tag:"[0.5, 0.9, 0.99]"`
+		}{A: [3]float64{0.1, 0, 0.3}},
+		expect: mock.Chain(
+			Call("a.0", 0.1),
+			Call("a.2", 0.3),
+		),
+	},
+	"struct-array-value-zero": {
+		value: struct {
+			A [3]float64 `tag:"[0.5, 0.9, 0.99]"`
+		}{A: [3]float64{0.1, 0, 0.3}},
+		zero: true,
+		expect: mock.Chain(
+			Call("a.0", 0.1),
+			Call("a.1", 0.0),
+			Call("a.2", 0.3),
+		),
+	},
+	"struct-ptr-array-value": {
+		value: struct {
+			A *[3]float64 `tag:"[0.5, 0.9, 0.99]"`
+		}{A: &[3]float64{0.1, 0.2, 0.3}},
+		expect: mock.Chain(
+			Call("a.0", 0.1),
+			Call("a.1", 0.2),
+			Call("a.2", 0.3),
+		),
+	},
+	"struct-array-struct-tag": {
+		value: struct {
+			A [2]struct {
+				B any `tag:"any"`
+			} `tag:"[2]struct{any}"`
+		}{},
+		expect: Call("a", "[2]struct{any}"),
+	},
+	"struct-array-struct-value": {
+		value: struct {
+			A [2]struct {
+				B any `tag:"any"`
+			} `tag:"[2]struct{any}"`
+		}{A: [2]struct {
+			B any `tag:"any"`
+		}{{B: 1}, {B: 2}}},
+		expect: mock.Chain(
+			Call("a.0.b", 1),
+			Call("a.1.b", 2),
+		),
+	},
+	"struct-array-ptr-struct-value": {
+		value: struct {
+			A [2]*struct {
+				B any `tag:"any"`
+			} `tag:"[2]*struct{any}"`
+		}{A: [2]*struct {
+			B any `tag:"any"`
+		}{{B: 1}, nil}},
+		expect: Call("a.0.b", 1),
+	},
+
 	// Test struct with nested maps.
 	"struct-map-tag": {
 		value: struct {
@@ -502,7 +658,10 @@ var testTagWalkerParams = map[string]tagWalkerParam{
 				A any `tag:"any"`
 			} `tag:"map[string]struct{any}"`
 		}{},
-		expect: Call("m", "map[string]struct{any}"),
+		expect: mock.Setup(
+			Call("m", "map[string]struct{any}"),
+			Call("m.*.a", "any"),
+		),
 	},
 	"struct-ptr-map-struct-tag": {
 		value: &struct {
@@ -510,7 +669,10 @@ var testTagWalkerParams = map[string]tagWalkerParam{
 				A any `tag:"any"`
 			} `tag:"*map[string]struct{any}"`
 		}{},
-		expect: Call("m", "*map[string]struct{any}"),
+		expect: mock.Setup(
+			Call("m", "*map[string]struct{any}"),
+			Call("m.*.a", "any"),
+		),
 	},
 	"struct-map-ptr-struct-tag": {
 		value: &struct {
@@ -518,7 +680,36 @@ var testTagWalkerParams = map[string]tagWalkerParam{
 				A any `tag:"any"`
 			} `tag:"map[string]*struct{any}"`
 		}{},
-		expect: Call("m", "map[string]*struct{any}"),
+		expect: mock.Setup(
+			Call("m", "map[string]*struct{any}"),
+			Call("m.*.a", "any"),
+		),
+	},
+	"struct-map-map-struct-tag": {
+		value: &struct {
+			M map[string]map[string]struct {
+				A any `tag:"any"`
+			}
+		}{},
+		expect: mock.Setup(
+			Call("m", ""),
+			Call("m.*.*.a", "any"),
+		),
+	},
+	"struct-map-text-tag": {
+		value: &struct {
+			M map[string]text `tag:"map[string]text"`
+		}{},
+		expect: Call("m", "map[string]text"),
+	},
+	"struct-map-self-tag": {
+		value: &struct {
+			M map[string]node
+		}{},
+		expect: mock.Setup(
+			Call("m", ""),
+			Call("m.*.name", "x"),
+		),
 	},
 
 	// Test struct with nested maps.
@@ -545,6 +736,7 @@ var testTagWalkerParams = map[string]tagWalkerParam{
 		expect: mock.Setup(
 			Call("m.key-0.a", 1),
 			Call("m.key-1.a", 2),
+			Call("m.*.a", "any"),
 		),
 	},
 	"struct-ptr-map-struct-value": {
@@ -558,6 +750,7 @@ var testTagWalkerParams = map[string]tagWalkerParam{
 		expect: mock.Setup(
 			Call("m.key-0.a", 1),
 			Call("m.key-1.a", 2),
+			Call("m.*.a", "any"),
 		),
 	},
 	"struct-ptr-map-ptr-struct-value": {
@@ -571,6 +764,193 @@ var testTagWalkerParams = map[string]tagWalkerParam{
 		expect: mock.Setup(
 			Call("m.key-0.a", 1),
 			Call("m.key-1.a", 2),
+			Call("m.*.a", "any"),
+		),
+	},
+	"struct-map-nested-value": {
+		value: struct {
+			M map[string]any `tag:"map[string]any"`
+		}{M: map[string]any{
+			"a": map[string]any{"b": 1},
+			"c": map[string]any{},
+		}},
+		expect: mock.Setup(
+			Call("m.a.b", 1),
+			Call("m.c", map[string]any{}),
+		),
+	},
+
+	// Test time values.
+	"time": {
+		value:  testTime,
+		expect: Call("", testTime),
+	},
+	"time-zero": {
+		value: time.Time{},
+	},
+	"time-zero-zero": {
+		value:  time.Time{},
+		zero:   true,
+		expect: Call("", time.Time{}),
+	},
+	"duration": {
+		value:  30 * time.Second,
+		expect: Call("", 30*time.Second),
+	},
+	"slice-time": {
+		value: []time.Time{testTime, {}},
+		zero:  true,
+		expect: mock.Chain(
+			Call("0", testTime),
+			Call("1", time.Time{}),
+		),
+	},
+	"slice-duration": {
+		value: []time.Duration{time.Second, 0},
+		zero:  true,
+		expect: mock.Chain(
+			Call("0", time.Second),
+			Call("1", time.Duration(0)),
+		),
+	},
+
+	// Test struct with time tags and values.
+	"struct-time-tag": {
+		value: struct {
+			T time.Time `tag:"2024-10-01T12:00:00Z"`
+		}{},
+		expect: Call("t", "2024-10-01T12:00:00Z"),
+	},
+	"struct-time-value": {
+		value: struct {
+			T time.Time `tag:"2024-10-01T12:00:00Z"`
+		}{T: testTime},
+		expect: Call("t", testTime),
+	},
+	"struct-ptr-time-tag": {
+		value: struct {
+			T *time.Time `tag:"2024-10-01T12:00:00Z"`
+		}{},
+		expect: Call("t", "2024-10-01T12:00:00Z"),
+	},
+	"struct-ptr-time-value": {
+		value: struct {
+			T *time.Time `tag:"2024-10-01T12:00:00Z"`
+		}{T: &testTime},
+		expect: Call("t", testTime),
+	},
+	"struct-slice-time-tag": {
+		value: struct {
+			T []time.Time `tag:"[2024-10-01T12:00:00Z]"`
+		}{},
+		expect: Call("t", "[2024-10-01T12:00:00Z]"),
+	},
+	"struct-slice-time-value": {
+		value: struct {
+			T []time.Time `tag:"[2024-10-01T12:00:00Z]"`
+		}{T: []time.Time{testTime}},
+		expect: Call("t.0", testTime),
+	},
+	"struct-duration-tag": {
+		value: struct {
+			D time.Duration `tag:"30s"`
+		}{},
+		expect: Call("d", "30s"),
+	},
+	"struct-duration-value": {
+		value: struct {
+			D time.Duration `tag:"30s"`
+		}{D: time.Minute},
+		expect: Call("d", time.Minute),
+	},
+	"struct-ptr-duration-tag": {
+		value: struct {
+			D *time.Duration `tag:"30s"`
+		}{},
+		expect: Call("d", "30s"),
+	},
+	"struct-slice-duration-value": {
+		value: struct {
+			D []time.Duration `tag:"[30s]"`
+		}{D: []time.Duration{time.Minute}},
+		expect: Call("d.0", time.Minute),
+	},
+
+	// Test values implementing the text unmarshaler interface.
+	"text": {
+		value:  text{Value: "value"},
+		expect: Call("", text{Value: "value"}),
+	},
+	"text-ip": {
+		value:  net.IPv4(127, 0, 0, 1),
+		expect: Call("", net.IPv4(127, 0, 0, 1)),
+	},
+	"struct-text-tag": {
+		value: struct {
+			T text `tag:"value"`
+		}{},
+		expect: Call("t", "value"),
+	},
+	"struct-text-value": {
+		value: struct {
+			T text `tag:"value"`
+		}{T: text{Value: "other"}},
+		expect: Call("t", text{Value: "other"}),
+	},
+	"struct-ptr-text-tag": {
+		value: struct {
+			T *text `tag:"value"`
+		}{},
+		expect: Call("t", "value"),
+	},
+	"struct-slice-text-value": {
+		value: struct {
+			T []text `tag:"value"`
+		}{T: []text{{Value: "other"}}},
+		expect: Call("t.0", text{Value: "other"}),
+	},
+	"struct-ip-tag": {
+		value: struct {
+			IP net.IP `tag:"127.0.0.1"`
+		}{},
+		expect: Call("ip", "127.0.0.1"),
+	},
+	"struct-ip-value": {
+		value: struct {
+			IP net.IP `tag:"127.0.0.1"`
+		}{IP: net.IPv4(10, 0, 0, 1)},
+		expect: Call("ip", net.IPv4(10, 0, 0, 1)),
+	},
+
+	// Test self-referential struct types.
+	"recursive-direct": {
+		value:  &node{},
+		expect: Call("name", "x"),
+	},
+	"recursive-direct-value": {
+		value: &node{Next: &node{Name: "y"}},
+		expect: mock.Chain(
+			Call("next.name", "y"),
+			Call("name", "x"),
+		),
+	},
+	"recursive-mutual": {
+		value: &nodeA{},
+		expect: mock.Chain(
+			Call("b.name", "b"),
+			Call("name", "a"),
+		),
+	},
+	"recursive-distinct": {
+		value: &struct {
+			A *nodeA
+			B *nodeB
+		}{},
+		expect: mock.Chain(
+			Call("a.b.name", "b"),
+			Call("a.name", "a"),
+			Call("b.a.name", "a"),
+			Call("b.name", "b"),
 		),
 	},
 
@@ -613,6 +993,76 @@ var testTagWalkerParams = map[string]tagWalkerParam{
 			Call("r", "map[string]any"),
 		),
 	},
+	"map-ignored": {
+		value: &struct {
+			A *any      `map:"a" tag:"*any"`
+			F func(int) `map:"-"`
+		}{},
+		expect: mock.Chain(
+			Call("a", "*any"),
+		),
+	},
+	"map-ignored-struct": {
+		value: &struct {
+			A *any `map:"a" tag:"*any"`
+			S struct {
+				B any `tag:"any"`
+			} `map:"-"`
+		}{},
+		expect: Call("a", "*any"),
+	},
+
+	// Test default skip tags.
+	"default-skip": {
+		value: &struct {
+			A any `tag:"any"`
+			B any `tag:"-"`
+		}{},
+		expect: Call("a", "any"),
+	},
+	"default-skip-value": {
+		value: &struct {
+			A any `tag:"any"`
+			B any `tag:"-"`
+		}{B: 1},
+		expect: mock.Chain(
+			Call("a", "any"),
+			Call("b", 1),
+		),
+	},
+	"default-skip-zero": {
+		value: &struct {
+			A int   `tag:"1"`
+			B []int `tag:"-"`
+		}{B: []int{0, 1}},
+		zero: true,
+		expect: mock.Chain(
+			Call("a", "1"),
+			Call("b.1", 1),
+		),
+	},
+	"default-skip-struct": {
+		value: &struct {
+			A any `tag:"any"`
+			S *struct {
+				B any `tag:"any"`
+			} `tag:"-"`
+		}{},
+		expect: Call("a", "any"),
+	},
+	"default-skip-struct-value": {
+		value: &struct {
+			S struct {
+				A any `tag:"any"`
+				B any `tag:"any"`
+			} `tag:"-"`
+		}{S: struct {
+			A any `tag:"any"`
+			B any `tag:"any"`
+		}{B: 1}},
+		expect: Call("s.b", 1),
+	},
+
 	"map-comma": {
 		value: &struct {
 			S struct {
@@ -640,3 +1090,320 @@ func TestTagWalker_Walk(t *testing.T) {
 			// Then
 		})
 }
+
+// errWalk is an arbitrary error returned by the walk function.
+var errWalk = errors.New("walk")
+
+// walkValue is the value used for testing the walk with errors.
+var walkValue = &struct {
+	A int `tag:"1"`
+	S struct {
+		B int `tag:"2"`
+		C int `tag:"3"`
+	}
+	D []int `tag:"[4]"`
+}{}
+
+// fallbackValue is the value used for testing fallback tags.
+var fallbackValue = &struct {
+	A any `map:"a-map" json:"a-json" yaml:"a-yaml" tag:"a"`
+	B any `json:"b-json" yaml:"b-yaml" tag:"b"`
+	C any `yaml:"c-yaml" tag:"c"`
+	D any `json:",omitempty" yaml:"d-yaml" tag:"d"`
+	E any `json:"-" yaml:"e-yaml" tag:"e"`
+	F any `map:"f-map" json:"-" tag:"f"`
+	G any `tag:"g"`
+}{}
+
+type tagWalkerFallbackParam struct {
+	tags        []string
+	expectPaths []string
+}
+
+var testTagWalkerFallbackParams = map[string]tagWalkerFallbackParam{
+	"no fallback": {
+		expectPaths: []string{"a-map", "b", "c", "d", "e", "f-map", "g"},
+	},
+	"json fallback": {
+		tags:        []string{"json"},
+		expectPaths: []string{"a-map", "b-json", "c", "d", "f-map", "g"},
+	},
+	"json yaml fallback": {
+		tags: []string{"json", "yaml"},
+		expectPaths: []string{
+			"a-map", "b-json", "c-yaml", "d-yaml", "f-map", "g",
+		},
+	},
+	"yaml json fallback": {
+		tags: []string{"yaml", "json"},
+		expectPaths: []string{
+			"a-map", "b-yaml", "c-yaml", "d-yaml", "e-yaml", "f-map", "g",
+		},
+	},
+}
+
+// TestTagWalker_Fallback tests TagWalker with fallback tags.
+func TestTagWalker_Fallback(t *testing.T) {
+	test.Map(t, testTagWalkerFallbackParams).
+		Run(func(t test.Test, param tagWalkerFallbackParam) {
+			// Given
+			walker := reflect.NewTagWalker("tag", "map", false, param.tags...)
+
+			// When
+			values, err := walker.Collect("", fallbackValue)
+
+			// Then
+			require.NoError(t, err)
+			paths := []string{}
+			for _, value := range values {
+				paths = append(paths, value.Path)
+			}
+			assert.Equal(t, param.expectPaths, paths)
+		})
+}
+
+type tagWalkerEParam struct {
+	fail        map[string]error
+	expectPaths []string
+	expectError error
+}
+
+var testTagWalkerEParams = map[string]tagWalkerEParam{
+	"no errors": {
+		expectPaths: []string{"a", "s.b", "s.c", "d"},
+	},
+	"single error": {
+		fail:        map[string]error{"s.b": errWalk},
+		expectPaths: []string{"a", "s.b", "s.c", "d"},
+		expectError: errors.Join(reflect.NewErrTagWalker("s.b", errWalk)),
+	},
+	"multiple errors": {
+		fail:        map[string]error{"a": errWalk, "d": errWalk},
+		expectPaths: []string{"a", "s.b", "s.c", "d"},
+		expectError: errors.Join(reflect.NewErrTagWalker("a", errWalk),
+			reflect.NewErrTagWalker("d", errWalk)),
+	},
+	"stop first": {
+		fail:        map[string]error{"a": reflect.ErrStopWalk},
+		expectPaths: []string{"a"},
+	},
+	"stop nested": {
+		fail:        map[string]error{"s.b": reflect.ErrStopWalk},
+		expectPaths: []string{"a", "s.b"},
+	},
+	"error before stop": {
+		fail: map[string]error{
+			"a": errWalk, "s.c": reflect.ErrStopWalk,
+		},
+		expectPaths: []string{"a", "s.b", "s.c"},
+		expectError: errors.Join(reflect.NewErrTagWalker("a", errWalk)),
+	},
+}
+
+// TestNewTagWalkerE tests TagWalker.Walk created via NewTagWalkerE.
+func TestNewTagWalkerE(t *testing.T) {
+	test.Map(t, testTagWalkerEParams).
+		Run(func(t test.Test, param tagWalkerEParam) {
+			// Given
+			paths := []string{}
+			walker := reflect.NewTagWalkerE("tag", "map", false,
+				func(path string, _ any) error {
+					paths = append(paths, path)
+					return param.fail[path]
+				})
+
+			// When
+			err := walker.Walk("", walkValue, nil)
+
+			// Then
+			assert.Equal(t, param.expectPaths, paths)
+			assert.Equal(t, param.expectError, err)
+		})
+}
+
+// nestedMap creates a nested map with the given depth using the key `m` for
+// each level and the value `value` on the deepest level.
+func nestedMap(depth int) any {
+	var value any = "value"
+	for ; depth > 0; depth-- {
+		value = map[string]any{"m": value}
+	}
+	return value
+}
+
+type tagWalkerMaxDepthParam struct {
+	value       any
+	depth       int
+	expectPaths []string
+	expectError error
+}
+
+var testTagWalkerMaxDepthParams = map[string]tagWalkerMaxDepthParam{
+	"nested map at depth": {
+		value:       nestedMap(4),
+		depth:       4,
+		expectPaths: []string{"m.m.m.m"},
+	},
+	"nested map beyond depth": {
+		value:       nestedMap(5),
+		depth:       4,
+		expectPaths: []string{},
+		expectError: errors.Join(reflect.NewErrTagWalker(
+			"m.m.m.m.m", reflect.NewErrMaxDepth(4))),
+	},
+	"nested map at default depth": {
+		value: nestedMap(reflect.DefaultMaxDepth),
+		expectPaths: []string{
+			strings.Repeat(".m", reflect.DefaultMaxDepth)[1:],
+		},
+	},
+	"nested map beyond default depth": {
+		value:       nestedMap(reflect.DefaultMaxDepth + 1),
+		expectPaths: []string{},
+		expectError: errors.Join(reflect.NewErrTagWalker(
+			strings.Repeat(".m", reflect.DefaultMaxDepth+1)[1:],
+			reflect.NewErrMaxDepth(reflect.DefaultMaxDepth))),
+	},
+	"nested map in struct at depth": {
+		value: struct {
+			A int `tag:"1"`
+			M map[string]any
+			B int `tag:"2"`
+		}{M: nestedMap(3).(map[string]any)},
+		depth:       4,
+		expectPaths: []string{"a", "m.m.m.m", "b"},
+	},
+	"nested map in struct beyond depth": {
+		value: struct {
+			A int `tag:"1"`
+			M map[string]any
+			B int `tag:"2"`
+		}{M: nestedMap(4).(map[string]any)},
+		depth:       4,
+		expectPaths: []string{"a", "b"},
+		expectError: errors.Join(reflect.NewErrTagWalker(
+			"m.m.m.m.m", reflect.NewErrMaxDepth(4))),
+	},
+	"nested struct at depth": {
+		value: struct {
+			S struct {
+				T struct {
+					A int `tag:"1"`
+				}
+			}
+			B int `tag:"2"`
+		}{},
+		depth:       3,
+		expectPaths: []string{"s.t.a", "b"},
+	},
+	"nested struct beyond depth": {
+		value: struct {
+			S struct {
+				T struct {
+					A int `tag:"1"`
+				}
+			}
+			B int `tag:"2"`
+		}{},
+		depth:       2,
+		expectPaths: []string{"b"},
+		expectError: errors.Join(reflect.NewErrTagWalker(
+			"s.t", reflect.NewErrMaxDepth(2))),
+	},
+	"nested struct pointer at depth": {
+		value: &struct {
+			S *struct {
+				A int `tag:"1"`
+			}
+		}{},
+		depth:       2,
+		expectPaths: []string{"s.a"},
+	},
+	"nested struct in slice at depth": {
+		value: struct {
+			L []struct {
+				A int `tag:"1"`
+			}
+		}{L: make([]struct {
+			A int `tag:"1"`
+		}, 1)},
+		depth:       3,
+		expectPaths: []string{"l.0.a"},
+	},
+	"nested struct in slice beyond depth": {
+		value: struct {
+			L []struct {
+				A int `tag:"1"`
+			}
+		}{L: make([]struct {
+			A int `tag:"1"`
+		}, 1)},
+		depth:       2,
+		expectPaths: []string{},
+		expectError: errors.Join(reflect.NewErrTagWalker(
+			"l.0", reflect.NewErrMaxDepth(2))),
+	},
+}
+
+// TestTagWalker_MaxDepth tests TagWalker with maximum depth.
+func TestTagWalker_MaxDepth(t *testing.T) {
+	test.Map(t, testTagWalkerMaxDepthParams).
+		Run(func(t test.Test, param tagWalkerMaxDepthParam) {
+			// Given
+			walker := reflect.NewTagWalker("tag", "map", false).
+				WithMaxDepth(param.depth)
+			paths := []string{}
+
+			// When
+			err := walker.Walk("", param.value,
+				func(path string, _ any) {
+					paths = append(paths, path)
+				})
+
+			// Then
+			assert.Equal(t, param.expectPaths, paths)
+			assert.Equal(t, param.expectError, err)
+		})
+}
+
+// formatPathValues formats the given path values as lines of path, type,
+// value, and tag.
+func formatPathValues(values []reflect.PathValue) string {
+	builder := strings.Builder{}
+	for _, pv := range values {
+		fmt.Fprintf(&builder, "%s %s %q `%s`\n",
+			pv.Path, pv.Type, fmt.Sprint(pv.Value), pv.Tag)
+	}
+	return builder.String()
+}
+
+func TestTagWalker_Collect(t *testing.T) {
+	// Given
+	walker := reflect.NewTagWalker("default", "mapstructure", true)
+	golden, err := os.ReadFile(filepath.Join("fixtures", "config.golden"))
+	require.NoError(t, err)
+
+	// When
+	values, err := walker.Collect("", &config.Config{})
+
+	// Then
+	require.NoError(t, err)
+	assert.Equal(t, string(golden), formatPathValues(values))
+}
+
+func TestTagWalker_CollectMap(t *testing.T) {
+	// Given
+	walker := reflect.NewTagWalker("tag", "map", false)
+	value := map[string]int{"c": 3, "a": 1, "b": 2}
+
+	// When
+	values, err := walker.Collect("m", value)
+
+	// Then
+	require.NoError(t, err)
+	assert.Equal(t, []reflect.PathValue{
+		{Path: "m.a", Value: 1, Type: goreflect.TypeOf(0)},
+		{Path: "m.b", Value: 2, Type: goreflect.TypeOf(0)},
+		{Path: "m.c", Value: 3, Type: goreflect.TypeOf(0)},
+	}, values)
+}
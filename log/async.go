@@ -0,0 +1,269 @@
+package log
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultAsyncBuffer is the default number of entries buffered by the
+// asynchronous writer.
+const DefaultAsyncBuffer = 1024
+
+// DefaultAsyncReport is the default interval for reporting dropped entries
+// of the asynchronous writer.
+const DefaultAsyncReport = 10 * time.Second
+
+// ErrAsyncClosed is a common error to indicate that the asynchronous writer
+// is already closed.
+var ErrAsyncClosed = errors.New("async writer closed")
+
+// AsyncOption is an option of the asynchronous writer.
+type AsyncOption func(*AsyncWriter)
+
+// WithAsyncBuffer sets the number of entries buffered by the asynchronous
+// writer. Non-positive sizes fall back to the default buffer size.
+func WithAsyncBuffer(size int) AsyncOption {
+	return func(w *AsyncWriter) {
+		if size > 0 {
+			w.size = size
+		}
+	}
+}
+
+// WithAsyncDrop sets whether the asynchronous writer drops entries on buffer
+// overflow instead of blocking the writing caller.
+func WithAsyncDrop(drop bool) AsyncOption {
+	return func(w *AsyncWriter) {
+		w.drop = drop
+	}
+}
+
+// WithAsyncReport sets the interval and the function used to periodically
+// report the number of entries dropped since the last report.
+func WithAsyncReport(
+	interval time.Duration, report func(dropped uint64),
+) AsyncOption {
+	return func(w *AsyncWriter) {
+		w.interval, w.report = interval, report
+	}
+}
+
+// asyncEntry is an entry of the asynchronous writer, i.e. either the data to
+// write or a flush marker that is closed when reached by the flusher.
+type asyncEntry struct {
+	// data is the data to write.
+	data []byte
+	// flushed is the flush marker closed when reached by the flusher.
+	flushed chan struct{}
+}
+
+// AsyncWriter is a writer decoupling the writing callers from slow writers
+// using a bounded buffer of entries written by a background flusher. On
+// buffer overflow, entries are either dropped or the caller blocks until the
+// flusher catches up.
+type AsyncWriter struct {
+	// writer is the underlying writer.
+	writer io.Writer
+	// size is the number of buffered entries.
+	size int
+	// drop is defining whether entries are dropped on buffer overflow.
+	drop bool
+	// interval is the interval for reporting dropped entries.
+	interval time.Duration
+	// report is the function for reporting dropped entries.
+	report func(dropped uint64)
+
+	// entries is the bounded buffer of entries.
+	entries chan asyncEntry
+	// mutex synchronizes writing entries with closing the writer.
+	mutex sync.RWMutex
+	// closed is defining whether the writer is closed.
+	closed bool
+	// once ensures that the writer is closed only once.
+	once sync.Once
+	// done is closed when the flusher has drained all entries.
+	done chan struct{}
+	// stop is closed to stop the reporter.
+	stop chan struct{}
+	// dropped is the number of dropped entries not reported yet.
+	dropped atomic.Uint64
+	// err is the last error of the underlying writer.
+	err atomic.Pointer[error]
+}
+
+// NewAsyncWriter creates a new asynchronous writer for the given writer using
+// the given options, and starts the background flusher. The writer must be
+// closed to flush all entries.
+func NewAsyncWriter(writer io.Writer, opts ...AsyncOption) *AsyncWriter {
+	w := &AsyncWriter{
+		writer:   writer,
+		size:     DefaultAsyncBuffer,
+		interval: DefaultAsyncReport,
+		done:     make(chan struct{}),
+		stop:     make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(w)
+	}
+	w.entries = make(chan asyncEntry, w.size)
+
+	go w.flusher()
+	if w.report != nil && w.interval > 0 {
+		go w.reporter()
+	}
+	return w
+}
+
+// Write copies the given bytes into the buffer of the writer. If the buffer
+// is full, the entry is dropped or the call blocks as configured. Dropped
+// entries are not reported as error.
+func (w *AsyncWriter) Write(p []byte) (int, error) {
+	w.mutex.RLock()
+	defer w.mutex.RUnlock()
+	if w.closed {
+		return 0, ErrAsyncClosed
+	}
+
+	entry := asyncEntry{data: append([]byte(nil), p...)}
+	if !w.drop {
+		w.entries <- entry
+		return len(p), nil
+	}
+
+	select {
+	case w.entries <- entry:
+	default:
+		w.dropped.Add(1)
+	}
+	return len(p), nil
+}
+
+// Dropped returns the number of dropped entries not reported yet.
+func (w *AsyncWriter) Dropped() uint64 {
+	return w.dropped.Load()
+}
+
+// Flush waits until all entries written before are written to the underlying
+// writer or the given context is done. It returns the last error of the
+// underlying writer.
+func (w *AsyncWriter) Flush(ctx context.Context) error {
+	w.mutex.RLock()
+	if w.closed {
+		w.mutex.RUnlock()
+		return w.error()
+	}
+
+	flushed := make(chan struct{})
+	select {
+	case w.entries <- asyncEntry{flushed: flushed}:
+		w.mutex.RUnlock()
+	case <-ctx.Done():
+		w.mutex.RUnlock()
+		return ctx.Err()
+	}
+
+	select {
+	case <-flushed:
+		return w.error()
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Close reports the remaining dropped entries, stops accepting new entries,
+// and waits until all buffered entries are written to the underlying writer.
+// The underlying writer is not closed. It returns the last error of the
+// underlying writer.
+func (w *AsyncWriter) Close() error {
+	w.once.Do(func() {
+		close(w.stop)
+		w.reportDropped()
+
+		w.mutex.Lock()
+		w.closed = true
+		close(w.entries)
+		w.mutex.Unlock()
+		<-w.done
+	})
+	return w.error()
+}
+
+// flusher writes the buffered entries to the underlying writer until the
+// writer is closed and all entries are drained.
+func (w *AsyncWriter) flusher() {
+	defer close(w.done)
+	for entry := range w.entries {
+		if entry.flushed != nil {
+			close(entry.flushed)
+		} else if _, err := w.writer.Write(entry.data); err != nil {
+			w.err.Store(&err)
+		}
+	}
+}
+
+// reporter periodically reports the dropped entries until the writer is
+// closed. Reporting is decoupled from the flusher, so that reports logged to
+// the writer itself cannot block it.
+func (w *AsyncWriter) reporter() {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			w.reportDropped()
+		case <-w.stop:
+			return
+		}
+	}
+}
+
+// reportDropped reports the dropped entries since the last report, if any.
+func (w *AsyncWriter) reportDropped() {
+	if w.report == nil {
+		return
+	} else if dropped := w.dropped.Swap(0); dropped > 0 {
+		w.report(dropped)
+	}
+}
+
+// error returns the last error of the underlying writer.
+func (w *AsyncWriter) error() error {
+	if err := w.err.Load(); err != nil {
+		return *err
+	}
+	return nil
+}
+
+// SetupWriter returns the output writer for the given writer. If `Async` is
+// configured, the writer is wrapped by an asynchronous writer, that must be
+// closed via `io.Closer` to flush all entries. Dropped entries are reported
+// as warning via the loggers set up by the config. Since the asynchronous
+// writer is no terminal, colors must be enabled explicitly. Else the writer
+// is returned as is.
+func (c *Config) SetupWriter(writer io.Writer) io.Writer {
+	if !c.Async {
+		return writer
+	}
+	return NewAsyncWriter(writer,
+		WithAsyncBuffer(c.AsyncBuffer), WithAsyncDrop(c.AsyncDrop),
+		WithAsyncReport(DefaultAsyncReport, c.setupLoggers().reportDropped))
+}
+
+// reportDropped reports the given number of dropped entries as warning via
+// the zerolog or logrus logger set up by the config.
+func (l *loggers) reportDropped(dropped uint64) {
+	l.mutex.RLock()
+	defer l.mutex.RUnlock()
+
+	if l.zero != nil {
+		l.zero.Warn().Uint64("dropped", dropped).
+			Msg("dropped log entries")
+	} else if l.rus != nil {
+		l.rus.WithField("dropped", dropped).
+			Warn("dropped log entries")
+	}
+}
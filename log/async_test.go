@@ -0,0 +1,198 @@
+package log_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tkrop/go-testing/test"
+
+	"github.com/tkrop/go-config/log"
+)
+
+// blockingWriter is a writer blocking each write until it is released.
+type blockingWriter struct {
+	mutex   sync.Mutex
+	buffer  bytes.Buffer
+	started chan struct{}
+	release chan struct{}
+	err     error
+}
+
+// newBlockingWriter creates a new blocking writer.
+func newBlockingWriter() *blockingWriter {
+	return &blockingWriter{
+		started: make(chan struct{}, 16),
+		release: make(chan struct{}),
+	}
+}
+
+// Write signals the start of the write and blocks until released.
+func (w *blockingWriter) Write(p []byte) (int, error) {
+	w.started <- struct{}{}
+	<-w.release
+
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	w.buffer.Write(p)
+	return len(p), w.err
+}
+
+// String returns the written bytes as string.
+func (w *blockingWriter) String() string {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	return w.buffer.String()
+}
+
+type testAsyncOverflowParam struct {
+	drop          bool
+	expect        string
+	expectDropped uint64
+}
+
+var testAsyncOverflowParams = map[string]testAsyncOverflowParam{
+	"overflow drop": {
+		drop:          true,
+		expect:        "a\nb\n",
+		expectDropped: 1,
+	},
+	"overflow block": {
+		drop:   false,
+		expect: "a\nb\nc\n",
+	},
+}
+
+func TestAsyncOverflow(t *testing.T) {
+	test.Map(t, testAsyncOverflowParams).
+		Run(func(t test.Test, param testAsyncOverflowParam) {
+			// Given
+			writer := newBlockingWriter()
+			reported := uint64(0)
+			async := log.NewAsyncWriter(writer,
+				log.WithAsyncBuffer(1), log.WithAsyncDrop(param.drop),
+				log.WithAsyncReport(time.Hour, func(dropped uint64) {
+					reported += dropped
+				}))
+			_, err := async.Write([]byte("a\n"))
+			require.NoError(t, err)
+			<-writer.started
+			_, err = async.Write([]byte("b\n"))
+			require.NoError(t, err)
+
+			// When
+			written := make(chan struct{})
+			go func() {
+				_, err := async.Write([]byte("c\n"))
+				assert.NoError(t, err)
+				close(written)
+			}()
+
+			// Then
+			if param.drop {
+				<-written
+			} else {
+				select {
+				case <-written:
+					assert.Fail(t, "write not blocked on overflow")
+				case <-time.After(20 * time.Millisecond):
+				}
+			}
+			assert.Equal(t, param.expectDropped, async.Dropped())
+
+			close(writer.release)
+			<-written
+			assert.NoError(t, async.Close())
+			assert.Equal(t, param.expect, writer.String())
+			assert.Equal(t, param.expectDropped, reported)
+		})
+}
+
+func TestAsyncClose(t *testing.T) {
+	// Given
+	buffer := &bytes.Buffer{}
+	async := log.NewAsyncWriter(buffer)
+	for _, line := range []string{"a\n", "b\n", "c\n"} {
+		_, err := async.Write([]byte(line))
+		require.NoError(t, err)
+	}
+
+	// When
+	err := async.Close()
+
+	// Then
+	assert.NoError(t, err)
+	assert.Equal(t, "a\nb\nc\n", buffer.String())
+	assert.NoError(t, async.Close())
+	_, err = async.Write([]byte("d\n"))
+	assert.ErrorIs(t, err, log.ErrAsyncClosed)
+	assert.NoError(t, async.Flush(context.Background()))
+}
+
+func TestAsyncFlush(t *testing.T) {
+	// Given
+	writer := newBlockingWriter()
+	writer.err = assert.AnError
+	async := log.NewAsyncWriter(writer)
+	_, err := async.Write([]byte("a\n"))
+	require.NoError(t, err)
+	<-writer.started
+
+	// When
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	err = async.Flush(ctx)
+
+	// Then
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	close(writer.release)
+	assert.ErrorIs(t, async.Flush(context.Background()), assert.AnError)
+	assert.Equal(t, "a\n", writer.String())
+	assert.ErrorIs(t, async.Close(), assert.AnError)
+}
+
+func TestSetupWriter(t *testing.T) {
+	// Given
+	writer := newBlockingWriter()
+	config := &log.Config{
+		Level:       log.LevelInfo,
+		TimeFormat:  log.TimeFormatNone,
+		ColorMode:   log.ColorModeOff,
+		Async:       true,
+		AsyncBuffer: 1,
+		AsyncDrop:   true,
+	}
+	output := config.SetupWriter(writer)
+	logger := config.SetupZero(output).ZeroLogger()
+
+	// When
+	logger.Info().Msg("a")
+	<-writer.started
+	logger.Info().Msg("b")
+	logger.Info().Msg("c")
+	close(writer.release)
+
+	// Then
+	async, ok := output.(*log.AsyncWriter)
+	require.True(t, ok)
+	require.NoError(t, async.Flush(context.Background()))
+	require.NoError(t, async.Close())
+	assert.Equal(t, "INFO a\nINFO b\n"+
+		"WARN dropped log entries dropped=1\n", writer.String())
+}
+
+func TestSetupWriterSync(t *testing.T) {
+	// Given
+	config := &log.Config{}
+
+	// When
+	writer := config.SetupWriter(io.Discard)
+
+	// Then
+	assert.Equal(t, io.Discard, writer)
+}
@@ -1,10 +1,18 @@
 package log
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
 	"io"
+	"reflect"
 	"runtime"
 	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+	"unicode/utf8"
 )
 
 // Buffer is the interface for writing bytes and strings.
@@ -36,6 +44,38 @@ func NewBuffer(p *Setup, b BufferWriter) *Buffer {
 	return &Buffer{pretty: p, buffer: b}
 }
 
+// maxPoolBuffer is the maximum capacity of byte buffers returned to the pool
+// to not retain the memory of exceptionally large log entries.
+const maxPoolBuffer = 64 << 10
+
+// bufferPool is the pool of byte buffers used by the pretty formatters.
+var bufferPool = sync.Pool{
+	New: func() any { return &bytes.Buffer{} },
+}
+
+// getBuffer returns an empty byte buffer from the pool.
+func getBuffer() *bytes.Buffer {
+	buffer, _ := bufferPool.Get().(*bytes.Buffer)
+	buffer.Reset()
+	return buffer
+}
+
+// putBuffer returns the given byte buffer to the pool. The buffer must not be
+// used afterwards.
+func putBuffer(buffer *bytes.Buffer) {
+	if buffer.Cap() <= maxPoolBuffer {
+		bufferPool.Put(buffer)
+	}
+}
+
+// format returns the string written by the given write function using a
+// pooled byte buffer.
+func (s *Setup) format(write func(*Buffer) *Buffer) string {
+	buffer := getBuffer()
+	defer putBuffer(buffer)
+	return write(NewBuffer(s, buffer)).String()
+}
+
 // WriteByte writes the given byte to the buffer.
 //
 //nolint:govet // Intentional deviation from the go vet check.
@@ -68,8 +108,8 @@ func (b *Buffer) WriteColored(color, str string) *Buffer {
 		return b
 	}
 
-	// Check if color mode is disabled.
-	if b.pretty.ColorMode == ColorOff {
+	// Check if color mode is disabled or no color is given.
+	if b.pretty.ColorMode.CheckFlag(ColorOff) || color == "" {
 		return b.WriteString(str)
 	}
 
@@ -85,9 +125,23 @@ func (b *Buffer) WriteLevel(level Level) *Buffer {
 
 	if b.pretty.ColorMode.CheckFlag(ColorLevels) {
 		return b.WriteColored(b.pretty.LevelColors[level],
-			b.pretty.LevelNames[level])
+			b.pretty.LevelName(level))
+	}
+	return b.WriteString(b.pretty.LevelName(level))
+}
+
+// WriteMessage writes the given message to the buffer using the color of the
+// given log level, if coloring of messages is enabled.
+func (b *Buffer) WriteMessage(level Level, message string) *Buffer {
+	if b.err != nil {
+		return b
+	}
+
+	if b.pretty.ColorMode.CheckFlag(ColorMessages) {
+		return b.WriteColored(b.pretty.LevelColors[level],
+			b.pretty.Message(message))
 	}
-	return b.WriteString(b.pretty.LevelNames[level])
+	return b.WriteString(b.pretty.Message(message))
 }
 
 // WriteField writes the given key with the given color to the buffer.
@@ -102,6 +156,22 @@ func (b *Buffer) WriteField(level Level, key string) *Buffer {
 	return b.WriteString(key)
 }
 
+// WriteAssign writes the key/value separator of the given field key to the
+// buffer padded to align the field values.
+func (b *Buffer) WriteAssign(key string) *Buffer {
+	if b.err != nil {
+		return b
+	}
+
+	return b.WriteString(b.pretty.KVSep()).
+		WriteString(b.pretty.FieldPadding(key))
+}
+
+// WriteSeparator writes the field separator to the buffer.
+func (b *Buffer) WriteSeparator() *Buffer {
+	return b.WriteString(b.pretty.FieldSep())
+}
+
 // WriteCaller writes the caller information to the buffer.
 func (b *Buffer) WriteCaller(caller *runtime.Frame) *Buffer {
 	if b.err != nil || caller == nil {
@@ -109,15 +179,21 @@ func (b *Buffer) WriteCaller(caller *runtime.Frame) *Buffer {
 	}
 
 	return b.WriteByte(' ').WriteByte('[').
-		WriteString(caller.File).WriteByte(':').
+		WriteString(b.pretty.CallerFile(caller.File)).WriteByte(':').
 		WriteString(strconv.Itoa(caller.Line)).WriteByte('#').
-		WriteString(caller.Function).WriteByte(']')
+		WriteString(b.pretty.CallerFunction(caller.Function)).
+		WriteByte(']')
 }
 
-// WriteString writes the given value to the buffer.
+// WriteValue writes the given value to the buffer. Strings and errors are
+// quoted, times and durations are written in their canonical formats, and
+// maps, slices, and structs as compact JSON. Nil values are written as
+// `<nil>`.
 func (b *Buffer) WriteValue(value any) *Buffer {
 	if b.err != nil {
 		return b
+	} else if isNil(value) {
+		return b.WriteString(NilValue)
 	}
 
 	switch value := value.(type) {
@@ -125,9 +201,26 @@ func (b *Buffer) WriteValue(value any) *Buffer {
 		uint, uint8, uint16, uint32, uint64,
 		float32, float64, complex64, complex128, bool:
 		return b.WriteString(fmt.Sprint(value))
-	default:
-		return b.WriteString(fmt.Sprintf("%q", value))
+	case string:
+		return b.WriteString(b.pretty.Quote(b.pretty.Truncate(value)))
+	case time.Time:
+		return b.WriteString(value.Format(time.RFC3339Nano))
+	case time.Duration:
+		return b.WriteString(value.String())
+	case error:
+		return b.WriteString(b.pretty.Quote(b.pretty.Truncate(value.Error())))
+	case fmt.Stringer:
+		return b.WriteString(b.pretty.QuoteSafe(b.pretty.Truncate(value.String())))
+	}
+
+	switch reflect.ValueOf(value).Kind() {
+	case reflect.Map, reflect.Slice, reflect.Array,
+		reflect.Struct, reflect.Pointer:
+		if data, err := marshalJSON(value); err == nil {
+			return b.WriteString(b.pretty.Truncate(data))
+		}
 	}
+	return b.WriteString(b.pretty.Quote(b.pretty.Truncate(fmt.Sprint(value))))
 }
 
 // WriteData writes the data to the buffer.
@@ -136,13 +229,153 @@ func (b *Buffer) WriteData(key string, value any) *Buffer {
 		return b
 	}
 
+	value, key = b.pretty.Redact(key, value), b.pretty.Escape(key)
 	if key == b.pretty.ErrorName {
-		return b.WriteField(ErrorLevel, key).
-			WriteByte('=').WriteValue(value)
+		b.WriteField(ErrorLevel, key).WriteAssign(key).WriteValue(value)
+		if err, ok := value.(error); ok && b.pretty.ErrorCauses > 0 {
+			for _, cause := range ErrorCauses(err, b.pretty.ErrorCauses) {
+				b.WriteSeparator().WriteField(FieldLevel, FieldCause).
+					WriteAssign(FieldCause).WriteValue(cause)
+			}
+		}
+		return b
 	} else {
 		return b.WriteField(FieldLevel, key).
-			WriteByte('=').WriteValue(value)
+			WriteAssign(key).WriteValue(value)
+	}
+}
+
+// NilValue is the value written for nil values.
+const NilValue = "<nil>"
+
+// isNil returns whether the given value is nil, including typed nil values
+// of pointers, maps, slices, and other nillable kinds.
+func isNil(value any) bool {
+	if value == nil {
+		return true
+	}
+
+	switch value := reflect.ValueOf(value); value.Kind() {
+	case reflect.Pointer, reflect.Map, reflect.Slice, reflect.Interface,
+		reflect.Func, reflect.Chan:
+		return value.IsNil()
+	}
+	return false
+}
+
+// marshalJSON marshals the given value to compact JSON without escaping HTML.
+func marshalJSON(value any) (string, error) {
+	buffer := &strings.Builder{}
+	encoder := json.NewEncoder(buffer)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(value); err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(buffer.String(), "\n"), nil
+}
+
+// Quote quotes the given string value according to the quote mode. Embedded
+// quotes and control characters are escaped when quoting. If values are never
+// quoted, only control characters are escaped.
+func (s *Setup) Quote(str string) string {
+	switch s.QuoteMode {
+	case QuoteNever:
+		return s.Escape(str)
+	case QuoteAuto:
+		if !s.needsQuote(str) {
+			return str
+		}
+		fallthrough
+	default:
+		return strconv.Quote(str)
+	}
+}
+
+// QuoteSafe quotes the given string value only when needed, unless values
+// are never quoted. It is used for values that are not strings by nature,
+// e.g. the results of `fmt.Stringer`.
+func (s *Setup) QuoteSafe(str string) string {
+	if s.QuoteMode == QuoteNever || !s.needsQuote(str) {
+		return s.Escape(str)
+	}
+	return strconv.Quote(str)
+}
+
+// needsQuote returns whether the given string needs to be quoted, i.e. it is
+// empty or contains whitespaces, quotes, equal signs, or control characters.
+func needsQuote(str string) bool {
+	return str == "" || strings.ContainsFunc(str, func(r rune) bool {
+		return r == '"' || r == '=' || unicode.IsSpace(r) ||
+			unicode.IsControl(r) || r == utf8.RuneError
+	})
+}
+
+// needsQuote returns whether the given string needs to be quoted, i.e. it
+// needs to be quoted in general or contains the field or key/value separator.
+func (s *Setup) needsQuote(str string) bool {
+	return needsQuote(str) || strings.Contains(str, s.FieldSep()) ||
+		strings.Contains(str, s.KVSep())
+}
+
+// Truncate truncates the given value to the maximum field length at a rune
+// boundary, marking the number of truncated bytes by an `…(+N)` suffix.
+func (s *Setup) Truncate(value string) string {
+	return truncate(value, s.MaxFieldLength)
+}
+
+// Message returns the given message with embedded newlines replaced by `⏎`,
+// if configured, truncated to the maximum message length, and escaped.
+func (s *Setup) Message(message string) string {
+	if s.CollapseNewlines {
+		message = collapser.Replace(message)
+	}
+	return s.Escape(truncate(message, s.MaxMessageLength))
+}
+
+// Escape returns the given user supplied string with all control characters
+// except tabs escaped, e.g. `\x1b`, to prevent log injection via carriage
+// returns or ANSI escape sequences. If escaping is disabled, the string is
+// returned as is.
+func (s *Setup) Escape(str string) string {
+	if s.DisableEscape || !strings.ContainsFunc(str, isEscaped) {
+		return str
+	}
+
+	builder := strings.Builder{}
+	builder.Grow(len(str) + 8)
+	for _, r := range str {
+		if isEscaped(r) {
+			quoted := strconv.QuoteRune(r)
+			builder.WriteString(quoted[1 : len(quoted)-1])
+		} else {
+			builder.WriteRune(r)
+		}
+	}
+	return builder.String()
+}
+
+// isEscaped returns whether the given rune is a control character that needs
+// to be escaped.
+func isEscaped(r rune) bool {
+	return r != '\t' && unicode.IsControl(r)
+}
+
+// collapser replaces embedded newlines including carriage returns by `⏎`.
+var collapser = strings.NewReplacer("\r\n", "⏎", "\n", "⏎", "\r", "⏎")
+
+// truncate truncates the given value to the given maximum length at a rune
+// boundary, marking the number of truncated bytes by an `…(+N)` suffix. If
+// the maximum length is not positive, the value is returned as is.
+func truncate(value string, limit int) string {
+	if limit <= 0 || len(value) <= limit {
+		return value
+	}
+
+	cut := limit
+	for cut > 0 && !utf8.RuneStart(value[cut]) {
+		cut--
 	}
+	return value[:cut] + "…(+" + strconv.Itoa(len(value)-cut) + ")"
 }
 
 // Bytes returns current bytes of the buffer with the current error.
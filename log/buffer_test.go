@@ -2,8 +2,14 @@ package log_test
 
 import (
 	"bytes"
+	"errors"
+	"io"
+	"strconv"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/rs/zerolog"
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
 	"github.com/tkrop/go-config/log"
@@ -27,6 +33,20 @@ func setupWriter(
 	return &bytes.Buffer{}
 }
 
+// testValue is a struct value used for testing.
+type testValue struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+// testStringer is a stringer used for testing.
+type testStringer string
+
+// String returns the string of the stringer.
+func (s testStringer) String() string {
+	return string(s)
+}
+
 type testBufferWriteParam struct {
 	colorMode    log.ColorModeString
 	error        error
@@ -121,6 +141,34 @@ var testBufferWriteParams = map[string]testBufferWriteParam{
 		},
 		expectString: fieldC("string"),
 	},
+	"write colored no color": {
+		colorMode: log.ColorModeOn,
+		setup: func(buffer *log.Buffer) {
+			buffer.WriteColored("", "string")
+		},
+		expectString: field("string"),
+	},
+	"write colored true color": {
+		colorMode: log.ColorModeOn,
+		setup: func(buffer *log.Buffer) {
+			buffer.WriteColored("38;2;255;128;0", "string")
+		},
+		expectString: "\x1b[38;2;255;128;0mstring\x1b[0m",
+	},
+	"write colored off-levels": {
+		colorMode: "off|levels",
+		setup: func(buffer *log.Buffer) {
+			buffer.WriteColored(log.ColorField, "string")
+		},
+		expectString: fieldC("string"),
+	},
+	"write colored levels-off": {
+		colorMode: "levels|off",
+		setup: func(buffer *log.Buffer) {
+			buffer.WriteColored(log.ColorField, "string")
+		},
+		expectString: field("string"),
+	},
 
 	// Test write level.
 	"write level error": {
@@ -150,6 +198,20 @@ var testBufferWriteParams = map[string]testBufferWriteParam{
 		},
 		expectString: level(log.PanicLevel),
 	},
+	"write level off-levels": {
+		colorMode: "off|levels",
+		setup: func(buffer *log.Buffer) {
+			buffer.WriteLevel(log.PanicLevel)
+		},
+		expectString: levelC(log.PanicLevel),
+	},
+	"write level off-fields": {
+		colorMode: "off|fields",
+		setup: func(buffer *log.Buffer) {
+			buffer.WriteLevel(log.PanicLevel)
+		},
+		expectString: level(log.PanicLevel),
+	},
 
 	// Test write colored field.
 	"write field error": {
@@ -179,6 +241,20 @@ var testBufferWriteParams = map[string]testBufferWriteParam{
 		},
 		expectString: field("value"),
 	},
+	"write field off-fields": {
+		colorMode: "off|fields",
+		setup: func(buffer *log.Buffer) {
+			buffer.WriteField(log.FieldLevel, "value")
+		},
+		expectString: fieldC("value"),
+	},
+	"write field off-levels": {
+		colorMode: "off|levels",
+		setup: func(buffer *log.Buffer) {
+			buffer.WriteField(log.FieldLevel, "value")
+		},
+		expectString: field("value"),
+	},
 
 	// Test write caller.
 	"write caller error": {
@@ -239,6 +315,92 @@ var testBufferWriteParams = map[string]testBufferWriteParam{
 		},
 		expectString: "true",
 	},
+	"write value nil": {
+		setup: func(buffer *log.Buffer) {
+			buffer.WriteValue(nil)
+		},
+		expectString: "<nil>",
+	},
+	"write value nil pointer": {
+		setup: func(buffer *log.Buffer) {
+			buffer.WriteValue((*testValue)(nil))
+		},
+		expectString: "<nil>",
+	},
+	"write value nil map": {
+		setup: func(buffer *log.Buffer) {
+			buffer.WriteValue(map[string]int(nil))
+		},
+		expectString: "<nil>",
+	},
+	"write value map": {
+		setup: func(buffer *log.Buffer) {
+			buffer.WriteValue(map[string]any{"b": 2, "a": "<1>"})
+		},
+		expectString: `{"a":"<1>","b":2}`,
+	},
+	"write value nested map": {
+		setup: func(buffer *log.Buffer) {
+			buffer.WriteValue(map[string]any{
+				"outer": map[string]any{"inner": []int{1, 2}},
+			})
+		},
+		expectString: `{"outer":{"inner":[1,2]}}`,
+	},
+	"write value slice": {
+		setup: func(buffer *log.Buffer) {
+			buffer.WriteValue([]string{"a", "b"})
+		},
+		expectString: `["a","b"]`,
+	},
+	"write value struct": {
+		setup: func(buffer *log.Buffer) {
+			buffer.WriteValue(testValue{Name: "name", Count: 1})
+		},
+		expectString: `{"name":"name","count":1}`,
+	},
+	"write value struct pointer": {
+		setup: func(buffer *log.Buffer) {
+			buffer.WriteValue(&testValue{Name: "name", Count: 1})
+		},
+		expectString: `{"name":"name","count":1}`,
+	},
+	"write value time": {
+		setup: func(buffer *log.Buffer) {
+			buffer.WriteValue(time.Date(2024, 1, 2, 3, 4, 5, 6, time.UTC))
+		},
+		expectString: "2024-01-02T03:04:05.000000006Z",
+	},
+	"write value duration": {
+		setup: func(buffer *log.Buffer) {
+			buffer.WriteValue(1500 * time.Millisecond)
+		},
+		expectString: "1.5s",
+	},
+	"write value stringer safe": {
+		setup: func(buffer *log.Buffer) {
+			buffer.WriteValue(testStringer("safe"))
+		},
+		expectString: "safe",
+	},
+	"write value stringer unsafe": {
+		setup: func(buffer *log.Buffer) {
+			buffer.WriteValue(testStringer("not safe"))
+		},
+		expectString: `"not safe"`,
+	},
+	"write value stringer empty": {
+		setup: func(buffer *log.Buffer) {
+			buffer.WriteValue(testStringer(""))
+		},
+		expectString: `""`,
+	},
+	"write value unsupported": {
+		setup: func(buffer *log.Buffer) {
+			buffer.WriteValue(map[string]any{"complex": 1i})
+		},
+		expectString: `"map[complex:(0+1i)]"`,
+	},
 
 	// Test write data.
 	"write data error": {
@@ -260,6 +422,49 @@ var testBufferWriteParams = map[string]testBufferWriteParam{
 		},
 		expectString: dataC(logrus.ErrorKey, errAny.Error()),
 	},
+	// Test write message.
+	"write message error": {
+		error: errAny,
+		setup: func(buffer *log.Buffer) {
+			buffer.WriteMessage(log.ErrorLevel, "message")
+		},
+		expectError: errAny,
+	},
+	"write message color-on": {
+		colorMode: log.ColorModeOn,
+		setup: func(buffer *log.Buffer) {
+			buffer.WriteMessage(log.ErrorLevel, "message")
+		},
+		expectString: "message",
+	},
+	"write message color-messages error": {
+		colorMode: "on|messages",
+		setup: func(buffer *log.Buffer) {
+			buffer.WriteMessage(log.ErrorLevel, "message")
+		},
+		expectString: messageC(log.ErrorLevel, "message"),
+	},
+	"write message color-messages info": {
+		colorMode: "on|messages",
+		setup: func(buffer *log.Buffer) {
+			buffer.WriteMessage(log.InfoLevel, "message")
+		},
+		expectString: messageC(log.InfoLevel, "message"),
+	},
+	"write message off-messages": {
+		colorMode: "off|messages",
+		setup: func(buffer *log.Buffer) {
+			buffer.WriteMessage(log.InfoLevel, "message")
+		},
+		expectString: messageC(log.InfoLevel, "message"),
+	},
+	"write message color-off": {
+		colorMode: log.ColorModeOff,
+		setup: func(buffer *log.Buffer) {
+			buffer.WriteMessage(log.ErrorLevel, "message")
+		},
+		expectString: "message",
+	},
 	"write data color-on": {
 		colorMode: log.ColorModeOn,
 		setup: func(buffer *log.Buffer) {
@@ -274,6 +479,55 @@ var testBufferWriteParams = map[string]testBufferWriteParam{
 		},
 		expectString: data("key", "value"),
 	},
+	"write data int": {
+		colorMode: log.ColorModeOff,
+		setup: func(buffer *log.Buffer) {
+			buffer.WriteData("count", 42)
+		},
+		expectString: "count=42",
+	},
+	"write data float": {
+		colorMode: log.ColorModeOff,
+		setup: func(buffer *log.Buffer) {
+			buffer.WriteData("ratio", 1.5)
+		},
+		expectString: "ratio=1.5",
+	},
+	"write data bool": {
+		colorMode: log.ColorModeOff,
+		setup: func(buffer *log.Buffer) {
+			buffer.WriteData("flag", true)
+		},
+		expectString: "flag=true",
+	},
+	"write data nil": {
+		colorMode: log.ColorModeOff,
+		setup: func(buffer *log.Buffer) {
+			buffer.WriteData("key", nil)
+		},
+		expectString: "key=" + log.NilValue,
+	},
+	"write data struct": {
+		colorMode: log.ColorModeOff,
+		setup: func(buffer *log.Buffer) {
+			buffer.WriteData("key", struct{ A int }{A: 1})
+		},
+		expectString: `key={"A":1}`,
+	},
+	"write data error string": {
+		colorMode: log.ColorModeOff,
+		setup: func(buffer *log.Buffer) {
+			buffer.WriteData(log.DefaultErrorName, "failure")
+		},
+		expectString: data(log.DefaultErrorName, "failure"),
+	},
+	"write data error int": {
+		colorMode: log.ColorModeOff,
+		setup: func(buffer *log.Buffer) {
+			buffer.WriteData(log.DefaultErrorName, 42)
+		},
+		expectString: log.DefaultErrorName + "=42",
+	},
 }
 
 func TestBufferWrite(t *testing.T) {
@@ -304,3 +558,546 @@ func TestBufferWrite(t *testing.T) {
 			}
 		})
 }
+
+type testColorOffParam struct {
+	mode log.ColorMode
+}
+
+var testColorOffParams = map[string]testColorOffParam{
+	"off":          {mode: log.ColorOff},
+	"off levels":   {mode: log.ColorOff | log.ColorLevels},
+	"off fields":   {mode: log.ColorOff | log.ColorFields},
+	"off messages": {mode: log.ColorOff | log.ColorMessages},
+	"off on":       {mode: log.ColorOff | log.ColorOn | log.ColorMessages},
+}
+
+func TestColorOff(t *testing.T) {
+	test.Map(t, testColorOffParams).
+		Run(func(t test.Test, param testColorOffParam) {
+			// Given
+			pretty := &log.Setup{
+				ColorMode:   param.mode,
+				ErrorName:   log.DefaultErrorName,
+				LevelNames:  log.DefaultLevelNames,
+				LevelColors: log.DefaultLevelColors,
+			}
+			buffer := log.NewBuffer(pretty, &bytes.Buffer{})
+
+			// When
+			buffer.WriteColored(log.ColorError, "colored").WriteByte(' ').
+				WriteLevel(log.ErrorLevel).WriteByte(' ').
+				WriteMessage(log.ErrorLevel, "message").WriteByte(' ').
+				WriteData("key", "value")
+
+			// Then
+			assert.Equal(t, "colored ERROR message key=\"value\"",
+				buffer.String())
+			assert.Equal(t, "ERROR", pretty.FormatLevel("error"))
+			assert.Equal(t, "key=", pretty.FormatFieldName("key"))
+			assert.Equal(t, "error=", pretty.FormatErrFieldName("error"))
+		})
+}
+
+type testTruncateParam struct {
+	limit  int
+	value  string
+	expect string
+}
+
+var testTruncateParams = map[string]testTruncateParam{
+	"unlimited": {
+		value:  "value",
+		expect: "value",
+	},
+	"negative": {
+		limit:  -1,
+		value:  "value",
+		expect: "value",
+	},
+	"shorter": {
+		limit:  6,
+		value:  "value",
+		expect: "value",
+	},
+	"exact": {
+		limit:  5,
+		value:  "value",
+		expect: "value",
+	},
+	"longer": {
+		limit:  3,
+		value:  "value",
+		expect: "val…(+2)",
+	},
+	"multi-byte boundary": {
+		limit:  3,
+		value:  "añbc",
+		expect: "añ…(+2)",
+	},
+	"multi-byte inside": {
+		limit:  2,
+		value:  "añbc",
+		expect: "a…(+4)",
+	},
+	"multi-byte first": {
+		limit:  2,
+		value:  "€uro",
+		expect: "…(+6)",
+	},
+}
+
+func TestTruncate(t *testing.T) {
+	test.Map(t, testTruncateParams).
+		Run(func(t test.Test, param testTruncateParam) {
+			// Given
+			setup := &log.Setup{MaxFieldLength: param.limit}
+
+			// When
+			value := setup.Truncate(param.value)
+
+			// Then
+			assert.Equal(t, param.expect, value)
+		})
+}
+
+type testMaxFieldLengthParam struct {
+	formatter  log.Formatter
+	expectRus  string
+	expectZero string
+}
+
+var testMaxFieldLengthParams = map[string]testMaxFieldLengthParam{
+	"pretty": {
+		formatter: log.FormatterPretty,
+		expectRus: "INFO info message error=\"connecti…(+8)\" " +
+			"count=123456789 flag=true key=\"grüße …(+4)\"\n",
+		expectZero: "INFO info message error=\"connecti…(+8)\" " +
+			"count=123456789 flag=true key=\"grüße …(+4)\"\n",
+	},
+	"json": {
+		formatter: log.FormatterJSON,
+		expectRus: `{"count":123456789,"error":"connection reset",` +
+			`"flag":true,"key":"grüße welt","level":"info",` +
+			`"msg":"info message"}` + "\n",
+		expectZero: `{"level":"info","error":"connection reset",` +
+			`"count":123456789,"flag":true,"key":"grüße welt",` +
+			`"message":"info message"}` + "\n",
+	},
+}
+
+func TestMaxFieldLength(t *testing.T) {
+	test.Map(t, testMaxFieldLengthParams).
+		Run(func(t test.Test, param testMaxFieldLengthParam) {
+			// Given
+			config := &log.Config{
+				Level:          log.LevelInfo,
+				Formatter:      param.formatter,
+				TimeFormat:     log.TimeFormatNone,
+				ColorMode:      log.ColorModeOff,
+				OrderMode:      log.OrderModeOn,
+				MaxFieldLength: 8,
+			}
+			rus, zero := &bytes.Buffer{}, &bytes.Buffer{}
+			rlogger := config.SetupRus(rus, logrus.New())
+			zlogger := config.SetupZero(zero).ZeroLogger()
+			err := errors.New("connection reset")
+
+			// When
+			rlogger.WithError(err).WithFields(logrus.Fields{
+				"count": 123456789, "flag": true, "key": "grüße welt",
+			}).Info("info message")
+			zlogger.Info().Err(err).Int("count", 123456789).
+				Bool("flag", true).Str("key", "grüße welt").
+				Msg("info message")
+
+			// Then
+			assert.Equal(t, param.expectRus, rus.String())
+			assert.Equal(t, param.expectZero, zero.String())
+		})
+}
+
+// panicMessage is a multi-line panic-style message used for testing.
+const panicMessage = "panic: runtime error\r\n\ngoroutine 1 [running]:\n" +
+	"main.main()\n\t/app/main.go:12 +0x1d"
+
+type testMessageParam struct {
+	formatter  log.Formatter
+	limit      int
+	collapse   bool
+	expectRus  string
+	expectZero string
+}
+
+var testMessageParams = map[string]testMessageParam{
+	"pretty collapse": {
+		formatter: log.FormatterPretty,
+		collapse:  true,
+		expectRus: "ERROR panic: runtime error⏎⏎goroutine 1 [running]:⏎" +
+			"main.main()⏎\t/app/main.go:12 +0x1d\n",
+		expectZero: "ERROR panic: runtime error⏎⏎goroutine 1 [running]:⏎" +
+			"main.main()⏎\t/app/main.go:12 +0x1d\n",
+	},
+	"pretty collapse limit": {
+		formatter:  log.FormatterPretty,
+		limit:      24,
+		collapse:   true,
+		expectRus:  "ERROR panic: runtime error⏎…(+64)\n",
+		expectZero: "ERROR panic: runtime error⏎…(+64)\n",
+	},
+	"pretty limit": {
+		formatter:  log.FormatterPretty,
+		limit:      20,
+		expectRus:  "ERROR panic: runtime error…(+60)\n",
+		expectZero: "ERROR panic: runtime error…(+60)\n",
+	},
+	"json collapse limit": {
+		formatter: log.FormatterJSON,
+		limit:     20,
+		collapse:  true,
+		expectRus: `{"level":"error","msg":"panic: runtime error\r\n\n` +
+			`goroutine 1 [running]:\nmain.main()\n\t/app/main.go:12 +0x1d"}` +
+			"\n",
+		expectZero: `{"level":"error","message":"panic: runtime error\r\n\n` +
+			`goroutine 1 [running]:\nmain.main()\n\t/app/main.go:12 +0x1d"}` +
+			"\n",
+	},
+}
+
+func TestMessage(t *testing.T) {
+	test.Map(t, testMessageParams).
+		Run(func(t test.Test, param testMessageParam) {
+			// Given
+			config := &log.Config{
+				Level:            log.LevelInfo,
+				Formatter:        param.formatter,
+				TimeFormat:       log.TimeFormatNone,
+				ColorMode:        log.ColorModeOff,
+				MaxMessageLength: param.limit,
+				CollapseNewlines: param.collapse,
+			}
+			rus, zero := &bytes.Buffer{}, &bytes.Buffer{}
+			rlogger := config.SetupRus(rus, logrus.New())
+			zlogger := config.SetupZero(zero).ZeroLogger()
+
+			// When
+			rlogger.Error(panicMessage)
+			zlogger.Error().Msg(panicMessage)
+
+			// Then
+			assert.Equal(t, param.expectRus, rus.String())
+			assert.Equal(t, param.expectZero, zero.String())
+		})
+}
+
+type testEscapeParam struct {
+	disable bool
+	value   string
+	expect  string
+}
+
+var testEscapeParams = map[string]testEscapeParam{
+	"plain": {
+		value:  "plain välue",
+		expect: "plain välue",
+	},
+	"tab": {
+		value:  "tab\tvalue",
+		expect: "tab\tvalue",
+	},
+	"newlines": {
+		value:  "line\r\nline",
+		expect: `line\r\nline`,
+	},
+	"ansi escape": {
+		value:  "\x1b[31mred\x1b[0m",
+		expect: `\x1b[31mred\x1b[0m`,
+	},
+	"c1 control": {
+		value:  "next\u0085line\x7f",
+		expect: `next\u0085line\x7f`,
+	},
+	"disabled": {
+		disable: true,
+		value:   "\x1b[31mred\r",
+		expect:  "\x1b[31mred\r",
+	},
+}
+
+func TestEscape(t *testing.T) {
+	test.Map(t, testEscapeParams).
+		Run(func(t test.Test, param testEscapeParam) {
+			// Given
+			setup := &log.Setup{DisableEscape: param.disable}
+
+			// When
+			value := setup.Escape(param.value)
+
+			// Then
+			assert.Equal(t, param.expect, value)
+		})
+}
+
+type testEscapeInjectionParam struct {
+	disable    bool
+	expectRus  string
+	expectZero string
+}
+
+var testEscapeInjectionParams = map[string]testEscapeInjectionParam{
+	"escaped": {
+		expectRus: `INFO login\r\nINFO fake \x1b[2K ` +
+			`user\nINFO="x" value="alice\nINFO admin=\"true\""` + "\n",
+		expectZero: `INFO login\r\nINFO fake \x1b[2K ` +
+			`user\nINFO="x" value="alice\nINFO admin=\"true\""` + "\n",
+	},
+	"disabled": {
+		disable: true,
+		expectRus: "INFO login\r\nINFO fake \x1b[2K " +
+			"user\nINFO=\"x\" value=\"alice\\nINFO admin=\\\"true\\\"\"\n",
+		expectZero: "INFO login\r\nINFO fake \x1b[2K " +
+			"user\nINFO=\"x\" value=\"alice\\nINFO admin=\\\"true\\\"\"\n",
+	},
+}
+
+func TestEscapeInjection(t *testing.T) {
+	test.Map(t, testEscapeInjectionParams).
+		Run(func(t test.Test, param testEscapeInjectionParam) {
+			// Given
+			config := &log.Config{
+				Level:         log.LevelInfo,
+				TimeFormat:    log.TimeFormatNone,
+				ColorMode:     log.ColorModeOff,
+				OrderMode:     log.OrderModeOn,
+				DisableEscape: param.disable,
+			}
+			rus, zero := &bytes.Buffer{}, &bytes.Buffer{}
+			rlogger := config.SetupRus(rus, logrus.New())
+			zlogger := config.SetupZero(zero).ZeroLogger()
+			message := "login\r\nINFO fake \x1b[2K"
+			value := "alice\nINFO admin=\"true\""
+
+			// When
+			rlogger.WithField("value", value).WithField("user\nINFO", "x").
+				Info(message)
+			zlogger.Info().Str("value", value).Str("user\nINFO", "x").
+				Msg(message)
+
+			// Then
+			assert.Equal(t, param.expectRus, rus.String())
+			assert.Equal(t, param.expectZero, zero.String())
+		})
+}
+
+type testPrettyValueParam struct {
+	value      any
+	expectRus  string
+	expectZero string
+}
+
+var testPrettyValueParams = map[string]testPrettyValueParam{
+	"nil": {
+		value:      nil,
+		expectRus:  "INFO info message key=<nil>\n",
+		expectZero: "INFO info message key=<nil>\n",
+	},
+	"nested map": {
+		value: map[string]any{
+			"outer": map[string]any{"inner": []int{1, 2}},
+		},
+		expectRus:  "INFO info message key={\"outer\":{\"inner\":[1,2]}}\n",
+		expectZero: "INFO info message key={\"outer\":{\"inner\":[1,2]}}\n",
+	},
+	"slice": {
+		value:      []string{"a", "b"},
+		expectRus:  "INFO info message key=[\"a\",\"b\"]\n",
+		expectZero: "INFO info message key=[\"a\",\"b\"]\n",
+	},
+	"struct": {
+		value:      testValue{Name: "name", Count: 1},
+		expectRus:  "INFO info message key={\"name\":\"name\",\"count\":1}\n",
+		expectZero: "INFO info message key={\"count\":1,\"name\":\"name\"}\n",
+	},
+}
+
+func TestPrettyValue(t *testing.T) {
+	test.Map(t, testPrettyValueParams).
+		Run(func(t test.Test, param testPrettyValueParam) {
+			// Given
+			config := &log.Config{
+				Level:      log.LevelInfo,
+				TimeFormat: log.TimeFormatNone,
+				ColorMode:  log.ColorModeOff,
+			}
+			rus, zero := &bytes.Buffer{}, &bytes.Buffer{}
+			rlogger := config.SetupRus(rus, logrus.New())
+			zlogger := config.SetupZero(zero).ZeroLogger()
+
+			// When
+			rlogger.WithField("key", param.value).Info("info message")
+			zlogger.Info().Interface("key", param.value).Msg("info message")
+
+			// Then
+			assert.Equal(t, param.expectRus, rus.String())
+			assert.Equal(t, param.expectZero, zero.String())
+		})
+}
+
+type testQuoteModeParam struct {
+	mode   log.QuoteModeString
+	expect string
+}
+
+var testQuoteModeParams = map[string]testQuoteModeParam{
+	"always": {
+		mode: log.QuoteModeAlways,
+		expect: `INFO info message assign="a=b" count=42 empty="" ` +
+			`flag=true plain="plain" quote="say \"hi\"" space="a b"` + "\n",
+	},
+	"auto": {
+		mode: log.QuoteModeAuto,
+		expect: `INFO info message assign="a=b" count=42 empty="" ` +
+			`flag=true plain=plain quote="say \"hi\"" space="a b"` + "\n",
+	},
+	"never": {
+		mode: log.QuoteModeNever,
+		expect: `INFO info message assign=a=b count=42 empty= ` +
+			`flag=true plain=plain quote=say "hi" space=a b` + "\n",
+	},
+	"never-mixed-case": {
+		mode: " Never ",
+		expect: `INFO info message assign=a=b count=42 empty= ` +
+			`flag=true plain=plain quote=say "hi" space=a b` + "\n",
+	},
+	"default": {
+		expect: `INFO info message assign="a=b" count=42 empty="" ` +
+			`flag=true plain="plain" quote="say \"hi\"" space="a b"` + "\n",
+	},
+}
+
+func TestQuoteMode(t *testing.T) {
+	test.Map(t, testQuoteModeParams).
+		Run(func(t test.Test, param testQuoteModeParam) {
+			// Given
+			config := &log.Config{
+				Level:      log.LevelInfo,
+				TimeFormat: log.TimeFormatNone,
+				ColorMode:  log.ColorModeOff,
+				OrderMode:  log.OrderModeOn,
+				QuoteMode:  param.mode,
+			}
+			rus, zero := &bytes.Buffer{}, &bytes.Buffer{}
+			rlogger := config.SetupRus(rus, logrus.New())
+			zlogger := config.SetupZero(zero).ZeroLogger()
+			fields := map[string]any{
+				"plain": "plain", "space": "a b", "assign": "a=b",
+				"quote": `say "hi"`, "empty": "", "count": 42, "flag": true,
+			}
+
+			// When
+			rlogger.WithFields(fields).Info("info message")
+			zlogger.Info().Fields(fields).Msg("info message")
+
+			// Then
+			assert.Equal(t, param.expect, rus.String())
+			assert.Equal(t, param.expect, zero.String())
+		})
+}
+
+type testPrettyScalarParam struct {
+	field  func(*zerolog.Event) *zerolog.Event
+	value  any
+	expect string
+}
+
+var testPrettyScalarParams = map[string]testPrettyScalarParam{
+	"int": {
+		field:  func(e *zerolog.Event) *zerolog.Event { return e.Int("key", 42) },
+		value:  42,
+		expect: "INFO info message key=42\n",
+	},
+	"int negative": {
+		field:  func(e *zerolog.Event) *zerolog.Event { return e.Int("key", -7) },
+		value:  -7,
+		expect: "INFO info message key=-7\n",
+	},
+	"float": {
+		field:  func(e *zerolog.Event) *zerolog.Event { return e.Float64("key", 1.5) },
+		value:  1.5,
+		expect: "INFO info message key=1.5\n",
+	},
+	"bool true": {
+		field:  func(e *zerolog.Event) *zerolog.Event { return e.Bool("key", true) },
+		value:  true,
+		expect: "INFO info message key=true\n",
+	},
+	"bool false": {
+		field:  func(e *zerolog.Event) *zerolog.Event { return e.Bool("key", false) },
+		value:  false,
+		expect: "INFO info message key=false\n",
+	},
+	"nil": {
+		field:  func(e *zerolog.Event) *zerolog.Event { return e.Interface("key", nil) },
+		value:  nil,
+		expect: "INFO info message key=<nil>\n",
+	},
+	"string number": {
+		field:  func(e *zerolog.Event) *zerolog.Event { return e.Str("key", "42") },
+		value:  "42",
+		expect: "INFO info message key=\"42\"\n",
+	},
+}
+
+func TestPrettyScalar(t *testing.T) {
+	test.Map(t, testPrettyScalarParams).
+		Run(func(t test.Test, param testPrettyScalarParam) {
+			// Given
+			config := &log.Config{
+				Level:      log.LevelInfo,
+				TimeFormat: log.TimeFormatNone,
+				ColorMode:  log.ColorModeOff,
+			}
+			rus, zero := &bytes.Buffer{}, &bytes.Buffer{}
+			rlogger := config.SetupRus(rus, logrus.New())
+			zlogger := config.SetupZero(zero).ZeroLogger()
+
+			// When
+			rlogger.WithField("key", param.value).Info("info message")
+			param.field(zlogger.Info()).Msg("info message")
+
+			// Then
+			assert.Equal(t, param.expect, rus.String())
+			assert.Equal(t, param.expect, zero.String())
+		})
+}
+
+func TestBufferPool(t *testing.T) {
+	// Given
+	pretty := log.NewLogRusPretty(&log.Config{
+		TimeFormat: log.TimeFormatNone,
+		ColorMode:  log.ColorModeOff,
+	}, io.Discard)
+	results := make([][]byte, 64)
+
+	// When
+	wg := sync.WaitGroup{}
+	for index := range results {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			result, err := pretty.Format(&logrus.Entry{
+				Level:   logrus.InfoLevel,
+				Message: "info message",
+				Data:    logrus.Fields{"index": index},
+			})
+			assert.NoError(t, err)
+			results[index] = result
+		}()
+	}
+	wg.Wait()
+
+	// Then
+	for index, result := range results {
+		assert.Equal(t, "INFO info message index="+
+			strconv.Itoa(index)+"\n", string(result))
+	}
+}
@@ -0,0 +1,84 @@
+package log
+
+import (
+	"github.com/rs/zerolog"
+	"github.com/sirupsen/logrus"
+)
+
+// IsCallerEnabled returns whether the caller is reported for log entries,
+// i.e. for all levels or at or above the configured caller level.
+func (c *Config) IsCallerEnabled() bool {
+	return c.Caller || c.CallerLevel != ""
+}
+
+// IsCallerLevelEnabled returns whether the caller is reported only for log
+// entries at or above the configured caller level.
+func (c *Config) IsCallerLevelEnabled() bool {
+	return !c.Caller && c.CallerLevel != ""
+}
+
+// ParseCallerLevel returns the level at or above which the caller is
+// reported. If the caller is reported for all levels, the trace level is
+// returned.
+func (c *Config) ParseCallerLevel() Level {
+	if !c.IsCallerLevelEnabled() {
+		return TraceLevel
+	}
+	return ParseLevel(c.CallerLevel)
+}
+
+// LogRusCallerFormatter is a formatter wrapper reporting the caller set up by
+// the caller hook for log entries at or above the caller level, since the
+// logrus formatters only report the caller, if the report caller flag of the
+// logger is set, that would capture the caller for all log entries.
+type LogRusCallerFormatter struct {
+	logrus.Formatter
+}
+
+// Format formats the log entry using the wrapped formatter reporting the
+// caller of the entry, if present.
+func (f *LogRusCallerFormatter) Format(entry *logrus.Entry) ([]byte, error) {
+	if entry.Caller == nil || entry.HasCaller() {
+		return f.Formatter.Format(entry)
+	}
+
+	clone := *entry
+	clone.Logger = &logrus.Logger{ReportCaller: true}
+	if entry.Logger != nil {
+		clone.Logger.Out = entry.Logger.Out
+	}
+	return f.Formatter.Format(&clone)
+}
+
+// ZeroLogCallerHook is a hook attaching the caller to log events at or above
+// the configured caller level.
+type ZeroLogCallerHook struct {
+	// level is the level at or above which the caller is attached.
+	level Level
+	// skip is the number of additional caller frames to skip.
+	skip int
+}
+
+// NewZeroLogCallerHook creates a new caller hook for zerolog using the given
+// config.
+func NewZeroLogCallerHook(c *Config) *ZeroLogCallerHook {
+	return &ZeroLogCallerHook{
+		level: c.ParseCallerLevel(),
+		skip:  c.CallerSkip,
+	}
+}
+
+// Run attaches the caller to the given log event, if the level is at or above
+// the configured caller level.
+func (h *ZeroLogCallerHook) Run(
+	event *zerolog.Event, level zerolog.Level, _ string,
+) {
+	if level == zerolog.NoLevel || level == zerolog.Disabled ||
+		ZeroLevel(level) > h.level {
+		return
+	}
+
+	frame := CallerFrame(h.skip)
+	event.Str(zerolog.CallerFieldName,
+		zerolog.CallerMarshalFunc(frame.PC, frame.File, frame.Line))
+}
@@ -0,0 +1,145 @@
+package log_test
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/tkrop/go-testing/test"
+
+	"github.com/tkrop/go-config/log"
+)
+
+// callerFile is the file of the call site reported as caller.
+const callerFile = "caller_test.go"
+
+// logCaller logs a message with the given level via the given loggers.
+func logCaller(rus *logrus.Logger, zero zerolog.Logger, level log.Level) {
+	rus.Log(logrus.Level(level), "message")
+	zero.WithLevel(log.ToZeroLevel(level)).Msg("message")
+}
+
+type testCallerLevelParam struct {
+	formatter log.Formatter
+	caller    bool
+	level     log.Level
+	expect    bool
+}
+
+var testCallerLevelParams = map[string]testCallerLevelParam{
+	"pretty error": {
+		formatter: log.FormatterPretty,
+		level:     log.ErrorLevel,
+		expect:    true,
+	},
+	"pretty warn": {
+		formatter: log.FormatterPretty,
+		level:     log.WarnLevel,
+		expect:    true,
+	},
+	"pretty info": {
+		formatter: log.FormatterPretty,
+		level:     log.InfoLevel,
+	},
+	"pretty info caller": {
+		formatter: log.FormatterPretty,
+		caller:    true,
+		level:     log.InfoLevel,
+		expect:    true,
+	},
+	"text warn": {
+		formatter: log.FormatterText,
+		level:     log.WarnLevel,
+		expect:    true,
+	},
+	"text info": {
+		formatter: log.FormatterText,
+		level:     log.InfoLevel,
+	},
+	"json error": {
+		formatter: log.FormatterJSON,
+		level:     log.ErrorLevel,
+		expect:    true,
+	},
+	"json info": {
+		formatter: log.FormatterJSON,
+		level:     log.InfoLevel,
+	},
+	"json info caller": {
+		formatter: log.FormatterJSON,
+		caller:    true,
+		level:     log.InfoLevel,
+		expect:    true,
+	},
+}
+
+func TestCallerLevel(t *testing.T) {
+	test.Map(t, testCallerLevelParams).
+		Run(func(t test.Test, param testCallerLevelParam) {
+			// Given
+			config := &log.Config{
+				Level:       log.LevelInfo,
+				Formatter:   param.formatter,
+				TimeFormat:  log.TimeFormatNone,
+				ColorMode:   log.ColorModeOff,
+				Caller:      param.caller,
+				CallerLevel: log.LevelWarn,
+			}
+			rus, zero := &bytes.Buffer{}, &bytes.Buffer{}
+			rlogger := config.SetupRus(rus, logrus.New())
+			zlogger := config.SetupZero(zero).ZeroLogger()
+
+			// When
+			logCaller(rlogger, zlogger, param.level)
+
+			// Then
+			for _, output := range []string{rus.String(), zero.String()} {
+				assert.Equal(t, param.expect,
+					strings.Contains(output, callerFile), output)
+			}
+			assert.Equal(t, param.caller, rlogger.ReportCaller)
+		})
+}
+
+// benchmarkCallerLevel benchmarks logging info entries via the given logger
+// set up using the given caller config.
+func benchmarkCallerLevel(b *testing.B, caller bool, level string) {
+	config := &log.Config{
+		Level:       log.LevelInfo,
+		TimeFormat:  log.TimeFormatNone,
+		ColorMode:   log.ColorModeOff,
+		Caller:      caller,
+		CallerLevel: level,
+	}
+	rus := config.SetupRus(io.Discard, logrus.New())
+	zero := config.SetupZero(io.Discard).ZeroLogger()
+
+	b.Run("logrus", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			rus.Info("message")
+		}
+	})
+	b.Run("zerolog", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			zero.Info().Msg("message")
+		}
+	})
+}
+
+func BenchmarkCallerLevel(b *testing.B) {
+	b.Run("none", func(b *testing.B) {
+		benchmarkCallerLevel(b, false, "")
+	})
+	b.Run("warn", func(b *testing.B) {
+		benchmarkCallerLevel(b, false, log.LevelWarn)
+	})
+	b.Run("all", func(b *testing.B) {
+		benchmarkCallerLevel(b, true, "")
+	})
+}
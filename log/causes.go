@@ -0,0 +1,135 @@
+package log
+
+import (
+	"encoding/json"
+	"strconv"
+
+	"github.com/rs/zerolog"
+	"github.com/sirupsen/logrus"
+)
+
+// FieldCause is the field name of the error causes printed by the pretty
+// formatters.
+const FieldCause = "cause"
+
+// ErrorChain is the error representation used by the JSON formatters when
+// error causes are expanded.
+type ErrorChain struct {
+	// Message is the message of the error.
+	Message string `json:"message"`
+	// Causes are the messages of the wrapped causes of the error.
+	Causes []string `json:"causes,omitempty"`
+}
+
+// ErrorCauses returns the messages of the wrapped causes of the given error
+// expanding the unwrap chain up to the given depth. Joined errors list all
+// branches in order. If the depth is not positive, no causes are returned.
+func ErrorCauses(err error, depth int) []string {
+	causes := []string{}
+	var expand func(err error, level int)
+	expand = func(err error, level int) {
+		if level > depth {
+			return
+		}
+
+		var errs []error
+		switch err := err.(type) {
+		case interface{ Unwrap() []error }:
+			errs = err.Unwrap()
+		case interface{ Unwrap() error }:
+			if cause := err.Unwrap(); cause != nil {
+				errs = []error{cause}
+			}
+		}
+		for _, cause := range errs {
+			causes = append(causes, cause.Error())
+			expand(cause, level+1)
+		}
+	}
+	expand(err, 1)
+	return causes
+}
+
+// NewErrorChain creates the error representation of the given error with its
+// wrapped causes up to the given depth. If the error has no causes, nil is
+// returned.
+func NewErrorChain(err error, depth int) *ErrorChain {
+	causes := ErrorCauses(err, depth)
+	if len(causes) == 0 {
+		return nil
+	}
+	return &ErrorChain{Message: err.Error(), Causes: causes}
+}
+
+// zeroErrorMarshal is the original zerolog error marshal function.
+var zeroErrorMarshal = zerolog.ErrorMarshalFunc
+
+// zeroErrorCauses is the depth of error causes set up for zerolog.
+var zeroErrorCauses = 0
+
+// setupZeroErrorCauses sets up the global zerolog error marshal function to
+// expand error causes up to the configured depth. The global function is
+// only replaced when the depth changes.
+func (c *Config) setupZeroErrorCauses() {
+	depth := max(c.ErrorCauses, 0)
+	if zeroErrorCauses == depth {
+		return
+	}
+
+	zeroErrorCauses = depth
+	if depth == 0 {
+		zerolog.ErrorMarshalFunc = zeroErrorMarshal
+		return
+	}
+	zerolog.ErrorMarshalFunc = func(err error) any {
+		if chain := NewErrorChain(err, depth); chain != nil {
+			return chain
+		}
+		return zeroErrorMarshal(err)
+	}
+}
+
+// FormatErrorChain formats the given raw JSON error representation of the
+// zerolog console writer as quoted error message followed by the error
+// causes. If the value is not an error chain, false is returned.
+func (s *Setup) FormatErrorChain(raw []byte) (string, bool) {
+	chain := ErrorChain{}
+	if err := json.Unmarshal(raw, &chain); err != nil || len(chain.Causes) == 0 {
+		return "", false
+	}
+
+	result := strconv.Quote(s.Truncate(chain.Message))
+	for _, cause := range chain.Causes {
+		result += s.FieldSep() + s.FormatFieldName(FieldCause) +
+			strconv.Quote(s.Truncate(cause))
+	}
+	return result, true
+}
+
+// LogRusCausesHook is a hook replacing error values by their error chain
+// including the wrapped causes for the JSON formatter.
+type LogRusCausesHook struct {
+	// depth is the depth of the expanded error causes.
+	depth int
+}
+
+// NewLogRusCausesHook creates a new error causes hook for logrus using the
+// given depth.
+func NewLogRusCausesHook(depth int) *LogRusCausesHook {
+	return &LogRusCausesHook{depth: depth}
+}
+
+// Levels returns all log levels, since errors can be logged on all levels.
+func (*LogRusCausesHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+// Fire replaces the error value of the given log entry by its error chain.
+func (h *LogRusCausesHook) Fire(entry *logrus.Entry) error {
+	if err, ok := entry.Data[logrus.ErrorKey].(error); ok {
+		if chain := NewErrorChain(err, h.depth); chain != nil {
+			entry.Data[logrus.ErrorKey] = chain
+		}
+	}
+	return nil
+}
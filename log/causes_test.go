@@ -0,0 +1,198 @@
+package log_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/tkrop/go-testing/test"
+
+	"github.com/tkrop/go-config/log"
+)
+
+var (
+	// errRefused is the root cause of the wrapped errors used for testing.
+	errRefused = errors.New("connection refused")
+	// errWrapped is the three-level wrapped error used for testing.
+	errWrapped = fmt.Errorf("failed to save: %w",
+		fmt.Errorf("db: %w", errRefused))
+	// errJoined is the joined error used for testing.
+	errJoined = fmt.Errorf("failed to sync: %w", errors.Join(
+		fmt.Errorf("replica-1: %w", errRefused),
+		errors.New("replica-2: timeout")))
+)
+
+type testErrorCausesParam struct {
+	err    error
+	depth  int
+	expect []string
+}
+
+var testErrorCausesParams = map[string]testErrorCausesParam{
+	"disabled": {
+		err:    errWrapped,
+		expect: []string{},
+	},
+	"no causes": {
+		err:    errRefused,
+		depth:  3,
+		expect: []string{},
+	},
+	"wrapped depth-1": {
+		err:    errWrapped,
+		depth:  1,
+		expect: []string{"db: connection refused"},
+	},
+	"wrapped depth-3": {
+		err:    errWrapped,
+		depth:  3,
+		expect: []string{"db: connection refused", "connection refused"},
+	},
+	"joined depth-2": {
+		err:   errJoined,
+		depth: 2,
+		expect: []string{
+			"replica-1: connection refused\nreplica-2: timeout",
+			"replica-1: connection refused", "replica-2: timeout",
+		},
+	},
+	"joined depth-3": {
+		err:   errJoined,
+		depth: 3,
+		expect: []string{
+			"replica-1: connection refused\nreplica-2: timeout",
+			"replica-1: connection refused", "connection refused",
+			"replica-2: timeout",
+		},
+	},
+}
+
+func TestErrorCauses(t *testing.T) {
+	test.Map(t, testErrorCausesParams).
+		Run(func(t test.Test, param testErrorCausesParam) {
+			// Given
+			err := param.err
+
+			// When
+			causes := log.ErrorCauses(err, param.depth)
+
+			// Then
+			assert.Equal(t, param.expect, causes)
+		})
+}
+
+type testErrorChainParam struct {
+	formatter  log.Formatter
+	depth      int
+	err        error
+	expectRus  string
+	expectZero string
+}
+
+var testErrorChainParams = map[string]testErrorChainParam{
+	"pretty disabled": {
+		formatter: log.FormatterPretty,
+		err:       errWrapped,
+		expectRus: "ERROR failed error=\"" +
+			"failed to save: db: connection refused\"\n",
+		expectZero: "ERROR failed error=\"" +
+			"failed to save: db: connection refused\"\n",
+	},
+	"pretty wrapped": {
+		formatter: log.FormatterPretty,
+		depth:     3,
+		err:       errWrapped,
+		expectRus: "ERROR failed error=\"" +
+			"failed to save: db: connection refused\" " +
+			"cause=\"db: connection refused\" cause=\"connection refused\"\n",
+		expectZero: "ERROR failed error=\"" +
+			"failed to save: db: connection refused\" " +
+			"cause=\"db: connection refused\" cause=\"connection refused\"\n",
+	},
+	"pretty joined": {
+		formatter: log.FormatterPretty,
+		depth:     2,
+		err:       errJoined,
+		expectRus: "ERROR failed error=\"failed to sync: " +
+			"replica-1: connection refused\\nreplica-2: timeout\" " +
+			"cause=\"replica-1: connection refused\\nreplica-2: timeout\" " +
+			"cause=\"replica-1: connection refused\" " +
+			"cause=\"replica-2: timeout\"\n",
+		expectZero: "ERROR failed error=\"failed to sync: " +
+			"replica-1: connection refused\\nreplica-2: timeout\" " +
+			"cause=\"replica-1: connection refused\\nreplica-2: timeout\" " +
+			"cause=\"replica-1: connection refused\" " +
+			"cause=\"replica-2: timeout\"\n",
+	},
+	"pretty no causes": {
+		formatter:  log.FormatterPretty,
+		depth:      3,
+		err:        errRefused,
+		expectRus:  "ERROR failed error=\"connection refused\"\n",
+		expectZero: "ERROR failed error=\"connection refused\"\n",
+	},
+	"json wrapped": {
+		formatter: log.FormatterJSON,
+		depth:     3,
+		err:       errWrapped,
+		expectRus: `{"error":{"message":"failed to save: db: connection ` +
+			`refused","causes":["db: connection refused",` +
+			`"connection refused"]},"level":"error","msg":"failed"}` + "\n",
+		expectZero: `{"level":"error","error":{"message":"failed to save: ` +
+			`db: connection refused","causes":["db: connection refused",` +
+			`"connection refused"]},"message":"failed"}` + "\n",
+	},
+	"json no causes": {
+		formatter: log.FormatterJSON,
+		depth:     3,
+		err:       errRefused,
+		expectRus: `{"error":"connection refused","level":"error",` +
+			`"msg":"failed"}` + "\n",
+		expectZero: `{"level":"error","error":"connection refused",` +
+			`"message":"failed"}` + "\n",
+	},
+	"ecs wrapped": {
+		formatter: log.FormatterECS,
+		depth:     3,
+		err:       errWrapped,
+		expectRus: `{"ecs":{"version":"8.11.0"},"error":{"message":` +
+			`"failed to save: db: connection refused"},"log":{"level":` +
+			`"error"},"message":"failed"}` + "\n",
+		expectZero: `{"ecs":{"version":"8.11.0"},"error":{"message":` +
+			`"failed to save: db: connection refused"},"log":{"level":` +
+			`"error"},"message":"failed"}` + "\n",
+	},
+}
+
+func TestErrorChain(t *testing.T) {
+	t.Cleanup(func() {
+		(&log.Config{}).SetupZero(io.Discard)
+	})
+
+	test.Map(t, testErrorChainParams).
+		RunSeq(func(t test.Test, param testErrorChainParam) {
+			// Given
+			config := &log.Config{
+				Level:       log.LevelInfo,
+				Formatter:   param.formatter,
+				TimeFormat:  log.TimeFormatNone,
+				ColorMode:   log.ColorModeOff,
+				ErrorCauses: param.depth,
+			}
+			rus, zero := &bytes.Buffer{}, &bytes.Buffer{}
+			rlogger := config.SetupRus(rus, logrus.New())
+			zlogger := config.SetupZero(zero).ZeroLogger()
+
+			// When
+			rlogger.WithError(param.err).Error("failed")
+			zlogger.Error().Err(param.err).Msg("failed")
+
+			// Then
+			assert.Equal(t, param.expectRus, rus.String())
+			assert.Equal(t, param.expectZero, zero.String())
+		})
+}
@@ -0,0 +1,274 @@
+package log
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/sirupsen/logrus"
+)
+
+// FieldRepeated is the field name of the number of consecutive identical log
+// entries collapsed by deduplication.
+const FieldRepeated = "repeated"
+
+// IsDedupEnabled returns whether deduplication of log entries is enabled.
+func (c *Config) IsDedupEnabled() bool {
+	return c.Dedup
+}
+
+// Deduper is detecting consecutive identical log entries within a window. The
+// first entry is logged as is, while the following identical entries are
+// collapsed into a single summary entry carrying the number of repeated
+// entries. The summary is emitted when the window closes, a different entry
+// arrives, or the deduper is flushed.
+type Deduper struct {
+	// window is the window in which identical entries are collapsed.
+	window time.Duration
+
+	// mutex synchronizes the access to the pending entry.
+	mutex sync.Mutex
+	// key is the key of the pending entry.
+	key string
+	// count is the number of repeated entries of the pending entry.
+	count int
+	// emit is the function emitting the summary of the pending entry.
+	emit func(repeated int)
+	// timer is the timer closing the window of the pending entry.
+	timer *time.Timer
+	// seq is the sequence number of the window of the pending entry.
+	seq uint64
+}
+
+// NewDeduper creates a new deduper with the given window. A non-positive
+// window collapses identical entries until a different entry arrives.
+func NewDeduper(window time.Duration) *Deduper {
+	return &Deduper{window: window}
+}
+
+// Dedup returns whether the entry with the given key is logged. If not, the
+// entry is counted as repeated entry of the pending entry. Else the summary of
+// the pending entry is emitted first and the given entry, using the given emit
+// function for its summary, becomes the pending entry.
+func (d *Deduper) Dedup(key string, emit func(repeated int)) bool {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	if d.emit != nil && d.key == key {
+		d.count++
+		return false
+	}
+
+	d.flush()
+	d.key, d.count, d.emit = key, 0, emit
+	if d.window > 0 {
+		d.seq++
+		seq := d.seq
+		d.timer = time.AfterFunc(d.window, func() { d.close(seq) })
+	}
+	return true
+}
+
+// Flush emits the summary of the pending entry, if any. It must be called on
+// shutdown to not lose pending repeated entries.
+func (d *Deduper) Flush() {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	d.flush()
+}
+
+// close closes the window of the pending entry, if the window with the given
+// sequence number is still open.
+func (d *Deduper) close(seq uint64) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	if d.timer != nil && d.seq == seq {
+		d.flush()
+	}
+}
+
+// flush emits the summary of the pending entry, if entries were repeated, and
+// resets the pending entry. The emitting happens while holding the lock to
+// keep the order of entries.
+func (d *Deduper) flush() {
+	if d.timer != nil {
+		d.timer.Stop()
+		d.timer = nil
+	}
+	if d.emit != nil && d.count > 0 {
+		d.emit(d.count)
+	}
+	d.key, d.count, d.emit = "", 0, nil
+}
+
+// repeatedKey is the context key marking summary log entries emitted by the
+// dedup hook.
+type repeatedKey struct{}
+
+// LogRusDedupHook is a hook collapsing consecutive identical log entries, i.e.
+// entries with the same level, message, and fields. Since logrus hooks cannot
+// drop entries, repeated entries are marked in the context of the entry and
+// dropped by the `LogRusSuppressed` formatter, while summaries are logged via
+// the logger.
+type LogRusDedupHook struct {
+	// logger is the logger used for logging summaries.
+	logger *logrus.Logger
+	// deduper is the deduper of the log entries.
+	deduper *Deduper
+}
+
+// NewLogRusDedupHook creates a new dedup hook for logrus using the given
+// config and logger.
+func NewLogRusDedupHook(c *Config, logger *logrus.Logger) *LogRusDedupHook {
+	return &LogRusDedupHook{
+		logger:  logger,
+		deduper: NewDeduper(c.DedupWindow),
+	}
+}
+
+// Levels returns all log levels, since all entries are deduplicated.
+func (*LogRusDedupHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+// Fire marks the given log entry as suppressed, if it repeats the pending
+// entry, and else emits the summary of the pending entry before the given
+// entry.
+func (h *LogRusDedupHook) Fire(entry *logrus.Entry) error {
+	ctx := entry.Context
+	if isSuppressed(entry) || ctx != nil && ctx.Value(repeatedKey{}) != nil {
+		return nil
+	}
+
+	level, message := entry.Level, entry.Message
+	data := maps.Clone(entry.Data)
+	key := fmt.Sprintf("%d %s %v", level, message, data)
+	if !h.deduper.Dedup(key, func(repeated int) {
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		h.logger.WithContext(context.WithValue(ctx, repeatedKey{}, true)).
+			WithFields(data).WithField(FieldRepeated, repeated).
+			Log(level, message)
+	}) {
+		suppress(entry)
+	}
+	return nil
+}
+
+// Flush emits the summary of the pending entry, if any.
+func (h *LogRusDedupHook) Flush() {
+	h.deduper.Flush()
+}
+
+// ZeroLogDedup is a writer collapsing consecutive identical log events, i.e.
+// events with the same level, message, and fields ignoring the timestamp. The
+// summary is written as copy of the first event with the number of repeated
+// events attached.
+type ZeroLogDedup struct {
+	// writer is the underlying writer.
+	writer io.Writer
+	// deduper is the deduper of the log events.
+	deduper *Deduper
+}
+
+// NewZeroLogDedup creates a new dedup writer for zerolog using the given
+// config and underlying writer.
+func NewZeroLogDedup(c *Config, writer io.Writer) *ZeroLogDedup {
+	return &ZeroLogDedup{
+		writer:  writer,
+		deduper: NewDeduper(c.DedupWindow),
+	}
+}
+
+// Write writes the given log event, if it does not repeat the pending event.
+func (w *ZeroLogDedup) Write(p []byte) (int, error) {
+	return w.WriteLevel(zerolog.NoLevel, p)
+}
+
+// WriteLevel writes the given log event with the given level, if it does not
+// repeat the pending event.
+func (w *ZeroLogDedup) WriteLevel(
+	level zerolog.Level, p []byte,
+) (int, error) {
+	key, ok := zeroDedupKey(level, p)
+	if !ok {
+		w.deduper.Flush()
+		return w.write(level, p)
+	}
+
+	event := bytes.Clone(p)
+	if !w.deduper.Dedup(key, func(repeated int) {
+		_, _ = w.write(level, zeroRepeated(event, repeated))
+	}) {
+		return len(p), nil
+	}
+	return w.write(level, p)
+}
+
+// Flush writes the summary of the pending event, if any.
+func (w *ZeroLogDedup) Flush() {
+	w.deduper.Flush()
+}
+
+// write writes the given log event to the underlying writer using the level
+// writer interface, if supported.
+func (w *ZeroLogDedup) write(level zerolog.Level, p []byte) (int, error) {
+	if writer, ok := w.writer.(zerolog.LevelWriter); ok {
+		return writer.WriteLevel(level, p)
+	}
+	return w.writer.Write(p)
+}
+
+// zeroDedupKey returns the key of the given log event ignoring the timestamp,
+// and whether the event is a valid JSON object.
+func zeroDedupKey(level zerolog.Level, p []byte) (string, bool) {
+	fields := map[string]json.RawMessage{}
+	if err := json.Unmarshal(p, &fields); err != nil {
+		return "", false
+	}
+	delete(fields, zerolog.TimestampFieldName)
+
+	// Marshalling sorts the fields and cannot fail for raw messages.
+	key, _ := json.Marshal(fields)
+	return strconv.Itoa(int(level)) + " " + string(key), true
+}
+
+// zeroRepeated returns a copy of the given log event with the number of
+// repeated events attached as last field.
+func zeroRepeated(event []byte, repeated int) []byte {
+	event = bytes.TrimRight(event, " \t\r\n")
+	event = bytes.TrimSuffix(event, []byte("}"))
+	if len(bytes.TrimSpace(event)) > 1 {
+		event = append(event, ',')
+	}
+	event = append(event, `"`+FieldRepeated+`":`...)
+	event = strconv.AppendInt(event, int64(repeated), 10)
+	return append(event, '}', '\n')
+}
+
+// Flush emits the summaries of the pending repeated entries of the loggers
+// set up by the config. It must be called on shutdown, if deduplication is
+// enabled, to not lose pending repeated entries.
+func (c *Config) Flush() {
+	loggers := c.lookupLoggers()
+	if loggers == nil {
+		return
+	}
+	loggers.mutex.RLock()
+	flushers := []func(){loggers.rusFlush, loggers.zeroFlush}
+	loggers.mutex.RUnlock()
+
+	for _, flush := range flushers {
+		if flush != nil {
+			flush()
+		}
+	}
+}
@@ -0,0 +1,217 @@
+package log_test
+
+import (
+	"bytes"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/tkrop/go-testing/test"
+
+	"github.com/tkrop/go-config/log"
+)
+
+// syncBuffer is a buffer safe for concurrent use.
+type syncBuffer struct {
+	mutex  sync.Mutex
+	buffer bytes.Buffer
+}
+
+// Write writes the given bytes to the buffer.
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return b.buffer.Write(p)
+}
+
+// Lines returns the trimmed lines written to the buffer.
+func (b *syncBuffer) Lines() []string {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return lines(&b.buffer)
+}
+
+// dedupEntry is a log entry used for testing deduplication.
+type dedupEntry struct {
+	msg   string
+	field string
+}
+
+// dedupLoggers returns the logging functions of both logging backends for
+// the given config writing to the given buffer.
+func dedupLoggers(
+	config *log.Config, buffer *syncBuffer,
+) map[string]func(entry dedupEntry) {
+	rus := config.SetupRus(buffer, logrus.New())
+	zero := config.SetupZero(buffer).ZeroLogger()
+	return map[string]func(entry dedupEntry){
+		"logrus": func(entry dedupEntry) {
+			if entry.field != "" {
+				rus.WithField("key", entry.field).Info(entry.msg)
+			} else {
+				rus.Info(entry.msg)
+			}
+		},
+		"zerolog": func(entry dedupEntry) {
+			if entry.field != "" {
+				zero.Info().Str("key", entry.field).Msg(entry.msg)
+			} else {
+				zero.Info().Msg(entry.msg)
+			}
+		},
+	}
+}
+
+type testDedupParam struct {
+	entries     []dedupEntry
+	expect      []string
+	expectFlush []string
+}
+
+var testDedupParams = map[string]testDedupParam{
+	"single": {
+		entries:     []dedupEntry{{msg: "a"}},
+		expect:      []string{"INFO a"},
+		expectFlush: []string{"INFO a"},
+	},
+	"burst": {
+		entries: []dedupEntry{
+			{msg: "a"}, {msg: "a"}, {msg: "a"}, {msg: "a"}, {msg: "a"},
+		},
+		expect:      []string{"INFO a"},
+		expectFlush: []string{"INFO a", "INFO a repeated=4"},
+	},
+	"burst closed": {
+		entries: []dedupEntry{
+			{msg: "a"}, {msg: "a"}, {msg: "a"}, {msg: "b"},
+		},
+		expect:      []string{"INFO a", "INFO a repeated=2", "INFO b"},
+		expectFlush: []string{"INFO a", "INFO a repeated=2", "INFO b"},
+	},
+	"interleaved": {
+		entries: []dedupEntry{
+			{msg: "a"}, {msg: "a"}, {msg: "b"},
+			{msg: "a"}, {msg: "a"}, {msg: "a"},
+		},
+		expect: []string{
+			"INFO a", "INFO a repeated=1", "INFO b", "INFO a",
+		},
+		expectFlush: []string{
+			"INFO a", "INFO a repeated=1", "INFO b", "INFO a",
+			"INFO a repeated=2",
+		},
+	},
+	"alternating": {
+		entries: []dedupEntry{
+			{msg: "a"}, {msg: "b"}, {msg: "a"}, {msg: "b"},
+		},
+		expect:      []string{"INFO a", "INFO b", "INFO a", "INFO b"},
+		expectFlush: []string{"INFO a", "INFO b", "INFO a", "INFO b"},
+	},
+	"fields": {
+		entries: []dedupEntry{
+			{msg: "a", field: "x"}, {msg: "a", field: "x"},
+			{msg: "a", field: "y"},
+		},
+		expect: []string{
+			`INFO a key="x"`, `INFO a key="x" repeated=1`, `INFO a key="y"`,
+		},
+		expectFlush: []string{
+			`INFO a key="x"`, `INFO a key="x" repeated=1`, `INFO a key="y"`,
+		},
+	},
+}
+
+func TestDedup(t *testing.T) {
+	test.Map(t, testDedupParams).
+		RunSeq(func(t test.Test, param testDedupParam) {
+			for _, backend := range []string{"logrus", "zerolog"} {
+				// Given
+				buffer := &syncBuffer{}
+				config := &log.Config{
+					Level:       log.LevelInfo,
+					Formatter:   log.FormatterPretty,
+					TimeFormat:  log.TimeFormatNone,
+					ColorMode:   log.ColorModeOff,
+					Dedup:       true,
+					DedupWindow: time.Hour,
+				}
+				logf := dedupLoggers(config, buffer)[backend]
+
+				// When
+				for _, entry := range param.entries {
+					logf(entry)
+				}
+
+				// Then
+				assert.Equal(t, param.expect, buffer.Lines(), backend)
+				config.Flush()
+				assert.Equal(t, param.expectFlush, buffer.Lines(), backend)
+			}
+		})
+}
+
+func TestDedupSetupRepeated(t *testing.T) {
+	for _, backend := range []string{"logrus", "zerolog"} {
+		t.Run(backend, func(t *testing.T) {
+			// Given
+			first, second := &syncBuffer{}, &syncBuffer{}
+			config := &log.Config{
+				Level:       log.LevelInfo,
+				Formatter:   log.FormatterPretty,
+				TimeFormat:  log.TimeFormatNone,
+				ColorMode:   log.ColorModeOff,
+				Dedup:       true,
+				DedupWindow: time.Hour,
+			}
+			logf := dedupLoggers(config, first)[backend]
+			logf(dedupEntry{msg: "a"})
+			logf(dedupEntry{msg: "a"})
+
+			// When
+			logf = dedupLoggers(config, second)[backend]
+
+			// Then
+			assert.Equal(t, []string{"INFO a", "INFO a repeated=1"},
+				first.Lines())
+			logf(dedupEntry{msg: "b"})
+			logf(dedupEntry{msg: "b"})
+			config.Flush()
+			assert.Equal(t, []string{"INFO a", "INFO a repeated=1"},
+				first.Lines())
+			assert.Equal(t, []string{"INFO b", "INFO b repeated=1"},
+				second.Lines())
+		})
+	}
+}
+
+func TestDedupWindow(t *testing.T) {
+	for _, backend := range []string{"logrus", "zerolog"} {
+		t.Run(backend, func(t *testing.T) {
+			// Given
+			buffer := &syncBuffer{}
+			config := &log.Config{
+				Level:       log.LevelInfo,
+				Formatter:   log.FormatterPretty,
+				TimeFormat:  log.TimeFormatNone,
+				ColorMode:   log.ColorModeOff,
+				Dedup:       true,
+				DedupWindow: 10 * time.Millisecond,
+			}
+			logf := dedupLoggers(config, buffer)[backend]
+			logf(dedupEntry{msg: "a"})
+			logf(dedupEntry{msg: "a"})
+
+			// When
+			time.Sleep(50 * time.Millisecond)
+			logf(dedupEntry{msg: "a"})
+
+			// Then
+			assert.Equal(t, []string{
+				"INFO a", "INFO a repeated=1", "INFO a",
+			}, buffer.Lines())
+		})
+	}
+}
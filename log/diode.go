@@ -0,0 +1,50 @@
+package log
+
+import (
+	"io"
+
+	"github.com/rs/zerolog/diode"
+)
+
+// DefaultDiodeBuffer is the default number of entries buffered by the
+// non-blocking zerolog diode writer.
+const DefaultDiodeBuffer = 1000
+
+// ZeroLogDiodeFd is a non-blocking zerolog diode writer providing the file
+// descriptor of the wrapped writer to keep terminal detection working.
+type ZeroLogDiodeFd struct {
+	// Writer is the diode writer.
+	diode.Writer
+	// fd is the wrapped writer providing the file descriptor.
+	fd FdWriter
+}
+
+// Fd returns the file descriptor of the wrapped writer.
+func (w *ZeroLogDiodeFd) Fd() uintptr {
+	return w.fd.Fd()
+}
+
+// ZeroDiode wraps the given writer with a non-blocking zerolog diode writer,
+// if configured. The diode drops entries, if the writer cannot keep up, and
+// reports the number of dropped entries as warning via the zerolog logger
+// set up by the config. Else the writer is returned as is.
+func (c *Config) ZeroDiode(writer io.Writer) io.Writer {
+	if !c.NonBlocking {
+		return writer
+	}
+
+	size := c.NonBlockingBuffer
+	if size <= 0 {
+		size = DefaultDiodeBuffer
+	}
+	loggers := c.setupLoggers()
+	nonblocking := diode.NewWriter(writer, size, c.NonBlockingPoll,
+		func(missed int) {
+			// #nosec G115 // missed entries are never negative.
+			loggers.reportDropped(uint64(missed))
+		})
+	if fd, ok := writer.(FdWriter); ok {
+		return &ZeroLogDiodeFd{Writer: nonblocking, fd: fd}
+	}
+	return nonblocking
+}
@@ -0,0 +1,82 @@
+package log_test
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/tkrop/go-config/log"
+)
+
+// slowWriter is a writer delaying each write by the given delay.
+type slowWriter struct {
+	mutex  sync.Mutex
+	buffer bytes.Buffer
+	delay  time.Duration
+}
+
+// Write writes the given bytes after the delay.
+func (w *slowWriter) Write(p []byte) (int, error) {
+	time.Sleep(w.delay)
+
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	return w.buffer.Write(p)
+}
+
+// String returns the written bytes as string.
+func (w *slowWriter) String() string {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	return w.buffer.String()
+}
+
+func TestZeroDiode(t *testing.T) {
+	// Given
+	writer := &slowWriter{delay: 5 * time.Millisecond}
+	logger := (&log.Config{
+		Level:             log.LevelInfo,
+		TimeFormat:        log.TimeFormatNone,
+		ColorMode:         log.ColorModeOff,
+		NonBlocking:       true,
+		NonBlockingBuffer: 8,
+		NonBlockingPoll:   time.Millisecond,
+	}).SetupZero(writer).ZeroLogger()
+
+	// When
+	start := time.Now()
+	for range 1000 {
+		logger.Info().Msg("info message")
+	}
+	elapsed := time.Since(start)
+
+	// Then
+	assert.Less(t, elapsed, 500*time.Millisecond)
+	assert.Eventually(t, func() bool {
+		return strings.Contains(writer.String(),
+			"WARN dropped log entries dropped=")
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Less(t, strings.Count(writer.String(), "INFO info message\n"), 1000)
+}
+
+func TestZeroDiodeSetup(t *testing.T) {
+	// Given
+	config := &log.Config{NonBlocking: true}
+
+	// When
+	writer := config.ZeroDiode(os.Stderr)
+
+	// Then
+	fd, ok := writer.(log.FdWriter)
+	assert.True(t, ok)
+	assert.Equal(t, os.Stderr.Fd(), fd.Fd())
+	assert.Equal(t, io.Discard, (&log.Config{}).ZeroDiode(io.Discard))
+	_, ok = config.ZeroDiode(io.Discard).(log.FdWriter)
+	assert.False(t, ok)
+}
@@ -0,0 +1,100 @@
+package log
+
+import (
+	"io"
+	"maps"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ECSVersion is the version of the Elastic Common Schema (ECS) implemented by
+// the ECS formatters.
+const ECSVersion = "8.11.0"
+
+// FormatECS formats the given log entry as ECS compliant JSON. User fields
+// are kept top-level unless `ECSLabels` is set, in which case they are nested
+// under `labels`. The ECS fields take precedence over clashing user fields.
+func (s *Setup) FormatECS(entry *Entry) ([]byte, error) {
+	data := make(map[string]any, len(entry.Fields)+6)
+	if s.ECSLabels {
+		if len(entry.Fields) > 0 {
+			data["labels"] = entry.Fields
+		}
+	} else {
+		maps.Copy(data, entry.Fields)
+	}
+
+	if entry.Time != nil {
+		data["@timestamp"] = s.Time(*entry.Time).Format(s.ECSTimeFormat())
+	}
+	data["ecs"] = map[string]any{"version": ECSVersion}
+	data["message"] = entry.Message
+	if entry.Error != "" {
+		data["error"] = map[string]any{"message": entry.Error}
+	}
+
+	log := map[string]any{"level": AllLevels[entry.Level]}
+	if entry.File != "" || entry.Function != "" {
+		origin := map[string]any{}
+		if entry.File != "" {
+			origin["file"] = map[string]any{
+				"name": entry.File, "line": entry.Line,
+			}
+		}
+		if entry.Function != "" {
+			origin["function"] = entry.Function
+		}
+		log["origin"] = origin
+	}
+	data["log"] = log
+
+	return encodeJSON(data)
+}
+
+// ECSTimeFormat returns the time layout used for ECS timestamps. Since ECS
+// requires dates, numeric and missing time formats fall back to RFC3339 with
+// nanoseconds.
+func (s *Setup) ECSTimeFormat() string {
+	if s.TimeFormat == "" || s.TimeFormat == TimeFormatNone ||
+		IsTimeEpoch(s.TimeFormat) {
+		return time.RFC3339Nano
+	}
+	return s.TimeFormat
+}
+
+// LogRusECS is a logrus formatter writing ECS compliant JSON.
+type LogRusECS struct {
+	// Setup provides the setup for formatting logs.
+	*Setup
+}
+
+// NewLogRusECS creates a new ECS formatter for logrus using the given config.
+func NewLogRusECS(c *Config, writer io.Writer) *LogRusECS {
+	return &LogRusECS{Setup: c.Setup(writer)}
+}
+
+// Format formats the log entry as ECS compliant JSON.
+func (f *LogRusECS) Format(entry *logrus.Entry) ([]byte, error) {
+	return f.FormatECS(f.RusEntry(entry))
+}
+
+// ZeroLogECS is a zerolog writer rewriting JSON events into ECS compliant
+// JSON.
+type ZeroLogECS struct {
+	// Setup provides the setup for formatting logs.
+	*Setup
+	// writer is the writer for the ECS compliant JSON.
+	writer io.Writer
+}
+
+// NewZeroLogECS creates a new ECS writer for zerolog using the given config.
+func NewZeroLogECS(c *Config, writer io.Writer) *ZeroLogECS {
+	return &ZeroLogECS{Setup: c.Setup(writer), writer: writer}
+}
+
+// Write rewrites the given JSON event into ECS compliant JSON and writes it
+// to the underlying writer.
+func (w *ZeroLogECS) Write(p []byte) (int, error) {
+	return w.ZeroWrite(w.writer, p, w.FormatECS)
+}
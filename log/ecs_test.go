@@ -0,0 +1,142 @@
+package log_test
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tkrop/go-testing/test"
+
+	"github.com/tkrop/go-config/log"
+)
+
+// ecsTime is the fixed time of the ECS log entries.
+var ecsTime = time.Date(2024, 1, 2, 3, 4, 5, 678000000, time.UTC)
+
+// readGolden reads the golden file with the given name from the fixtures.
+func readGolden(t test.Test, name string) string {
+	golden, err := os.ReadFile(filepath.Join("fixtures", name))
+	require.NoError(t, err)
+	return string(golden)
+}
+
+type testECSParam struct {
+	config *log.Config
+	caller bool
+	data   logrus.Fields
+	event  string
+	expect string
+}
+
+var testECSParams = map[string]testECSParam{
+	"ecs top-level fields": {
+		config: &log.Config{TimeLocation: "UTC"},
+		caller: true,
+		data: logrus.Fields{
+			"key":   "value",
+			"count": 3,
+			"error": errors.New("failure"),
+		},
+		event: `{"level":"error","key":"value","count":3,` +
+			`"error":"failure","caller":"/app/main.go:42",` +
+			`"time":"2024-01-02T03:04:05.678Z","message":"error message"}`,
+		expect: "ecs-fields",
+	},
+	"ecs labels": {
+		config: &log.Config{TimeLocation: "UTC", ECSLabels: true},
+		caller: true,
+		data: logrus.Fields{
+			"key":   "value",
+			"count": 3,
+			"error": errors.New("failure"),
+		},
+		event: `{"level":"error","key":"value","count":3,` +
+			`"error":"failure","caller":"/app/main.go:42",` +
+			`"time":"2024-01-02T03:04:05.678Z","message":"error message"}`,
+		expect: "ecs-labels",
+	},
+	"ecs minimal": {
+		config: &log.Config{TimeLocation: "UTC", ECSLabels: true},
+		event: `{"level":"error","time":"2024-01-02T03:04:05.678Z",` +
+			`"message":"error message"}`,
+		expect: "ecs-minimal",
+	},
+}
+
+func TestECSRus(t *testing.T) {
+	test.Map(t, testECSParams).
+		Run(func(t test.Test, param testECSParam) {
+			// Given
+			formatter := log.NewLogRusECS(param.config, &bytes.Buffer{})
+			logger := logrus.New()
+			logger.SetReportCaller(param.caller)
+			entry := &logrus.Entry{
+				Logger:  logger,
+				Time:    ecsTime,
+				Level:   logrus.ErrorLevel,
+				Message: "error message",
+				Data:    logrus.Fields{},
+				Caller: &runtime.Frame{
+					File: "/app/main.go", Line: 42, Function: "main.main",
+				},
+			}
+			for key, value := range param.data {
+				entry.Data[key] = value
+			}
+
+			// When
+			result, err := formatter.Format(entry)
+
+			// Then
+			require.NoError(t, err)
+			assert.JSONEq(t, readGolden(t, param.expect+"-rus.json"),
+				string(result))
+		})
+}
+
+func TestECSZero(t *testing.T) {
+	test.Map(t, testECSParams).
+		Run(func(t test.Test, param testECSParam) {
+			// Given
+			buffer := &bytes.Buffer{}
+			writer := log.NewZeroLogECS(param.config, buffer)
+
+			// When
+			n, err := writer.Write([]byte(param.event + "\n"))
+
+			// Then
+			require.NoError(t, err)
+			assert.Equal(t, len(param.event)+1, n)
+			assert.JSONEq(t, readGolden(t, param.expect+"-zero.json"),
+				buffer.String())
+		})
+}
+
+func TestECSSetup(t *testing.T) {
+	// Given
+	config := &log.Config{Formatter: log.FormatterECS, Caller: true}
+	rus, zero := &bytes.Buffer{}, &bytes.Buffer{}
+	rlogger := config.SetupRus(rus, logrus.New())
+	zlogger := config.SetupZero(zero).ZeroLogger()
+
+	// When
+	rlogger.WithError(errors.New("failure")).Error("error message")
+	zlogger.Error().Err(errors.New("failure")).Msg("error message")
+
+	// Then
+	for _, output := range []string{rus.String(), zero.String()} {
+		assert.Contains(t, output, `"@timestamp":`)
+		assert.Contains(t, output, `"ecs":{"version":"`+log.ECSVersion+`"}`)
+		assert.Contains(t, output, `"error":{"message":"failure"}`)
+		assert.Contains(t, output, `"message":"error message"`)
+		assert.Contains(t, output, `"origin":{"file":{"line":`)
+		assert.Contains(t, output, `ecs_test.go"}`)
+	}
+}
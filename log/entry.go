@@ -0,0 +1,212 @@
+package log
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/sirupsen/logrus"
+)
+
+// Entry is the backend independent log entry formatted by the structured
+// formatters, e.g. the ECS formatter.
+type Entry struct {
+	// Time is the time of the log entry. If nil, the timestamp is omitted.
+	Time *time.Time
+	// Level is the log level of the log entry.
+	Level Level
+	// Message is the message of the log entry.
+	Message string
+	// Error is the error message of the log entry, if any.
+	Error string
+	// File is the caller file of the log entry, if any.
+	File string
+	// Line is the caller line of the log entry, if any.
+	Line int
+	// Function is the caller function of the log entry, if any.
+	Function string
+	// Fields are the user fields of the log entry.
+	Fields map[string]any
+}
+
+// RusEntry creates the backend independent log entry from the given logrus
+// log entry.
+func (s *Setup) RusEntry(entry *logrus.Entry) *Entry {
+	result := &Entry{
+		Level:   Level(entry.Level),
+		Message: entry.Message,
+		Fields:  make(map[string]any, len(entry.Data)),
+	}
+	if s.TimeFormat != TimeFormatNone {
+		result.Time = &entry.Time
+	}
+	if entry.HasCaller() {
+		result.File = entry.Caller.File
+		result.Line = entry.Caller.Line
+		result.Function = entry.Caller.Function
+	}
+
+	for key, value := range entry.Data {
+		if key == logrus.ErrorKey {
+			result.Error = fmt.Sprintf("%v", value)
+			continue
+		}
+		// Errors are not serializable by default and need special handling.
+		if err, ok := value.(error); ok {
+			value = err.Error()
+		}
+		result.Fields[key] = value
+	}
+	return result
+}
+
+// ZeroEntry creates the backend independent log entry from the given zerolog
+// JSON event.
+func (s *Setup) ZeroEntry(p []byte) (*Entry, error) {
+	data := map[string]any{}
+	decoder := json.NewDecoder(bytes.NewReader(p))
+	decoder.UseNumber()
+	if err := decoder.Decode(&data); err != nil {
+		return nil, fmt.Errorf("cannot decode event: %w", err)
+	}
+
+	entry := &Entry{Level: InfoLevel, Fields: data}
+	if level, ok := data[zerolog.LevelFieldName].(string); ok {
+		entry.Level = ParseLevel(level)
+	}
+	if message, ok := data[zerolog.MessageFieldName].(string); ok {
+		entry.Message = message
+	}
+	switch err := data[zerolog.ErrorFieldName].(type) {
+	case nil:
+	case map[string]any:
+		entry.Error = fmt.Sprintf("%v", err["message"])
+	default:
+		entry.Error = fmt.Sprintf("%v", err)
+	}
+	if caller, ok := data[zerolog.CallerFieldName].(string); ok {
+		entry.File = caller
+		if index := strings.LastIndex(caller, ":"); index >= 0 {
+			if line, err := strconv.Atoi(caller[index+1:]); err == nil {
+				entry.File, entry.Line = caller[:index], line
+			}
+		}
+	}
+	if timestamp, ok := data[zerolog.TimestampFieldName]; ok {
+		entry.Time = s.zeroTime(timestamp)
+	}
+
+	for _, key := range []string{
+		zerolog.LevelFieldName, zerolog.MessageFieldName,
+		zerolog.ErrorFieldName, zerolog.CallerFieldName,
+		zerolog.TimestampFieldName,
+	} {
+		delete(data, key)
+	}
+	return entry, nil
+}
+
+// ZeroWrite rewrites the given zerolog JSON event using the given format
+// function and writes the result to the given writer.
+func (s *Setup) ZeroWrite(
+	writer io.Writer, p []byte, format func(*Entry) ([]byte, error),
+) (int, error) {
+	entry, err := s.ZeroEntry(p)
+	if err != nil {
+		return 0, err
+	}
+
+	bytes, err := format(entry)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := writer.Write(bytes); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// rewriteJSON rewrites the fields of the given zerolog JSON event keeping the
+// order of the fields. The rewrite function returns the new raw value of the
+// field with the given name and whether it has changed. Fields changed to nil
+// are dropped. Unchanged events are returned as is.
+func rewriteJSON(
+	p []byte, rewrite func(key string, raw json.RawMessage) (json.RawMessage, bool),
+) ([]byte, error) {
+	decoder := json.NewDecoder(bytes.NewReader(p))
+	decoder.UseNumber()
+	if token, err := decoder.Token(); err != nil {
+		return nil, fmt.Errorf("cannot decode event: %w", err)
+	} else if token != json.Delim('{') {
+		return nil, fmt.Errorf("cannot decode event: invalid token [%v]", token)
+	}
+
+	changed := false
+	buffer := &bytes.Buffer{}
+	buffer.WriteByte('{')
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, fmt.Errorf("cannot decode event: %w", err)
+		}
+		key, _ := token.(string)
+		raw := json.RawMessage{}
+		if err := decoder.Decode(&raw); err != nil {
+			return nil, fmt.Errorf("cannot decode event: %w", err)
+		}
+
+		value, ok := rewrite(key, raw)
+		if changed = changed || ok; value == nil && ok {
+			continue
+		} else if buffer.Len() > 1 {
+			buffer.WriteByte(',')
+		}
+		name, _ := json.Marshal(key)
+		buffer.Write(name)
+		buffer.WriteByte(':')
+		if ok {
+			buffer.Write(value)
+		} else {
+			buffer.Write(raw)
+		}
+	}
+	if !changed {
+		return p, nil
+	}
+	buffer.WriteString("}\n")
+	return buffer.Bytes(), nil
+}
+
+// encodeJSON encodes the given data as JSON line without escaping HTML.
+func encodeJSON(data map[string]any) ([]byte, error) {
+	buffer := &bytes.Buffer{}
+	encoder := json.NewEncoder(buffer)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(data); err != nil {
+		return nil, fmt.Errorf("failed to marshal fields to JSON: %w", err)
+	}
+	return buffer.Bytes(), nil
+}
+
+// zeroTime returns the time of the given zerolog timestamp supporting RFC3339
+// strings as well as unix timestamps in the precision of the numeric time
+// format. If the timestamp cannot be parsed, nil is returned.
+func (s *Setup) zeroTime(timestamp any) *time.Time {
+	switch timestamp := timestamp.(type) {
+	case string:
+		if ttime, err := time.Parse(time.RFC3339Nano, timestamp); err == nil {
+			return &ttime
+		}
+	case json.Number:
+		if epoch, err := timestamp.Int64(); err == nil {
+			ttime := EpochTime(s.TimeFormat, epoch)
+			return &ttime
+		}
+	}
+	return nil
+}
@@ -0,0 +1,263 @@
+package log
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/rs/zerolog"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// FieldHost is the field name of the hostname attached to log entries.
+	FieldHost = "host"
+	// FieldPID is the field name of the process id attached to log entries.
+	FieldPID = "pid"
+
+	// FieldKeyTime is the field map key of the time field.
+	FieldKeyTime = "time"
+	// FieldKeyLevel is the field map key of the level field.
+	FieldKeyLevel = "level"
+	// FieldKeyMessage is the field map key of the message field.
+	FieldKeyMessage = "message"
+)
+
+// fieldKeys are the field map keys of the renamable fields in order.
+var fieldKeys = []string{FieldKeyTime, FieldKeyLevel, FieldKeyMessage}
+
+// ErrFieldMap is a common error to indicate an invalid field map.
+var ErrFieldMap = errors.New("field map")
+
+// NewErrFieldMap is a convenience method to create a new field map error for
+// the given key and name with the given reason.
+func NewErrFieldMap(key, name, reason string) error {
+	return fmt.Errorf("%w [%s=%s]: %s", ErrFieldMap, key, name, reason)
+}
+
+// ParseFieldMap parses the field map of the config renaming the time, level,
+// and message fields of the JSON formatters. The keys are matched
+// case-insensitive, while `msg` is accepted as alias for `message`. Unknown
+// keys and names colliding with other renamed fields or static fields are
+// ignored returning an error.
+func (c *Config) ParseFieldMap() (map[string]string, error) {
+	if len(c.FieldMap) == 0 {
+		return nil, nil
+	}
+
+	errs := []error{}
+	fields := make(map[string]string, len(fieldKeys))
+	for _, key := range slices.Sorted(maps.Keys(c.FieldMap)) {
+		name, field := c.FieldMap[key], strings.ToLower(key)
+		if field == "msg" {
+			field = FieldKeyMessage
+		}
+		if !slices.Contains(fieldKeys, field) {
+			errs = append(errs, NewErrFieldMap(key, name, "unknown field"))
+			continue
+		}
+		fields[field] = name
+	}
+
+	names := map[string]string{}
+	for _, key := range fieldKeys {
+		name, ok := fields[key]
+		if !ok {
+			continue
+		} else if other, ok := names[name]; ok {
+			errs = append(errs, NewErrFieldMap(key, name,
+				"collides with field ["+other+"]"))
+			delete(fields, key)
+		} else if _, ok := c.Fields[name]; ok {
+			errs = append(errs, NewErrFieldMap(key, name,
+				"collides with static field"))
+			delete(fields, key)
+		} else {
+			names[name] = key
+		}
+	}
+	return fields, errors.Join(errs...)
+}
+
+// FieldName returns the field name of the given field map key using the given
+// default name if the field is not renamed.
+func FieldName(fields map[string]string, key, name string) string {
+	if renamed, ok := fields[key]; ok {
+		return renamed
+	}
+	return name
+}
+
+// WithHostname sets up the function used to look up the hostname attached to
+// log entries, e.g. if `IncludeHostname` is enabled (default `os.Hostname`).
+func (c *Config) WithHostname(hostname func() (string, error)) *Config {
+	c.hostname = hostname
+	return c
+}
+
+// LookupHostname looks up the hostname using the function set up via
+// `WithHostname` falling back to `os.Hostname`.
+func (c *Config) LookupHostname() (string, error) {
+	if c.hostname != nil {
+		return c.hostname()
+	}
+	return os.Hostname()
+}
+
+// StaticFields returns the static fields attached to every log entry including
+// the hostname and the process id if enabled. The values are resolved once at
+// setup. If the hostname lookup fails, the hostname field is omitted and the
+// lookup error is returned to report it.
+func (c *Config) StaticFields() (map[string]any, error) {
+	fields := make(map[string]any, len(c.Fields)+2)
+	for key, value := range c.Fields {
+		fields[key] = value
+	}
+
+	var err error
+	if c.IncludeHostname {
+		var host string
+		if host, err = c.LookupHostname(); err == nil {
+			fields[FieldHost] = host
+		}
+	}
+	if c.IncludePID {
+		fields[FieldPID] = os.Getpid()
+	}
+	return fields, err
+}
+
+// LogRusFieldsHook is a hook adding a set of fixed fields to every log entry
+// without overwriting fields provided by the user.
+type LogRusFieldsHook struct {
+	// data contains the fixed fields.
+	data map[string]any
+}
+
+// NewLogRusFieldsHook creates a new fixed fields hook for logrus using the
+// given field data.
+func NewLogRusFieldsHook(data map[string]any) *LogRusFieldsHook {
+	return &LogRusFieldsHook{data: data}
+}
+
+// Levels returns all log levels, since the fields are added to all entries.
+func (*LogRusFieldsHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+// Fire adds the fixed fields to the given log entry.
+func (h *LogRusFieldsHook) Fire(entry *logrus.Entry) error {
+	for key, value := range h.data {
+		if _, ok := entry.Data[key]; !ok {
+			entry.Data[key] = value
+		}
+	}
+	return nil
+}
+
+// ZeroLogFieldsHook is a hook adding a set of fixed fields to every log event.
+type ZeroLogFieldsHook struct {
+	// data contains the fixed fields.
+	data map[string]any
+}
+
+// NewZeroLogFieldsHook creates a new fixed fields hook for zerolog using the
+// given field data.
+func NewZeroLogFieldsHook(data map[string]any) *ZeroLogFieldsHook {
+	return &ZeroLogFieldsHook{data: data}
+}
+
+// Run adds the fixed fields to the given log event.
+func (h *ZeroLogFieldsHook) Run(
+	event *zerolog.Event, _ zerolog.Level, _ string,
+) {
+	event.Fields(h.data)
+}
+
+// IsExcludeJSON returns whether the excluded fields are also omitted by the
+// JSON formatters, i.e. by `json`, `ecs`, `gcp`, and `gelf`.
+func (c *Config) IsExcludeJSON() bool {
+	if !c.ExcludeJSON || len(c.ExcludeFields) == 0 {
+		return false
+	}
+
+	switch c.Formatter {
+	case FormatterJSON, FormatterECS, FormatterGCP, FormatterGELF:
+		return true
+	}
+	return false
+}
+
+// LogRusExcludeHook is a hook omitting the excluded fields from every log
+// entry.
+type LogRusExcludeHook struct {
+	// fields contains the names of the excluded fields.
+	fields []string
+}
+
+// NewLogRusExcludeHook creates a new exclude hook for logrus using the given
+// field names.
+func NewLogRusExcludeHook(fields []string) *LogRusExcludeHook {
+	return &LogRusExcludeHook{fields: fields}
+}
+
+// Levels returns all log levels, since the fields are omitted for all
+// entries.
+func (*LogRusExcludeHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+// Fire omits the excluded fields from the given log entry.
+func (h *LogRusExcludeHook) Fire(entry *logrus.Entry) error {
+	for _, key := range h.fields {
+		delete(entry.Data, key)
+	}
+	return nil
+}
+
+// ZeroLogExclude is a zerolog writer omitting the excluded fields of JSON
+// events.
+type ZeroLogExclude struct {
+	// fields contains the names of the excluded fields.
+	fields []string
+	// writer is the writer for the JSON events without excluded fields.
+	writer io.Writer
+}
+
+// NewZeroLogExclude creates a new exclude writer for zerolog using the given
+// field names.
+func NewZeroLogExclude(fields []string, writer io.Writer) *ZeroLogExclude {
+	return &ZeroLogExclude{fields: fields, writer: writer}
+}
+
+// ZeroExclude wraps the given zerolog JSON writer to omit the excluded
+// fields, if the excluded fields are also omitted by the JSON formatters.
+func (c *Config) ZeroExclude(writer io.Writer) io.Writer {
+	if !c.IsExcludeJSON() {
+		return writer
+	}
+	return NewZeroLogExclude(c.ExcludeFields, writer)
+}
+
+// Write omits the excluded fields of the given JSON event and writes it to
+// the underlying writer.
+func (w *ZeroLogExclude) Write(p []byte) (int, error) {
+	bytes, err := rewriteJSON(p, w.exclude)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := w.writer.Write(bytes); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// exclude drops the given field, if it is excluded.
+func (w *ZeroLogExclude) exclude(key string, _ json.RawMessage) (json.RawMessage, bool) {
+	return nil, slices.Contains(w.fields, key)
+}
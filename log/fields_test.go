@@ -0,0 +1,387 @@
+package log_test
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/tkrop/go-testing/test"
+
+	"github.com/tkrop/go-config/log"
+)
+
+// errHostname is the error returned by the failing hostname lookup.
+var errHostname = errors.New("hostname lookup")
+
+// hostname is a hostname lookup stub returning the given hostname or error.
+func hostname(host string, err error) func() (string, error) {
+	return func() (string, error) { return host, err }
+}
+
+type testStaticFieldsParam struct {
+	config     *log.Config
+	expectRus  string
+	expectZero string
+}
+
+var testStaticFieldsParams = map[string]testStaticFieldsParam{
+	"no fields": {
+		config:     &log.Config{},
+		expectRus:  "INFO info message\n",
+		expectZero: "INFO info message\n",
+	},
+	"hostname field": {
+		config: (&log.Config{IncludeHostname: true}).
+			WithHostname(hostname("replica-1", nil)),
+		expectRus:  "INFO info message host=\"replica-1\"\n",
+		expectZero: "INFO info message host=\"replica-1\"\n",
+	},
+	"pid field": {
+		config: &log.Config{IncludePID: true},
+		expectRus: "INFO info message pid=" +
+			strconv.Itoa(os.Getpid()) + "\n",
+		expectZero: "INFO info message pid=" +
+			strconv.Itoa(os.Getpid()) + "\n",
+	},
+	"hostname and pid field": {
+		config: (&log.Config{IncludeHostname: true, IncludePID: true}).
+			WithHostname(hostname("replica-1", nil)),
+		expectRus: "INFO info message host=\"replica-1\" pid=" +
+			strconv.Itoa(os.Getpid()) + "\n",
+		expectZero: "INFO info message host=\"replica-1\" pid=" +
+			strconv.Itoa(os.Getpid()) + "\n",
+	},
+	"hostname failure": {
+		config: (&log.Config{IncludeHostname: true}).
+			WithHostname(hostname("", errHostname)),
+		expectRus:  "INFO info message\n",
+		expectZero: "INFO info message\n",
+	},
+	"hostname failure debug": {
+		config: (&log.Config{Level: log.LevelDebug, IncludeHostname: true}).
+			WithHostname(hostname("", errHostname)),
+		expectRus: "DEBUG omitting hostname field " +
+			"error=\"hostname lookup\"\nINFO info message\n",
+		expectZero: "DEBUG omitting hostname field " +
+			"error=\"hostname lookup\"\nINFO info message\n",
+	},
+}
+
+func TestStaticFields(t *testing.T) {
+	test.Map(t, testStaticFieldsParams).
+		Run(func(t test.Test, param testStaticFieldsParam) {
+			// Given
+			config := param.config
+			config.TimeFormat = log.TimeFormatNone
+			config.ColorMode = log.ColorModeOff
+			config.OrderMode = log.OrderModeOn
+			rus, zero := &bytes.Buffer{}, &bytes.Buffer{}
+			rlogger := config.SetupRus(rus, logrus.New())
+			zlogger := config.SetupZero(zero).ZeroLogger()
+
+			// When
+			rlogger.Info("info message")
+			zlogger.Info().Msg("info message")
+
+			// Then
+			assert.Equal(t, param.expectRus, rus.String())
+			assert.Equal(t, param.expectZero, zero.String())
+		})
+}
+
+type testParseFieldMapParam struct {
+	fieldMap    map[string]string
+	fields      map[string]string
+	expect      map[string]string
+	expectError error
+}
+
+var testParseFieldMapParams = map[string]testParseFieldMapParam{
+	"field map empty": {},
+	"field map all": {
+		fieldMap: map[string]string{"time": "ts", "level": "lvl", "msg": "m"},
+		expect:   map[string]string{"time": "ts", "level": "lvl", "message": "m"},
+	},
+	"field map unknown": {
+		fieldMap: map[string]string{"caller": "src", "Level": "lvl"},
+		expect:   map[string]string{"level": "lvl"},
+		expectError: errors.Join(
+			log.NewErrFieldMap("caller", "src", "unknown field")),
+	},
+	"field map collision": {
+		fieldMap: map[string]string{"time": "ts", "level": "ts"},
+		expect:   map[string]string{"time": "ts"},
+		expectError: errors.Join(log.NewErrFieldMap("level", "ts",
+			"collides with field [time]")),
+	},
+	"field map static collision": {
+		fieldMap: map[string]string{"message": "service"},
+		fields:   map[string]string{"service": "checkout"},
+		expect:   map[string]string{},
+		expectError: errors.Join(log.NewErrFieldMap("message", "service",
+			"collides with static field")),
+	},
+}
+
+func TestParseFieldMap(t *testing.T) {
+	test.Map(t, testParseFieldMapParams).
+		Run(func(t test.Test, param testParseFieldMapParam) {
+			// Given
+			config := &log.Config{FieldMap: param.fieldMap, Fields: param.fields}
+
+			// When
+			fields, err := config.ParseFieldMap()
+
+			// Then
+			assert.Equal(t, param.expectError, err)
+			assert.Equal(t, param.expect, fields)
+		})
+}
+
+type testFieldMapParam struct {
+	timeFormat string
+	fieldMap   map[string]string
+	expectRus  string
+	expectZero string
+}
+
+var testFieldMapParams = map[string]testFieldMapParam{
+	"field map json": {
+		timeFormat: time.RFC3339,
+		fieldMap:   map[string]string{"time": "ts", "level": "lvl", "message": "msg"},
+		expectRus: `{"key":"value","lvl":"info","msg":"info message",` +
+			`"ts":"2024-01-02T03:04:05Z"}` + "\n",
+		expectZero: `{"lvl":"info","key":"value",` +
+			`"ts":"2024-01-02T03:04:05Z","msg":"info message"}` + "\n",
+	},
+	"field map json epoch": {
+		timeFormat: log.TimeFormatUnix,
+		fieldMap:   map[string]string{"time": "ts"},
+		expectRus: `{"key":"value","level":"info","msg":"info message",` +
+			`"ts":1704164645}` + "\n",
+		expectZero: `{"level":"info","key":"value","ts":1704164645,` +
+			`"message":"info message"}` + "\n",
+	},
+	"field map json default": {
+		timeFormat: log.TimeFormatNone,
+		expectRus:  `{"key":"value","level":"info","msg":"info message"}` + "\n",
+		expectZero: `{"level":"info","key":"value","message":"info message"}` +
+			"\n",
+	},
+}
+
+func TestFieldMap(t *testing.T) {
+	timestamp := zerolog.TimestampFunc
+	t.Cleanup(func() {
+		zerolog.TimeFieldFormat = time.RFC3339
+		zerolog.TimestampFieldName = "time"
+		zerolog.LevelFieldName = "level"
+		zerolog.MessageFieldName = "message"
+		zerolog.TimestampFunc = timestamp
+	})
+	zerolog.TimestampFunc = func() time.Time {
+		return time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	}
+
+	test.Map(t, testFieldMapParams).
+		RunSeq(func(t test.Test, param testFieldMapParam) {
+			// Given
+			config := &log.Config{
+				TimeFormat:   param.timeFormat,
+				TimeLocation: "UTC",
+				Formatter:    log.FormatterJSON,
+				FieldMap:     param.fieldMap,
+			}
+			rus, zero := &bytes.Buffer{}, &bytes.Buffer{}
+			rlogger := config.SetupRus(rus, logrus.New())
+			zlogger := config.SetupZero(zero).ZeroLogger()
+
+			// When
+			rlogger.WithTime(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)).
+				WithField("key", "value").Info("info message")
+			zlogger.Info().Str("key", "value").Msg("info message")
+
+			// Then
+			assert.Equal(t, param.expectRus, rus.String())
+			assert.Equal(t, param.expectZero, zero.String())
+		})
+}
+
+func TestFieldMapCollision(t *testing.T) {
+	// Given
+	config := &log.Config{
+		TimeFormat: log.TimeFormatNone,
+		ColorMode:  log.ColorModeOff,
+		Formatter:  log.FormatterText,
+		FieldMap:   map[string]string{"level": "service"},
+		Fields:     map[string]string{"service": "checkout"},
+	}
+	buffer := &bytes.Buffer{}
+
+	// When
+	config.SetupRus(buffer, logrus.New())
+
+	// Then
+	assert.Equal(t, "level=warning msg=\"setting up field map\" "+
+		"error=\"field map [level=service]: collides with static field\" "+
+		"service=checkout\n", buffer.String())
+}
+
+type testExcludeFieldsParam struct {
+	formatter  log.Formatter
+	json       bool
+	expectRus  string
+	expectZero string
+}
+
+var testExcludeFieldsParams = map[string]testExcludeFieldsParam{
+	"exclude pretty": {
+		formatter:  log.FormatterPretty,
+		expectRus:  "INFO info message user=\"alice\"\n",
+		expectZero: "INFO info message user=\"alice\"\n",
+	},
+	"exclude text": {
+		formatter:  log.FormatterText,
+		expectRus:  "level=info msg=\"info message\" user=alice\n",
+		expectZero: "INF info message user=alice\n",
+	},
+	"exclude json ignored": {
+		formatter: log.FormatterJSON,
+		expectRus: `{"level":"info","msg":"info message",` +
+			`"request_body":"{...}","user":"alice"}` + "\n",
+		expectZero: `{"level":"info","request_body":"{...}",` +
+			`"user":"alice","message":"info message"}` + "\n",
+	},
+	"exclude json": {
+		formatter: log.FormatterJSON,
+		json:      true,
+		expectRus: `{"level":"info","msg":"info message",` +
+			`"user":"alice"}` + "\n",
+		expectZero: `{"level":"info","user":"alice",` +
+			`"message":"info message"}` + "\n",
+	},
+	"exclude ecs": {
+		formatter: log.FormatterECS,
+		json:      true,
+		expectRus: `{"ecs":{"version":"8.11.0"},"log":{"level":"info"},` +
+			`"message":"info message","user":"alice"}` + "\n",
+		expectZero: `{"ecs":{"version":"8.11.0"},"log":{"level":"info"},` +
+			`"message":"info message","user":"alice"}` + "\n",
+	},
+}
+
+func TestExcludeFields(t *testing.T) {
+	test.Map(t, testExcludeFieldsParams).
+		Run(func(t test.Test, param testExcludeFieldsParam) {
+			// Given
+			config := &log.Config{
+				Level:         log.LevelInfo,
+				Formatter:     param.formatter,
+				TimeFormat:    log.TimeFormatNone,
+				ColorMode:     log.ColorModeOff,
+				ExcludeFields: []string{"request_body"},
+				ExcludeJSON:   param.json,
+			}
+			rus, zero := &bytes.Buffer{}, &bytes.Buffer{}
+			rlogger := config.SetupRus(rus, logrus.New())
+			zlogger := config.SetupZero(zero).ZeroLogger()
+
+			// When
+			rlogger.WithField("request_body", "{...}").
+				WithField("user", "alice").Info("info message")
+			zlogger.Info().Str("request_body", "{...}").
+				Str("user", "alice").Msg("info message")
+
+			// Then
+			assert.Equal(t, param.expectRus, rus.String())
+			assert.Equal(t, param.expectZero, zero.String())
+		})
+}
+
+type testFieldOrderParam struct {
+	order      log.OrderModeString
+	fields     []string
+	data       map[string]string
+	expectRus  string
+	expectZero string
+}
+
+var testFieldOrderParams = map[string]testFieldOrderParam{
+	"order on": {
+		order:  log.OrderModeOn,
+		fields: []string{"request_id", "component"},
+		data: map[string]string{
+			"user": "alice", "component": "api", "zone": "eu",
+			"request_id": "r-1",
+		},
+		expectRus: "INFO info message request_id=\"r-1\" component=\"api\" " +
+			"user=\"alice\" zone=\"eu\" revision=\"" + testInfo.Revision + "\"\n",
+		expectZero: "INFO info message request_id=\"r-1\" component=\"api\" " +
+			"user=\"alice\" zone=\"eu\" revision=\"" + testInfo.Revision + "\"\n",
+	},
+	"order off": {
+		order:  log.OrderModeOff,
+		fields: []string{"request_id", "component"},
+		data: map[string]string{
+			"user": "alice", "component": "api", "request_id": "r-1",
+		},
+		expectRus: "INFO info message request_id=\"r-1\" component=\"api\" " +
+			"user=\"alice\" revision=\"" + testInfo.Revision + "\"\n",
+		expectZero: "INFO info message request_id=\"r-1\" component=\"api\" " +
+			"user=\"alice\" revision=\"" + testInfo.Revision + "\"\n",
+	},
+	"order missing": {
+		order:  log.OrderModeOn,
+		fields: []string{"request_id", "component"},
+		data:   map[string]string{"zone": "eu", "component": "api"},
+		expectRus: "INFO info message component=\"api\" zone=\"eu\" " +
+			"revision=\"" + testInfo.Revision + "\"\n",
+		expectZero: "INFO info message component=\"api\" zone=\"eu\" " +
+			"revision=\"" + testInfo.Revision + "\"\n",
+	},
+	"order info field": {
+		order:  log.OrderModeOn,
+		fields: []string{"revision", "component"},
+		data:   map[string]string{"zone": "eu", "component": "api"},
+		expectRus: "INFO info message revision=\"" + testInfo.Revision +
+			"\" component=\"api\" zone=\"eu\"\n",
+		expectZero: "INFO info message revision=\"" + testInfo.Revision +
+			"\" component=\"api\" zone=\"eu\"\n",
+	},
+}
+
+func TestFieldOrder(t *testing.T) {
+	test.Map(t, testFieldOrderParams).
+		Run(func(t test.Test, param testFieldOrderParam) {
+			// Given
+			config := (&log.Config{
+				Level:      log.LevelInfo,
+				TimeFormat: log.TimeFormatNone,
+				ColorMode:  log.ColorModeOff,
+				OrderMode:  param.order,
+				FieldOrder: param.fields,
+				InfoFields: []string{"revision"},
+			}).WithInfo(testInfo)
+			rus, zero := &bytes.Buffer{}, &bytes.Buffer{}
+			rlogger := config.SetupRus(rus, logrus.New())
+			zlogger := config.SetupZero(zero).ZeroLogger()
+
+			// When
+			fields := logrus.Fields{}
+			for key, value := range param.data {
+				fields[key] = value
+			}
+			rlogger.WithFields(fields).Info("info message")
+			zlogger.Info().Fields(map[string]any(fields)).Msg("info message")
+
+			// Then
+			assert.Equal(t, param.expectRus, rus.String())
+			assert.Equal(t, param.expectZero, zero.String())
+		})
+}
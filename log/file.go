@@ -0,0 +1,240 @@
+package log
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Default file permissions of log files created by the file writer.
+const DefaultFileMode os.FileMode = 0o644
+
+// ErrFileOpen is a common error to indicate that a log file cannot be opened.
+var ErrFileOpen = errors.New("file open")
+
+// NewErrFileOpen creates a new error to indicate that the log file with the
+// given path cannot be opened.
+func NewErrFileOpen(path string, err error) error {
+	return fmt.Errorf("%w [%s]: %w", ErrFileOpen, path, err)
+}
+
+// FileWriter is a writer appending to a log file that can be reopened, e.g.
+// after the file was rotated by logrotate. Writes continue concurrently on
+// the reopened file descriptor.
+type FileWriter struct {
+	// path is the path of the log file.
+	path string
+	// mutex synchronizes writes with reopening the file.
+	mutex sync.RWMutex
+	// file is the currently open log file.
+	file *os.File
+	// std is defining whether the file is a standard stream not reopened.
+	std bool
+}
+
+// NewFileWriter creates a new file writer appending to the log file with the
+// given path. The standard streams `/dev/stdout` and `/dev/stderr` are used
+// as is and not reopened.
+func NewFileWriter(path string) (*FileWriter, error) {
+	switch path {
+	case "/dev/stdout":
+		return &FileWriter{path: path, file: os.Stdout, std: true}, nil
+	case "/dev/stderr":
+		return &FileWriter{path: path, file: os.Stderr, std: true}, nil
+	}
+
+	file, err := openFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return &FileWriter{path: path, file: file}, nil
+}
+
+// openFile opens the log file with the given path for appending.
+func openFile(path string) (*os.File, error) {
+	// #nosec G302,G304 // log files are configured by the operator.
+	file, err := os.OpenFile(path,
+		os.O_WRONLY|os.O_APPEND|os.O_CREATE, DefaultFileMode)
+	if err != nil {
+		return nil, NewErrFileOpen(path, err)
+	}
+	return file, nil
+}
+
+// Write writes the given bytes to the currently open log file.
+func (w *FileWriter) Write(p []byte) (int, error) {
+	w.mutex.RLock()
+	defer w.mutex.RUnlock()
+	return w.file.Write(p)
+}
+
+// Fd returns the file descriptor of the currently open log file.
+func (w *FileWriter) Fd() uintptr {
+	w.mutex.RLock()
+	defer w.mutex.RUnlock()
+	return w.file.Fd()
+}
+
+// Reopen opens the log file at the original path anew and swaps it with the
+// currently open log file, that is closed afterwards. If the file cannot be
+// opened, the current log file is kept.
+func (w *FileWriter) Reopen() error {
+	if w.std {
+		return nil
+	}
+
+	file, err := openFile(w.path)
+	if err != nil {
+		return err
+	}
+
+	w.mutex.Lock()
+	old := w.file
+	w.file = file
+	w.mutex.Unlock()
+	return old.Close()
+}
+
+// Close closes the currently open log file. Standard streams are not closed.
+func (w *FileWriter) Close() error {
+	if w.std {
+		return nil
+	}
+
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	return w.file.Close()
+}
+
+// FileWriter returns the file writer for the configured log file opening it
+// on first use. Syslog urls are not supported and return `nil`.
+func (c *Config) FileWriter() (*FileWriter, error) {
+	if IsSyslog(c.File) {
+		return nil, nil
+	}
+
+	loggers := c.setupLoggers()
+	loggers.mutex.Lock()
+	defer loggers.mutex.Unlock()
+	return loggers.setupFile(c)
+}
+
+// setupFile returns the file writer for the configured log file opening it
+// on first use. If the log file has changed, the former file writer is closed
+// and the new log file is opened. The loggers must be locked by the caller.
+func (l *loggers) setupFile(c *Config) (*FileWriter, error) {
+	if l.file != nil {
+		if l.file.path == c.File {
+			return l.file, nil
+		}
+		_ = l.file.Close()
+		l.file = nil
+	}
+
+	file, err := NewFileWriter(c.File)
+	if err != nil {
+		return nil, err
+	}
+	l.file = file
+	return file, nil
+}
+
+// IsFileOutputEnabled returns whether log entries are written additionally
+// to the configured log file using the file formatter.
+func (c *Config) IsFileOutputEnabled() bool {
+	return c.FileFormatter != "" && c.File != "" && !IsSyslog(c.File)
+}
+
+// fileConfig returns a copy of the config using the file formatter as
+// formatter for setting up the additional file output.
+func (c *Config) fileConfig() *Config {
+	config := *c
+	config.Formatter = c.FileFormatter
+	return &config
+}
+
+// LogRusFileHook is a hook writing log entries additionally to the log file
+// using the file formatter independent of the console formatter.
+type LogRusFileHook struct {
+	// file is the file writer of the log file.
+	file *FileWriter
+	// formatter is the formatter used for the log file.
+	formatter logrus.Formatter
+}
+
+// NewLogRusFileHook creates a new file hook for logrus using the file
+// formatter of the given config and the given file writer.
+func NewLogRusFileHook(c *Config, file *FileWriter) *LogRusFileHook {
+	return &LogRusFileHook{
+		file:      file,
+		formatter: c.fileConfig().RusFormatter(file),
+	}
+}
+
+// Levels returns all log levels, since all entries are written to the file.
+func (*LogRusFileHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+// Fire formats the given log entry and writes it to the log file.
+func (h *LogRusFileHook) Fire(entry *logrus.Entry) error {
+	bytes, err := h.formatter.Format(entry)
+	if err != nil || len(bytes) == 0 {
+		return err
+	}
+
+	_, err = h.file.Write(bytes)
+	return err
+}
+
+// ReopenOnSignal registers a handler reopening the log file of the file
+// writer set up via `FileWriter` on the given signal, e.g. `SIGUSR1` or
+// `SIGHUP` send by logrotate. Failures to reopen the file are logged as
+// warning. The returned function deregisters the handler.
+func (c *Config) ReopenOnSignal(sig os.Signal) func() {
+	loggers := c.setupLoggers()
+	signals := make(chan os.Signal, 1)
+	done := make(chan struct{})
+	signal.Notify(signals, sig)
+
+	go func() {
+		for {
+			select {
+			case <-signals:
+				loggers.reopen()
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			signal.Stop(signals)
+			close(done)
+		})
+	}
+}
+
+// reopen reopens the log file of the file writer, if any, logging failures
+// as warning via the zerolog or logrus logger set up by the config.
+func (l *loggers) reopen() {
+	l.mutex.RLock()
+	defer l.mutex.RUnlock()
+	if l.file == nil {
+		return
+	}
+
+	if err := l.file.Reopen(); err != nil {
+		if l.zero != nil {
+			l.zero.Warn().Err(err).Msg("reopening log file")
+		} else if l.rus != nil {
+			l.rus.WithError(err).Warn("reopening log file")
+		}
+	}
+}
@@ -0,0 +1,238 @@
+package log_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tkrop/go-testing/test"
+
+	"github.com/tkrop/go-config/log"
+)
+
+// readFile returns the content of the file with the given path.
+func readFile(t test.Test, path string) string {
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	return string(content)
+}
+
+type testFileWriterParam struct {
+	path        string
+	expectError error
+}
+
+var testFileWriterParams = map[string]testFileWriterParam{
+	"file": {
+		path: "test.log",
+	},
+	"stdout": {
+		path: "/dev/stdout",
+	},
+	"stderr": {
+		path: "/dev/stderr",
+	},
+	"missing dir": {
+		path:        "missing/test.log",
+		expectError: log.ErrFileOpen,
+	},
+}
+
+func TestFileWriter(t *testing.T) {
+	test.Map(t, testFileWriterParams).
+		Run(func(t test.Test, param testFileWriterParam) {
+			// Given
+			path := param.path
+			if !filepath.IsAbs(path) {
+				path = filepath.Join(t.TempDir(), path)
+			}
+
+			// When
+			writer, err := log.NewFileWriter(path)
+
+			// Then
+			if param.expectError != nil {
+				assert.ErrorIs(t, err, param.expectError)
+				assert.Nil(t, writer)
+				return
+			}
+			require.NoError(t, err)
+			assert.NoError(t, writer.Reopen())
+			assert.NotZero(t, writer.Fd())
+			assert.NoError(t, writer.Close())
+		})
+}
+
+func TestFileWriterReopen(t *testing.T) {
+	// Given
+	path := filepath.Join(t.TempDir(), "test.log")
+	writer, err := log.NewFileWriter(path)
+	require.NoError(t, err)
+	defer writer.Close()
+
+	var wg sync.WaitGroup
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 100 {
+				_, err := writer.Write([]byte("a\n"))
+				assert.NoError(t, err)
+			}
+		}()
+	}
+
+	// When
+	for range 10 {
+		require.NoError(t, writer.Reopen())
+	}
+	wg.Wait()
+
+	// Then
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Len(t, content, 800)
+}
+
+func TestReopenOnSignal(t *testing.T) {
+	// Given
+	path := filepath.Join(t.TempDir(), "test.log")
+	config := &log.Config{File: path}
+	writer, err := config.FileWriter()
+	require.NoError(t, err)
+	defer writer.Close()
+	stop := config.ReopenOnSignal(syscall.SIGHUP)
+	defer stop()
+
+	_, err = writer.Write([]byte("a\n"))
+	require.NoError(t, err)
+	require.NoError(t, os.Rename(path, path+".1"))
+	_, err = writer.Write([]byte("b\n"))
+	require.NoError(t, err)
+
+	// When
+	process, err := os.FindProcess(os.Getpid())
+	require.NoError(t, err)
+	require.NoError(t, process.Signal(syscall.SIGHUP))
+	require.Eventually(t, func() bool {
+		_, err := os.Stat(path)
+		return err == nil
+	}, time.Second, time.Millisecond)
+	_, err = writer.Write([]byte("c\n"))
+	require.NoError(t, err)
+
+	// Then
+	assert.Equal(t, "a\nb\n", readFile(t, path+".1"))
+	assert.Equal(t, "c\n", readFile(t, path))
+	same, err := config.FileWriter()
+	require.NoError(t, err)
+	assert.Same(t, writer, same)
+}
+
+func TestFileWriterSyslog(t *testing.T) {
+	// Given
+	config := &log.Config{File: "syslog://localhost:514"}
+
+	// When
+	writer, err := config.FileWriter()
+
+	// Then
+	assert.NoError(t, err)
+	assert.Nil(t, writer)
+}
+
+type testFileOutputParam struct {
+	setup      func(config *log.Config, output io.Writer)
+	expectFile string
+}
+
+var testFileOutputParams = map[string]testFileOutputParam{
+	"logrus": {
+		setup: func(config *log.Config, output io.Writer) {
+			config.SetupRus(output, logrus.New()).
+				WithField("key", "value").Info("info message")
+		},
+		expectFile: `{"key":"value","level":"info","msg":"info message"}` + "\n",
+	},
+	"zerolog": {
+		setup: func(config *log.Config, output io.Writer) {
+			logger := config.SetupZeroLogger(zerolog.New(io.Discard), output)
+			logger.Info().Str("key", "value").Msg("info message")
+		},
+		expectFile: `{"level":"info","key":"value","message":"info message"}` +
+			"\n",
+	},
+}
+
+func TestFileOutputChanged(t *testing.T) {
+	// Given
+	dir := t.TempDir()
+	first, second := filepath.Join(dir, "first.log"),
+		filepath.Join(dir, "second.log")
+	config := &log.Config{
+		Level:         log.LevelInfo,
+		TimeFormat:    log.TimeFormatNone,
+		ColorMode:     log.ColorModeOff,
+		File:          first,
+		FileFormatter: log.FormatterText,
+	}
+	logger := config.SetupRus(io.Discard, logrus.New())
+	logger.Info("first message")
+	writer, err := config.FileWriter()
+	require.NoError(t, err)
+
+	// When
+	config.File = second
+	logger = config.SetupRus(io.Discard, logger)
+	logger.Info("second message")
+
+	// Then
+	assert.Equal(t, "level=info msg=\"first message\"\n", readFile(t, first))
+	assert.Equal(t, "level=info msg=\"second message\"\n", readFile(t, second))
+	_, err = writer.Write([]byte("closed\n"))
+	assert.Error(t, err)
+	file, err := config.FileWriter()
+	require.NoError(t, err)
+	assert.NotSame(t, writer, file)
+	assert.NoError(t, file.Close())
+}
+
+func TestFileOutput(t *testing.T) {
+	test.Map(t, testFileOutputParams).
+		RunSeq(func(t test.Test, param testFileOutputParam) {
+			// Given
+			path := filepath.Join(t.TempDir(), "test.log")
+			output := &bytes.Buffer{}
+			config := &log.Config{
+				Level:         log.LevelInfo,
+				TimeFormat:    log.TimeFormatNone,
+				ColorMode:     log.ColorModeOff,
+				Formatter:     log.FormatterPretty,
+				File:          path,
+				FileFormatter: log.FormatterJSON,
+			}
+
+			// When
+			param.setup(config, output)
+
+			// Then
+			assert.Equal(t, "INFO info message key=\"value\"\n",
+				output.String())
+			content := readFile(t, path)
+			assert.Equal(t, param.expectFile, content)
+			assert.True(t, json.Valid([]byte(content)))
+			file, err := config.FileWriter()
+			require.NoError(t, err)
+			assert.NoError(t, file.Close())
+		})
+}
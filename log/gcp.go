@@ -0,0 +1,96 @@
+package log
+
+import (
+	"io"
+	"maps"
+	"strconv"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// GCPSourceLocation is the field name of the caller source location in Google
+// Cloud Logging.
+const GCPSourceLocation = "logging.googleapis.com/sourceLocation"
+
+// gcpSeverities maps the log levels to the Google Cloud Logging severities.
+var gcpSeverities = []string{
+	"CRITICAL", "ALERT", "ERROR", "WARNING", "INFO", "DEBUG", "DEBUG",
+}
+
+// GCPSeverity returns the Google Cloud Logging severity of the given level.
+func GCPSeverity(level Level) string {
+	if int(level) < len(gcpSeverities) {
+		return gcpSeverities[level]
+	}
+	return "DEFAULT"
+}
+
+// FormatGCP formats the given log entry as JSON understood by the Google Cloud
+// Logging agent. The logging fields take precedence over clashing user fields.
+func (s *Setup) FormatGCP(entry *Entry) ([]byte, error) {
+	data := make(map[string]any, len(entry.Fields)+5)
+	maps.Copy(data, entry.Fields)
+
+	if entry.Time != nil {
+		data["time"] = s.Time(*entry.Time).Format(time.RFC3339Nano)
+	}
+	data["severity"] = GCPSeverity(entry.Level)
+	data["message"] = entry.Message
+	if entry.Error != "" {
+		data[s.ErrorName] = entry.Error
+	}
+
+	if entry.File != "" || entry.Function != "" {
+		location := map[string]any{}
+		if entry.File != "" {
+			location["file"] = entry.File
+			location["line"] = strconv.Itoa(entry.Line)
+		}
+		if entry.Function != "" {
+			location["function"] = entry.Function
+		}
+		data[GCPSourceLocation] = location
+	}
+
+	return encodeJSON(data)
+}
+
+// LogRusGCP is a logrus formatter writing JSON understood by the Google Cloud
+// Logging agent.
+type LogRusGCP struct {
+	// Setup provides the setup for formatting logs.
+	*Setup
+}
+
+// NewLogRusGCP creates a new Google Cloud Logging formatter for logrus using
+// the given config.
+func NewLogRusGCP(c *Config, writer io.Writer) *LogRusGCP {
+	return &LogRusGCP{Setup: c.Setup(writer)}
+}
+
+// Format formats the log entry as Google Cloud Logging JSON.
+func (f *LogRusGCP) Format(entry *logrus.Entry) ([]byte, error) {
+	return f.FormatGCP(f.RusEntry(entry))
+}
+
+// ZeroLogGCP is a zerolog writer rewriting JSON events into JSON understood
+// by the Google Cloud Logging agent.
+type ZeroLogGCP struct {
+	// Setup provides the setup for formatting logs.
+	*Setup
+	// writer is the writer for the Google Cloud Logging JSON.
+	writer io.Writer
+}
+
+// NewZeroLogGCP creates a new Google Cloud Logging writer for zerolog using
+// the given config.
+func NewZeroLogGCP(c *Config, writer io.Writer) *ZeroLogGCP {
+	return &ZeroLogGCP{Setup: c.Setup(writer), writer: writer}
+}
+
+// Write rewrites the given JSON event into Google Cloud Logging JSON and
+// writes it to the underlying writer.
+func (w *ZeroLogGCP) Write(p []byte) (int, error) {
+	return w.ZeroWrite(w.writer, p, w.FormatGCP)
+}
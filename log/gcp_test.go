@@ -0,0 +1,85 @@
+package log_test
+
+import (
+	"bytes"
+	"errors"
+	"runtime"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tkrop/go-testing/test"
+
+	"github.com/tkrop/go-config/log"
+)
+
+type testGCPParam struct {
+	level log.Level
+}
+
+var testGCPParams = map[string]testGCPParam{
+	"level panic": {level: log.PanicLevel},
+	"level fatal": {level: log.FatalLevel},
+	"level error": {level: log.ErrorLevel},
+	"level warn":  {level: log.WarnLevel},
+	"level info":  {level: log.InfoLevel},
+	"level debug": {level: log.DebugLevel},
+	"level trace": {level: log.TraceLevel},
+}
+
+func TestGCPRus(t *testing.T) {
+	test.Map(t, testGCPParams).
+		Run(func(t test.Test, param testGCPParam) {
+			// Given
+			config := &log.Config{TimeLocation: "UTC"}
+			formatter := log.NewLogRusGCP(config, &bytes.Buffer{})
+			logger := logrus.New()
+			logger.SetReportCaller(true)
+			name := log.AllLevels[param.level]
+			entry := &logrus.Entry{
+				Logger: logger,
+				Time:   ecsTime,
+				// #nosec G115 // cannot happen.
+				Level:   logrus.Level(param.level),
+				Message: name + " message",
+				Data: logrus.Fields{
+					"key":   "value",
+					"error": errors.New("failure"),
+				},
+				Caller: &runtime.Frame{File: "/app/main.go", Line: 42},
+			}
+
+			// When
+			result, err := formatter.Format(entry)
+
+			// Then
+			require.NoError(t, err)
+			assert.JSONEq(t, readGolden(t, "gcp-"+name+".json"),
+				string(result))
+		})
+}
+
+func TestGCPZero(t *testing.T) {
+	test.Map(t, testGCPParams).
+		Run(func(t test.Test, param testGCPParam) {
+			// Given
+			config := &log.Config{TimeLocation: "UTC"}
+			buffer := &bytes.Buffer{}
+			writer := log.NewZeroLogGCP(config, buffer)
+			name := log.AllLevels[param.level]
+			event := `{"level":"` + name + `","key":"value",` +
+				`"error":"failure","caller":"/app/main.go:42",` +
+				`"time":"2024-01-02T03:04:05.678Z",` +
+				`"message":"` + name + ` message"}` + "\n"
+
+			// When
+			n, err := writer.Write([]byte(event))
+
+			// Then
+			require.NoError(t, err)
+			assert.Equal(t, len(event), n)
+			assert.JSONEq(t, readGolden(t, "gcp-"+name+".json"),
+				buffer.String())
+		})
+}
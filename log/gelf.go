@@ -0,0 +1,297 @@
+package log
+
+import (
+	"bytes"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// GELFVersion is the version of the Graylog Extended Log Format (GELF)
+	// implemented by the GELF formatters.
+	GELFVersion = "1.1"
+	// DefaultGELFPort is the default port of GELF endpoints.
+	DefaultGELFPort = "12201"
+	// DefaultGELFChunkSize is the default maximum size of GELF UDP datagrams
+	// suitable for WAN connections.
+	DefaultGELFChunkSize = 1420
+	// gelfChunkHeader is the size of the GELF chunk header.
+	gelfChunkHeader = 12
+	// gelfChunkMax is the maximum number of GELF chunks per message.
+	gelfChunkMax = 128
+)
+
+var (
+	// gelfNetworks maps the GELF url schemes to the networks.
+	gelfNetworks = map[string]string{
+		"gelf": "udp", "gelf+udp": "udp", "gelf+tcp": "tcp",
+	}
+
+	// gelfFieldRegex matches characters not allowed in GELF field names.
+	// Dots are replaced as well, since they are interpreted as nesting.
+	gelfFieldRegex = regexp.MustCompile(`[^\w-]`)
+
+	// gelfChunkMagic is the magic prefix of GELF chunks.
+	gelfChunkMagic = []byte{0x1e, 0x0f}
+)
+
+// GELFField returns the additional GELF field name of the given field name by
+// prefixing the sanitized name with `_`. Characters not allowed in GELF field
+// names, including dots, are replaced with `_`, and the reserved field `_id`
+// is escaped as `__id`.
+func GELFField(name string) string {
+	name = gelfFieldRegex.ReplaceAllString(name, "_")
+	if name == "id" {
+		return "__id"
+	}
+	return "_" + name
+}
+
+// FormatGELF formats the given log entry as GELF message. The first line of
+// the message is used as short message, while multiline messages are also
+// reported completely as full message.
+func (s *Setup) FormatGELF(entry *Entry) ([]byte, error) {
+	data := make(map[string]any, len(entry.Fields)+10)
+	for key, value := range entry.Fields {
+		data[GELFField(key)] = value
+	}
+
+	short, _, multiline := strings.Cut(entry.Message, "\n")
+	data["version"] = GELFVersion
+	data["host"] = s.Hostname
+	data["short_message"] = short
+	if multiline {
+		data["full_message"] = entry.Message
+	}
+	if entry.Time != nil {
+		data["timestamp"] = float64(entry.Time.UnixMilli()) / 1000
+	}
+	severity := SyslogDebug
+	if int(entry.Level) < len(syslogSeverities) {
+		severity = syslogSeverities[entry.Level]
+	}
+	data["level"] = severity
+
+	if entry.Error != "" {
+		data[GELFField(s.ErrorName)] = entry.Error
+	}
+	if entry.File != "" {
+		data["_file"] = entry.File
+		data["_line"] = entry.Line
+	}
+	if entry.Function != "" {
+		data["_function"] = entry.Function
+	}
+
+	return encodeJSON(data)
+}
+
+// LogRusGELF is a logrus formatter writing GELF messages.
+type LogRusGELF struct {
+	// Setup provides the setup for formatting logs.
+	*Setup
+}
+
+// NewLogRusGELF creates a new GELF formatter for logrus using the given
+// config. The hostname is resolved once on creation.
+func NewLogRusGELF(c *Config, writer io.Writer) *LogRusGELF {
+	setup := c.Setup(writer)
+	setup.Hostname, _ = c.LookupHostname()
+	return &LogRusGELF{Setup: setup}
+}
+
+// Format formats the log entry as GELF message.
+func (f *LogRusGELF) Format(entry *logrus.Entry) ([]byte, error) {
+	return f.FormatGELF(f.RusEntry(entry))
+}
+
+// ZeroLogGELF is a zerolog writer rewriting JSON events into GELF messages.
+type ZeroLogGELF struct {
+	// Setup provides the setup for formatting logs.
+	*Setup
+	// writer is the writer for the GELF messages.
+	writer io.Writer
+}
+
+// NewZeroLogGELF creates a new GELF writer for zerolog using the given config.
+// The hostname is resolved once on creation.
+func NewZeroLogGELF(c *Config, writer io.Writer) *ZeroLogGELF {
+	setup := c.Setup(writer)
+	setup.Hostname, _ = c.LookupHostname()
+	return &ZeroLogGELF{Setup: setup, writer: writer}
+}
+
+// Write rewrites the given JSON event into a GELF message and writes it to
+// the underlying writer.
+func (w *ZeroLogGELF) Write(p []byte) (int, error) {
+	return w.ZeroWrite(w.writer, p, w.FormatGELF)
+}
+
+// ErrGELF is a common error to indicate a GELF setup or write error.
+var ErrGELF = errors.New("gelf")
+
+// NewErrGELF is a convenience method to create a new GELF error with the
+// given message and endpoint wrapping the original error.
+func NewErrGELF(message, endpoint string, err error) error {
+	return fmt.Errorf("%w - %s [%s]: %w", ErrGELF, message, endpoint, err)
+}
+
+// GELFWriter is a writer sending each written GELF message to a GELF endpoint.
+// Via UDP large messages are split into chunks, while via TCP messages are
+// delimited by null bytes. The writer reconnects on failures of the network
+// connection.
+type GELFWriter struct {
+	// network is the network used for connecting the endpoint.
+	network string
+	// address is the address of the endpoint.
+	address string
+	// chunk is the maximum size of UDP datagrams.
+	chunk int
+
+	// conn is the current network connection.
+	conn net.Conn
+	// mutex is the mutex to synchronize writes.
+	mutex sync.Mutex
+}
+
+// NewGELFWriter creates a new GELF writer for the given endpoint url, e.g.
+// `gelf://localhost:12201?chunk=8154`. The schemes `gelf` and `gelf+udp` are
+// using UDP with the given maximum chunk size (default 1420), while `gelf+tcp`
+// is using TCP. The connection is established lazily on first write.
+func NewGELFWriter(endpoint string) (*GELFWriter, error) {
+	uri, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, NewErrGELF("parsing url", endpoint, err)
+	}
+
+	network, ok := gelfNetworks[uri.Scheme]
+	if !ok {
+		return nil, NewErrGELF("unknown scheme", endpoint,
+			errors.New(uri.Scheme))
+	}
+
+	chunk := DefaultGELFChunkSize
+	if value := uri.Query().Get("chunk"); value != "" {
+		if chunk, err = strconv.Atoi(value); err != nil ||
+			chunk <= gelfChunkHeader {
+			return nil, NewErrGELF("invalid chunk size", endpoint,
+				errors.New(value))
+		}
+	}
+
+	address := uri.Host
+	if uri.Port() == "" {
+		address = net.JoinHostPort(uri.Hostname(), DefaultGELFPort)
+	}
+
+	return &GELFWriter{
+		network: network,
+		address: address,
+		chunk:   chunk,
+	}, nil
+}
+
+// Write writes the given GELF message to the endpoint. On failure the
+// connection is reestablished and the write is retried once.
+func (w *GELFWriter) Write(p []byte) (int, error) {
+	packets, err := w.packets(bytes.TrimRight(p, "\n"))
+	if err != nil {
+		return 0, NewErrGELF("writing message", w.address, err)
+	}
+
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	if err := w.write(packets); err != nil {
+		w.close()
+		if err := w.write(packets); err != nil {
+			return 0, NewErrGELF("writing message", w.address, err)
+		}
+	}
+	return len(p), nil
+}
+
+// packets returns the network packets for the given message. Via TCP the
+// message is terminated by a null byte, while via UDP messages exceeding the
+// chunk size are split into chunks.
+func (w *GELFWriter) packets(message []byte) ([][]byte, error) {
+	if w.network == "tcp" {
+		return [][]byte{append(message, 0)}, nil
+	} else if len(message) <= w.chunk {
+		return [][]byte{message}, nil
+	}
+	return GELFChunks(message, w.chunk)
+}
+
+// GELFChunks splits the given message into GELF chunks not exceeding the given
+// chunk size including the chunk header. An error is returned, if the message
+// requires more than 128 chunks.
+func GELFChunks(message []byte, size int) ([][]byte, error) {
+	data := size - gelfChunkHeader
+	count := (len(message) + data - 1) / data
+	if count > gelfChunkMax {
+		return nil, fmt.Errorf("message too large [%d chunks]", count)
+	}
+
+	id := make([]byte, 8)
+	if _, err := rand.Read(id); err != nil {
+		return nil, err
+	}
+
+	chunks := make([][]byte, 0, count)
+	for seq := 0; seq < count; seq++ {
+		part := message[seq*data : min((seq+1)*data, len(message))]
+		chunk := make([]byte, 0, gelfChunkHeader+len(part))
+		chunk = append(chunk, gelfChunkMagic...)
+		chunk = append(chunk, id...)
+		// #nosec G115 // cannot happen, since count <= 128.
+		chunk = append(chunk, byte(seq), byte(count))
+		chunks = append(chunks, append(chunk, part...))
+	}
+	return chunks, nil
+}
+
+// write writes the given packets to the current connection. If no connection
+// is available, a new connection is established.
+func (w *GELFWriter) write(packets [][]byte) error {
+	if w.conn == nil {
+		conn, err := net.Dial(w.network, w.address)
+		if err != nil {
+			return err
+		}
+		w.conn = conn
+	}
+
+	for _, packet := range packets {
+		if _, err := w.conn.Write(packet); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Close closes the current connection of the GELF writer.
+func (w *GELFWriter) Close() error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	return w.close()
+}
+
+// close closes the current connection without locking.
+func (w *GELFWriter) close() error {
+	if w.conn != nil {
+		err := w.conn.Close()
+		w.conn = nil
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,302 @@
+package log_test
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tkrop/go-testing/test"
+
+	"github.com/tkrop/go-config/log"
+)
+
+type testGELFFieldParam struct {
+	name   string
+	expect string
+}
+
+var testGELFFieldParams = map[string]testGELFFieldParam{
+	"field plain":    {name: "key", expect: "_key"},
+	"field dotted":   {name: "http.method", expect: "_http_method"},
+	"field invalid":  {name: "a b/c", expect: "_a_b_c"},
+	"field dash":     {name: "request-id", expect: "_request-id"},
+	"field reserved": {name: "id", expect: "__id"},
+}
+
+func TestGELFField(t *testing.T) {
+	test.Map(t, testGELFFieldParams).
+		Run(func(t test.Test, param testGELFFieldParam) {
+			// When
+			field := log.GELFField(param.name)
+
+			// Then
+			assert.Equal(t, param.expect, field)
+		})
+}
+
+type testGELFParam struct {
+	level   log.Level
+	message string
+	expect  map[string]any
+}
+
+var testGELFParams = map[string]testGELFParam{
+	"gelf error": {
+		level:   log.ErrorLevel,
+		message: "error message",
+		expect: map[string]any{
+			"version":       "1.1",
+			"host":          "replica-1",
+			"short_message": "error message",
+			"timestamp":     1704164645.678,
+			"level":         float64(3),
+			"_error":        "failure",
+			"_http_method":  "GET",
+			"__id":          "42",
+			"_file":         "/app/main.go",
+			"_line":         float64(42),
+		},
+	},
+	"gelf info multiline": {
+		level:   log.InfoLevel,
+		message: "info message\nwith details",
+		expect: map[string]any{
+			"version":       "1.1",
+			"host":          "replica-1",
+			"short_message": "info message",
+			"full_message":  "info message\nwith details",
+			"timestamp":     1704164645.678,
+			"level":         float64(6),
+			"_error":        "failure",
+			"_http_method":  "GET",
+			"__id":          "42",
+			"_file":         "/app/main.go",
+			"_line":         float64(42),
+		},
+	},
+}
+
+// gelfConfig creates a GELF config with a fixed hostname.
+func gelfConfig() *log.Config {
+	return (&log.Config{Formatter: log.FormatterGELF}).
+		WithHostname(hostname("replica-1", nil))
+}
+
+// decodeGELF decodes the given GELF message.
+func decodeGELF(t test.Test, message []byte) map[string]any {
+	result := map[string]any{}
+	require.NoError(t, json.Unmarshal(message, &result))
+	return result
+}
+
+func TestGELFRus(t *testing.T) {
+	test.Map(t, testGELFParams).
+		Run(func(t test.Test, param testGELFParam) {
+			// Given
+			formatter := log.NewLogRusGELF(gelfConfig(), &bytes.Buffer{})
+			logger := logrus.New()
+			logger.SetReportCaller(true)
+			entry := &logrus.Entry{
+				Logger: logger,
+				Time:   ecsTime,
+				// #nosec G115 // cannot happen.
+				Level:   logrus.Level(param.level),
+				Message: param.message,
+				Data: logrus.Fields{
+					"http.method": "GET",
+					"id":          "42",
+					"error":       errors.New("failure"),
+				},
+				Caller: &runtime.Frame{File: "/app/main.go", Line: 42},
+			}
+
+			// When
+			result, err := formatter.Format(entry)
+
+			// Then
+			require.NoError(t, err)
+			assert.Equal(t, param.expect, decodeGELF(t, result))
+		})
+}
+
+func TestGELFZero(t *testing.T) {
+	test.Map(t, testGELFParams).
+		Run(func(t test.Test, param testGELFParam) {
+			// Given
+			buffer := &bytes.Buffer{}
+			writer := log.NewZeroLogGELF(gelfConfig(), buffer)
+			message, err := json.Marshal(param.message)
+			require.NoError(t, err)
+			event := `{"level":"` + log.AllLevels[param.level] + `",` +
+				`"http.method":"GET","id":"42","error":"failure",` +
+				`"caller":"/app/main.go:42",` +
+				`"time":"2024-01-02T03:04:05.678Z",` +
+				`"message":` + string(message) + `}` + "\n"
+
+			// When
+			n, err := writer.Write([]byte(event))
+
+			// Then
+			require.NoError(t, err)
+			assert.Equal(t, len(event), n)
+			assert.Equal(t, param.expect, decodeGELF(t, buffer.Bytes()))
+		})
+}
+
+type testNewGELFWriterParam struct {
+	endpoint      string
+	expectNetwork string
+	expectAddress string
+	expectChunk   int
+	expectError   error
+}
+
+var testNewGELFWriterParams = map[string]testNewGELFWriterParam{
+	"gelf default": {
+		endpoint:      "gelf://localhost",
+		expectNetwork: "udp",
+		expectAddress: "localhost:12201",
+		expectChunk:   log.DefaultGELFChunkSize,
+	},
+	"gelf udp chunk": {
+		endpoint:      "gelf+udp://localhost:1220?chunk=8154",
+		expectNetwork: "udp",
+		expectAddress: "localhost:1220",
+		expectChunk:   8154,
+	},
+	"gelf tcp": {
+		endpoint:      "gelf+tcp://localhost",
+		expectNetwork: "tcp",
+		expectAddress: "localhost:12201",
+		expectChunk:   log.DefaultGELFChunkSize,
+	},
+	"gelf unknown scheme": {
+		endpoint: "gelf+http://localhost",
+		expectError: log.NewErrGELF("unknown scheme",
+			"gelf+http://localhost", errors.New("gelf+http")),
+	},
+	"gelf invalid chunk": {
+		endpoint: "gelf://localhost?chunk=12",
+		expectError: log.NewErrGELF("invalid chunk size",
+			"gelf://localhost?chunk=12", errors.New("12")),
+	},
+}
+
+func TestNewGELFWriter(t *testing.T) {
+	test.Map(t, testNewGELFWriterParams).
+		Run(func(t test.Test, param testNewGELFWriterParam) {
+			// When
+			writer, err := log.NewGELFWriter(param.endpoint)
+
+			// Then
+			if param.expectError != nil {
+				assert.ErrorIs(t, err, log.ErrGELF)
+				assert.Equal(t, param.expectError.Error(), err.Error())
+				assert.Nil(t, writer)
+				return
+			}
+
+			require.NoError(t, err)
+			accessor := test.NewAccessor(writer)
+			assert.Equal(t, param.expectNetwork, accessor.Get("network"))
+			assert.Equal(t, param.expectAddress, accessor.Get("address"))
+			assert.Equal(t, param.expectChunk, accessor.Get("chunk"))
+		})
+}
+
+// receiveGELF receives the given number of GELF packets from the listener.
+func receiveGELF(t test.Test, conn net.PacketConn, count int) [][]byte {
+	packets := make([][]byte, 0, count)
+	for range count {
+		buffer := make([]byte, 65536)
+		require.NoError(t, conn.SetReadDeadline(time.Now().Add(time.Second)))
+		n, _, err := conn.ReadFrom(buffer)
+		require.NoError(t, err)
+		packets = append(packets, buffer[:n])
+	}
+	return packets
+}
+
+func TestGELFWriterUDP(t *testing.T) {
+	// Given
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer conn.Close()
+	writer, err := log.NewGELFWriter("gelf://" +
+		conn.LocalAddr().String() + "?chunk=200")
+	require.NoError(t, err)
+	defer writer.Close()
+	logger := gelfConfig().SetupRus(writer, logrus.New())
+	message := strings.Repeat("large message ", 40)
+
+	// When
+	logger.Info("small message")
+	logger.Info(message)
+
+	// Then
+	small := receiveGELF(t, conn, 1)[0]
+	assert.Equal(t, "small message", decodeGELF(t, small)["short_message"])
+
+	first := receiveGELF(t, conn, 1)[0]
+	require.Equal(t, []byte{0x1e, 0x0f}, first[:2])
+	count := int(first[11])
+	require.Greater(t, count, 1)
+	chunks := append([][]byte{first}, receiveGELF(t, conn, count-1)...)
+
+	assembled := make([][]byte, count)
+	for _, chunk := range chunks {
+		assert.LessOrEqual(t, len(chunk), 200)
+		assert.Equal(t, first[:10], chunk[:10])
+		assert.Equal(t, byte(count), chunk[11])
+		assembled[chunk[10]] = chunk[12:]
+	}
+	assert.Equal(t, message,
+		decodeGELF(t, bytes.Join(assembled, nil))["short_message"])
+}
+
+func TestGELFWriterTCP(t *testing.T) {
+	// Given
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+	writer, err := log.NewGELFWriter("gelf+tcp://" +
+		listener.Addr().String())
+	require.NoError(t, err)
+	defer writer.Close()
+	logger := gelfConfig().SetupZero(writer).ZeroLogger()
+
+	// When
+	logger.Info().Msg("first message")
+	logger.Info().Msg("second message")
+
+	// Then
+	conn, err := listener.Accept()
+	require.NoError(t, err)
+	defer conn.Close()
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(time.Second)))
+	reader := bufio.NewReader(conn)
+	for _, expect := range []string{"first message", "second message"} {
+		message, err := reader.ReadBytes(0)
+		require.NoError(t, err)
+		assert.Equal(t, expect,
+			decodeGELF(t, message[:len(message)-1])["short_message"])
+	}
+}
+
+func TestGELFChunksTooLarge(t *testing.T) {
+	// When
+	chunks, err := log.GELFChunks(make([]byte, 129*8), 20)
+
+	// Then
+	assert.EqualError(t, err, "message too large [129 chunks]")
+	assert.Nil(t, chunks)
+}
@@ -0,0 +1,28 @@
+package log
+
+import (
+	"io"
+
+	"github.com/rs/zerolog"
+	zlog "github.com/rs/zerolog/log"
+	"github.com/sirupsen/logrus"
+)
+
+// SetupGlobals sets up the global loggers, i.e. the package-level zerolog
+// logger `log.Logger` and the logrus standard logger, from the config using
+// the given writer. Both loggers are set up the same way as by `SetupZero`
+// and `SetupRus` including formatter, level, caller, and hooks, and become
+// the loggers of the config, i.e. they replace loggers set up before and are
+// replaced by loggers set up afterwards. Repeated calls are idempotent, since
+// the hooks of the standard logger and the context of the package-level
+// logger are replaced. Setting up the package-level zerolog logger is not
+// safe for concurrent use with logging via the package-level logger.
+func (c *Config) SetupGlobals(writer io.Writer) *Config {
+	loggers := c.setupLoggers()
+	loggers.mutex.Lock()
+	defer loggers.mutex.Unlock()
+
+	c.setupRus(loggers, writer, logrus.StandardLogger())
+	zlog.Logger = c.setupZero(loggers, zerolog.New(writer), writer)
+	return c
+}
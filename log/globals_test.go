@@ -0,0 +1,78 @@
+package log_test
+
+import (
+	"bytes"
+	"testing"
+
+	zlog "github.com/rs/zerolog/log"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/tkrop/go-testing/test"
+
+	"github.com/tkrop/go-config/log"
+)
+
+// restoreGlobals restores the global loggers after the test.
+func restoreGlobals(t test.Test) {
+	zero, rus := zlog.Logger, logrus.StandardLogger()
+	out, formatter, level := rus.Out, rus.Formatter, rus.GetLevel()
+	hooks, caller := logrus.LevelHooks{}, rus.ReportCaller
+	for level, list := range rus.Hooks {
+		hooks[level] = append(hooks[level], list...)
+	}
+	t.Cleanup(func() {
+		zlog.Logger = zero
+		rus.SetOutput(out)
+		rus.SetFormatter(formatter)
+		rus.SetLevel(level)
+		rus.ReplaceHooks(hooks)
+		rus.SetReportCaller(caller)
+	})
+}
+
+type testSetupGlobalsParam struct {
+	calls  int
+	expect string
+}
+
+var testSetupGlobalsParams = map[string]testSetupGlobalsParam{
+	"once": {
+		calls: 1,
+		expect: "INFO rus message key=\"value\" service=\"test\"\n" +
+			"INFO zero message key=\"value\" service=\"test\"\n",
+	},
+	"repeated": {
+		calls: 3,
+		expect: "INFO rus message key=\"value\" service=\"test\"\n" +
+			"INFO zero message key=\"value\" service=\"test\"\n",
+	},
+}
+
+func TestSetupGlobals(t *testing.T) {
+	test.Map(t, testSetupGlobalsParams).
+		RunSeq(func(t test.Test, param testSetupGlobalsParam) {
+			// Given
+			restoreGlobals(t)
+			buffer := &bytes.Buffer{}
+			config := &log.Config{
+				Level:      log.LevelInfo,
+				TimeFormat: log.TimeFormatNone,
+				ColorMode:  log.ColorModeOff,
+				Fields:     map[string]string{"service": "test"},
+			}
+
+			// When
+			for range param.calls {
+				config.SetupGlobals(buffer)
+			}
+			logrus.WithField("key", "value").Info("rus message")
+			logrus.Debug("rus debug")
+			zlog.Info().Str("key", "value").Msg("zero message")
+			zlog.Debug().Msg("zero debug")
+
+			// Then
+			assert.Equal(t, param.expect, buffer.String())
+			assert.Same(t, logrus.StandardLogger(), config.RusLogger())
+			assert.Equal(t, zlog.Logger, config.ZeroLogger())
+		})
+}
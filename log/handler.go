@@ -0,0 +1,140 @@
+package log
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// LevelRequest is the request body for changing the log level via the level
+// handler.
+type LevelRequest struct {
+	// Level is the new log level.
+	Level string `json:"level"`
+	// TTL is the optional duration after which the previous log level is
+	// restored, e.g. `10m`.
+	TTL string `json:"ttl,omitempty"`
+}
+
+// LevelResponse is the response body of the level handler.
+type LevelResponse struct {
+	// Level is the current log level.
+	Level string `json:"level,omitempty"`
+	// Error is the error message in case of a failure.
+	Error string `json:"error,omitempty"`
+	// Allowed are the allowed log levels in case of an invalid log level.
+	Allowed []string `json:"allowed,omitempty"`
+}
+
+// levelRequestLimit is the maximum size of the request body accepted by the
+// level handler.
+const levelRequestLimit = 1 << 10
+
+// levelHandler is the http handler to inspect and change the log level.
+type levelHandler struct {
+	// config is the config providing the log level.
+	config *Config
+	// mutex is the mutex to synchronize level changes.
+	mutex sync.Mutex
+	// previous is the log level restored after the ttl expired. The level is
+	// stored normalized to ensure that restoring the level cannot fail.
+	previous string
+	// timer is the timer restoring the previous log level.
+	timer *time.Timer
+}
+
+// LevelHandler creates a http handler to inspect and change the log level of
+// the given config at runtime. On `GET` the handler returns the current log
+// level, on `PUT` and `POST` it changes the log level as requested via
+// `{"level":"debug"}`. If the request contains a `ttl`, the previous log level
+// is restored automatically after the given duration. Request bodies larger
+// than 1 KiB are rejected.
+func LevelHandler(config *Config) http.Handler {
+	return &levelHandler{config: config}
+}
+
+// ServeHTTP serves the http request to inspect and change the log level.
+func (h *levelHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.write(w, http.StatusOK, &LevelResponse{Level: h.config.GetLevel()})
+	case http.MethodPut, http.MethodPost:
+		h.change(w, r)
+	default:
+		w.Header().Set("Allow", "GET, PUT, POST")
+		h.write(w, http.StatusMethodNotAllowed, &LevelResponse{
+			Error: "method not allowed",
+		})
+	}
+}
+
+// change changes the log level as requested by the given http request.
+func (h *levelHandler) change(w http.ResponseWriter, r *http.Request) {
+	request := &LevelRequest{}
+	body := http.MaxBytesReader(w, r.Body, levelRequestLimit)
+	if err := json.NewDecoder(body).Decode(request); err != nil {
+		status := http.StatusBadRequest
+		if merr := (*http.MaxBytesError)(nil); errors.As(err, &merr) {
+			status = http.StatusRequestEntityTooLarge
+		}
+		h.write(w, status, &LevelResponse{Error: err.Error()})
+		return
+	}
+
+	var ttl time.Duration
+	if request.TTL != "" {
+		duration, err := time.ParseDuration(request.TTL)
+		if err != nil || duration <= 0 {
+			h.write(w, http.StatusBadRequest, &LevelResponse{
+				Error: "invalid ttl [" + request.TTL + "]",
+			})
+			return
+		}
+		ttl = duration
+	}
+
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	level := AllLevels[ParseLevel(h.config.GetLevel())]
+	if err := h.config.SetLevel(request.Level); err != nil {
+		h.write(w, http.StatusBadRequest, &LevelResponse{
+			Error: err.Error(), Allowed: AllLevels,
+		})
+		return
+	}
+
+	// Keeps the original level if a previous change is not yet restored.
+	if h.timer != nil {
+		h.timer.Stop()
+		h.timer = nil
+	} else {
+		h.previous = level
+	}
+
+	if ttl > 0 {
+		var timer *time.Timer
+		timer = time.AfterFunc(ttl, func() {
+			h.mutex.Lock()
+			defer h.mutex.Unlock()
+			if h.timer == timer {
+				_ = h.config.SetLevel(h.previous)
+				h.timer = nil
+			}
+		})
+		h.timer = timer
+	}
+
+	h.write(w, http.StatusOK, &LevelResponse{Level: h.config.GetLevel()})
+}
+
+// write writes the given response with the given status code.
+func (*levelHandler) write(
+	w http.ResponseWriter, status int, response *LevelResponse,
+) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(response)
+}
@@ -0,0 +1,201 @@
+package log_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tkrop/go-testing/test"
+
+	"github.com/tkrop/go-config/log"
+)
+
+// serveLevel serves the given request using the given level handler and
+// returns the status code and the decoded response.
+func serveLevel(
+	t test.Test, handler http.Handler, method, body string,
+) (int, *log.LevelResponse) {
+	request := httptest.NewRequest(method, "/log/level",
+		strings.NewReader(body))
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, request)
+
+	response := &log.LevelResponse{}
+	require.NoError(t, json.NewDecoder(recorder.Body).Decode(response))
+	assert.Equal(t, "application/json",
+		recorder.Header().Get("Content-Type"))
+	return recorder.Code, response
+}
+
+type testLevelHandlerParam struct {
+	method       string
+	body         string
+	expectStatus int
+	expect       *log.LevelResponse
+	expectLevel  string
+}
+
+var testLevelHandlerParams = map[string]testLevelHandlerParam{
+	"get level": {
+		method:       http.MethodGet,
+		expectStatus: http.StatusOK,
+		expect:       &log.LevelResponse{Level: log.LevelInfo},
+		expectLevel:  log.LevelInfo,
+	},
+	"put level": {
+		method:       http.MethodPut,
+		body:         `{"level":"debug"}`,
+		expectStatus: http.StatusOK,
+		expect:       &log.LevelResponse{Level: log.LevelDebug},
+		expectLevel:  log.LevelDebug,
+	},
+	"post level": {
+		method:       http.MethodPost,
+		body:         `{"level":"trace"}`,
+		expectStatus: http.StatusOK,
+		expect:       &log.LevelResponse{Level: log.LevelTrace},
+		expectLevel:  log.LevelTrace,
+	},
+	"put level invalid": {
+		method:       http.MethodPut,
+		body:         `{"level":"inf0"}`,
+		expectStatus: http.StatusBadRequest,
+		expect: &log.LevelResponse{
+			Error:   log.NewErrLevel("inf0").Error(),
+			Allowed: log.AllLevels,
+		},
+		expectLevel: log.LevelInfo,
+	},
+	"put level invalid ttl": {
+		method:       http.MethodPut,
+		body:         `{"level":"debug","ttl":"soon"}`,
+		expectStatus: http.StatusBadRequest,
+		expect:       &log.LevelResponse{Error: "invalid ttl [soon]"},
+		expectLevel:  log.LevelInfo,
+	},
+	"put level invalid body": {
+		method:       http.MethodPut,
+		body:         `{"level":`,
+		expectStatus: http.StatusBadRequest,
+		expect:       &log.LevelResponse{Error: "unexpected EOF"},
+		expectLevel:  log.LevelInfo,
+	},
+	"put level too large": {
+		method: http.MethodPut,
+		body: `{"level":"debug","ttl":"` +
+			strings.Repeat("1", 1<<10) + `s"}`,
+		expectStatus: http.StatusRequestEntityTooLarge,
+		expect: &log.LevelResponse{
+			Error: "http: request body too large",
+		},
+		expectLevel: log.LevelInfo,
+	},
+	"delete level": {
+		method:       http.MethodDelete,
+		expectStatus: http.StatusMethodNotAllowed,
+		expect:       &log.LevelResponse{Error: "method not allowed"},
+		expectLevel:  log.LevelInfo,
+	},
+}
+
+func TestLevelHandler(t *testing.T) {
+	test.Map(t, testLevelHandlerParams).
+		Run(func(t test.Test, param testLevelHandlerParam) {
+			// Given
+			config := &log.Config{Level: log.LevelInfo}
+			handler := log.LevelHandler(config)
+
+			// When
+			status, response := serveLevel(t, handler,
+				param.method, param.body)
+
+			// Then
+			assert.Equal(t, param.expectStatus, status)
+			assert.Equal(t, param.expect, response)
+			assert.Equal(t, param.expectLevel, config.GetLevel())
+		})
+}
+
+type testLevelHandlerTTLParam struct {
+	level  string
+	expect string
+}
+
+var testLevelHandlerTTLParams = map[string]testLevelHandlerTTLParam{
+	"level info": {
+		level:  log.LevelInfo,
+		expect: log.LevelInfo,
+	},
+	"level warn": {
+		level:  log.LevelWarn,
+		expect: log.LevelWarn,
+	},
+	"level unset": {
+		expect: log.LevelInfo,
+	},
+}
+
+func TestLevelHandlerTTL(t *testing.T) {
+	test.Map(t, testLevelHandlerTTLParams).
+		Run(func(t test.Test, param testLevelHandlerTTLParam) {
+			// Given
+			config := &log.Config{Level: param.level}
+			handler := log.LevelHandler(config)
+
+			// When
+			status, response := serveLevel(t, handler, http.MethodPut,
+				`{"level":"debug","ttl":"50ms"}`)
+			require.Equal(t, http.StatusOK, status)
+			require.Equal(t, log.LevelDebug, response.Level)
+			status, response = serveLevel(t, handler, http.MethodPut,
+				`{"level":"trace","ttl":"50ms"}`)
+			require.Equal(t, http.StatusOK, status)
+			require.Equal(t, log.LevelTrace, response.Level)
+
+			// Then
+			assert.Eventually(t, func() bool {
+				return config.GetLevel() == param.expect
+			}, time.Second, 10*time.Millisecond)
+		})
+}
+
+func TestLevelHandlerZero(t *testing.T) {
+	// Given
+	buffer := &bytes.Buffer{}
+	config := &log.Config{
+		Level:      log.LevelInfo,
+		TimeFormat: log.TimeFormatNone,
+		ColorMode:  log.ColorModeOff,
+	}
+	logger := config.SetupZero(buffer).ZeroLogger()
+	handler := log.LevelHandler(config)
+	logger.Debug().Msg("debug message")
+
+	// When
+	status, _ := serveLevel(t, handler, http.MethodPut, `{"level":"debug"}`)
+
+	// Then
+	require.Equal(t, http.StatusOK, status)
+	logger.Debug().Msg("debug message")
+	assert.Equal(t, "DEBUG debug message\n", buffer.String())
+}
+
+func TestLevelHandlerTTLCancel(t *testing.T) {
+	// Given
+	config := &log.Config{Level: log.LevelInfo}
+	handler := log.LevelHandler(config)
+
+	// When
+	serveLevel(t, handler, http.MethodPut, `{"level":"debug","ttl":"50ms"}`)
+	serveLevel(t, handler, http.MethodPut, `{"level":"warn"}`)
+	time.Sleep(100 * time.Millisecond)
+
+	// Then
+	assert.Equal(t, log.LevelWarn, config.GetLevel())
+}
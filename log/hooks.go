@@ -0,0 +1,78 @@
+package log
+
+import (
+	"sync/atomic"
+
+	"github.com/rs/zerolog"
+	"github.com/sirupsen/logrus"
+)
+
+// AddRusHook registers the given logrus hook to be added by `SetupRus` after
+// the built-in hooks. Hooks are added in the order of registration.
+func (c *Config) AddRusHook(hook logrus.Hook) *Config {
+	c.RusHooks = append(c.RusHooks, hook)
+	return c
+}
+
+// setupRusHooks adds the registered logrus hooks to the given logger in the
+// order of registration. Since the hooks of the logger are replaced by each
+// setup, hooks are not duplicated on repeated setups.
+func (c *Config) setupRusHooks(logger *logrus.Logger) {
+	for _, hook := range c.RusHooks {
+		if hook != nil {
+			logger.AddHook(hook)
+		}
+	}
+}
+
+// AddZeroHook registers the given zerolog hook to be added by `SetupZero`
+// after the built-in hooks. Hooks are added in the order of registration.
+func (c *Config) AddZeroHook(hook zerolog.Hook) *Config {
+	c.ZeroHooks = append(c.ZeroHooks, hook)
+	return c
+}
+
+// setupZeroHooks adds the registered zerolog hooks to the given logger in the
+// order of registration. Since the logger is created by each setup, hooks are
+// not duplicated on repeated setups.
+func (c *Config) setupZeroHooks(logger zerolog.Logger) zerolog.Logger {
+	for _, hook := range c.ZeroHooks {
+		if hook != nil {
+			logger = logger.Hook(hook)
+		}
+	}
+	return logger
+}
+
+// LogRusMetricsHook is a hook counting the log entries per level. Entries
+// suppressed by sampling or deduplication are not counted.
+type LogRusMetricsHook struct {
+	// counts are the numbers of log entries per level.
+	counts [FieldLevel]atomic.Uint64
+}
+
+// NewLogRusMetricsHook creates a new metrics hook for logrus.
+func NewLogRusMetricsHook() *LogRusMetricsHook {
+	return &LogRusMetricsHook{}
+}
+
+// Levels returns all log levels, since all entries are counted.
+func (*LogRusMetricsHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+// Fire counts the given log entry for its level.
+func (h *LogRusMetricsHook) Fire(entry *logrus.Entry) error {
+	if !isSuppressed(entry) && int(entry.Level) < len(h.counts) {
+		h.counts[entry.Level].Add(1)
+	}
+	return nil
+}
+
+// Count returns the number of log entries counted for the given level.
+func (h *LogRusMetricsHook) Count(level Level) uint64 {
+	if level < 0 || int(level) >= len(h.counts) {
+		return 0
+	}
+	return h.counts[level].Load()
+}
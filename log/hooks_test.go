@@ -0,0 +1,172 @@
+package log_test
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tkrop/go-testing/test"
+
+	"github.com/tkrop/go-config/log"
+)
+
+// recordHook is a hook recording its name on each fired log entry.
+type recordHook struct {
+	name   string
+	record *[]string
+}
+
+// Levels returns all log levels.
+func (*recordHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+// Fire records the name of the hook.
+func (h *recordHook) Fire(*logrus.Entry) error {
+	*h.record = append(*h.record, h.name)
+	return nil
+}
+
+type testRusHooksParam struct {
+	setups int
+	expect []string
+}
+
+var testRusHooksParams = map[string]testRusHooksParam{
+	"single setup": {
+		setups: 1,
+		expect: []string{"a", "b", "c"},
+	},
+	"repeated setup": {
+		setups: 3,
+		expect: []string{"a", "b", "c"},
+	},
+}
+
+func TestRusHooks(t *testing.T) {
+	test.Map(t, testRusHooksParams).
+		Run(func(t test.Test, param testRusHooksParam) {
+			// Given
+			record := []string{}
+			config := &log.Config{
+				Level:    log.LevelInfo,
+				RusHooks: []logrus.Hook{&recordHook{name: "a", record: &record}},
+			}
+			config.AddRusHook(&recordHook{name: "b", record: &record}).
+				AddRusHook(&recordHook{name: "c", record: &record})
+			logger := logrus.New()
+			for range param.setups {
+				config.SetupRus(io.Discard, logger)
+			}
+
+			// When
+			logger.Info("message")
+
+			// Then
+			assert.Equal(t, param.expect, record)
+		})
+}
+
+func TestLogRusMetricsHook(t *testing.T) {
+	// Given
+	metrics := log.NewLogRusMetricsHook()
+	config := (&log.Config{Level: log.LevelInfo}).AddRusHook(metrics)
+	logger := config.SetupRus(io.Discard, logrus.New())
+
+	// When
+	logger.Error("error")
+	logger.Warn("warn")
+	logger.Info("info")
+	logger.Info("info")
+	logger.Debug("debug")
+
+	// Then
+	assert.Equal(t, uint64(0), metrics.Count(log.PanicLevel))
+	assert.Equal(t, uint64(1), metrics.Count(log.ErrorLevel))
+	assert.Equal(t, uint64(1), metrics.Count(log.WarnLevel))
+	assert.Equal(t, uint64(2), metrics.Count(log.InfoLevel))
+	assert.Equal(t, uint64(0), metrics.Count(log.DebugLevel))
+	assert.Equal(t, uint64(0), metrics.Count(log.FieldLevel))
+}
+
+func TestLogRusMetricsHookSuppressed(t *testing.T) {
+	// Given
+	metrics := log.NewLogRusMetricsHook()
+	config := (&log.Config{
+		Level: log.LevelInfo, Dedup: true,
+	}).AddRusHook(metrics)
+	logger := config.SetupRus(io.Discard, logrus.New())
+
+	// When
+	logger.Info("info")
+	logger.Info("info")
+	logger.Info("info")
+	config.Flush()
+
+	// Then
+	assert.Equal(t, uint64(2), metrics.Count(log.InfoLevel))
+}
+
+// recordZeroHook is a hook recording its name on each log event.
+type recordZeroHook struct {
+	name   string
+	record *[]string
+}
+
+// Run records the name of the hook.
+func (h *recordZeroHook) Run(*zerolog.Event, zerolog.Level, string) {
+	*h.record = append(*h.record, h.name)
+}
+
+type testZeroHooksParam struct {
+	setups int
+}
+
+var testZeroHooksParams = map[string]testZeroHooksParam{
+	"single setup": {
+		setups: 1,
+	},
+	"repeated setup": {
+		setups: 3,
+	},
+}
+
+func TestZeroHooks(t *testing.T) {
+	test.Map(t, testZeroHooksParams).
+		RunSeq(func(t test.Test, param testZeroHooksParam) {
+			// Given
+			buffer := &bytes.Buffer{}
+			record := []string{}
+			config := &log.Config{
+				Level:      log.LevelInfo,
+				Formatter:  log.FormatterJSON,
+				TimeFormat: log.TimeFormatNone,
+			}
+			base := test.NewAccessor(config.SetupZero(buffer).ZeroLogger()).
+				Get("hooks").([]zerolog.Hook)
+			config.AddZeroHook(&recordZeroHook{name: "a", record: &record}).
+				AddZeroHook(log.NewZeroLogFieldsHook(map[string]any{
+					"service": "test",
+				})).
+				AddZeroHook(&recordZeroHook{name: "b", record: &record})
+			for range param.setups {
+				config.SetupZero(buffer)
+			}
+			logger := config.ZeroLogger()
+
+			// When
+			logger.Info().Msg("message")
+
+			// Then
+			hooks := test.NewAccessor(logger).Get("hooks")
+			require.IsType(t, []zerolog.Hook{}, hooks)
+			assert.Len(t, hooks, len(base)+3)
+			assert.Equal(t, []string{"a", "b"}, record)
+			assert.Equal(t, `{"level":"info","service":"test",`+
+				`"message":"message"}`+"\n", buffer.String())
+		})
+}
@@ -0,0 +1,78 @@
+package log
+
+import (
+	"slices"
+
+	"github.com/rs/zerolog"
+
+	"github.com/tkrop/go-config/info"
+)
+
+// infoFields are the build info fields that can be attached to log entries.
+var infoFields = map[string]func(info *info.Info) any{
+	"path":     func(info *info.Info) any { return info.Path },
+	"repo":     func(info *info.Info) any { return info.Repo },
+	"version":  func(info *info.Info) any { return info.Version },
+	"revision": func(info *info.Info) any { return info.Revision },
+	"branch":   func(info *info.Info) any { return info.Branch },
+	"tag":      func(info *info.Info) any { return info.Tag },
+	"build":    func(info *info.Info) any { return info.Build },
+	"commit":   func(info *info.Info) any { return info.Commit },
+	"dirty":    func(info *info.Info) any { return info.Dirty },
+	"checksum": func(info *info.Info) any { return info.Checksum },
+	"go":       func(info *info.Info) any { return info.Go },
+	"platform": func(info *info.Info) any { return info.Platform },
+	"compiler": func(info *info.Info) any { return info.Compiler },
+}
+
+// WithInfo sets up the build info attached to every log entry using the
+// configured info fields. The build info must be set up before the loggers.
+func (c *Config) WithInfo(info *info.Info) *Config {
+	c.info = info
+	return c
+}
+
+// ParseInfoFields returns the names of the configured info fields attached to
+// every log entry in the configured order. Unknown info fields are ignored. If
+// no build info is set up, no fields are returned.
+func (c *Config) ParseInfoFields() []string {
+	if c.info == nil {
+		return nil
+	}
+
+	fields := make([]string, 0, len(c.InfoFields))
+	for _, field := range c.InfoFields {
+		if _, ok := infoFields[field]; ok && !slices.Contains(fields, field) {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
+
+// InfoData returns the configured info fields with the values of the build
+// info attached to every log entry.
+func (c *Config) InfoData() map[string]any {
+	fields := c.ParseInfoFields()
+	data := make(map[string]any, len(fields))
+	for _, field := range fields {
+		data[field] = infoFields[field](c.info)
+	}
+	return data
+}
+
+// ZeroLogInfoHook is a hook adding the build info fields to every log event.
+type ZeroLogInfoHook struct {
+	// data contains the build info fields.
+	data map[string]any
+}
+
+// NewZeroLogInfoHook creates a new build info hook for zerolog using the given
+// config.
+func NewZeroLogInfoHook(c *Config) *ZeroLogInfoHook {
+	return &ZeroLogInfoHook{data: c.InfoData()}
+}
+
+// Run adds the build info fields to the given log event.
+func (h *ZeroLogInfoHook) Run(event *zerolog.Event, _ zerolog.Level, _ string) {
+	event.Fields(h.data)
+}
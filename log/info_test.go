@@ -0,0 +1,114 @@
+package log_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/tkrop/go-testing/test"
+
+	"github.com/tkrop/go-config/info"
+	"github.com/tkrop/go-config/log"
+)
+
+// testInfo is the build info used for testing.
+var testInfo = &info.Info{
+	Version:  "v1.2.3",
+	Revision: "1b66a5d4cbcf6179258cb2f0b8ad4d0a0b5b5c3e",
+	Dirty:    true,
+}
+
+type testInfoParam struct {
+	formatter  log.Formatter
+	info       *info.Info
+	fields     []string
+	expectRus  string
+	expectZero string
+}
+
+var testInfoParams = map[string]testInfoParam{
+	"pretty default": {
+		formatter: log.FormatterPretty,
+		info:      testInfo,
+		fields:    []string{"version", "revision"},
+		expectRus: "INFO info message key=\"value\" version=\"v1.2.3\" " +
+			"revision=\"" + testInfo.Revision + "\"\n",
+		expectZero: "INFO info message key=\"value\" version=\"v1.2.3\" " +
+			"revision=\"" + testInfo.Revision + "\"\n",
+	},
+	"pretty dirty": {
+		formatter: log.FormatterPretty,
+		info:      testInfo,
+		fields:    []string{"dirty", "unknown", "version", "dirty"},
+		expectRus: "INFO info message key=\"value\" dirty=true " +
+			"version=\"v1.2.3\"\n",
+		expectZero: "INFO info message key=\"value\" dirty=true " +
+			"version=\"v1.2.3\"\n",
+	},
+	"json default": {
+		formatter: log.FormatterJSON,
+		info:      testInfo,
+		fields:    []string{"version"},
+		expectRus: `{"key":"value","level":"info",` +
+			`"msg":"info message","version":"v1.2.3"}` + "\n",
+		expectZero: `{"level":"info","key":"value",` +
+			`"version":"v1.2.3","message":"info message"}` + "\n",
+	},
+	"disabled fields": {
+		formatter:  log.FormatterPretty,
+		info:       testInfo,
+		fields:     []string{},
+		expectRus:  "INFO info message key=\"value\"\n",
+		expectZero: "INFO info message key=\"value\"\n",
+	},
+	"disabled info": {
+		formatter:  log.FormatterPretty,
+		fields:     []string{"version", "revision"},
+		expectRus:  "INFO info message key=\"value\"\n",
+		expectZero: "INFO info message key=\"value\"\n",
+	},
+}
+
+func TestInfo(t *testing.T) {
+	test.Map(t, testInfoParams).
+		Run(func(t test.Test, param testInfoParam) {
+			// Given
+			config := (&log.Config{
+				Level:      log.LevelInfo,
+				Formatter:  param.formatter,
+				TimeFormat: log.TimeFormatNone,
+				ColorMode:  log.ColorModeOff,
+				InfoFields: param.fields,
+			}).WithInfo(param.info)
+			rus, zero := &bytes.Buffer{}, &bytes.Buffer{}
+			rlogger := config.SetupRus(rus, logrus.New())
+			zlogger := config.SetupZero(zero).ZeroLogger()
+
+			// When
+			rlogger.WithField("key", "value").Info("info message")
+			zlogger.Info().Str("key", "value").Msg("info message")
+
+			// Then
+			assert.Equal(t, param.expectRus, rus.String())
+			assert.Equal(t, param.expectZero, zero.String())
+		})
+}
+
+func TestInfoNoOverwrite(t *testing.T) {
+	// Given
+	config := (&log.Config{
+		Level:      log.LevelInfo,
+		TimeFormat: log.TimeFormatNone,
+		ColorMode:  log.ColorModeOff,
+		InfoFields: []string{"version"},
+	}).WithInfo(testInfo)
+	rus := &bytes.Buffer{}
+	rlogger := config.SetupRus(rus, logrus.New())
+
+	// When
+	rlogger.WithField("version", "v0.0.0").Info("info message")
+
+	// Then
+	assert.Equal(t, "INFO info message version=\"v0.0.0\"\n", rus.String())
+}
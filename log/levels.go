@@ -0,0 +1,210 @@
+package log
+
+import (
+	"reflect"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/rs/zerolog"
+	"github.com/sirupsen/logrus"
+)
+
+// pkgPath is the package path of this logging package.
+var pkgPath = reflect.TypeOf(Config{}).PkgPath()
+
+// ignoredPackages are the packages skipped when looking up the caller.
+var ignoredPackages = map[string]bool{
+	pkgPath:                      true,
+	"github.com/sirupsen/logrus": true,
+	"github.com/rs/zerolog":      true,
+}
+
+// loggers is holding the loggers set up by a config to support changing the
+// log level at runtime.
+type loggers struct {
+	// mutex is the mutex to synchronize level changes.
+	mutex sync.RWMutex
+	// level is the current log level.
+	level string
+	// modules are the module specific log levels.
+	modules *Modules
+	// rus is the logrus logger set up by the config.
+	rus *logrus.Logger
+	// zero is the zerolog logger set up by the config.
+	zero *zerolog.Logger
+	// verbose is the log level applied by the zerolog level hook to all
+	// zerolog loggers set up by the config.
+	verbose atomic.Int32
+	// rusFlush is the function flushing the pending repeated entries of the
+	// logrus logger, if deduplication is enabled.
+	rusFlush func()
+	// zeroFlush is the function flushing the pending repeated entries of the
+	// zerolog logger, if deduplication is enabled.
+	zeroFlush func()
+	// file is the file writer of the configured log file.
+	file *FileWriter
+	// syslog is the syslog writer of the configured syslog endpoint.
+	syslog *SyslogWriter
+	// router is the level router of the configured level writers.
+	router *LevelRouter
+	// samplers are the level samplers of the configured level sampling.
+	samplers *LevelSamplers
+}
+
+// loggersMutex synchronizes the lazy creation of the loggers of configs and
+// the setup of the process-wide settings of logrus and zerolog.
+var loggersMutex sync.Mutex
+
+// setupLoggers returns the loggers of the config creating them if necessary.
+func (c *Config) setupLoggers() *loggers {
+	loggersMutex.Lock()
+	defer loggersMutex.Unlock()
+	if c.loggers == nil {
+		c.loggers = &loggers{level: c.Level, modules: c.ParseModules()}
+	}
+	return c.loggers
+}
+
+// lookupLoggers returns the loggers of the config, if already created.
+func (c *Config) lookupLoggers() *loggers {
+	loggersMutex.Lock()
+	defer loggersMutex.Unlock()
+	return c.loggers
+}
+
+// SetLevel sets the log level of the config and applies it to the logrus and
+// zerolog loggers already set up by the config, including zerolog loggers
+// retrieved before the change. The method is safe for concurrent use with
+// active logging.
+func (c *Config) SetLevel(level string) error {
+	parsed, err := ParseLevelStrict(level)
+	if err != nil {
+		return err
+	}
+
+	loggers := c.setupLoggers()
+	loggers.mutex.Lock()
+	defer loggers.mutex.Unlock()
+
+	verbose := parsed
+	if loggers.modules != nil {
+		loggers.modules.SetLevel(parsed)
+		verbose = loggers.modules.Verbose()
+	}
+	if loggers.rus != nil {
+		// #nosec G115 // cannot happen.
+		loggers.rus.SetLevel(logrus.Level(verbose))
+	}
+	// #nosec G115 // cannot happen.
+	loggers.verbose.Store(int32(parsed))
+	loggers.level, c.Level = level, level
+
+	return nil
+}
+
+// GetLevel returns the current log level of the config.
+func (c *Config) GetLevel() string {
+	loggers := c.setupLoggers()
+	loggers.mutex.RLock()
+	defer loggers.mutex.RUnlock()
+	return loggers.level
+}
+
+// Modules is defining module specific log levels. The log level applicable
+// for a caller package is determined via longest prefix matching of the module
+// names falling back to the default log level if no module matches.
+type Modules struct {
+	// level is the default log level.
+	level atomic.Int32
+	// levels are the module specific log levels.
+	levels map[string]Level
+}
+
+// ParseModules parses the module specific log levels of the config. Module
+// names are matched case-insensitive, since the config reader is normalizing
+// keys to lower case. If no module specific log levels are configured, `nil`
+// is returned.
+func (c *Config) ParseModules() *Modules {
+	if len(c.Levels) == 0 {
+		return nil
+	}
+
+	levels := make(map[string]Level, len(c.Levels))
+	for module, level := range c.Levels {
+		levels[strings.ToLower(module)] = ParseLevel(level)
+	}
+	modules := &Modules{levels: levels}
+	modules.SetLevel(ParseLevel(c.Level))
+	return modules
+}
+
+// SetLevel sets the default log level of the modules.
+func (m *Modules) SetLevel(level Level) {
+	// #nosec G115 // cannot happen.
+	m.level.Store(int32(level))
+}
+
+// Level returns the log level applicable for the given package.
+func (m *Modules) Level(pkg string) Level {
+	pkg = strings.ToLower(pkg)
+	level, length := Level(m.level.Load()), -1
+	for module, mlevel := range m.levels {
+		if len(module) > length && (pkg == module ||
+			strings.HasPrefix(pkg, strings.TrimSuffix(module, "/")+"/")) {
+			level, length = mlevel, len(module)
+		}
+	}
+	return level
+}
+
+// Verbose returns the most verbose log level of the default log level and the
+// module specific log levels. The logger must be set up using this level to
+// ensure that entries of verbose modules are not dropped too early.
+func (m *Modules) Verbose() Level {
+	verbose := Level(m.level.Load())
+	for _, level := range m.levels {
+		verbose = max(verbose, level)
+	}
+	return verbose
+}
+
+// Enabled returns whether the given log level is enabled for given package.
+func (m *Modules) Enabled(pkg string, level Level) bool {
+	return level <= m.Level(pkg)
+}
+
+// FramePackage returns the package path of the function of the given frame.
+func FramePackage(frame *runtime.Frame) string {
+	function := frame.Function
+	slash := strings.LastIndex(function, "/")
+	if dot := strings.Index(function[slash+1:], "."); dot >= 0 {
+		return function[:slash+1+dot]
+	}
+	return function
+}
+
+// CallerFrame returns the frame of the first caller outside of the logging
+// packages, i.e. the frame of the function that created the log entry. The
+// given number of additional frames is skipped to support wrapper libraries.
+func CallerFrame(skip int) *runtime.Frame {
+	pcs := make([]uintptr, 32+skip)
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(2, pcs)])
+	found := false
+	for {
+		frame, more := frames.Next()
+		if !found {
+			found = !ignoredPackages[FramePackage(&frame)]
+		}
+		if found {
+			if skip == 0 {
+				return &frame
+			}
+			skip--
+		}
+		if !more {
+			return &runtime.Frame{}
+		}
+	}
+}
@@ -0,0 +1,492 @@
+package log_test
+
+import (
+	"bytes"
+	"io"
+	"runtime"
+	"sync"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/tkrop/go-testing/test"
+
+	"github.com/tkrop/go-config/config"
+	"github.com/tkrop/go-config/log"
+)
+
+// packageTest is the package path of the test package.
+const packageTest = "github.com/tkrop/go-config/log_test"
+
+type testModulesLevelParam struct {
+	levels map[string]string
+	pkg    string
+	expect log.Level
+}
+
+var testModulesLevelParams = map[string]testModulesLevelParam{
+	"default level": {
+		levels: map[string]string{"net/http": "warn"},
+		pkg:    "github.com/ourorg/payments",
+		expect: log.InfoLevel,
+	},
+	"exact match": {
+		levels: map[string]string{"net/http": "warn"},
+		pkg:    "net/http",
+		expect: log.WarnLevel,
+	},
+	"prefix match": {
+		levels: map[string]string{"net/http": "warn"},
+		pkg:    "net/http/httputil",
+		expect: log.WarnLevel,
+	},
+	"prefix match slash": {
+		levels: map[string]string{"net/": "warn"},
+		pkg:    "net/http",
+		expect: log.WarnLevel,
+	},
+	"prefix partial name": {
+		levels: map[string]string{"net/http": "warn"},
+		pkg:    "net/httpx",
+		expect: log.InfoLevel,
+	},
+	"longest prefix": {
+		levels: map[string]string{
+			"github.com/ourorg":          "error",
+			"github.com/ourorg/payments": "debug",
+		},
+		pkg:    "github.com/ourorg/payments/api",
+		expect: log.DebugLevel,
+	},
+	"shorter prefix": {
+		levels: map[string]string{
+			"github.com/ourorg":          "error",
+			"github.com/ourorg/payments": "debug",
+		},
+		pkg:    "github.com/ourorg/billing",
+		expect: log.ErrorLevel,
+	},
+	"case insensitive": {
+		levels: map[string]string{"github.com/OurOrg": "debug"},
+		pkg:    "github.com/ourOrg/payments",
+		expect: log.DebugLevel,
+	},
+}
+
+func TestModulesLevel(t *testing.T) {
+	test.Map(t, testModulesLevelParams).
+		Run(func(t test.Test, param testModulesLevelParam) {
+			// Given
+			config := &log.Config{Level: log.LevelInfo, Levels: param.levels}
+
+			// When
+			level := config.ParseModules().Level(param.pkg)
+
+			// Then
+			assert.Equal(t, param.expect, level)
+		})
+}
+
+func TestParseModules(t *testing.T) {
+	// Given
+	config := &log.Config{Level: log.LevelInfo, Levels: map[string]string{
+		"net/http": "warn", "github.com/ourorg/payments": "trace",
+	}}
+
+	// When
+	modules := config.ParseModules()
+
+	// Then
+	assert.Nil(t, (&log.Config{}).ParseModules())
+	assert.Equal(t, log.TraceLevel, modules.Verbose())
+	assert.True(t, modules.Enabled("github.com/ourorg/payments",
+		log.TraceLevel))
+	assert.False(t, modules.Enabled("net/http", log.InfoLevel))
+}
+
+type testFramePackageParam struct {
+	function string
+	expect   string
+}
+
+var testFramePackageParams = map[string]testFramePackageParam{
+	"empty": {
+		function: "",
+		expect:   "",
+	},
+	"function": {
+		function: "github.com/ourorg/payments.Process",
+		expect:   "github.com/ourorg/payments",
+	},
+	"method": {
+		function: "net/http.(*Server).Serve",
+		expect:   "net/http",
+	},
+	"closure": {
+		function: "github.com/ourorg/payments.Process.func1",
+		expect:   "github.com/ourorg/payments",
+	},
+	"main": {
+		function: "main.main",
+		expect:   "main",
+	},
+}
+
+func TestFramePackage(t *testing.T) {
+	test.Map(t, testFramePackageParams).
+		Run(func(t test.Test, param testFramePackageParam) {
+			// When
+			pkg := log.FramePackage(&runtime.Frame{Function: param.function})
+
+			// Then
+			assert.Equal(t, param.expect, pkg)
+		})
+}
+
+func TestCallerFrame(t *testing.T) {
+	// When
+	frame := log.CallerFrame(0)
+
+	// Then
+	assert.Equal(t, packageTest, log.FramePackage(frame))
+}
+
+type testModulesRusParam struct {
+	function string
+	level    logrus.Level
+	expect   string
+}
+
+var testModulesRusParams = map[string]testModulesRusParam{
+	"payments debug": {
+		function: "github.com/ourorg/payments.Process",
+		level:    logrus.DebugLevel,
+		expect:   "debug message",
+	},
+	"payments trace": {
+		function: "github.com/ourorg/payments.Process",
+		level:    logrus.TraceLevel,
+	},
+	"http warn": {
+		function: "net/http.(*Server).Serve",
+		level:    logrus.WarnLevel,
+		expect:   "warn message",
+	},
+	"http info": {
+		function: "net/http.(*Server).Serve",
+		level:    logrus.InfoLevel,
+	},
+	"other info": {
+		function: "github.com/ourorg/billing.Charge",
+		level:    logrus.InfoLevel,
+		expect:   "info message",
+	},
+	"other debug": {
+		function: "github.com/ourorg/billing.Charge",
+		level:    logrus.DebugLevel,
+	},
+}
+
+func TestModulesRus(t *testing.T) {
+	test.Map(t, testModulesRusParams).
+		Run(func(t test.Test, param testModulesRusParam) {
+			// Given
+			config := &log.Config{
+				Level:      log.LevelInfo,
+				TimeFormat: fixedTimeFormat,
+				ColorMode:  log.ColorModeOff,
+				Levels: map[string]string{
+					"github.com/ourorg/payments": "debug",
+					"net/http":                   "warn",
+				},
+			}
+			logger := config.SetupRus(&bytes.Buffer{}, logrus.New())
+			entry := logrus.NewEntry(logger)
+			entry.Level = param.level
+			entry.Message = message(log.Level(param.level))
+			entry.Caller = &runtime.Frame{Function: param.function}
+
+			// When
+			result, err := logger.Formatter.Format(entry)
+
+			// Then
+			assert.NoError(t, err)
+			assert.Equal(t, logrus.DebugLevel, logger.GetLevel())
+			if param.expect != "" {
+				assert.Contains(t, string(result), param.expect)
+			} else {
+				assert.Empty(t, result)
+			}
+		})
+}
+
+type testModulesParam struct {
+	levels map[string]string
+	expect string
+}
+
+var testModulesParams = map[string]testModulesParam{
+	"module more verbose": {
+		levels: map[string]string{packageTest: "debug"},
+		expect: level(log.WarnLevel) + " warn message\n" +
+			level(log.InfoLevel) + " info message\n" +
+			level(log.DebugLevel) + " debug message\n",
+	},
+	"module less verbose": {
+		levels: map[string]string{packageTest: "warn"},
+		expect: level(log.WarnLevel) + " warn message\n",
+	},
+	"module longest prefix": {
+		levels: map[string]string{
+			"github.com/tkrop": "debug",
+			packageTest:        "warn",
+		},
+		expect: level(log.WarnLevel) + " warn message\n",
+	},
+	"module other": {
+		levels: map[string]string{"net/http": "error"},
+		expect: level(log.WarnLevel) + " warn message\n" +
+			level(log.InfoLevel) + " info message\n",
+	},
+}
+
+func TestModules(t *testing.T) {
+	test.Map(t, testModulesParams).
+		Run(func(t test.Test, param testModulesParam) {
+			// Given
+			config := config.NewReader[config.Config]("X", "app").
+				SetDefaultConfig("log", &log.Config{
+					TimeFormat: fixedTimeFormat,
+					ColorMode:  log.ColorModeOff,
+					Levels:     param.levels,
+				}, false).GetConfig("modules")
+			rus, zero := &bytes.Buffer{}, &bytes.Buffer{}
+
+			// When
+			logger := config.Log.SetupRus(rus, logrus.New())
+			logger.Warn("warn message")
+			logger.Info("info message")
+			logger.Debug("debug message")
+			zlogger := config.Log.SetupZero(zero).ZeroLogger()
+			zlogger.Warn().Msg("warn message")
+			zlogger.Info().Msg("info message")
+			zlogger.Debug().Msg("debug message")
+
+			// Then
+			assert.Equal(t, param.expect, trimTimes(rus.String()))
+			assert.Equal(t, param.expect, trimTimes(zero.String()))
+		})
+}
+
+type testSetLevelParam struct {
+	levels map[string]string
+}
+
+var testSetLevelParams = map[string]testSetLevelParam{
+	"set level": {},
+	"set level modules": {
+		levels: map[string]string{"net/http": "warn"},
+	},
+}
+
+func TestSetLevel(t *testing.T) {
+	test.Map(t, testSetLevelParams).
+		Run(func(t test.Test, param testSetLevelParam) {
+			// Given
+			config := config.NewReader[config.Config]("X", "app").
+				SetDefaultConfig("log", &log.Config{
+					TimeFormat: fixedTimeFormat,
+					ColorMode:  log.ColorModeOff,
+					Levels:     param.levels,
+				}, false).GetConfig("level")
+			rus, zero := &bytes.Buffer{}, &bytes.Buffer{}
+			logger := config.Log.SetupRus(rus, logrus.New())
+			config.Log.SetupZero(zero)
+			expect := level(log.InfoLevel) + " info message\n" +
+				level(log.DebugLevel) + " debug message\n"
+
+			// When
+			logger.Debug("debug message")
+			logger.Info("info message")
+			zlogger := config.Log.ZeroLogger()
+			zlogger.Debug().Msg("debug message")
+			zlogger.Info().Msg("info message")
+
+			assert.NoError(t, config.Log.SetLevel(log.LevelTrace))
+			assert.Equal(t, log.LevelTrace, config.Log.GetLevel())
+
+			logger.Debug("debug message")
+			zlogger = config.Log.ZeroLogger()
+			zlogger.Debug().Msg("debug message")
+
+			// Then
+			assert.Equal(t, expect, trimTimes(rus.String()))
+			assert.Equal(t, expect, trimTimes(zero.String()))
+		})
+}
+
+type testSetLevelZeroParam struct {
+	levels map[string]string
+	level  string
+	change string
+	expect string
+}
+
+var testSetLevelZeroParams = map[string]testSetLevelZeroParam{
+	"info to trace": {
+		level:  log.LevelInfo,
+		change: log.LevelTrace,
+		expect: "INFO info message\nTRACE trace message\n",
+	},
+	"trace to info": {
+		level:  log.LevelTrace,
+		change: log.LevelInfo,
+		expect: "TRACE trace message\nINFO info message\n",
+	},
+	"info to trace modules": {
+		levels: map[string]string{"net/http": "warn"},
+		level:  log.LevelInfo,
+		change: log.LevelTrace,
+		expect: "INFO info message\nTRACE trace message\n",
+	},
+}
+
+func TestSetLevelZero(t *testing.T) {
+	test.Map(t, testSetLevelZeroParams).
+		Run(func(t test.Test, param testSetLevelZeroParam) {
+			// Given
+			buffer := &bytes.Buffer{}
+			config := &log.Config{
+				Level:      param.level,
+				Levels:     param.levels,
+				TimeFormat: log.TimeFormatNone,
+				ColorMode:  log.ColorModeOff,
+			}
+			logger := config.SetupZero(buffer).ZeroLogger()
+			logger.Trace().Msg("trace message")
+			logger.Info().Msg("info message")
+
+			// When
+			err := config.SetLevel(param.change)
+
+			// Then
+			assert.NoError(t, err)
+			logger.Trace().Msg("trace message")
+			assert.Equal(t, param.expect, buffer.String())
+		})
+}
+
+func TestSetLevelInvalid(t *testing.T) {
+	// Given
+	config := &log.Config{Level: log.LevelInfo}
+
+	// When
+	err := config.SetLevel("inf0")
+
+	// Then
+	assert.Equal(t, log.NewErrLevel("inf0"), err)
+	assert.Equal(t, log.LevelInfo, config.GetLevel())
+}
+
+func TestSetLevelConcurrent(t *testing.T) {
+	// Given
+	config := &log.Config{Level: log.LevelInfo}
+	logger := config.SetupRus(&bytes.Buffer{}, logrus.New())
+	zero := &bytes.Buffer{}
+	config.SetupZero(zero)
+	done := make(chan struct{})
+
+	// When
+	go func() {
+		defer close(done)
+		for index := 0; index < 100; index++ {
+			logger.Debug("debug message")
+			zlogger := config.ZeroLogger()
+			zlogger.Debug().Msg("debug message")
+		}
+	}()
+	for _, level := range []string{log.LevelTrace, log.LevelWarn} {
+		assert.NoError(t, config.SetLevel(level))
+	}
+	<-done
+
+	// Then
+	assert.Equal(t, log.LevelWarn, config.GetLevel())
+	assert.Equal(t, logrus.WarnLevel, logger.GetLevel())
+	zero.Reset()
+	zlogger := config.ZeroLogger()
+	zlogger.Info().Msg("info message")
+	assert.Empty(t, zero.String())
+}
+
+func TestSetupConcurrent(t *testing.T) {
+	// Given
+	config := &log.Config{Level: log.LevelInfo, Formatter: log.FormatterJSON}
+	logger := logrus.New()
+	start := make(chan struct{})
+	var wg sync.WaitGroup
+
+	// When
+	for range 8 {
+		wg.Add(4)
+		go func() {
+			defer wg.Done()
+			<-start
+			config.SetupZero(io.Discard)
+		}()
+		go func() {
+			defer wg.Done()
+			<-start
+			config.SetupRus(io.Discard, logger)
+		}()
+		go func() {
+			defer wg.Done()
+			<-start
+			if zero, ok := config.LookupZeroLogger(); ok {
+				zero.Info().Msg("message")
+			}
+			_ = config.SetLevel(log.LevelDebug)
+		}()
+		go func() {
+			defer wg.Done()
+			<-start
+			if rus, ok := config.LookupRusLogger(); ok {
+				rus.Info("message")
+			}
+			_ = config.GetLevel()
+		}()
+	}
+	close(start)
+	wg.Wait()
+
+	// Then
+	_, ok := config.LookupZeroLogger()
+	assert.True(t, ok)
+	rus, ok := config.LookupRusLogger()
+	assert.True(t, ok)
+	assert.Same(t, logger, rus)
+	assert.Equal(t, log.LevelDebug, config.GetLevel())
+}
+
+func TestZeroLoggerConcurrent(t *testing.T) {
+	// Given
+	config := &log.Config{Level: log.LevelPanic}
+	loggers := make([]zerolog.Logger, 8)
+	var wg sync.WaitGroup
+
+	// When
+	for index := range loggers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			loggers[index] = config.ZeroLogger()
+		}()
+	}
+	wg.Wait()
+
+	// Then
+	for _, logger := range loggers {
+		assert.Equal(t, loggers[0], logger)
+	}
+}
@@ -1,12 +1,21 @@
 package log
 
 import (
+	"errors"
+	"fmt"
 	"io"
-	"os"
+	"maps"
 	"regexp"
+	"slices"
+	"strconv"
 	"strings"
+	"time"
+	"unicode/utf8"
 
-	"golang.org/x/term"
+	"github.com/rs/zerolog"
+	"github.com/sirupsen/logrus"
+
+	"github.com/tkrop/go-config/info"
 )
 
 // Default values for the log configuration.
@@ -17,6 +26,8 @@ const (
 	DefaultCaller = false
 	// TImeFormat is defining default time format.
 	DefaultTimeFormat = "2006-01-02 15:04:05.999999"
+	// DefaultSplitLevel is the default level used for splitting log output.
+	DefaultSplitLevel = LevelWarn
 )
 
 // Default values for the log formatter.
@@ -35,6 +46,12 @@ var (
 
 	// DefaultErrorName is the default name used for marking errors.
 	DefaultErrorName = "error"
+
+	// DefaultFieldSeparator is the default separator written between fields.
+	DefaultFieldSeparator = " "
+	// DefaultKVSeparator is the default separator written between field keys
+	// and values.
+	DefaultKVSeparator = "="
 )
 
 // Log levels.
@@ -55,8 +72,37 @@ const (
 	LevelDebug string = "debug"
 	// LevelTrace is the trace log level.
 	LevelTrace string = "trace"
+	// LevelField is the pseudo log level used for configuring fields.
+	LevelField string = "field"
+)
+
+// Time format presets.
+const (
+	// TimeFormatUnix is the time format preset for unix timestamps in seconds.
+	TimeFormatUnix = "unix"
+	// TimeFormatUnixMs is the time format preset for unix timestamps in
+	// milliseconds.
+	TimeFormatUnixMs = "unixms"
+	// TimeFormatUnixNs is the time format preset for unix timestamps in
+	// nanoseconds.
+	TimeFormatUnixNs = "unixns"
+	// TimeFormatNone is the time format preset for omitting timestamps.
+	TimeFormatNone = "none"
 )
 
+// TimeFormats contains the time format presets mapping the symbolic names to
+// the corresponding time layouts or numeric time format modes.
+var TimeFormats = map[string]string{
+	"rfc3339":        time.RFC3339,
+	"rfc3339nano":    time.RFC3339Nano,
+	"kitchen":        time.Kitchen,
+	"iso8601":        "2006-01-02T15:04:05.000Z07:00",
+	TimeFormatUnix:   TimeFormatUnix,
+	TimeFormatUnixMs: TimeFormatUnixMs,
+	TimeFormatUnixNs: TimeFormatUnixNs,
+	TimeFormatNone:   TimeFormatNone,
+}
+
 // Level is the log level used for logging.
 type Level int
 
@@ -81,25 +127,37 @@ const (
 )
 
 // ParseLevel parses the log level string and returns the corresponding level.
+// Invalid log levels are mapped to the info level.
 func ParseLevel(level string) Level {
-	switch strings.ToLower(level) {
-	case LevelPanic:
-		return PanicLevel
-	case LevelFatal:
-		return FatalLevel
-	case LevelError:
-		return ErrorLevel
-	case LevelWarn, LevelWarning:
-		return WarnLevel
-	case LevelInfo:
-		return InfoLevel
-	case LevelDebug:
-		return DebugLevel
-	case LevelTrace:
-		return TraceLevel
-	default:
-		return InfoLevel
+	parsed, _ := ParseLevelStrict(level)
+	return parsed
+}
+
+// AllLevels contains all valid log level names.
+var AllLevels = []string{
+	LevelPanic, LevelFatal, LevelError, LevelWarn,
+	LevelInfo, LevelDebug, LevelTrace,
+}
+
+// ErrLevel is a common error to indicate an invalid log level.
+var ErrLevel = errors.New("invalid level")
+
+// NewErrLevel is a convenience method to create a new invalid log level error
+// for the given level listing all valid log levels.
+func NewErrLevel(level string) error {
+	return fmt.Errorf("%w [%s]: allowed values are %s", ErrLevel,
+		level, strings.Join(AllLevels, ", "))
+}
+
+// ParseLevelStrict parses the log level string and returns the corresponding
+// level. In contrast to `ParseLevel`, an error is returned if the log level
+// is invalid.
+func ParseLevelStrict(level string) (Level, error) {
+	if parsed, ok := levelKeys[strings.ToLower(level)]; ok &&
+		parsed != FieldLevel {
+		return parsed, nil
 	}
+	return InfoLevel, NewErrLevel(level)
 }
 
 // Formatter is the formatter used for logging.
@@ -113,6 +171,12 @@ const (
 	FormatterText Formatter = "text"
 	// JSON is the JSON formatter.
 	FormatterJSON Formatter = "json"
+	// ECS is the Elastic Common Schema (ECS) compliant JSON formatter.
+	FormatterECS Formatter = "ecs"
+	// GCP is the Google Cloud Logging compliant JSON formatter.
+	FormatterGCP Formatter = "gcp"
+	// GELF is the Graylog Extended Log Format (GELF) formatter.
+	FormatterGELF Formatter = "gelf"
 )
 
 // Color codes for the different log levels.
@@ -150,6 +214,58 @@ const (
 	ColorField = ColorGray
 )
 
+// Color themes.
+const (
+	// ThemeDark is the default color theme for dark terminals.
+	ThemeDark = "dark"
+	// ThemeLight is the color theme for light terminals.
+	ThemeLight = "light"
+	// ThemeMono is the monochrome color theme using only bold text.
+	ThemeMono = "mono"
+)
+
+// Themes contains the color mappings for the log levels of the color themes.
+var Themes = map[string][]string{
+	ThemeDark: DefaultLevelColors,
+	ThemeLight: {
+		"1;31", "1;31", "1;31", "1;33", "1;36", "1;34", "1;35", "90",
+	},
+	ThemeMono: {
+		"1", "1", "1", "1", "", "", "", "",
+	},
+}
+
+// Level formats.
+const (
+	// LevelFormatFull is the default level format using the full level names.
+	LevelFormatFull = "full"
+	// LevelFormatShort is the level format using three-letter level names.
+	LevelFormatShort = "short"
+)
+
+// LevelFormats contains the level names of the level formats.
+var LevelFormats = map[string][]string{
+	LevelFormatFull: DefaultLevelNames,
+	LevelFormatShort: {
+		"PNC", "FTL", "ERR", "WRN", "INF", "DBG", "TRC", "-",
+	},
+}
+
+// ParseColor parses the given color and returns the corresponding color code.
+// Besides the basic color codes, e.g. `1;91`, and extended 256-color codes,
+// e.g. `38;5;208`, and truecolor codes, e.g. `38;2;255;128;0`, it supports
+// hexadecimal truecolor notation, e.g. `#ff8000`.
+func ParseColor(color string) string {
+	if len(color) == 7 && color[0] == '#' {
+		if rgb, err := strconv.ParseUint(color[1:], 16, 32); err == nil {
+			return "38;2;" + strconv.FormatUint(rgb>>16&0xff, 10) + ";" +
+				strconv.FormatUint(rgb>>8&0xff, 10) + ";" +
+				strconv.FormatUint(rgb&0xff, 10)
+		}
+	}
+	return color
+}
+
 // ColorModeString is the color mode used for logging.
 type ColorModeString string
 
@@ -165,36 +281,54 @@ const (
 	ColorModeLevels ColorModeString = "levels"
 	// ColorFields enables the color mode for fields.
 	ColorModeFields ColorModeString = "fields"
+	// ColorModeMessages enables the color mode for messages.
+	ColorModeMessages ColorModeString = "messages"
 )
 
 var splitRegex = regexp.MustCompile(`[|,:;]`)
 
-// Parse parses the color mode.
+// Parse parses the color mode. Combined color modes, e.g. `auto|fields`, are
+// processed from left to right with later tokens refining earlier ones: `off`
+// clears all colors, `on` adds coloring of levels and fields, `auto` resolves
+// to `on` or `off` depending on whether the output is colorized, while
+// `levels`, `fields`, and `messages` add coloring of levels, fields,
+// respectively messages. Unknown tokens are ignored leaving the color mode
+// unchanged. If no token sets a color mode, the color mode is resolved as
+// `auto`.
 func (m ColorModeString) Parse(colorized bool) ColorMode {
 	mode := ColorUnset
 	for _, m := range splitRegex.Split(string(m), -1) {
-		switch ColorModeString(m) {
+		switch ColorModeString(strings.ToLower(strings.TrimSpace(m))) {
 		case ColorModeOff:
 			mode = ColorOff
 		case ColorModeOn:
-			mode = ColorOn
+			mode = mode&^ColorOff | ColorOn
+		case ColorModeAuto:
+			mode = auto(mode, colorized)
 		case ColorModeLevels:
-			mode |= ColorLevels
+			mode = mode&^ColorOff | ColorLevels
 		case ColorModeFields:
-			mode |= ColorFields
-		case ColorModeAuto:
-			fallthrough
-		default:
-			if colorized {
-				mode = ColorOn
-			} else {
-				mode = ColorOff
-			}
+			mode = mode&^ColorOff | ColorFields
+		case ColorModeMessages:
+			mode = mode&^ColorOff | ColorMessages
 		}
 	}
+	if mode == ColorUnset {
+		return auto(mode, colorized)
+	}
 	return mode
 }
 
+// auto resolves the `auto` color mode adding coloring of levels and fields to
+// the given color mode, if the output is colorized, and disabling coloring
+// otherwise.
+func auto(mode ColorMode, colorized bool) ColorMode {
+	if colorized {
+		return mode&^ColorOff | ColorOn
+	}
+	return ColorOff
+}
+
 // ColorMode is the color mode used for logging.
 type ColorMode int
 
@@ -212,6 +346,8 @@ const (
 	ColorLevels ColorMode = 2
 	// ColorFields enables coloring for fields names only.
 	ColorFields ColorMode = 4
+	// ColorMessages enables coloring of messages using the level color.
+	ColorMessages ColorMode = 8
 )
 
 // CheckFlag checks if the given color mode flag is set.
@@ -230,15 +366,16 @@ const (
 	OrderModeOn OrderModeString = "on"
 )
 
-// Parse parses the order mode.
+// Parse parses the order mode case-insensitive. Empty and unknown order modes
+// fall back to the default order mode, i.e. ordering is on.
 func (m OrderModeString) Parse() OrderMode {
-	switch m {
+	switch OrderModeString(strings.ToLower(strings.TrimSpace(string(m)))) {
 	case OrderModeOff:
 		return OrderOff
 	case OrderModeOn:
 		return OrderOn
 	default:
-		return OrderOff
+		return OrderDefault
 	}
 }
 
@@ -262,34 +399,269 @@ func (m OrderMode) CheckFlag(flag OrderMode) bool {
 	return m&flag == flag
 }
 
-// IsTerminal checks whether the given writer is a terminal.
-func IsTerminal(writer io.Writer) bool {
-	if file, ok := writer.(*os.File); ok {
-		// #nosec G115 // is a safe conversion for files.
-		return term.IsTerminal(int(file.Fd()))
+// QuoteModeString is the quote mode used for printing field values.
+type QuoteModeString string
+
+// Quote modes.
+const (
+	// QuoteModeAlways quotes all string values.
+	QuoteModeAlways QuoteModeString = "always"
+	// QuoteModeAuto quotes string values only when needed.
+	QuoteModeAuto QuoteModeString = "auto"
+	// QuoteModeNever quotes no values.
+	QuoteModeNever QuoteModeString = "never"
+)
+
+// Parse parses the quote mode.
+func (m QuoteModeString) Parse() QuoteMode {
+	switch QuoteModeString(strings.ToLower(strings.TrimSpace(string(m)))) {
+	case QuoteModeAuto:
+		return QuoteAuto
+	case QuoteModeNever:
+		return QuoteNever
+	default:
+		return QuoteAlways
 	}
-	return false
 }
 
+// QuoteMode is the quote mode used for printing field values.
+type QuoteMode int
+
+// Quote modes.
+const (
+	// QuoteAlways quotes all string values (default).
+	QuoteAlways QuoteMode = 0
+	// QuoteAuto quotes string values only when they are empty or contain
+	// whitespaces, equal signs, quotes, or control characters.
+	QuoteAuto QuoteMode = 1
+	// QuoteNever quotes no values.
+	QuoteNever QuoteMode = 2
+)
+
 // Config common configuration for logging.
+//
+// **Note:** The error name, the field map, the time format, and the error
+// causes are applied to the process-wide settings of logrus and zerolog, e.g.
+// `logrus.ErrorKey` and `zerolog.TimestampFieldName`, since the libraries do
+// not support them per logger. Setting up loggers from configs with different
+// values overwrites these settings for all loggers, and setting up loggers
+// while other goroutines are logging is not safe for concurrent use.
 type Config struct {
 	// Level is defining the logger level (default `info`).
 	Level string `default:"info"`
+	// Levels is defining module specific log levels overriding the default
+	// log level for all packages matching the module name by prefix.
+	Levels map[string]string
 	// TImeFormat is defining the time format for timestamps.
 	TimeFormat string `default:"2006-01-02 15:04:05.999999"`
+	// TimeLocation is defining the time location used for timestamps, i.e.
+	// `UTC`, `Local`, or an IANA time zone name (default `Local`).
+	TimeLocation string `default:"Local"`
 	// Caller is defining whether the caller is logged (default `false`).
 	Caller bool `default:"false"`
-	// File is defining the file name used for the log output.
+	// CallerLevel is defining the level at or above which the caller is
+	// logged, if the caller is not logged for all levels (default ``, i.e.
+	// no caller).
+	CallerLevel string `default:""`
+	// CallerSkip is defining the number of additional caller frames skipped
+	// to report the true call site when wrapping the logger (default `0`).
+	CallerSkip int `default:"0"`
+	// CallerPaths is defining the number of trailing path elements of caller
+	// files that are logged (default `0`, i.e. the full path).
+	CallerPaths int `default:"0"`
+	// CallerShort is defining whether caller function names are logged without
+	// package qualifier (default `false`).
+	CallerShort bool `default:"false"`
+	// File is defining the file name used for the log output set up via
+	// `FileWriter`, or a syslog url.
 	File string `default:"/dev/stderr"`
 	// ColorMode is defining the color mode used for logging.
 	ColorMode ColorModeString `default:"auto"`
 	// OrderMode is defining the order mode used for logging.
 	OrderMode OrderModeString `default:"on"`
+	// QuoteMode is defining the quote mode of field values used by the
+	// pretty formatters, i.e. `always`, `auto` (logfmt-style), and `never`
+	// (default `always`).
+	QuoteMode QuoteModeString `default:"always"`
+	// FieldOrder is defining the fields, e.g. `request_id` or `component`,
+	// that are always printed first in the given order by the pretty
+	// formatters.
+	FieldOrder []string
 	// Formatter is defining the formatter used for logging.
 	Formatter Formatter `default:"pretty"`
+	// FileFormatter is defining the formatter used for writing log entries
+	// additionally to the log file set up via `File`, e.g. `json`, while the
+	// console output is using `Formatter` (default ``, i.e. no additional
+	// file output).
+	FileFormatter Formatter `default:""`
+	// FieldMap is defining new names for the `time`, `level`, and `message`
+	// fields of the JSON formatters, e.g. `{time: ts, level: lvl}`.
+	FieldMap map[string]string
+	// JSONPretty is defining whether the JSON formatter is indenting the
+	// output for local debugging.
+	JSONPretty bool `default:"false"`
+	// ErrorName is defining the field name used for errors (default `error`).
+	ErrorName string `default:"error"`
+	// FieldSeparator is defining the separator written between the fields of
+	// the pretty formatters, e.g. `\t` (default ` `).
+	FieldSeparator string
+	// KVSeparator is defining the separator written between field keys and
+	// values of the pretty formatters, e.g. `:` (default `=`).
+	KVSeparator string `default:"="`
+	// ErrorCauses is defining the depth up to which the wrapped causes of
+	// errors are expanded, i.e. as `cause` fields by the pretty formatters
+	// and as `causes` array by the JSON formatter (default `0`, i.e. none).
+	ErrorCauses int `default:"0"`
+	// Theme is defining the color theme used for logging (default `dark`).
+	Theme string `default:"dark"`
+	// LevelColors is defining custom colors for the log levels overriding the
+	// colors of the theme. The keys are the level names including `field`.
+	LevelColors map[string]string
+	// LevelFormat is defining the format of the level names, i.e. `full` or
+	// `short` for three-letter level names (default `full`).
+	LevelFormat string `default:"full"`
+	// LevelNames is defining custom names for the log levels overriding the
+	// default level names. The keys are the level names.
+	LevelNames map[string]string
+	// LevelWidth is defining the fixed width the level names are padded or
+	// truncated to for aligned output (default `0`, i.e. no alignment).
+	LevelWidth int `default:"0"`
+	// AlignFields is defining the width each `key=` of the pretty formatters
+	// is padded to, so that the values of consecutive lines are aligned.
+	// Longer keys are not truncated (default `0`, i.e. no alignment).
+	AlignFields int `default:"0"`
+	// ECSLabels is defining whether user fields are nested under `labels`
+	// by the ECS formatter instead of keeping them top-level.
+	ECSLabels bool `default:"false"`
+	// Fields is defining static fields attached to every log entry, e.g.
+	// `service`, `env`, or `region`.
+	Fields map[string]string
+	// IncludeHostname is defining whether the hostname is attached to every
+	// log entry as `host` field.
+	IncludeHostname bool `default:"false"`
+	// IncludePID is defining whether the process id is attached to every log
+	// entry as `pid` field.
+	IncludePID bool `default:"false"`
+	// DisableEscape is defining whether the pretty formatters print control
+	// characters, e.g. `\r` or raw ESC bytes, in messages and field names as
+	// is instead of escaping them to prevent log injection. Field values are
+	// always quoted and escaped.
+	DisableEscape bool `default:"false"`
+	// MaxMessageLength is defining the maximum length in bytes of messages
+	// printed by the pretty formatters. Longer messages are truncated
+	// (default `0`, i.e. unlimited).
+	MaxMessageLength int `default:"0"`
+	// CollapseNewlines is defining whether embedded newlines in messages are
+	// replaced by `⏎` by the pretty formatters to keep one line per entry.
+	CollapseNewlines bool `default:"false"`
+	// KeepDuplicates is defining whether the pretty formatters print all
+	// values of duplicate field keys, e.g. of base logger and per-call fields
+	// of zerolog, instead of only the last value. Dropped values are noted at
+	// debug level.
+	KeepDuplicates bool `default:"false"`
+	// MaxFieldLength is defining the maximum length in bytes of field values
+	// printed by the pretty formatters. Longer values are truncated (default
+	// `0`, i.e. unlimited).
+	MaxFieldLength int `default:"0"`
+	// ExcludeFields is defining the names of noisy fields, e.g.
+	// `request_body`, omitted by the pretty and text formatters.
+	ExcludeFields []string
+	// ExcludeJSON is defining whether the excluded fields are also omitted by
+	// the JSON formatters, i.e. by `json`, `ecs`, `gcp`, and `gelf`.
+	ExcludeJSON bool `default:"false"`
+	// RedactFields is defining the names and glob patterns of fields, e.g.
+	// `password` or `*token*`, whose values are replaced by `***`. Names are
+	// matched case-insensitive.
+	RedactFields []string
+	// RedactErrors is defining whether the values of `key=value` pairs with
+	// redacted keys are also replaced in error messages.
+	RedactErrors bool `default:"false"`
+	// TraceIDName is defining the field name of the trace id of the active
+	// span context attached to log entries created with a context by the
+	// hooks of the `log/otel` package, e.g. `trace_id`. An empty name omits
+	// the field.
+	TraceIDName string `default:""`
+	// SpanIDName is defining the field name of the span id of the active span
+	// context attached to log entries created with a context by the hooks of
+	// the `log/otel` package, e.g. `span_id`. An empty name omits the field.
+	SpanIDName string `default:""`
+	// InfoFields is defining the build info fields attached to every log
+	// entry, if build info is set up via `WithInfo` (default `version` and
+	// `revision`).
+	InfoFields []string `default:"version,revision"`
+	// Stacktrace is defining the level at or above which a stack trace of the
+	// call site is attached to log entries as `stack` field (default ``,
+	// i.e. no stack traces).
+	Stacktrace string `default:""`
+	// StackDepth is defining the maximum number of stack trace frames
+	// (default `32`).
+	StackDepth int `default:"32"`
+	// SplitLevel is defining the level at which the log output is split, i.e.
+	// entries at or above this level are written to the high level writer,
+	// while all other entries are written to the low level writer (default
+	// ``, i.e. no split).
+	SplitLevel string `default:""`
+	// LevelWriters is defining the log files, e.g. a dedicated audit file,
+	// log entries are written to in addition to the default output. The keys
+	// are the level names, the values the file paths. Log files shared by
+	// multiple levels are opened once.
+	LevelWriters map[string]string
+	// Async is defining whether the output writer set up via `SetupWriter`
+	// writes log entries asynchronously via a bounded buffer (default
+	// `false`).
+	Async bool `default:"false"`
+	// AsyncBuffer is defining the number of log entries buffered by the
+	// asynchronous writer (default `1024`).
+	AsyncBuffer int `default:"1024"`
+	// AsyncDrop is defining whether the asynchronous writer drops log entries
+	// on buffer overflow instead of blocking (default `false`).
+	AsyncDrop bool `default:"false"`
+	// NonBlocking is defining whether the zerolog output is written via a
+	// non-blocking diode writer dropping entries, if the output cannot keep
+	// up (default `false`).
+	NonBlocking bool `default:"false"`
+	// NonBlockingBuffer is defining the number of entries buffered by the
+	// non-blocking diode writer (default `1000`).
+	NonBlockingBuffer int `default:"1000"`
+	// NonBlockingPoll is defining the poll interval of the non-blocking
+	// diode writer (default `10ms`, `0` waits for entries instead).
+	NonBlockingPoll time.Duration `default:"10ms"`
+	// Text is defining the options fine-tuning the `text` formatter.
+	Text Text
+	// Sampling is defining the sampling of identical log entries (default
+	// disabled).
+	Sampling Sampling
+	// LevelSampling is defining the sampling of log entries per level
+	// independent of their message, e.g. to sample only debug and trace
+	// entries. Levels without sampling config are not sampled, while the
+	// sampling level is ignored (default none).
+	LevelSampling map[string]Sampling
+	// Dedup is defining whether consecutive identical log entries are
+	// collapsed into a single entry carrying the number of repeated entries
+	// (default `false`).
+	Dedup bool `default:"false"`
+	// DedupWindow is defining the window in which consecutive identical log
+	// entries are collapsed (default `1s`, `0` collapses until a different
+	// entry arrives).
+	DedupWindow time.Duration `default:"1s"`
+	// ExitFunc is defining the function called with the exit code after
+	// fatal log entries (default `os.Exit`). Since zerolog is calling
+	// `os.Exit` unconditionally after fatal entries, the function must not
+	// return to prevent the exit, e.g. by panicking.
+	ExitFunc func(code int) `mapstructure:"-"`
+	// RusHooks are defining the additional logrus hooks added by `SetupRus`
+	// after the built-in hooks in the given order (default none).
+	RusHooks []logrus.Hook `mapstructure:"-"`
+	// ZeroHooks are defining the additional zerolog hooks added by
+	// `SetupZero` after the built-in hooks in the given order (default none).
+	ZeroHooks []zerolog.Hook `mapstructure:"-"`
 
-	// logger is the logger instance defined by the config.
-	logger any
+	// info is the build info attached to every log entry.
+	info *info.Info
+	// hostname is the function to look up the hostname.
+	hostname func() (string, error)
+	// loggers are the logger instances set up by the config.
+	loggers *loggers
 }
 
 // Setup is a data structure that contains all necessary setup information to
@@ -297,32 +669,523 @@ type Config struct {
 type Setup struct {
 	// TimeFormat is defining the time format used for printing timestamps.
 	TimeFormat string
+	// TimeLocation is defining the time location used for printing
+	// timestamps. If no location is given, timestamps are printed as is.
+	TimeLocation *time.Location
 	// ColorMode is defining the color mode (default = ColorAuto).
 	ColorMode ColorMode
 	// OrderMode is defining the order mode.
 	OrderMode OrderMode
+	// QuoteMode is defining the quote mode of field values.
+	QuoteMode QuoteMode
 	// Caller is defining whether the caller is reported.
 	Caller bool
+	// CallerPaths is defining the number of trailing path elements of caller
+	// files. If zero, the full path is reported.
+	CallerPaths int
+	// CallerShort is defining whether caller functions are reported without
+	// package qualifier.
+	CallerShort bool
 
 	// ErrorName is defining the name used for marking errors.
 	ErrorName string
+	// FieldSeparator is defining the separator written between fields. If
+	// empty, the default field separator is used.
+	FieldSeparator string
+	// KVSeparator is defining the separator written between field keys and
+	// values. If empty, the default key/value separator is used.
+	KVSeparator string
+	// ErrorCauses is defining the depth of expanded error causes.
+	ErrorCauses int
 	// LevelNames is defining the names used for marking the different log
 	// levels.
 	LevelNames []string
+	// LevelWidth is defining the fixed width of the level names. If zero, the
+	// level names are used as is.
+	LevelWidth int
+	// AlignFields is defining the width field keys are padded to. If zero,
+	// fields are not aligned.
+	AlignFields int
 	// LevelColors is defining the colors used for marking the different log
 	// levels.
 	LevelColors []string
+	// InfoFields is defining the build info fields that are printed after
+	// the user fields.
+	InfoFields []string
+	// ECSLabels is defining whether user fields are nested under `labels`.
+	ECSLabels bool
+	// Hostname is defining the hostname reported by formatters requiring it,
+	// e.g. the GELF formatter.
+	Hostname string
+	// DisableEscape is defining whether control characters are kept as is.
+	DisableEscape bool
+	// MaxMessageLength is defining the maximum length of messages.
+	MaxMessageLength int
+	// CollapseNewlines is defining whether newlines in messages are replaced.
+	CollapseNewlines bool
+	// KeepDuplicates is defining whether all values of duplicate keys are
+	// printed.
+	KeepDuplicates bool
+	// MaxFieldLength is defining the maximum length of field values.
+	MaxFieldLength int
+	// FieldOrder is defining the fields printed first in the given order.
+	FieldOrder []string
+	// ExcludeFields is defining the names of omitted fields.
+	ExcludeFields []string
+	// RedactFields is defining the lower case patterns of redacted fields.
+	RedactFields []string
+	// RedactErrors is defining whether error messages are redacted.
+	RedactErrors bool
+
+	// levels are the precomputed, optionally colored level strings.
+	levels []string
+	// levelsMode is the color mode the level strings are precomputed and the
+	// field names are cached for.
+	levelsMode ColorMode
+	// fieldNames are the cached, optionally colored field names.
+	fieldNames *nameCache
+	// errNames are the cached, optionally colored error field names.
+	errNames *nameCache
 }
 
 // Setup creates a new pretty formatter config.
 func (c *Config) Setup(writer io.Writer) *Setup {
-	return &Setup{
-		TimeFormat:  c.TimeFormat,
-		ColorMode:   c.ColorMode.Parse(IsTerminal(writer)),
-		OrderMode:   c.OrderMode.Parse(),
-		Caller:      c.Caller,
-		ErrorName:   DefaultErrorName,
-		LevelNames:  DefaultLevelNames,
-		LevelColors: DefaultLevelColors,
+	location, _ := c.ParseTimeLocation()
+	redact, _ := c.ParseRedactFields()
+	setup := &Setup{
+		TimeFormat:       c.ParseTimeFormat(),
+		TimeLocation:     location,
+		ColorMode:        c.ParseColorMode(writer),
+		OrderMode:        c.OrderMode.Parse(),
+		QuoteMode:        c.QuoteMode.Parse(),
+		Caller:           c.IsCallerEnabled(),
+		CallerPaths:      c.CallerPaths,
+		CallerShort:      c.CallerShort,
+		ErrorName:        c.ParseErrorName(),
+		FieldSeparator:   c.ParseFieldSeparator(),
+		KVSeparator:      c.ParseKVSeparator(),
+		ErrorCauses:      c.ErrorCauses,
+		LevelNames:       c.ParseLevelNames(),
+		LevelWidth:       c.LevelWidth,
+		AlignFields:      c.AlignFields,
+		LevelColors:      c.ParseLevelColors(),
+		InfoFields:       c.ParseInfoFields(),
+		ECSLabels:        c.ECSLabels,
+		DisableEscape:    c.DisableEscape,
+		MaxMessageLength: c.MaxMessageLength,
+		CollapseNewlines: c.CollapseNewlines,
+		KeepDuplicates:   c.KeepDuplicates,
+		MaxFieldLength:   c.MaxFieldLength,
+		FieldOrder:       c.FieldOrder,
+		ExcludeFields:    c.ExcludeFields,
+		RedactFields:     redact,
+		RedactErrors:     c.RedactErrors,
+	}
+	setup.levels, setup.levelsMode = setup.levelStrings(), setup.ColorMode
+	setup.fieldNames, setup.errNames = &nameCache{}, &nameCache{}
+	return setup
+}
+
+// levelStrings returns the formatted, optionally colored level strings of
+// all log levels.
+func (s *Setup) levelStrings() []string {
+	levels := make([]string, len(s.LevelNames))
+	for level := range levels {
+		levels[level] = s.format(func(buffer *Buffer) *Buffer {
+			return buffer.WriteLevel(Level(level))
+		})
+	}
+	return levels
+}
+
+// TrailingFields returns the build info fields placed after the user fields
+// in the configured order, i.e. all build info fields that are neither
+// ordered first nor excluded.
+func (s *Setup) TrailingFields() []string {
+	return slices.DeleteFunc(slices.Clone(s.InfoFields), func(key string) bool {
+		return slices.Contains(s.FieldOrder, key) ||
+			slices.Contains(s.ExcludeFields, key)
+	})
+}
+
+// FieldKeys returns the given field keys in the order printed by the pretty
+// formatters, i.e. the error field first, followed by the ordered fields, the
+// remaining fields sorted if the order mode is on and else in the given
+// order, and finally the trailing build info fields. Excluded fields, the
+// stack trace, and duplicate keys are omitted.
+func (s *Setup) FieldKeys(keys []string) []string {
+	present := make(map[string]bool, len(keys))
+	fields := make([]string, 0, len(keys))
+	for _, key := range keys {
+		if present[key] {
+			continue
+		}
+		present[key] = true
+		if key != s.ErrorName && key != FieldStack &&
+			!slices.Contains(s.FieldOrder, key) &&
+			!slices.Contains(s.InfoFields, key) &&
+			!slices.Contains(s.ExcludeFields, key) {
+			fields = append(fields, key)
+		}
+	}
+	if s.OrderMode.CheckFlag(OrderOn) {
+		slices.Sort(fields)
+	}
+
+	ordered := make([]string, 0, len(present))
+	if present[s.ErrorName] && !slices.Contains(s.ExcludeFields, s.ErrorName) {
+		ordered = append(ordered, s.ErrorName)
+	}
+	for _, key := range s.FieldOrder {
+		if present[key] && key != s.ErrorName &&
+			!slices.Contains(s.ExcludeFields, key) {
+			ordered = append(ordered, key)
+		}
+	}
+	ordered = append(ordered, fields...)
+	for _, key := range s.TrailingFields() {
+		if present[key] && key != s.ErrorName {
+			ordered = append(ordered, key)
+		}
+	}
+	return ordered
+}
+
+// CallerFile returns the given caller file shortened to the configured number
+// of trailing path elements.
+func (s *Setup) CallerFile(file string) string {
+	if s.CallerPaths <= 0 {
+		return file
+	}
+
+	index := len(file)
+	for count := 0; count < s.CallerPaths; count++ {
+		if index = strings.LastIndex(file[:index], "/"); index < 0 {
+			return file
+		}
+	}
+	return file[index+1:]
+}
+
+// CallerFunction returns the given caller function without the package
+// qualifier, if configured.
+func (s *Setup) CallerFunction(function string) string {
+	if !s.CallerShort {
+		return function
+	}
+
+	slash := strings.LastIndex(function, "/")
+	if dot := strings.Index(function[slash+1:], "."); dot >= 0 {
+		return function[slash+dot+2:]
+	}
+	return function
+}
+
+// LevelName returns the name of the given log level padded or truncated to
+// the level width. The width is counting visible characters, i.e. runes.
+func (s *Setup) LevelName(level Level) string {
+	name := s.LevelNames[level]
+	if s.LevelWidth <= 0 {
+		return name
+	}
+
+	runes := []rune(name)
+	if len(runes) > s.LevelWidth {
+		return string(runes[:s.LevelWidth])
+	}
+	return name + strings.Repeat(" ", s.LevelWidth-len(runes))
+}
+
+// FieldSep returns the separator written between fields.
+func (s *Setup) FieldSep() string {
+	if s.FieldSeparator != "" {
+		return s.FieldSeparator
+	}
+	return DefaultFieldSeparator
+}
+
+// KVSep returns the separator written between field keys and values.
+func (s *Setup) KVSep() string {
+	if s.KVSeparator != "" {
+		return s.KVSeparator
+	}
+	return DefaultKVSeparator
+}
+
+// FieldPadding returns the padding appended to `key=` of the given key to
+// align the field values. The width is counting visible characters, i.e.
+// runes excluding color codes.
+func (s *Setup) FieldPadding(key string) string {
+	width := utf8.RuneCountInString(key) + utf8.RuneCountInString(s.KVSep())
+	if s.AlignFields <= width {
+		return ""
+	}
+	return strings.Repeat(" ", s.AlignFields-width)
+}
+
+// FormatTime formats the given time using the time location and the time
+// format of the setup supporting time layouts as well as numeric time formats.
+// If timestamps are omitted, an empty string is returned.
+func (s *Setup) FormatTime(t time.Time) string {
+	if s.TimeFormat == TimeFormatNone {
+		return ""
+	} else if IsTimeEpoch(s.TimeFormat) {
+		return strconv.FormatInt(TimeEpoch(s.TimeFormat, t), 10)
+	}
+	return s.Time(t).Format(s.TimeFormat)
+}
+
+// IsTimeEpoch returns whether the given time format is a numeric time format
+// writing unix timestamps.
+func IsTimeEpoch(format string) bool {
+	switch format {
+	case TimeFormatUnix, TimeFormatUnixMs, TimeFormatUnixNs:
+		return true
+	default:
+		return false
+	}
+}
+
+// TimeEpoch returns the unix timestamp of the given time using the precision
+// of the given numeric time format.
+func TimeEpoch(format string, t time.Time) int64 {
+	switch format {
+	case TimeFormatUnixMs:
+		return t.UnixMilli()
+	case TimeFormatUnixNs:
+		return t.UnixNano()
+	default:
+		return t.Unix()
+	}
+}
+
+// EpochTime returns the time of the given unix timestamp using the precision
+// of the given numeric time format.
+func EpochTime(format string, epoch int64) time.Time {
+	switch format {
+	case TimeFormatUnixMs:
+		return time.UnixMilli(epoch)
+	case TimeFormatUnixNs:
+		return time.Unix(0, epoch)
+	default:
+		return time.Unix(epoch, 0)
+	}
+}
+
+// ParseTimeFormat parses the time format of the config translating the time
+// format presets to the corresponding time layouts or numeric time formats.
+// Time formats not matching a preset are returned as is.
+func (c *Config) ParseTimeFormat() string {
+	if format, ok := TimeFormats[strings.ToLower(c.TimeFormat)]; ok {
+		return format
+	}
+	return c.TimeFormat
+}
+
+// ParseTimeLayout parses the time format of the config like `ParseTimeFormat`
+// but returns time layouts only. Numeric time formats and omitted timestamps
+// are replaced by the given fallback layout for formatters that only support
+// time layouts.
+func (c *Config) ParseTimeLayout(fallback string) string {
+	if format := c.ParseTimeFormat(); !IsTimeEpoch(format) &&
+		format != TimeFormatNone {
+		return format
+	}
+	return fallback
+}
+
+// IsTimeNone returns whether timestamps are omitted by the config.
+func (c *Config) IsTimeNone() bool {
+	return c.ParseTimeFormat() == TimeFormatNone
+}
+
+// Time returns the given time in the time location of the setup.
+func (s *Setup) Time(t time.Time) time.Time {
+	if s.TimeLocation != nil {
+		return t.In(s.TimeLocation)
+	}
+	return t
+}
+
+// ErrJSONPretty is a common error to indicate an ignored JSON pretty option.
+var ErrJSONPretty = errors.New("json pretty")
+
+// NewErrJSONPretty is a convenience method to create a new JSON pretty error
+// for the given formatter.
+func NewErrJSONPretty(formatter Formatter) error {
+	return fmt.Errorf("%w [%s]: ignored for non-json formatter",
+		ErrJSONPretty, formatter)
+}
+
+// ParseJSONPretty returns whether JSON output is indented. Since indenting is
+// only supported by the JSON formatter, the option is ignored for all other
+// formatters returning an error.
+func (c *Config) ParseJSONPretty() (bool, error) {
+	if !c.JSONPretty {
+		return false, nil
+	} else if c.Formatter != FormatterJSON {
+		return false, NewErrJSONPretty(c.Formatter)
+	}
+	return true, nil
+}
+
+// ErrTimeLocation is a common error to indicate an invalid time location.
+var ErrTimeLocation = errors.New("time location")
+
+// NewErrTimeLocation is a convenience method to create a new time location
+// error for the given location wrapping the original error.
+func NewErrTimeLocation(location string, err error) error {
+	return fmt.Errorf("%w [%s]: %w", ErrTimeLocation, location, err)
+}
+
+// ParseTimeLocation parses the time location of the config. Besides `UTC` and
+// `Local`, IANA time zone names are supported. Invalid time locations fall
+// back to the local time location returning an error.
+func (c *Config) ParseTimeLocation() (*time.Location, error) {
+	switch strings.ToLower(c.TimeLocation) {
+	case "", "local":
+		return time.Local, nil
+	case "utc":
+		return time.UTC, nil
+	}
+
+	location, err := time.LoadLocation(c.TimeLocation)
+	if err != nil {
+		return time.Local, NewErrTimeLocation(c.TimeLocation, err)
+	}
+	return location, nil
+}
+
+// Validate validates the config returning an error for all log levels that
+// cannot be parsed, i.e. the default log level, the split level, as well as
+// the module specific log levels, and the sampling level, and for invalid
+// time locations.
+func (c *Config) Validate() error {
+	errs := []error{}
+	if _, err := c.ParseTimeLocation(); err != nil {
+		errs = append(errs, err)
 	}
+	if _, err := ParseLevelStrict(c.Level); err != nil {
+		errs = append(errs, err)
+	}
+	if c.SplitLevel != "" {
+		if _, err := ParseLevelStrict(c.SplitLevel); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if c.CallerLevel != "" {
+		if _, err := ParseLevelStrict(c.CallerLevel); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if c.Stacktrace != "" {
+		if _, err := ParseLevelStrict(c.Stacktrace); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if c.IsSamplingEnabled() {
+		if _, err := ParseLevelStrict(c.Sampling.Level); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	for _, level := range slices.Sorted(maps.Keys(c.LevelSampling)) {
+		if _, err := ParseLevelStrict(level); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	for _, level := range slices.Sorted(maps.Keys(c.LevelWriters)) {
+		if _, err := ParseLevelStrict(level); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	for _, module := range slices.Sorted(maps.Keys(c.Levels)) {
+		if _, err := ParseLevelStrict(c.Levels[module]); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if _, err := c.ParseFieldMap(); err != nil {
+		errs = append(errs, err)
+	}
+	if _, err := c.ParseRedactFields(); err != nil {
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
+// ParseErrorName returns the configured error field name falling back to the
+// default error name if no name is configured.
+func (c *Config) ParseErrorName() string {
+	if c.ErrorName != "" {
+		return c.ErrorName
+	}
+	return DefaultErrorName
+}
+
+// ParseFieldSeparator returns the field separator falling back to the
+// default field separator.
+func (c *Config) ParseFieldSeparator() string {
+	if c.FieldSeparator != "" {
+		return c.FieldSeparator
+	}
+	return DefaultFieldSeparator
+}
+
+// ParseKVSeparator returns the key/value separator falling back to the
+// default key/value separator.
+func (c *Config) ParseKVSeparator() string {
+	if c.KVSeparator != "" {
+		return c.KVSeparator
+	}
+	return DefaultKVSeparator
+}
+
+// ParseLevelColors parses the color theme and the custom level colors and
+// returns the resulting colors for the log levels. Unknown themes fall back to
+// the default colors, unknown level names are ignored.
+func (c *Config) ParseLevelColors() []string {
+	colors, ok := Themes[strings.ToLower(c.Theme)]
+	if !ok {
+		colors = DefaultLevelColors
+	}
+
+	if len(c.LevelColors) == 0 {
+		return colors
+	}
+
+	colors = slices.Clone(colors)
+	for name, color := range c.LevelColors {
+		if level, ok := levelKeys[strings.ToLower(name)]; ok {
+			colors[level] = ParseColor(color)
+		}
+	}
+	return colors
+}
+
+// ParseLevelNames parses the level format and the custom level names and
+// returns the resulting names for the log levels. Level names not provided
+// fall back to the level names of the level format, unknown level formats
+// fall back to the default level names, and unknown level names are ignored.
+func (c *Config) ParseLevelNames() []string {
+	defaults, ok := LevelFormats[strings.ToLower(c.LevelFormat)]
+	if !ok {
+		defaults = DefaultLevelNames
+	}
+	if len(c.LevelNames) == 0 {
+		return defaults
+	}
+
+	names := slices.Clone(defaults)
+	for key, name := range c.LevelNames {
+		if level, ok := levelKeys[strings.ToLower(key)]; ok {
+			names[level] = name
+		}
+	}
+	return names
+}
+
+// levelKeys maps the level names used for level specific settings to levels.
+var levelKeys = map[string]Level{
+	LevelPanic: PanicLevel, LevelFatal: FatalLevel, LevelError: ErrorLevel,
+	LevelWarn: WarnLevel, LevelWarning: WarnLevel, LevelInfo: InfoLevel,
+	LevelDebug: DebugLevel, LevelTrace: TraceLevel, LevelField: FieldLevel,
 }
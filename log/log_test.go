@@ -1,11 +1,21 @@
 package log_test
 
 import (
+	"bytes"
 	"errors"
+	"io"
 	"runtime"
 	"strconv"
+	"strings"
+	"testing"
 	"time"
 
+	"github.com/rs/zerolog"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/tkrop/go-testing/test"
+
+	"github.com/tkrop/go-config/config"
 	"github.com/tkrop/go-config/log"
 )
 
@@ -22,6 +32,12 @@ var (
 		Function: "function",
 		Line:     123,
 	}
+	// Realistic deep frame for testing.
+	deepFrame = &runtime.Frame{
+		File:     "/home/user/go/src/github.com/org/app/pkg/service/file.go",
+		Function: "github.com/org/app/pkg/service.(*Service).Process",
+		Line:     42,
+	}
 	// Arbitrary error for testing.
 	errAny = errors.New("any error")
 )
@@ -45,6 +61,11 @@ func levelC(level log.Level) string {
 		"m" + log.DefaultLevelNames[level] + "\x1b[0m"
 }
 
+// Helper functions for testing messages with level color.
+func messageC(level log.Level, message string) string {
+	return "\x1b[" + log.DefaultLevelColors[level] + "m" + message + "\x1b[0m"
+}
+
 // Helper functions for testing fields without color.
 func field(value string) string {
 	return value
@@ -83,6 +104,41 @@ func dataC(key, value string) string {
 	return "\x1b[" + color + "m" + key + "\x1b[0m=\"" + value + "\""
 }
 
+// message returns the log message for the given level.
+func message(level log.Level) string {
+	return [...]string{
+		"panic", "fatal", "error", "warn", "info", "debug", "trace",
+	}[level] + " message"
+}
+
+// fixedTimeFormat is a fixed width time format for testing.
+const fixedTimeFormat = "2006-01-02 15:04:05.000000"
+
+// trimTime removes the leading timestamp from the given log output.
+func trimTime(output string) string {
+	if len(output) > len(fixedTimeFormat) {
+		return output[len(fixedTimeFormat)+1:]
+	}
+	return output
+}
+
+// trimTimes removes the leading timestamps from all lines of the given log
+// output.
+func trimTimes(output string) string {
+	lines := strings.SplitAfter(output, "\n")
+	for index, line := range lines {
+		lines[index] = trimTime(line)
+	}
+	return strings.Join(lines, "")
+}
+
+// zeroLevels maps the log levels to the zerolog levels.
+var zeroLevels = []zerolog.Level{
+	zerolog.PanicLevel, zerolog.FatalLevel, zerolog.ErrorLevel,
+	zerolog.WarnLevel, zerolog.InfoLevel, zerolog.DebugLevel,
+	zerolog.TraceLevel,
+}
+
 type setupParams struct {
 	config           *log.Config
 	expectTimeFormat string
@@ -348,7 +404,1619 @@ var testSetupParams = map[string]setupParams{
 		expectLogLevel:   log.DefaultLevel,
 		expectTimeFormat: log.DefaultTimeFormat,
 		expectColorMode:  log.ColorOff,
-		expectOrderMode:  log.OrderOff,
+		expectOrderMode:  log.OrderOn,
 		expectLogCaller:  log.DefaultCaller,
 	},
 }
+
+type testParseLevelColorsParam struct {
+	config log.Config
+	expect []string
+}
+
+var testParseLevelColorsParams = map[string]testParseLevelColorsParam{
+	"theme default": {
+		expect: log.DefaultLevelColors,
+	},
+	"theme dark": {
+		config: log.Config{Theme: log.ThemeDark},
+		expect: log.DefaultLevelColors,
+	},
+	"theme light": {
+		config: log.Config{Theme: log.ThemeLight},
+		expect: log.Themes[log.ThemeLight],
+	},
+	"theme mono": {
+		config: log.Config{Theme: "MONO"},
+		expect: log.Themes[log.ThemeMono],
+	},
+	"theme unknown": {
+		config: log.Config{Theme: "unknown"},
+		expect: log.DefaultLevelColors,
+	},
+	"level colors custom": {
+		config: log.Config{
+			LevelColors: map[string]string{
+				log.LevelError:   "#ff8000",
+				log.LevelWarning: "38;5;208",
+				log.LevelField:   "2;37",
+				"unknown":        "1;91",
+			},
+		},
+		expect: []string{
+			log.ColorPanic, log.ColorFatal, "38;2;255;128;0",
+			"38;5;208", log.ColorInfo, log.ColorDebug,
+			log.ColorTrace, "2;37",
+		},
+	},
+	"level colors with theme": {
+		config: log.Config{
+			Theme: log.ThemeMono,
+			LevelColors: map[string]string{
+				log.LevelInfo: "#00ff00",
+			},
+		},
+		expect: []string{
+			"1", "1", "1", "1", "38;2;0;255;0", "", "", "",
+		},
+	},
+}
+
+func TestParseLevelColors(t *testing.T) {
+	test.Map(t, testParseLevelColorsParams).
+		Run(func(t test.Test, param testParseLevelColorsParam) {
+			// When
+			colors := param.config.ParseLevelColors()
+
+			// Then
+			assert.Equal(t, param.expect, colors)
+		})
+}
+
+type testParseColorParam struct {
+	color  string
+	expect string
+}
+
+var testParseColorParams = map[string]testParseColorParam{
+	"basic color":     {color: log.ColorRed, expect: log.ColorRed},
+	"256 color":       {color: "38;5;208", expect: "38;5;208"},
+	"true color":      {color: "38;2;1;2;3", expect: "38;2;1;2;3"},
+	"hex color":       {color: "#0a0b0c", expect: "38;2;10;11;12"},
+	"hex color upper": {color: "#FFFFFF", expect: "38;2;255;255;255"},
+	"hex invalid":     {color: "#gggggg", expect: "#gggggg"},
+	"empty color":     {color: "", expect: ""},
+}
+
+func TestParseColor(t *testing.T) {
+	test.Map(t, testParseColorParams).
+		Run(func(t test.Test, param testParseColorParam) {
+			// When
+			color := log.ParseColor(param.color)
+
+			// Then
+			assert.Equal(t, param.expect, color)
+		})
+}
+
+type testColorModeParam struct {
+	mode      log.ColorModeString
+	colorized bool
+	expect    log.ColorMode
+}
+
+var testColorModeParams = map[string]testColorModeParam{
+	"off tty": {
+		mode: "off", colorized: true,
+		expect: log.ColorOff,
+	},
+	"off no-tty": {
+		mode: "off", colorized: false,
+		expect: log.ColorOff,
+	},
+	"on tty": {
+		mode: "on", colorized: true,
+		expect: log.ColorOn,
+	},
+	"on no-tty": {
+		mode: "on", colorized: false,
+		expect: log.ColorOn,
+	},
+	"auto tty": {
+		mode: "auto", colorized: true,
+		expect: log.ColorOn,
+	},
+	"auto no-tty": {
+		mode: "auto", colorized: false,
+		expect: log.ColorOff,
+	},
+	"levels tty": {
+		mode: "levels", colorized: true,
+		expect: log.ColorLevels,
+	},
+	"levels no-tty": {
+		mode: "levels", colorized: false,
+		expect: log.ColorLevels,
+	},
+	"fields tty": {
+		mode: "fields", colorized: true,
+		expect: log.ColorFields,
+	},
+	"fields no-tty": {
+		mode: "fields", colorized: false,
+		expect: log.ColorFields,
+	},
+	"messages tty": {
+		mode: "messages", colorized: true,
+		expect: log.ColorMessages,
+	},
+	"messages no-tty": {
+		mode: "messages", colorized: false,
+		expect: log.ColorMessages,
+	},
+	"on|messages tty": {
+		mode: "on|messages", colorized: true,
+		expect: log.ColorOn | log.ColorMessages,
+	},
+	"auto|messages no-tty": {
+		mode: "auto|messages", colorized: false,
+		expect: log.ColorMessages,
+	},
+	"levels|messages tty": {
+		mode: "off|levels|messages", colorized: true,
+		expect: log.ColorLevels | log.ColorMessages,
+	},
+	"off|off tty": {
+		mode: "off|off", colorized: true,
+		expect: log.ColorOff,
+	},
+	"off|off no-tty": {
+		mode: "off|off", colorized: false,
+		expect: log.ColorOff,
+	},
+	"off|on tty": {
+		mode: "off|on", colorized: true,
+		expect: log.ColorOn,
+	},
+	"off|on no-tty": {
+		mode: "off|on", colorized: false,
+		expect: log.ColorOn,
+	},
+	"off|auto tty": {
+		mode: "off|auto", colorized: true,
+		expect: log.ColorOn,
+	},
+	"off|auto no-tty": {
+		mode: "off|auto", colorized: false,
+		expect: log.ColorOff,
+	},
+	"off|levels tty": {
+		mode: "off|levels", colorized: true,
+		expect: log.ColorLevels,
+	},
+	"off|levels no-tty": {
+		mode: "off|levels", colorized: false,
+		expect: log.ColorLevels,
+	},
+	"off|fields tty": {
+		mode: "off|fields", colorized: true,
+		expect: log.ColorFields,
+	},
+	"off|fields no-tty": {
+		mode: "off|fields", colorized: false,
+		expect: log.ColorFields,
+	},
+	"on|off tty": {
+		mode: "on|off", colorized: true,
+		expect: log.ColorOff,
+	},
+	"on|off no-tty": {
+		mode: "on|off", colorized: false,
+		expect: log.ColorOff,
+	},
+	"on|on tty": {
+		mode: "on|on", colorized: true,
+		expect: log.ColorOn,
+	},
+	"on|on no-tty": {
+		mode: "on|on", colorized: false,
+		expect: log.ColorOn,
+	},
+	"on|auto tty": {
+		mode: "on|auto", colorized: true,
+		expect: log.ColorOn,
+	},
+	"on|auto no-tty": {
+		mode: "on|auto", colorized: false,
+		expect: log.ColorOff,
+	},
+	"on|levels tty": {
+		mode: "on|levels", colorized: true,
+		expect: log.ColorOn,
+	},
+	"on|levels no-tty": {
+		mode: "on|levels", colorized: false,
+		expect: log.ColorOn,
+	},
+	"on|fields tty": {
+		mode: "on|fields", colorized: true,
+		expect: log.ColorOn,
+	},
+	"on|fields no-tty": {
+		mode: "on|fields", colorized: false,
+		expect: log.ColorOn,
+	},
+	"auto|off tty": {
+		mode: "auto|off", colorized: true,
+		expect: log.ColorOff,
+	},
+	"auto|off no-tty": {
+		mode: "auto|off", colorized: false,
+		expect: log.ColorOff,
+	},
+	"auto|on tty": {
+		mode: "auto|on", colorized: true,
+		expect: log.ColorOn,
+	},
+	"auto|on no-tty": {
+		mode: "auto|on", colorized: false,
+		expect: log.ColorOn,
+	},
+	"auto|auto tty": {
+		mode: "auto|auto", colorized: true,
+		expect: log.ColorOn,
+	},
+	"auto|auto no-tty": {
+		mode: "auto|auto", colorized: false,
+		expect: log.ColorOff,
+	},
+	"auto|levels tty": {
+		mode: "auto|levels", colorized: true,
+		expect: log.ColorOn,
+	},
+	"auto|levels no-tty": {
+		mode: "auto|levels", colorized: false,
+		expect: log.ColorLevels,
+	},
+	"auto|fields tty": {
+		mode: "auto|fields", colorized: true,
+		expect: log.ColorOn,
+	},
+	"auto|fields no-tty": {
+		mode: "auto|fields", colorized: false,
+		expect: log.ColorFields,
+	},
+	"levels|off tty": {
+		mode: "levels|off", colorized: true,
+		expect: log.ColorOff,
+	},
+	"levels|off no-tty": {
+		mode: "levels|off", colorized: false,
+		expect: log.ColorOff,
+	},
+	"levels|on tty": {
+		mode: "levels|on", colorized: true,
+		expect: log.ColorOn,
+	},
+	"levels|on no-tty": {
+		mode: "levels|on", colorized: false,
+		expect: log.ColorOn,
+	},
+	"levels|auto tty": {
+		mode: "levels|auto", colorized: true,
+		expect: log.ColorOn,
+	},
+	"levels|auto no-tty": {
+		mode: "levels|auto", colorized: false,
+		expect: log.ColorOff,
+	},
+	"levels|levels tty": {
+		mode: "levels|levels", colorized: true,
+		expect: log.ColorLevels,
+	},
+	"levels|levels no-tty": {
+		mode: "levels|levels", colorized: false,
+		expect: log.ColorLevels,
+	},
+	"levels|fields tty": {
+		mode: "levels|fields", colorized: true,
+		expect: log.ColorOn,
+	},
+	"levels|fields no-tty": {
+		mode: "levels|fields", colorized: false,
+		expect: log.ColorOn,
+	},
+	"fields|off tty": {
+		mode: "fields|off", colorized: true,
+		expect: log.ColorOff,
+	},
+	"fields|off no-tty": {
+		mode: "fields|off", colorized: false,
+		expect: log.ColorOff,
+	},
+	"fields|on tty": {
+		mode: "fields|on", colorized: true,
+		expect: log.ColorOn,
+	},
+	"fields|on no-tty": {
+		mode: "fields|on", colorized: false,
+		expect: log.ColorOn,
+	},
+	"fields|auto tty": {
+		mode: "fields|auto", colorized: true,
+		expect: log.ColorOn,
+	},
+	"fields|auto no-tty": {
+		mode: "fields|auto", colorized: false,
+		expect: log.ColorOff,
+	},
+	"fields|levels tty": {
+		mode: "fields|levels", colorized: true,
+		expect: log.ColorOn,
+	},
+	"fields|levels no-tty": {
+		mode: "fields|levels", colorized: false,
+		expect: log.ColorOn,
+	},
+	"fields|fields tty": {
+		mode: "fields|fields", colorized: true,
+		expect: log.ColorFields,
+	},
+	"fields|fields no-tty": {
+		mode: "fields|fields", colorized: false,
+		expect: log.ColorFields,
+	},
+	"off|levels|fields tty": {
+		mode: "off|levels|fields", colorized: true,
+		expect: log.ColorOn,
+	},
+	"off|levels|fields no-tty": {
+		mode: "off|levels|fields", colorized: false,
+		expect: log.ColorOn,
+	},
+	"auto|off|fields tty": {
+		mode: "auto|off|fields", colorized: true,
+		expect: log.ColorFields,
+	},
+	"auto|off|fields no-tty": {
+		mode: "auto|off|fields", colorized: false,
+		expect: log.ColorFields,
+	},
+	"levels|fields|off tty": {
+		mode: "levels|fields|off", colorized: true,
+		expect: log.ColorOff,
+	},
+	"levels|fields|off no-tty": {
+		mode: "levels|fields|off", colorized: false,
+		expect: log.ColorOff,
+	},
+	"on|auto|levels tty": {
+		mode: "on|auto|levels", colorized: true,
+		expect: log.ColorOn,
+	},
+	"on|auto|levels no-tty": {
+		mode: "on|auto|levels", colorized: false,
+		expect: log.ColorLevels,
+	},
+	"empty tty": {
+		mode: "", colorized: true,
+		expect: log.ColorOn,
+	},
+	"empty no-tty": {
+		mode: "", colorized: false,
+		expect: log.ColorOff,
+	},
+	"unknown tty": {
+		mode: "unknown", colorized: true,
+		expect: log.ColorOn,
+	},
+	"unknown no-tty": {
+		mode: "unknown", colorized: false,
+		expect: log.ColorOff,
+	},
+	"levels unknown tty": {
+		mode: "levels|garbage", colorized: true,
+		expect: log.ColorLevels,
+	},
+	"levels unknown no-tty": {
+		mode: "levels|garbage", colorized: false,
+		expect: log.ColorLevels,
+	},
+	"off unknown tty": {
+		mode: "off|garbage", colorized: true,
+		expect: log.ColorOff,
+	},
+	"unknown fields no-tty": {
+		mode: "garbage|fields", colorized: false,
+		expect: log.ColorFields,
+	},
+	"messages on tty": {
+		mode: "messages|on", colorized: true,
+		expect: log.ColorOn | log.ColorMessages,
+	},
+	"messages on no-tty": {
+		mode: "messages|on", colorized: false,
+		expect: log.ColorOn | log.ColorMessages,
+	},
+	"messages auto tty": {
+		mode: "messages|auto", colorized: true,
+		expect: log.ColorOn | log.ColorMessages,
+	},
+	"messages auto no-tty": {
+		mode: "messages|auto", colorized: false,
+		expect: log.ColorOff,
+	},
+	"spaced upper tty": {
+		mode: " Off , LEVELS ", colorized: true,
+		expect: log.ColorLevels,
+	},
+	"spaced upper no-tty": {
+		mode: " Off , LEVELS ", colorized: false,
+		expect: log.ColorLevels,
+	},
+	"semicolon tty": {
+		mode: "auto;fields", colorized: true,
+		expect: log.ColorOn,
+	},
+	"semicolon no-tty": {
+		mode: "auto;fields", colorized: false,
+		expect: log.ColorFields,
+	},
+	"colon tty": {
+		mode: "off:levels", colorized: true,
+		expect: log.ColorLevels,
+	},
+	"colon no-tty": {
+		mode: "off:levels", colorized: false,
+		expect: log.ColorLevels,
+	},
+	"comma tty": {
+		mode: "on,off", colorized: true,
+		expect: log.ColorOff,
+	},
+	"comma no-tty": {
+		mode: "on,off", colorized: false,
+		expect: log.ColorOff,
+	},
+}
+
+func TestColorMode(t *testing.T) {
+	test.Map(t, testColorModeParams).
+		Run(func(t test.Test, param testColorModeParam) {
+			// When
+			mode := param.mode.Parse(param.colorized)
+
+			// Then
+			assert.Equal(t, param.expect, mode)
+		})
+}
+
+type testOrderModeParam struct {
+	mode   log.OrderModeString
+	expect log.OrderMode
+}
+
+var testOrderModeParams = map[string]testOrderModeParam{
+	"empty":   {mode: "", expect: log.OrderOn},
+	"on":      {mode: log.OrderModeOn, expect: log.OrderOn},
+	"off":     {mode: log.OrderModeOff, expect: log.OrderOff},
+	"upper":   {mode: "OFF", expect: log.OrderOff},
+	"spaced":  {mode: " off ", expect: log.OrderOff},
+	"garbage": {mode: "any", expect: log.OrderOn},
+}
+
+func TestOrderMode(t *testing.T) {
+	test.Map(t, testOrderModeParams).
+		Run(func(t test.Test, param testOrderModeParam) {
+			// When
+			mode := param.mode.Parse()
+
+			// Then
+			assert.Equal(t, param.expect, mode)
+			assert.Equal(t, param.expect, (&log.Config{OrderMode: param.mode}).
+				Setup(io.Discard).OrderMode)
+		})
+}
+
+// truecolor is an arbitrary truecolor theme for testing.
+var truecolor = map[string]string{
+	log.LevelError: "#ff0000",
+	log.LevelInfo:  "#00ff00",
+	log.LevelField: "38;2;0;0;255",
+}
+
+// colored returns the given string in the given color.
+func colored(color, str string) string {
+	return "\x1b[" + color + "m" + str + "\x1b[0m"
+}
+
+func TestThemeTrueColor(t *testing.T) {
+	// Given
+	config := config.NewReader[config.Config]("X", "app").
+		SetDefaultConfig("log", &log.Config{
+			TimeFormat:  fixedTimeFormat,
+			ColorMode:   log.ColorModeOn,
+			LevelColors: truecolor,
+		}, false).GetConfig("theme")
+	rus, zero := &bytes.Buffer{}, &bytes.Buffer{}
+	expect := colored("38;2;0;255;0", "INFO") + " info message " +
+		colored("38;2;255;0;0", "error") + "=\"" + errAny.Error() + "\" " +
+		colored("38;2;0;0;255", "key") + "=\"value\"\n"
+
+	// When
+	config.Log.SetupRus(rus, logrus.New()).
+		WithField("key", "value").WithError(errAny).Info("info message")
+	logger := config.Log.SetupZero(zero).ZeroLogger()
+	logger.Info().Str("key", "value").Err(errAny).Msg("info message")
+
+	// Then
+	assert.Equal(t, expect, trimTime(rus.String()))
+	assert.Equal(t, expect, trimTime(zero.String()))
+}
+
+type testParseLevelNamesParam struct {
+	config log.Config
+	expect []string
+}
+
+var testParseLevelNamesParams = map[string]testParseLevelNamesParam{
+	"level names default": {
+		expect: log.DefaultLevelNames,
+	},
+	"level names full": {
+		config: log.Config{LevelFormat: log.LevelFormatFull},
+		expect: log.DefaultLevelNames,
+	},
+	"level names short": {
+		config: log.Config{LevelFormat: "SHORT"},
+		expect: []string{
+			"PNC", "FTL", "ERR", "WRN", "INF", "DBG", "TRC", "-",
+		},
+	},
+	"level names unknown format": {
+		config: log.Config{LevelFormat: "unknown"},
+		expect: log.DefaultLevelNames,
+	},
+	"level names short partial": {
+		config: log.Config{
+			LevelFormat: log.LevelFormatShort,
+			LevelNames:  map[string]string{log.LevelInfo: "info"},
+		},
+		expect: []string{
+			"PNC", "FTL", "ERR", "WRN", "info", "DBG", "TRC", "-",
+		},
+	},
+	"level names partial": {
+		config: log.Config{
+			LevelNames: map[string]string{
+				log.LevelInfo:    "info ",
+				log.LevelWarning: "warn ",
+				"unknown":        "unknown",
+			},
+		},
+		expect: []string{
+			"PANIC", "FATAL", "ERROR", "warn ",
+			"info ", "DEBUG", "TRACE", "-",
+		},
+	},
+	"level names localized": {
+		config: log.Config{
+			LevelNames: map[string]string{
+				log.LevelPanic: "PANIK", log.LevelFatal: "FATAL",
+				log.LevelError: "FEHLER", log.LevelWarn: "WARNUNG",
+				log.LevelInfo: "INFO", log.LevelDebug: "DEBUG",
+				log.LevelTrace: "SPUR", log.LevelField: "FELD",
+			},
+		},
+		expect: []string{
+			"PANIK", "FATAL", "FEHLER", "WARNUNG",
+			"INFO", "DEBUG", "SPUR", "FELD",
+		},
+	},
+}
+
+func TestParseLevelNames(t *testing.T) {
+	test.Map(t, testParseLevelNamesParams).
+		Run(func(t test.Test, param testParseLevelNamesParam) {
+			// When
+			names := param.config.ParseLevelNames()
+
+			// Then
+			assert.Equal(t, param.expect, names)
+		})
+}
+
+func TestLevelNames(t *testing.T) {
+	// Given
+	config := config.NewReader[config.Config]("X", "app").
+		SetDefaultConfig("log", &log.Config{
+			Level:      log.LevelDebug,
+			TimeFormat: fixedTimeFormat,
+			ColorMode:  log.ColorModeLevels,
+			LevelNames: map[string]string{
+				log.LevelInfo:  "info ",
+				log.LevelWarn:  "warn ",
+				log.LevelDebug: "debug",
+			},
+		}, false).GetConfig("names")
+	rus, zero := &bytes.Buffer{}, &bytes.Buffer{}
+	expect := colored(log.ColorInfo, "info ") + " info message\n" +
+		colored(log.ColorWarn, "warn ") + " warn message\n" +
+		colored(log.ColorDebug, "debug") + " debug message\n"
+
+	// When
+	logger := config.Log.SetupRus(rus, logrus.New())
+	logger.Info("info message")
+	logger.Warn("warn message")
+	logger.Debug("debug message")
+	zlogger := config.Log.SetupZero(zero).ZeroLogger()
+	zlogger.Info().Msg("info message")
+	zlogger.Warn().Msg("warn message")
+	zlogger.Debug().Msg("debug message")
+
+	// Then
+	assert.Equal(t, expect, trimTimes(rus.String()))
+	assert.Equal(t, expect, trimTimes(zero.String()))
+}
+
+func TestLevelFormatShort(t *testing.T) {
+	// Given
+	config := &log.Config{
+		Level:       log.LevelTrace,
+		TimeFormat:  log.TimeFormatNone,
+		ColorMode:   log.ColorModeLevels,
+		LevelFormat: log.LevelFormatShort,
+		ExitFunc:    exitPanic,
+	}
+	rus, zero := &bytes.Buffer{}, &bytes.Buffer{}
+	expect := colored(log.ColorError, "ERR") + " error message\n" +
+		colored(log.ColorWarn, "WRN") + " warn message\n" +
+		colored(log.ColorInfo, "INF") + " info message\n" +
+		colored(log.ColorDebug, "DBG") + " debug message\n" +
+		colored(log.ColorTrace, "TRC") + " trace message\n" +
+		colored(log.ColorFatal, "FTL") + " fatal message\n" +
+		colored(log.ColorPanic, "PNC") + " panic message\n"
+
+	// When
+	logger := config.SetupRus(rus, logrus.New())
+	logger.Error("error message")
+	logger.Warn("warn message")
+	logger.Info("info message")
+	logger.Debug("debug message")
+	logger.Trace("trace message")
+	assert.Panics(t, func() { logger.Fatal("fatal message") })
+	assert.Panics(t, func() { logger.Panic("panic message") })
+	zlogger := config.SetupZero(zero).ZeroLogger()
+	zlogger.Error().Msg("error message")
+	zlogger.Warn().Msg("warn message")
+	zlogger.Info().Msg("info message")
+	zlogger.Debug().Msg("debug message")
+	zlogger.Trace().Msg("trace message")
+	assert.Panics(t, func() { zlogger.Fatal().Msg("fatal message") })
+	assert.Panics(t, func() { zlogger.Panic().Msg("panic message") })
+
+	// Then
+	assert.Equal(t, expect, rus.String())
+	assert.Equal(t, expect, zero.String())
+}
+
+type testErrorNameParam struct {
+	errorName string
+	expectKey string
+}
+
+var testErrorNameParams = map[string]testErrorNameParam{
+	"error name default": {
+		expectKey: log.DefaultErrorName,
+	},
+	"error name custom": {
+		errorName: "err",
+		expectKey: "err",
+	},
+}
+
+func TestErrorName(t *testing.T) {
+	t.Cleanup(func() {
+		logrus.ErrorKey = log.DefaultErrorName
+		zerolog.ErrorFieldName = log.DefaultErrorName
+	})
+
+	test.Map(t, testErrorNameParams).
+		RunSeq(func(t test.Test, param testErrorNameParam) {
+			// Given
+			config := config.NewReader[config.Config]("X", "app").
+				SetDefaultConfig("log", &log.Config{
+					TimeFormat: fixedTimeFormat,
+					ColorMode:  log.ColorModeOn,
+					ErrorName:  param.errorName,
+				}, false).GetConfig("error")
+			rus, zero := &bytes.Buffer{}, &bytes.Buffer{}
+			expect := levelC(log.InfoLevel) + " info message " +
+				colored(log.ColorError, param.expectKey) + "=\"" +
+				errAny.Error() + "\"\n"
+
+			// When
+			config.Log.SetupRus(rus, logrus.New()).
+				WithError(errAny).Info("info message")
+			logger := config.Log.SetupZero(zero).ZeroLogger()
+			logger.Info().Err(errAny).Msg("info message")
+
+			// Then
+			assert.Equal(t, param.expectKey, logrus.ErrorKey)
+			assert.Equal(t, param.expectKey, zerolog.ErrorFieldName)
+			assert.Equal(t, expect, trimTime(rus.String()))
+			assert.Equal(t, expect, trimTime(zero.String()))
+		})
+}
+
+type testParseLevelParam struct {
+	level       string
+	expect      log.Level
+	expectError error
+}
+
+var testParseLevelParams = map[string]testParseLevelParam{
+	"level panic": {
+		level:  log.LevelPanic,
+		expect: log.PanicLevel,
+	},
+	"level fatal": {
+		level:  log.LevelFatal,
+		expect: log.FatalLevel,
+	},
+	"level error": {
+		level:  log.LevelError,
+		expect: log.ErrorLevel,
+	},
+	"level warn": {
+		level:  log.LevelWarn,
+		expect: log.WarnLevel,
+	},
+	"level warning": {
+		level:  log.LevelWarning,
+		expect: log.WarnLevel,
+	},
+	"level info": {
+		level:  log.LevelInfo,
+		expect: log.InfoLevel,
+	},
+	"level debug": {
+		level:  log.LevelDebug,
+		expect: log.DebugLevel,
+	},
+	"level trace": {
+		level:  log.LevelTrace,
+		expect: log.TraceLevel,
+	},
+	"level mixed case": {
+		level:  "DeBuG",
+		expect: log.DebugLevel,
+	},
+	"level upper case": {
+		level:  "WARNING",
+		expect: log.WarnLevel,
+	},
+	"level typo digit": {
+		level:       "inf0",
+		expect:      log.InfoLevel,
+		expectError: log.NewErrLevel("inf0"),
+	},
+	"level typo letter": {
+		level:       "debgu",
+		expect:      log.InfoLevel,
+		expectError: log.NewErrLevel("debgu"),
+	},
+	"level typo space": {
+		level:       " warn",
+		expect:      log.InfoLevel,
+		expectError: log.NewErrLevel(" warn"),
+	},
+	"level empty": {
+		level:       "",
+		expect:      log.InfoLevel,
+		expectError: log.NewErrLevel(""),
+	},
+	"level field": {
+		level:       log.LevelField,
+		expect:      log.InfoLevel,
+		expectError: log.NewErrLevel(log.LevelField),
+	},
+}
+
+func TestParseLevelStrict(t *testing.T) {
+	test.Map(t, testParseLevelParams).
+		Run(func(t test.Test, param testParseLevelParam) {
+			// When
+			level, err := log.ParseLevelStrict(param.level)
+
+			// Then
+			assert.Equal(t, param.expect, level)
+			assert.Equal(t, param.expectError, err)
+		})
+}
+
+func TestParseLevel(t *testing.T) {
+	test.Map(t, testParseLevelParams).
+		Run(func(t test.Test, param testParseLevelParam) {
+			// When
+			level := log.ParseLevel(param.level)
+
+			// Then
+			assert.Equal(t, param.expect, level)
+		})
+}
+
+type testValidateParam struct {
+	config      log.Config
+	expectError error
+}
+
+var testValidateParams = map[string]testValidateParam{
+	"valid config": {
+		config: log.Config{
+			Level:      log.LevelDebug,
+			SplitLevel: log.LevelWarn,
+			Levels:     map[string]string{"net/http": log.LevelWarn},
+		},
+	},
+	"invalid level": {
+		config:      log.Config{Level: "inf0"},
+		expectError: errors.Join(log.NewErrLevel("inf0")),
+	},
+	"invalid all levels": {
+		config: log.Config{
+			Level:      "inf0",
+			SplitLevel: "wran",
+			Levels: map[string]string{
+				"net/http": "eror", "github.com/org": "dbg",
+			},
+		},
+		expectError: errors.Join(log.NewErrLevel("inf0"),
+			log.NewErrLevel("wran"), log.NewErrLevel("dbg"),
+			log.NewErrLevel("eror")),
+	},
+	"invalid level writers": {
+		config: log.Config{
+			Level:        log.LevelInfo,
+			LevelWriters: map[string]string{"audit": "audit.log"},
+		},
+		expectError: errors.Join(log.NewErrLevel("audit")),
+	},
+	"invalid level sampling": {
+		config: log.Config{
+			Level:         log.LevelInfo,
+			LevelSampling: map[string]log.Sampling{"dbg": {}},
+		},
+		expectError: errors.Join(log.NewErrLevel("dbg")),
+	},
+	"invalid caller level": {
+		config: log.Config{
+			Level:       log.LevelInfo,
+			CallerLevel: "wran",
+		},
+		expectError: errors.Join(log.NewErrLevel("wran")),
+	},
+}
+
+func TestValidate(t *testing.T) {
+	test.Map(t, testValidateParams).
+		Run(func(t test.Test, param testValidateParam) {
+			// When
+			err := param.config.Validate()
+
+			// Then
+			assert.Equal(t, param.expectError, err)
+		})
+}
+
+type testTimeLocationParam struct {
+	location    string
+	expect      string
+	expectError error
+}
+
+var testTimeLocationParams = map[string]testTimeLocationParam{
+	"location default": {
+		expect: "2024-10-02 01:07:13.891012",
+	},
+	"location local": {
+		location: "Local",
+		expect:   "2024-10-02 01:07:13.891012",
+	},
+	"location utc": {
+		location: "UTC",
+		expect:   "2024-10-01 23:07:13.891012",
+	},
+	"location utc lower case": {
+		location: "utc",
+		expect:   "2024-10-01 23:07:13.891012",
+	},
+	"location iana": {
+		location: "America/New_York",
+		expect:   "2024-10-01 19:07:13.891012",
+	},
+	"location invalid": {
+		location: "Mars/Olympus",
+		expect:   "2024-10-02 01:07:13.891012",
+		expectError: log.NewErrTimeLocation("Mars/Olympus",
+			errors.New("unknown time zone Mars/Olympus")),
+	},
+}
+
+func TestTimeLocation(t *testing.T) {
+	local := time.Local
+	t.Cleanup(func() { time.Local = local })
+	time.Local = time.FixedZone("CEST", 2*60*60)
+
+	test.Map(t, testTimeLocationParams).
+		RunSeq(func(t test.Test, param testTimeLocationParam) {
+			// Given
+			config := &log.Config{
+				TimeFormat:   fixedTimeFormat,
+				TimeLocation: param.location,
+				ColorMode:    log.ColorModeOff,
+			}
+			entry := &logrus.Entry{Time: ttime, Level: logrus.InfoLevel}
+			buffer := &bytes.Buffer{}
+
+			// When
+			_, err := config.ParseTimeLocation()
+			rus, rerr := log.NewLogRusPretty(config, buffer).Format(entry)
+			zero := config.Setup(buffer).FormatTimestamp(itime)
+			config.SetupRus(buffer, logrus.New())
+
+			// Then
+			assert.Equal(t, param.expectError, err)
+			assert.NoError(t, rerr)
+			assert.Equal(t, param.expect+" INFO \n", string(rus))
+			assert.Equal(t, param.expect, zero)
+			if param.expectError != nil {
+				assert.Contains(t, buffer.String(),
+					"setting up time location")
+			} else {
+				assert.Empty(t, buffer.String())
+			}
+		})
+}
+
+type testTimeFormatParam struct {
+	format       string
+	expect       string
+	expectLayout string
+}
+
+var testTimeFormatParams = map[string]testTimeFormatParam{
+	"format rfc3339": {
+		format:       "rfc3339",
+		expect:       "2024-10-01T23:07:13Z",
+		expectLayout: time.RFC3339,
+	},
+	"format rfc3339nano": {
+		format:       "RFC3339Nano",
+		expect:       "2024-10-01T23:07:13.891012345Z",
+		expectLayout: time.RFC3339Nano,
+	},
+	"format kitchen": {
+		format:       "kitchen",
+		expect:       "11:07PM",
+		expectLayout: time.Kitchen,
+	},
+	"format iso8601": {
+		format:       "iso8601",
+		expect:       "2024-10-01T23:07:13.891Z",
+		expectLayout: "2006-01-02T15:04:05.000Z07:00",
+	},
+	"format unix": {
+		format:       "unix",
+		expect:       "1727824033",
+		expectLayout: time.RFC3339,
+	},
+	"format unixms": {
+		format:       "unixms",
+		expect:       "1727824033891",
+		expectLayout: time.RFC3339,
+	},
+	"format layout": {
+		format:       "2006-01-02 15:04",
+		expect:       "2024-10-01 23:07",
+		expectLayout: "2006-01-02 15:04",
+	},
+}
+
+func TestTimeFormat(t *testing.T) {
+	test.Map(t, testTimeFormatParams).
+		Run(func(t test.Test, param testTimeFormatParam) {
+			// Given
+			config := &log.Config{
+				TimeFormat:   param.format,
+				TimeLocation: "UTC",
+				ColorMode:    log.ColorModeOff,
+			}
+			entry := &logrus.Entry{Time: ttime, Level: logrus.InfoLevel}
+			buffer := &bytes.Buffer{}
+
+			// When
+			rus, err := log.NewLogRusPretty(config, buffer).Format(entry)
+			zero := config.Setup(buffer).FormatTimestamp(itime)
+			layout := config.ParseTimeLayout(time.RFC3339)
+
+			// Then
+			assert.NoError(t, err)
+			assert.Equal(t, param.expect+" INFO \n", string(rus))
+			assert.Equal(t, param.expect, zero)
+			assert.Equal(t, param.expectLayout, layout)
+		})
+}
+
+func TestTimeFormatText(t *testing.T) {
+	t.Cleanup(func() { zerolog.TimeFieldFormat = time.RFC3339 })
+
+	// Given
+	config := &log.Config{
+		TimeFormat:   "unix",
+		TimeLocation: "UTC",
+		ColorMode:    log.ColorModeOff,
+		Formatter:    log.FormatterText,
+	}
+	buffer := &bytes.Buffer{}
+	logger := config.SetupZero(buffer).ZeroLogger()
+
+	// When
+	logger.Info().Msg("info message")
+
+	// Then
+	assert.Regexp(t, "^[0-9]{10} INF info message\n$", buffer.String())
+}
+
+type testTimeEpochParam struct {
+	format string
+	expect string
+}
+
+var testTimeEpochParams = map[string]testTimeEpochParam{
+	"epoch seconds": {
+		format: log.TimeFormatUnix,
+		expect: "1727824033",
+	},
+	"epoch millis": {
+		format: log.TimeFormatUnixMs,
+		expect: "1727824033891",
+	},
+	"epoch nanos": {
+		format: log.TimeFormatUnixNs,
+		expect: "1727824033891012345",
+	},
+}
+
+func TestTimeEpoch(t *testing.T) {
+	timestamp := zerolog.TimestampFunc
+	t.Cleanup(func() {
+		zerolog.TimeFieldFormat = time.RFC3339
+		zerolog.TimestampFunc = timestamp
+	})
+	zerolog.TimestampFunc = func() time.Time { return ttime }
+
+	test.Map(t, testTimeEpochParams).
+		RunSeq(func(t test.Test, param testTimeEpochParam) {
+			// Given
+			pretty := &log.Config{
+				TimeFormat: param.format,
+				ColorMode:  log.ColorModeOff,
+			}
+			json := &log.Config{
+				TimeFormat: param.format,
+				Formatter:  log.FormatterJSON,
+			}
+			entry := &logrus.Entry{
+				Time: ttime, Level: logrus.InfoLevel,
+				Message: "info message",
+			}
+			prus, pzero := &bytes.Buffer{}, &bytes.Buffer{}
+			jrus, jzero := &bytes.Buffer{}, &bytes.Buffer{}
+
+			// When
+			rus, err := log.NewLogRusPretty(pretty, prus).Format(entry)
+			zlogger := pretty.SetupZero(pzero).ZeroLogger()
+			zlogger.Info().Msg("info message")
+			json.SetupRus(jrus, logrus.New()).WithTime(ttime).
+				Info("info message")
+			zlogger = json.SetupZero(jzero).ZeroLogger()
+			zlogger.Info().Msg("info message")
+
+			// Then
+			assert.NoError(t, err)
+			assert.Equal(t, param.expect+" INFO info message\n", string(rus))
+			assert.Equal(t, param.expect+" INFO info message\n",
+				pzero.String())
+			assert.Equal(t, `{"level":"info","msg":"info message",`+
+				`"time":`+param.expect+"}\n", jrus.String())
+			assert.Equal(t, `{"level":"info","time":`+param.expect+
+				`,"message":"info message"}`+"\n", jzero.String())
+		})
+}
+
+type testTimeNoneParam struct {
+	formatter  log.Formatter
+	expectRus  string
+	expectZero string
+}
+
+var testTimeNoneParams = map[string]testTimeNoneParam{
+	"none pretty": {
+		formatter:  log.FormatterPretty,
+		expectRus:  levelC(log.InfoLevel) + " info message\n",
+		expectZero: levelC(log.InfoLevel) + " info message\n",
+	},
+	"none text": {
+		formatter:  log.FormatterText,
+		expectRus:  "level=info msg=\"info message\"\n",
+		expectZero: "INF info message\n",
+	},
+	"none json": {
+		formatter: log.FormatterJSON,
+		expectRus: `{"level":"info","msg":"info message"}` + "\n",
+		expectZero: `{"level":"info","message":"info message"}` +
+			"\n",
+	},
+}
+
+func TestTimeNone(t *testing.T) {
+	test.Map(t, testTimeNoneParams).
+		Run(func(t test.Test, param testTimeNoneParam) {
+			// Given
+			config := &log.Config{
+				TimeFormat: "none",
+				ColorMode:  log.ColorModeOn,
+				Formatter:  param.formatter,
+			}
+			rus, zero := &bytes.Buffer{}, &bytes.Buffer{}
+			if param.formatter != log.FormatterPretty {
+				config.ColorMode = log.ColorModeOff
+			}
+
+			// When
+			config.SetupRus(rus, logrus.New()).Info("info message")
+			logger := config.SetupZero(zero).ZeroLogger()
+			logger.Info().Msg("info message")
+
+			// Then
+			assert.Equal(t, param.expectRus, rus.String())
+			assert.Equal(t, param.expectZero, zero.String())
+		})
+}
+
+type testJSONPrettyParam struct {
+	formatter  log.Formatter
+	timeFormat string
+	expectRus  string
+	expectZero string
+}
+
+var testJSONPrettyParams = map[string]testJSONPrettyParam{
+	"json pretty": {
+		formatter:  log.FormatterJSON,
+		timeFormat: log.TimeFormatNone,
+		expectRus: "{\n  \"key\": \"value\",\n  \"level\": \"info\",\n" +
+			"  \"msg\": \"info message\"\n}\n",
+		expectZero: "{\n  \"level\": \"info\",\n  \"key\": \"value\",\n" +
+			"  \"message\": \"info message\"\n}\n",
+	},
+	"json pretty epoch": {
+		formatter:  log.FormatterJSON,
+		timeFormat: log.TimeFormatUnix,
+		expectRus: "{\n  \"key\": \"value\",\n  \"level\": \"info\",\n" +
+			"  \"msg\": \"info message\",\n  \"time\": 1704164645\n}\n",
+		expectZero: "{\n  \"level\": \"info\",\n  \"key\": \"value\",\n" +
+			"  \"time\": 1704164645,\n  \"message\": \"info message\"\n}\n",
+	},
+	"json pretty ignored": {
+		formatter:  log.FormatterText,
+		timeFormat: log.TimeFormatNone,
+		expectRus: "level=warning msg=\"setting up json pretty\" " +
+			"error=\"json pretty [text]: ignored for non-json formatter\"\n" +
+			"level=info msg=\"info message\" key=value\n",
+		expectZero: "WRN setting up json pretty " +
+			"error=\"json pretty [text]: ignored for non-json formatter\"\n" +
+			"INF info message key=value\n",
+	},
+}
+
+func TestJSONPretty(t *testing.T) {
+	timestamp := zerolog.TimestampFunc
+	t.Cleanup(func() {
+		zerolog.TimeFieldFormat = time.RFC3339
+		zerolog.TimestampFunc = timestamp
+	})
+	zerolog.TimestampFunc = func() time.Time { return ecsTime }
+
+	test.Map(t, testJSONPrettyParams).
+		RunSeq(func(t test.Test, param testJSONPrettyParam) {
+			// Given
+			config := &log.Config{
+				TimeFormat: param.timeFormat,
+				ColorMode:  log.ColorModeOff,
+				Formatter:  param.formatter,
+				JSONPretty: true,
+			}
+			rus, zero := &bytes.Buffer{}, &bytes.Buffer{}
+			rlogger := config.SetupRus(rus, logrus.New())
+			zlogger := config.SetupZero(zero).ZeroLogger()
+
+			// When
+			rlogger.WithTime(ecsTime).WithField("key", "value").
+				Info("info message")
+			zlogger.Info().Str("key", "value").Msg("info message")
+
+			// Then
+			assert.Equal(t, param.expectRus, rus.String())
+			assert.Equal(t, param.expectZero, zero.String())
+		})
+}
+
+type testLevelWidthParam struct {
+	width     int
+	colorMode log.ColorModeString
+	names     map[string]string
+	expect    []string
+}
+
+var testLevelWidthParams = map[string]testLevelWidthParam{
+	"width zero": {
+		colorMode: log.ColorModeOff,
+		expect: []string{
+			"PANIC", "FATAL", "ERROR", "WARN", "INFO", "DEBUG", "TRACE",
+		},
+	},
+	"width pad color-off": {
+		width:     5,
+		colorMode: log.ColorModeOff,
+		expect: []string{
+			"PANIC", "FATAL", "ERROR", "WARN ", "INFO ", "DEBUG", "TRACE",
+		},
+	},
+	"width pad color-on": {
+		width:     5,
+		colorMode: log.ColorModeOn,
+		expect: []string{
+			colored(log.ColorPanic, "PANIC"),
+			colored(log.ColorFatal, "FATAL"),
+			colored(log.ColorError, "ERROR"),
+			colored(log.ColorWarn, "WARN "),
+			colored(log.ColorInfo, "INFO "),
+			colored(log.ColorDebug, "DEBUG"),
+			colored(log.ColorTrace, "TRACE"),
+		},
+	},
+	"width truncate color-off": {
+		width:     3,
+		colorMode: log.ColorModeOff,
+		expect: []string{
+			"PAN", "FAT", "ERR", "WAR", "INF", "DEB", "TRA",
+		},
+	},
+	"width truncate color-on": {
+		width:     3,
+		colorMode: log.ColorModeOn,
+		expect: []string{
+			colored(log.ColorPanic, "PAN"),
+			colored(log.ColorFatal, "FAT"),
+			colored(log.ColorError, "ERR"),
+			colored(log.ColorWarn, "WAR"),
+			colored(log.ColorInfo, "INF"),
+			colored(log.ColorDebug, "DEB"),
+			colored(log.ColorTrace, "TRA"),
+		},
+	},
+	"width unicode": {
+		width:     6,
+		colorMode: log.ColorModeOff,
+		names: map[string]string{
+			log.LevelPanic: "PANIK", log.LevelError: "FEHLER",
+			log.LevelWarn: "WARNUNG", log.LevelTrace: "SPÜR",
+		},
+		expect: []string{
+			"PANIK ", "FATAL ", "FEHLER", "WARNUN",
+			"INFO  ", "DEBUG ", "SPÜR  ",
+		},
+	},
+}
+
+func TestLevelWidth(t *testing.T) {
+	test.Map(t, testLevelWidthParams).
+		Run(func(t test.Test, param testLevelWidthParam) {
+			// Given
+			config := &log.Config{
+				ColorMode:  param.colorMode,
+				LevelNames: param.names,
+				LevelWidth: param.width,
+			}
+			setup := config.Setup(&bytes.Buffer{})
+
+			for level := log.PanicLevel; level <= log.TraceLevel; level++ {
+				// When
+				result := log.NewBuffer(setup, &bytes.Buffer{}).
+					WriteLevel(level).String()
+				zero := setup.FormatLevel(zeroLevels[level].String())
+
+				// Then
+				assert.Equal(t, param.expect[level], result)
+				assert.Equal(t, param.expect[level], zero)
+			}
+		})
+}
+
+type testCallerParam struct {
+	paths        int
+	short        bool
+	frame        *runtime.Frame
+	expect       string
+	expectFormat string
+}
+
+var testCallerParams = map[string]testCallerParam{
+	"caller any full": {
+		frame:        anyFrame,
+		expect:       " [file:123#function]",
+		expectFormat: "[file:123]",
+	},
+	"caller any short": {
+		paths:        2,
+		short:        true,
+		frame:        anyFrame,
+		expect:       " [file:123#function]",
+		expectFormat: "[file:123]",
+	},
+	"caller deep full": {
+		frame: deepFrame,
+		expect: " [" + deepFrame.File + ":42#" +
+			deepFrame.Function + "]",
+		expectFormat: "[" + deepFrame.File + ":42]",
+	},
+	"caller deep file": {
+		paths: 1,
+		frame: deepFrame,
+		expect: " [file.go:42#" +
+			deepFrame.Function + "]",
+		expectFormat: "[file.go:42]",
+	},
+	"caller deep package": {
+		paths: 2,
+		frame: deepFrame,
+		expect: " [service/file.go:42#" +
+			deepFrame.Function + "]",
+		expectFormat: "[service/file.go:42]",
+	},
+	"caller deep package short": {
+		paths:        2,
+		short:        true,
+		frame:        deepFrame,
+		expect:       " [service/file.go:42#(*Service).Process]",
+		expectFormat: "[service/file.go:42]",
+	},
+	"caller deep paths exceeding": {
+		paths: 20,
+		frame: deepFrame,
+		expect: " [" + deepFrame.File + ":42#" +
+			deepFrame.Function + "]",
+		expectFormat: "[" + deepFrame.File + ":42]",
+	},
+}
+
+func TestCaller(t *testing.T) {
+	test.Map(t, testCallerParams).
+		Run(func(t test.Test, param testCallerParam) {
+			// Given
+			config := &log.Config{
+				Caller:      true,
+				CallerPaths: param.paths,
+				CallerShort: param.short,
+			}
+			setup := config.Setup(&bytes.Buffer{})
+			caller := param.frame.File + ":" + strconv.Itoa(param.frame.Line)
+
+			// When
+			result := log.NewBuffer(setup, &bytes.Buffer{}).
+				WriteCaller(param.frame).String()
+			format := setup.FormatCaller(caller)
+
+			// Then
+			assert.Equal(t, param.expect, result)
+			assert.Equal(t, param.expectFormat, format)
+		})
+}
+
+// logRus is a logging helper wrapping the logrus logger.
+func logRus(logger *logrus.Logger, message string) {
+	logger.Info(message)
+}
+
+// logZero is a logging helper wrapping the zerolog logger.
+func logZero(logger zerolog.Logger, message string) {
+	logger.Info().Msg(message)
+}
+
+func TestCallerSkip(t *testing.T) {
+	// Given
+	config := &log.Config{
+		TimeFormat: "none",
+		ColorMode:  log.ColorModeOff,
+		Caller:     true,
+		CallerSkip: 1,
+	}
+	rus, zero := &bytes.Buffer{}, &bytes.Buffer{}
+	rlogger := config.SetupRus(rus, logrus.New())
+	zlogger := config.SetupZero(zero).ZeroLogger()
+
+	// When
+	logRus(rlogger, "info message")
+	rcaller := caller(-1)
+	logZero(zlogger, "info message")
+	zcaller := caller(-1)
+
+	// Then
+	assert.Equal(t, "INFO ["+rcaller+"#"+packageTest+
+		".TestCallerSkip] info message\n", rus.String())
+	assert.Equal(t, "INFO ["+zcaller+"] info message\n", zero.String())
+}
+
+type testMessageColorParam struct {
+	mode   log.ColorModeString
+	level  log.Level
+	expect string
+}
+
+var testMessageColorParams = map[string]testMessageColorParam{
+	"error messages": {
+		mode:   "off|messages",
+		level:  log.ErrorLevel,
+		expect: "ERROR " + messageC(log.ErrorLevel, "message") + "\n",
+	},
+	"info messages": {
+		mode:   "off|messages",
+		level:  log.InfoLevel,
+		expect: "INFO " + messageC(log.InfoLevel, "message") + "\n",
+	},
+	"error off": {
+		mode:   log.ColorModeOff,
+		level:  log.ErrorLevel,
+		expect: "ERROR message\n",
+	},
+	"info off": {
+		mode:   log.ColorModeOff,
+		level:  log.InfoLevel,
+		expect: "INFO message\n",
+	},
+}
+
+func TestMessageColor(t *testing.T) {
+	test.Map(t, testMessageColorParams).
+		RunSeq(func(t test.Test, param testMessageColorParam) {
+			// Given
+			rusBuffer, zeroBuffer := &bytes.Buffer{}, &bytes.Buffer{}
+			config := &log.Config{
+				Level:      log.LevelInfo,
+				TimeFormat: log.TimeFormatNone,
+				ColorMode:  param.mode,
+			}
+			rus := config.SetupRus(rusBuffer, logrus.New())
+			zero := config.SetupZeroLogger(zerolog.New(io.Discard), zeroBuffer)
+
+			// When
+			rus.Log(logrus.Level(param.level), "message")
+			zero.WithLevel(log.ToZeroLevel(param.level)).Msg("message")
+
+			// Then
+			assert.Equal(t, param.expect, rusBuffer.String())
+			assert.Equal(t, param.expect, zeroBuffer.String())
+		})
+}
+
+type testAlignFieldsParam struct {
+	mode   log.ColorModeString
+	align  int
+	expect string
+}
+
+var testAlignFieldsParams = map[string]testAlignFieldsParam{
+	"unaligned": {
+		mode: log.ColorModeOff,
+		expect: "INFO first id=\"1\" request=\"a\"\n" +
+			"INFO second identifier=\"10\" request=\"b\"\n",
+	},
+	"aligned": {
+		mode:  log.ColorModeOff,
+		align: 11,
+		expect: "INFO first id=        \"1\" request=   \"a\"\n" +
+			"INFO second identifier=\"10\" request=   \"b\"\n",
+	},
+	"aligned short": {
+		mode:  log.ColorModeOff,
+		align: 5,
+		expect: "INFO first id=  \"1\" request=\"a\"\n" +
+			"INFO second identifier=\"10\" request=\"b\"\n",
+	},
+	"aligned colors": {
+		mode:  log.ColorModeOn,
+		align: 11,
+		expect: levelC(log.InfoLevel) + " first " +
+			fieldC("id") + "=        \"1\" " + fieldC("request") + "=   \"a\"\n" +
+			levelC(log.InfoLevel) + " second " +
+			fieldC("identifier") + "=\"10\" " + fieldC("request") + "=   \"b\"\n",
+	},
+}
+
+func TestAlignFields(t *testing.T) {
+	test.Map(t, testAlignFieldsParams).
+		RunSeq(func(t test.Test, param testAlignFieldsParam) {
+			// Given
+			rusBuffer, zeroBuffer := &bytes.Buffer{}, &bytes.Buffer{}
+			config := &log.Config{
+				Level:       log.LevelInfo,
+				TimeFormat:  log.TimeFormatNone,
+				ColorMode:   param.mode,
+				AlignFields: param.align,
+			}
+			rus := config.SetupRus(rusBuffer, logrus.New())
+			zero := config.SetupZeroLogger(zerolog.New(io.Discard), zeroBuffer)
+
+			// When
+			rus.WithField("id", "1").WithField("request", "a").Info("first")
+			rus.WithField("identifier", "10").WithField("request", "b").
+				Info("second")
+			zero.Info().Str("id", "1").Str("request", "a").Msg("first")
+			zero.Info().Str("identifier", "10").Str("request", "b").
+				Msg("second")
+
+			// Then
+			assert.Equal(t, param.expect, rusBuffer.String())
+			assert.Equal(t, param.expect, zeroBuffer.String())
+		})
+}
+
+type testSeparatorsParam struct {
+	fieldSep string
+	kvSep    string
+	expect   string
+}
+
+var testSeparatorsParams = map[string]testSeparatorsParam{
+	"default": {
+		expect: "INFO message error=failure at=12:00 " +
+			"key=value url=\"a b\"\n",
+	},
+	"tab colon": {
+		fieldSep: "\t",
+		kvSep:    ":",
+		expect: "INFO message\terror:failure\tat:\"12:00\"" +
+			"\tkey:value\turl:\"a b\"\n",
+	},
+}
+
+func TestSeparators(t *testing.T) {
+	test.Map(t, testSeparatorsParams).
+		RunSeq(func(t test.Test, param testSeparatorsParam) {
+			// Given
+			rusBuffer, zeroBuffer := &bytes.Buffer{}, &bytes.Buffer{}
+			config := &log.Config{
+				Level:          log.LevelInfo,
+				TimeFormat:     log.TimeFormatNone,
+				ColorMode:      log.ColorModeOff,
+				QuoteMode:      log.QuoteModeAuto,
+				FieldSeparator: param.fieldSep,
+				KVSeparator:    param.kvSep,
+			}
+			rus := config.SetupRus(rusBuffer, logrus.New())
+			zero := config.SetupZeroLogger(zerolog.New(io.Discard), zeroBuffer)
+
+			// When
+			rus.WithError(errors.New("failure")).WithField("key", "value").
+				WithField("at", "12:00").WithField("url", "a b").
+				Info("message")
+			zero.Info().Err(errors.New("failure")).Str("key", "value").
+				Str("at", "12:00").Str("url", "a b").Msg("message")
+
+			// Then
+			assert.Equal(t, param.expect, rusBuffer.String())
+			assert.Equal(t, param.expect, zeroBuffer.String())
+		})
+}
@@ -4,47 +4,472 @@ import (
 	"bytes"
 	"io"
 	"maps"
+	"os"
 	"slices"
-	"sort"
+	"sync"
+	"time"
 
 	"github.com/sirupsen/logrus"
 )
 
 // SetupRus is setting up and returning the given logger. It particular sets up
 // the log level, the report caller flag, as well as the formatter with color
-// and order mode. If no logger is given, the standard logger is set up.
+// and order mode. If no logger is given, the standard logger is set up. The
+// setup is safe for concurrent use. Calling it again replaces the logrus
+// logger of the config as well as all hooks of the given logger.
 func (c *Config) SetupRus(writer io.Writer, logger *logrus.Logger) *logrus.Logger {
+	loggers := c.setupLoggers()
+	loggers.mutex.Lock()
+	defer loggers.mutex.Unlock()
+	return c.setupRus(loggers, writer, logger)
+}
+
+// setupRus is setting up and returning the given logger as logrus logger of
+// the given loggers. The loggers must be locked by the caller.
+func (c *Config) setupRus(
+	loggers *loggers, writer io.Writer, logger *logrus.Logger,
+) *logrus.Logger {
 	// Uses the standard logger if no logger is given.
 	if logger == nil {
 		logger = logrus.StandardLogger()
 	}
+	loggers.rus = logger
 
+	// Flushes the pending repeated entries of a former setup before replacing.
+	if loggers.rusFlush != nil {
+		loggers.rusFlush()
+	}
+	// Replaces the hooks of a former setup to not duplicate the output.
+	logger.ReplaceHooks(logrus.LevelHooks{})
 	logger.SetOutput(writer)
-	// #nosec G115 // cannot happen.
-	logger.SetLevel(logrus.Level(ParseLevel(c.Level)))
+	if modules := loggers.modules; modules != nil {
+		// #nosec G115 // cannot happen.
+		logger.SetLevel(logrus.Level(modules.Verbose()))
+	} else {
+		// #nosec G115 // cannot happen.
+		logger.SetLevel(logrus.Level(ParseLevel(c.Level)))
+	}
 	logger.SetReportCaller(c.Caller)
+	if c.ExitFunc != nil {
+		logger.ExitFunc = c.ExitFunc
+	}
+
+	// Sets up the global error key used by `WithError` consistently.
+	c.setupRusGlobals()
+
+	// Sets up the sampling hook first to suppress entries before output.
+	if c.IsSamplingEnabled() {
+		logger.AddHook(NewLogRusSampleHook(c))
+	}
+	if samplers := loggers.setupSamplers(c); samplers != nil {
+		logger.AddHook(NewLogRusLevelSampleHook(samplers))
+	}
+
+	// Sets up the dedup hook collapsing consecutive identical entries.
+	loggers.rusFlush = nil
+	if c.IsDedupEnabled() {
+		dedup := NewLogRusDedupHook(c, logger)
+		loggers.rusFlush = dedup.Flush
+		logger.AddHook(dedup)
+	}
+
+	// Sets up the log output split and format. The split is applied while
+	// formatting to ensure that all hooks have run before the entry is written.
+	if split := c.SetupSplit(writer); split != nil {
+		logger.SetOutput(io.Discard)
+		logger.SetFormatter(NewLogRusSplit(c, split))
+	} else {
+		logger.SetFormatter(c.RusFormatter(writer))
+	}
+
+	// Sets up the static and build info fields attached to every log entry.
+	fields, ferr := c.StaticFields()
+	if len(fields) > 0 {
+		logger.AddHook(NewLogRusFieldsHook(fields))
+	}
+	if len(c.ParseInfoFields()) > 0 {
+		logger.AddHook(NewLogRusFieldsHook(c.InfoData()))
+	}
+
+	// Sets up the stack trace hook capturing the call site stack.
+	if c.IsStacktraceEnabled() {
+		logger.AddHook(NewLogRusStackHook(c))
+	}
+
+	// Sets up the exclude hook omitting noisy fields for the formatters not
+	// supporting excluded fields.
+	if c.Formatter == FormatterText || c.IsExcludeJSON() {
+		logger.AddHook(NewLogRusExcludeHook(c.ExcludeFields))
+	}
+
+	// Sets up the error causes hook for the JSON formatter.
+	if c.ErrorCauses > 0 && c.Formatter == FormatterJSON {
+		logger.AddHook(NewLogRusCausesHook(c.ErrorCauses))
+	}
+
+	// Sets up the redact hook replacing the values of sensitive fields before
+	// the output hooks below and the formatter write the entry.
+	if c.IsRedactEnabled() {
+		logger.AddHook(NewLogRusRedactHook(c, writer))
+	}
+
+	// Sets up the caller hook reporting the true call site, or the call site
+	// of entries at or above the caller level only.
+	if (c.Caller && c.CallerSkip > 0) || c.IsCallerLevelEnabled() {
+		logger.AddHook(NewLogRusCallerHook(c))
+	}
+
+	// Sets up the additional syslog output.
+	if syslog, err := loggers.setupSyslog(c); err != nil {
+		logger.WithError(err).Warn("setting up syslog")
+	} else if syslog != nil {
+		logger.AddHook(NewLogRusSyslogHook(c, syslog))
+	}
+
+	// Sets up the additional log file output using the file formatter.
+	if c.IsFileOutputEnabled() {
+		if file, err := loggers.setupFile(c); err != nil {
+			logger.WithError(err).Warn("setting up file output")
+		} else {
+			logger.AddHook(NewLogRusFileHook(c, file))
+		}
+	}
 
-	// Sets up the log output format.
+	// Sets up the additional log files routed by level.
+	if router, err := loggers.setupRouter(c); err != nil {
+		logger.WithError(err).Warn("setting up level writers")
+	} else if router != nil {
+		logger.AddHook(NewLogRusLevelHook(c, router))
+	}
+
+	// Sets up the additional hooks registered via the config.
+	c.setupRusHooks(logger)
+
+	if _, err := c.ParseTimeLocation(); err != nil {
+		logger.WithError(err).Warn("setting up time location")
+	}
+	if _, err := c.ParseJSONPretty(); err != nil {
+		logger.WithError(err).Warn("setting up json pretty")
+	}
+	if _, err := c.ParseFieldMap(); err != nil {
+		logger.WithError(err).Warn("setting up field map")
+	}
+	if _, err := c.ParseRedactFields(); err != nil {
+		logger.WithError(err).Warn("setting up redact fields")
+	}
+	if ferr != nil {
+		logger.WithError(ferr).Debug("omitting hostname field")
+	}
+
+	return logger
+}
+
+// setupRusGlobals sets up the global error key of logrus used by `WithError`,
+// since logrus does not support the error key per logger. The global error key
+// is guarded by the loggers mutex to synchronize concurrent setups of
+// different configs, but it is shared by all logrus loggers of the process.
+func (c *Config) setupRusGlobals() {
+	loggersMutex.Lock()
+	defer loggersMutex.Unlock()
+	if name := c.ParseErrorName(); logrus.ErrorKey != name {
+		logrus.ErrorKey = name
+	}
+}
+
+// RusLogger returns the logrus logger set up by the config. If no logrus
+// logger was set up, the standard logger is set up writing to `os.Stderr`
+// first.
+func (c *Config) RusLogger() *logrus.Logger {
+	loggers := c.setupLoggers()
+	loggers.mutex.Lock()
+	defer loggers.mutex.Unlock()
+	if loggers.rus != nil {
+		return loggers.rus
+	}
+	return c.setupRus(loggers, os.Stderr, nil)
+}
+
+// LookupRusLogger returns the logrus logger set up by the config and whether
+// a logrus logger was set up.
+func (c *Config) LookupRusLogger() (*logrus.Logger, bool) {
+	loggers := c.lookupLoggers()
+	if loggers == nil {
+		return nil, false
+	}
+
+	loggers.mutex.RLock()
+	defer loggers.mutex.RUnlock()
+	return loggers.rus, loggers.rus != nil
+}
+
+// RusFormatter creates the logrus formatter for the given writer. It sets up
+// the time format as well as the color and order mode of the formatter. If
+// module specific log levels are configured, the formatter is wrapped to drop
+// entries below the module specific log level. If sampling or deduplication
+// is enabled, the formatter is wrapped to drop suppressed entries.
+func (c *Config) RusFormatter(writer io.Writer) logrus.Formatter {
+	formatter := c.rusFormatter(writer)
+	if modules := c.setupLoggers().modules; modules != nil {
+		formatter = &LogRusModules{
+			Formatter: formatter,
+			modules:   modules,
+			skip:      c.CallerSkip,
+		}
+	}
+	if c.IsCallerLevelEnabled() {
+		formatter = &LogRusCallerFormatter{Formatter: formatter}
+	}
+	if c.IsSamplingEnabled() || c.IsLevelSamplingEnabled() ||
+		c.IsDedupEnabled() {
+		formatter = &LogRusSuppressed{Formatter: formatter}
+	}
+	return formatter
+}
+
+// rusFormatter creates the plain logrus formatter for the given writer.
+func (c *Config) rusFormatter(writer io.Writer) logrus.Formatter {
 	switch c.Formatter {
 	case FormatterText:
-		color := c.ColorMode.Parse(IsTerminal(logger.Out))
-		logger.SetFormatter(&logrus.TextFormatter{
-			TimestampFormat: c.TimeFormat,
-			FullTimestamp:   true,
-			ForceColors:     color&ColorOn == ColorOn,
-			DisableColors:   color&ColorOff == ColorOff,
-		})
+		color := c.ParseColorMode(writer)
+		return &logrus.TextFormatter{
+			TimestampFormat:  c.ParseTimeLayout(time.RFC3339),
+			DisableTimestamp: c.IsTimeNone(),
+			FullTimestamp:    true,
+			ForceColors:      color.CheckFlag(ColorOn),
+			DisableColors:    color.CheckFlag(ColorOff),
+			FieldMap:         c.RusFieldMap(),
+
+			DisableQuote:           c.Text.DisableQuote,
+			QuoteEmptyFields:       c.Text.QuoteEmptyFields,
+			PadLevelText:           c.Text.PadLevelText,
+			DisableLevelTruncation: c.Text.DisableLevelTruncation,
+		}
 	case FormatterJSON:
-		logger.SetFormatter(&logrus.JSONFormatter{
-			TimestampFormat: c.TimeFormat,
-		})
+		pretty, _ := c.ParseJSONPretty()
+		if format := c.ParseTimeFormat(); IsTimeEpoch(format) {
+			epoch := NewLogRusEpoch(format, c.RusFieldMap())
+			epoch.PrettyPrint = pretty
+			return epoch
+		}
+		return &logrus.JSONFormatter{
+			TimestampFormat:  c.ParseTimeLayout(time.RFC3339),
+			DisableTimestamp: c.IsTimeNone(),
+			PrettyPrint:      pretty,
+			FieldMap:         c.RusFieldMap(),
+		}
+	case FormatterECS:
+		return NewLogRusECS(c, writer)
+	case FormatterGCP:
+		return NewLogRusGCP(c, writer)
+	case FormatterGELF:
+		return NewLogRusGELF(c, writer)
 	case FormatterPretty:
 		fallthrough
 	default:
-		logger.SetFormatter(NewLogRusPretty(c, writer))
+		return NewLogRusPretty(c, writer)
 	}
+}
 
-	return logger
+// RusFieldMap returns the logrus field map renaming the time, level, and
+// message fields as configured. Invalid renames are ignored.
+func (c *Config) RusFieldMap() logrus.FieldMap {
+	fields, _ := c.ParseFieldMap()
+	if len(fields) == 0 {
+		return nil
+	}
+
+	fieldMap := logrus.FieldMap{}
+	if name, ok := fields[FieldKeyTime]; ok {
+		fieldMap[logrus.FieldKeyTime] = name
+	}
+	if name, ok := fields[FieldKeyLevel]; ok {
+		fieldMap[logrus.FieldKeyLevel] = name
+	}
+	if name, ok := fields[FieldKeyMessage]; ok {
+		fieldMap[logrus.FieldKeyMsg] = name
+	}
+	return fieldMap
+}
+
+// LogRusEpoch is a JSON formatter writing unix timestamps instead of
+// formatted timestamps, since logrus only supports time layouts.
+type LogRusEpoch struct {
+	*logrus.JSONFormatter
+	// format is the numeric time format.
+	format string
+	// key is the field name of the unix timestamp.
+	key string
+}
+
+// NewLogRusEpoch creates a new JSON formatter writing unix timestamps using
+// the precision of the given numeric time format and the given field map.
+func NewLogRusEpoch(format string, fields logrus.FieldMap) *LogRusEpoch {
+	fieldMap := logrus.FieldMap{}
+	maps.Copy(fieldMap, fields)
+	key := logrus.FieldKeyTime
+	if name, ok := fields[logrus.FieldKeyTime]; ok {
+		key = name
+	}
+	// Prevents the unix timestamp from being prefixed as clash.
+	fieldMap[logrus.FieldKeyTime] = "fields." + key
+
+	return &LogRusEpoch{
+		JSONFormatter: &logrus.JSONFormatter{
+			DisableTimestamp: true,
+			FieldMap:         fieldMap,
+		},
+		format: format,
+		key:    key,
+	}
+}
+
+// Format formats the log entry adding the unix timestamp to the entry data.
+func (f *LogRusEpoch) Format(entry *logrus.Entry) ([]byte, error) {
+	data := make(logrus.Fields, len(entry.Data)+1)
+	maps.Copy(data, entry.Data)
+	data[f.key] = TimeEpoch(f.format, entry.Time)
+
+	clone := *entry
+	clone.Data = data
+	return f.JSONFormatter.Format(&clone)
+}
+
+// LogRusModules is a formatter wrapper dropping log entries below the module
+// specific log level of the caller package. Since logrus hooks cannot drop
+// entries, the filter is applied while formatting by returning no output.
+type LogRusModules struct {
+	logrus.Formatter
+	// modules are the module specific log levels.
+	modules *Modules
+	// skip is the number of additional caller frames to skip.
+	skip int
+}
+
+// Format formats the log entry using the wrapped formatter, if the log level
+// of the entry is enabled for the caller package.
+func (f *LogRusModules) Format(entry *logrus.Entry) ([]byte, error) {
+	frame := entry.Caller
+	if frame == nil {
+		frame = CallerFrame(f.skip)
+	}
+
+	if !f.modules.Enabled(FramePackage(frame), Level(entry.Level)) {
+		return nil, nil
+	}
+	return f.Formatter.Format(entry)
+}
+
+// LogRusCallerHook is a hook replacing the caller of log entries by the true
+// call site skipping the configured number of additional caller frames, since
+// logrus does not support skipping caller frames. If a caller level is
+// configured, the caller is only captured for entries at or above the level.
+type LogRusCallerHook struct {
+	// level is the level at or above which the caller is captured.
+	level Level
+	// skip is the number of additional caller frames to skip.
+	skip int
+}
+
+// NewLogRusCallerHook creates a new caller hook for logrus using the given
+// config.
+func NewLogRusCallerHook(c *Config) *LogRusCallerHook {
+	return &LogRusCallerHook{
+		level: c.ParseCallerLevel(),
+		skip:  c.CallerSkip,
+	}
+}
+
+// Levels returns the log levels at or above the caller level.
+func (h *LogRusCallerHook) Levels() []logrus.Level {
+	return logrus.AllLevels[:h.level+1]
+}
+
+// Fire replaces the caller of the given log entry.
+func (h *LogRusCallerHook) Fire(entry *logrus.Entry) error {
+	entry.Caller = CallerFrame(h.skip)
+	return nil
+}
+
+// LogRusSplit is a formatter wrapper routing log entries by level to the low
+// and high level writer of a split writer. Each writer is using its own
+// formatter to support terminal dependent formatting. Since logrus writes all
+// entries to a single output, the formatter writes the formatted entry itself
+// and returns no output for the logger output.
+type LogRusSplit struct {
+	// level is the split level.
+	level Level
+	// split is the split writer.
+	split *SplitWriter
+	// low is the formatter used for the low level writer.
+	low logrus.Formatter
+	// high is the formatter used for the high level writer.
+	high logrus.Formatter
+	// mutex is the mutex to synchronize writes.
+	mutex sync.Mutex
+}
+
+// NewLogRusSplit creates a new split formatter for logrus using the split
+// level of the given config and the given split writer.
+func NewLogRusSplit(c *Config, split *SplitWriter) *LogRusSplit {
+	return &LogRusSplit{
+		level: c.ParseSplitLevel(),
+		split: split,
+		low:   c.RusFormatter(split.Low),
+		high:  c.RusFormatter(split.High),
+	}
+}
+
+// Format formats the given log entry using the formatter of the writer
+// responsible for the log level of the entry and writes it to this writer.
+func (f *LogRusSplit) Format(entry *logrus.Entry) ([]byte, error) {
+	formatter := f.low
+	if Level(entry.Level) <= f.level {
+		formatter = f.high
+	}
+
+	bytes, err := formatter.Format(entry)
+	if err != nil || len(bytes) == 0 {
+		return nil, err
+	}
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	_, err = f.split.Writer(f.level, Level(entry.Level)).Write(bytes)
+	return nil, err
+}
+
+// LogRusSyslogHook is a hook sending log entries to a syslog endpoint. The
+// entries are formatted using the configured formatter without colors.
+type LogRusSyslogHook struct {
+	// syslog is the syslog writer.
+	syslog *SyslogWriter
+	// formatter is the formatter used for syslog entries.
+	formatter logrus.Formatter
+}
+
+// NewLogRusSyslogHook creates a new syslog hook for logrus using the given
+// config and syslog writer.
+func NewLogRusSyslogHook(c *Config, syslog *SyslogWriter) *LogRusSyslogHook {
+	return &LogRusSyslogHook{
+		syslog:    syslog,
+		formatter: c.RusFormatter(syslog),
+	}
+}
+
+// Levels returns all log levels, since all entries are sent to syslog.
+func (*LogRusSyslogHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+// Fire formats the given log entry and sends it to syslog.
+func (h *LogRusSyslogHook) Fire(entry *logrus.Entry) error {
+	bytes, err := h.formatter.Format(entry)
+	if err != nil || len(bytes) == 0 {
+		return err
+	}
+
+	_, err = h.syslog.WriteLevel(Level(entry.Level), bytes)
+	return err
 }
 
 // LogRusPretty formats logs into a pretty format.
@@ -59,27 +484,34 @@ func NewLogRusPretty(c *Config, writer io.Writer) *LogRusPretty {
 	}
 }
 
-// Format formats the log entry to a pretty format.
+// Format formats the log entry to a pretty format using a pooled buffer. The
+// formatted bytes are copied out before the buffer is returned to the pool.
 func (p *LogRusPretty) Format(entry *logrus.Entry) ([]byte, error) {
-	buffer := NewBuffer(p.Setup, &bytes.Buffer{})
-	buffer.WriteString(entry.Time.Format(p.TimeFormat)).
-		WriteByte(' ').WriteLevel(Level(entry.Level))
+	pooled := getBuffer()
+	defer putBuffer(pooled)
+
+	buffer := NewBuffer(p.Setup, pooled)
+	if p.TimeFormat != TimeFormatNone {
+		buffer.WriteString(p.FormatTime(entry.Time)).WriteByte(' ')
+	}
+	buffer.WriteLevel(Level(entry.Level))
 	if entry.HasCaller() {
 		buffer.WriteCaller(entry.Caller)
 	}
-	buffer.WriteByte(' ').WriteString(entry.Message)
+	buffer.WriteByte(' ').WriteMessage(Level(entry.Level), entry.Message)
 
 	for _, key := range p.getSortedKeys(entry.Data) {
-		buffer.WriteByte(' ').WriteData(key, entry.Data[key])
+		buffer.WriteSeparator().WriteData(key, entry.Data[key])
+	}
+	if stack, ok := entry.Data[FieldStack].([]string); ok {
+		buffer.WriteStack(stack)
 	}
-	return buffer.WriteByte('\n').Bytes()
+	result, err := buffer.WriteByte('\n').Bytes()
+	return bytes.Clone(result), err
 }
 
-// getSortedKeys returns the keys of the given data.
+// getSortedKeys returns the keys of the given data in the order printed by
+// the pretty formatter.
 func (p *LogRusPretty) getSortedKeys(data logrus.Fields) []string {
-	keys := slices.Collect(maps.Keys(data))
-	if p.OrderMode.CheckFlag(OrderOn) {
-		sort.Strings(keys)
-	}
-	return keys
+	return p.FieldKeys(slices.Collect(maps.Keys(data)))
 }
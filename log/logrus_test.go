@@ -1,12 +1,15 @@
 package log_test
 
 import (
+	"bytes"
 	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/tkrop/go-testing/test"
 
@@ -70,6 +73,87 @@ func TestSetupNil(t *testing.T) {
 	assert.Equal(t, logrus.StandardLogger(), logger)
 }
 
+func TestSetupRusExit(t *testing.T) {
+	// Given
+	buffer := &bytes.Buffer{}
+	code := -1
+	config := &log.Config{
+		Level:      log.LevelInfo,
+		TimeFormat: log.TimeFormatNone,
+		ColorMode:  log.ColorModeOff,
+		ExitFunc:   func(c int) { code = c },
+	}
+	logger := config.SetupRus(buffer, logrus.New())
+
+	// When
+	logger.Fatal("fatal message")
+
+	// Then
+	assert.Equal(t, 1, code)
+	assert.Equal(t, "FATAL fatal message\n", buffer.String())
+}
+
+type testSetupRusRepeatedParam struct {
+	setups int
+}
+
+var testSetupRusRepeatedParams = map[string]testSetupRusRepeatedParam{
+	"single setup": {
+		setups: 1,
+	},
+	"repeated setup": {
+		setups: 2,
+	},
+}
+
+func TestSetupRusRepeated(t *testing.T) {
+	test.Map(t, testSetupRusRepeatedParams).
+		Run(func(t test.Test, param testSetupRusRepeatedParam) {
+			// Given
+			path := filepath.Join(t.TempDir(), "test.log")
+			buffer := &bytes.Buffer{}
+			config := &log.Config{
+				Level:         log.LevelInfo,
+				TimeFormat:    log.TimeFormatNone,
+				ColorMode:     log.ColorModeOff,
+				File:          path,
+				FileFormatter: log.FormatterText,
+				Fields:        map[string]string{"app": "test"},
+			}
+			logger := logrus.New()
+			for range param.setups {
+				config.SetupRus(buffer, logger)
+			}
+
+			// When
+			logger.Info("info message")
+
+			// Then
+			assert.Equal(t, "INFO info message app=\"test\"\n", buffer.String())
+			assert.Equal(t, "level=info msg=\"info message\" app=test\n",
+				readFile(t, path))
+			file, err := config.FileWriter()
+			require.NoError(t, err)
+			assert.NoError(t, file.Close())
+		})
+}
+
+func TestRusLoggerLazy(t *testing.T) {
+	// Given
+	config := &log.Config{Level: log.LevelWarn}
+
+	// When
+	logger := config.RusLogger()
+
+	// Then
+	assert.Equal(t, logrus.StandardLogger(), logger)
+	assert.Equal(t, logrus.WarnLevel, logger.GetLevel())
+	assert.Equal(t, os.Stderr, logger.Out)
+	lookup, ok := config.LookupRusLogger()
+	assert.True(t, ok)
+	assert.Same(t, logger, lookup)
+}
+
 // Arbitrary data for testing.
 var anyData = logrus.Fields{
 	"key1": "value1",
@@ -528,3 +612,26 @@ func TestPrettyLogRus(t *testing.T) {
 			}
 		})
 }
+
+func BenchmarkLogRusPrettyFormat(b *testing.B) {
+	pretty := log.NewLogRusPretty(&log.Config{
+		TimeFormat: log.DefaultTimeFormat,
+		ColorMode:  log.ColorModeOn,
+	}, os.Stderr)
+	entry := &logrus.Entry{
+		Time:    time.Now(),
+		Level:   logrus.InfoLevel,
+		Message: "info message",
+		Data: logrus.Fields{
+			"user": "alice", "count": 42, "flag": true, "zone": "eu",
+		},
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for range b.N {
+		if _, err := pretty.Format(entry); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
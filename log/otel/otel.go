@@ -0,0 +1,100 @@
+// Package otel provides hooks attaching the trace and span ids of the active
+// OpenTelemetry span context to log entries created with a context. The
+// package is kept separate to avoid pulling in the OpenTelemetry dependency
+// for users of the log package.
+package otel
+
+import (
+	"context"
+
+	"github.com/rs/zerolog"
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/tkrop/go-config/log"
+)
+
+// AddHooks registers the trace hooks for logrus and zerolog at the given
+// config to be added by `SetupRus` and `SetupZero`, if the trace fields are
+// enabled, i.e. if any field name is set.
+func AddHooks(c *log.Config) *log.Config {
+	if c.IsTraceEnabled() {
+		c.AddRusHook(NewLogRusTraceHook(c)).
+			AddZeroHook(NewZeroLogTraceHook(c))
+	}
+	return c
+}
+
+// traceFields returns the trace and span id fields of the active span context
+// provided by the given context using the given field names. If the context
+// carries no valid span context, no fields are returned. Fields with empty
+// names are omitted.
+func traceFields(ctx context.Context, traceName, spanName string) map[string]any {
+	if ctx == nil {
+		return nil
+	}
+
+	span := trace.SpanContextFromContext(ctx)
+	if !span.IsValid() {
+		return nil
+	}
+
+	fields := make(map[string]any, 2)
+	if traceName != "" {
+		fields[traceName] = span.TraceID().String()
+	}
+	if spanName != "" {
+		fields[spanName] = span.SpanID().String()
+	}
+	return fields
+}
+
+// LogRusTraceHook is a hook adding the trace and span ids of the active span
+// context to log entries created with a context via `WithContext`.
+type LogRusTraceHook struct {
+	// trace is the field name of the trace id.
+	trace string
+	// span is the field name of the span id.
+	span string
+}
+
+// NewLogRusTraceHook creates a new trace hook for logrus using the field names
+// of the given config.
+func NewLogRusTraceHook(c *log.Config) *LogRusTraceHook {
+	return &LogRusTraceHook{trace: c.TraceIDName, span: c.SpanIDName}
+}
+
+// Levels returns all log levels, since the trace ids are added to all entries.
+func (*LogRusTraceHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+// Fire adds the trace and span ids to the given log entry.
+func (h *LogRusTraceHook) Fire(entry *logrus.Entry) error {
+	for key, value := range traceFields(entry.Context, h.trace, h.span) {
+		entry.Data[key] = value
+	}
+	return nil
+}
+
+// ZeroLogTraceHook is a hook adding the trace and span ids of the active span
+// context to log events created with a context via `Ctx`.
+type ZeroLogTraceHook struct {
+	// trace is the field name of the trace id.
+	trace string
+	// span is the field name of the span id.
+	span string
+}
+
+// NewZeroLogTraceHook creates a new trace hook for zerolog using the field
+// names of the given config.
+func NewZeroLogTraceHook(c *log.Config) *ZeroLogTraceHook {
+	return &ZeroLogTraceHook{trace: c.TraceIDName, span: c.SpanIDName}
+}
+
+// Run adds the trace and span ids to the given log event.
+func (h *ZeroLogTraceHook) Run(event *zerolog.Event, _ zerolog.Level, _ string) {
+	if fields := traceFields(event.GetCtx(), h.trace, h.span); fields != nil {
+		event.Fields(fields)
+	}
+}
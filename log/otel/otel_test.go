@@ -0,0 +1,105 @@
+package otel_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/tkrop/go-testing/test"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/tkrop/go-config/log"
+	"github.com/tkrop/go-config/log/otel"
+)
+
+// spanContext creates a context carrying a valid span context for testing.
+func spanContext() context.Context {
+	return trace.ContextWithSpanContext(context.Background(),
+		trace.NewSpanContext(trace.SpanContextConfig{
+			TraceID: trace.TraceID{0x4b, 0xf9, 0x2f, 0x35, 0x77, 0xb3,
+				0x4d, 0xa6, 0xa3, 0xce, 0x92, 0x9d, 0x0e, 0x0e, 0x47, 0x36},
+			SpanID:     trace.SpanID{0x00, 0xf0, 0x67, 0xaa, 0x0b, 0xa9, 0x02, 0xb7},
+			TraceFlags: trace.FlagsSampled,
+		}))
+}
+
+const (
+	// traceID is the trace id of the test span context.
+	traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+	// spanID is the span id of the test span context.
+	spanID = "00f067aa0ba902b7"
+)
+
+type testTraceParam struct {
+	ctx        context.Context
+	traceName  string
+	spanName   string
+	expectRus  string
+	expectZero string
+}
+
+var testTraceParams = map[string]testTraceParam{
+	"span context": {
+		ctx:       spanContext(),
+		traceName: "trace_id",
+		spanName:  "span_id",
+		expectRus: "INFO info message span_id=\"" + spanID +
+			"\" trace_id=\"" + traceID + "\"\n",
+		expectZero: "INFO info message span_id=\"" + spanID +
+			"\" trace_id=\"" + traceID + "\"\n",
+	},
+	"span context custom names": {
+		ctx:       spanContext(),
+		traceName: "trace",
+		spanName:  "span",
+		expectRus: "INFO info message span=\"" + spanID +
+			"\" trace=\"" + traceID + "\"\n",
+		expectZero: "INFO info message span=\"" + spanID +
+			"\" trace=\"" + traceID + "\"\n",
+	},
+	"span context trace only": {
+		ctx:        spanContext(),
+		traceName:  "trace_id",
+		expectRus:  "INFO info message trace_id=\"" + traceID + "\"\n",
+		expectZero: "INFO info message trace_id=\"" + traceID + "\"\n",
+	},
+	"span context disabled": {
+		ctx:        spanContext(),
+		expectRus:  "INFO info message\n",
+		expectZero: "INFO info message\n",
+	},
+	"no span context": {
+		ctx:        context.Background(),
+		traceName:  "trace_id",
+		spanName:   "span_id",
+		expectRus:  "INFO info message\n",
+		expectZero: "INFO info message\n",
+	},
+}
+
+func TestTrace(t *testing.T) {
+	test.Map(t, testTraceParams).
+		Run(func(t test.Test, param testTraceParam) {
+			// Given
+			config := otel.AddHooks(&log.Config{
+				TimeFormat:  log.TimeFormatNone,
+				ColorMode:   log.ColorModeOff,
+				OrderMode:   log.OrderModeOn,
+				TraceIDName: param.traceName,
+				SpanIDName:  param.spanName,
+			})
+			rus, zero := &bytes.Buffer{}, &bytes.Buffer{}
+			rlogger := config.SetupRus(rus, logrus.New())
+			zlogger := config.SetupZero(zero).ZeroLogger()
+
+			// When
+			rlogger.WithContext(param.ctx).Info("info message")
+			zlogger.Info().Ctx(param.ctx).Msg("info message")
+
+			// Then
+			assert.Equal(t, param.expectRus, rus.String())
+			assert.Equal(t, param.expectZero, zero.String())
+		})
+}
@@ -0,0 +1,208 @@
+package log
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/rs/zerolog"
+	"github.com/sirupsen/logrus"
+)
+
+// Redacted is the value replacing the values of redacted fields.
+const Redacted = "***"
+
+// redactError is matching the `key=value` pairs in error messages.
+var redactError = regexp.MustCompile(`([\w.-]+)=("(?:[^"\\]|\\.)*"|[^\s,;&]+)`)
+
+// ErrRedactField is a common error to indicate an invalid redact field.
+var ErrRedactField = errors.New("redact field")
+
+// NewErrRedactField is a convenience method to create a new redact field
+// error for the given pattern wrapping the original error.
+func NewErrRedactField(pattern string, err error) error {
+	return fmt.Errorf("%w [%s]: %w", ErrRedactField, pattern, err)
+}
+
+// ParseRedactFields returns the lower case redact field patterns. Invalid
+// patterns are ignored returning an error.
+func (c *Config) ParseRedactFields() ([]string, error) {
+	patterns := make([]string, 0, len(c.RedactFields))
+	errs := []error{}
+	for _, pattern := range c.RedactFields {
+		pattern = strings.ToLower(pattern)
+		if _, err := path.Match(pattern, ""); err != nil {
+			errs = append(errs, NewErrRedactField(pattern, err))
+			continue
+		}
+		patterns = append(patterns, pattern)
+	}
+	return patterns, errors.Join(errs...)
+}
+
+// IsRedactEnabled returns whether any field values are redacted.
+func (c *Config) IsRedactEnabled() bool {
+	patterns, _ := c.ParseRedactFields()
+	return len(patterns) > 0
+}
+
+// IsRedacted returns whether the value of the field with the given name is
+// redacted. The name is matched case-insensitive.
+func (s *Setup) IsRedacted(key string) bool {
+	key = strings.ToLower(key)
+	for _, pattern := range s.RedactFields {
+		if ok, _ := path.Match(pattern, key); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// Redact returns the redacted value of the field with the given name. If
+// errors are redacted, the values of matching `key=value` pairs in error
+// messages are redacted as well.
+func (s *Setup) Redact(key string, value any) any {
+	if s.IsRedacted(key) {
+		return Redacted
+	} else if !s.RedactErrors || key != s.ErrorName {
+		return value
+	}
+
+	switch value := value.(type) {
+	case error:
+		return s.RedactError(value.Error())
+	case string:
+		return s.RedactError(value)
+	}
+	return value
+}
+
+// RedactError returns the given error message with the values of all
+// `key=value` pairs with redacted keys replaced.
+func (s *Setup) RedactError(message string) string {
+	return redactError.ReplaceAllStringFunc(message, func(pair string) string {
+		key := pair[:strings.Index(pair, "=")]
+		if s.IsRedacted(key) {
+			return key + "=" + Redacted
+		}
+		return pair
+	})
+}
+
+// RedactEvent redacts the user fields of the given zerolog event. It is used
+// as preparation of the zerolog console writers.
+func (s *Setup) RedactEvent(event map[string]any) error {
+	for key, value := range event {
+		if !isZeroField(key) {
+			event[key] = s.Redact(key, value)
+		}
+	}
+	return nil
+}
+
+// RedactJSON redacts the user fields of the given zerolog JSON event keeping
+// the order of the fields. Unchanged events are returned as is.
+func (s *Setup) RedactJSON(p []byte) ([]byte, error) {
+	return rewriteJSON(p, s.redactRaw)
+}
+
+// redactRaw returns the redacted raw JSON value of the field with the given
+// name and whether the value has changed.
+func (s *Setup) redactRaw(
+	key string, raw json.RawMessage,
+) (json.RawMessage, bool) {
+	if isZeroField(key) {
+		return nil, false
+	} else if s.IsRedacted(key) {
+		return json.RawMessage(`"` + Redacted + `"`), true
+	} else if !s.RedactErrors || key != s.ErrorName {
+		return nil, false
+	}
+
+	message := ""
+	if err := json.Unmarshal(raw, &message); err != nil {
+		return nil, false
+	} else if redacted := s.RedactError(message); redacted != message {
+		bytes, _ := json.Marshal(redacted)
+		return bytes, true
+	}
+	return nil, false
+}
+
+// isZeroField returns whether the given field name is one of the zerolog base
+// fields, i.e. the time, level, message, and caller field, that are never
+// redacted.
+func isZeroField(key string) bool {
+	switch key {
+	case zerolog.TimestampFieldName, zerolog.LevelFieldName,
+		zerolog.MessageFieldName, zerolog.CallerFieldName:
+		return true
+	}
+	return false
+}
+
+// LogRusRedactHook is a hook redacting the values of sensitive fields.
+type LogRusRedactHook struct {
+	// Setup provides the setup for redacting fields.
+	*Setup
+}
+
+// NewLogRusRedactHook creates a new redact hook for logrus using the given
+// config.
+func NewLogRusRedactHook(c *Config, writer io.Writer) *LogRusRedactHook {
+	return &LogRusRedactHook{Setup: c.Setup(writer)}
+}
+
+// Levels returns all log levels, since sensitive fields must be redacted for
+// all log levels.
+func (*LogRusRedactHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+// Fire redacts the values of sensitive fields of the given log entry.
+func (h *LogRusRedactHook) Fire(entry *logrus.Entry) error {
+	for key, value := range entry.Data {
+		entry.Data[key] = h.Redact(key, value)
+	}
+	return nil
+}
+
+// ZeroLogRedact is a zerolog writer redacting the values of sensitive fields
+// of JSON events.
+type ZeroLogRedact struct {
+	// Setup provides the setup for redacting fields.
+	*Setup
+	// writer is the writer for the redacted JSON events.
+	writer io.Writer
+}
+
+// NewZeroLogRedact creates a new redact writer for zerolog using the given
+// config.
+func NewZeroLogRedact(c *Config, writer io.Writer) *ZeroLogRedact {
+	return &ZeroLogRedact{Setup: c.Setup(writer), writer: writer}
+}
+
+// ZeroRedact wraps the given zerolog JSON writer to redact the values of
+// sensitive fields, if any redact fields are configured.
+func (c *Config) ZeroRedact(writer io.Writer) io.Writer {
+	if !c.IsRedactEnabled() {
+		return writer
+	}
+	return NewZeroLogRedact(c, writer)
+}
+
+// Write redacts the given JSON event and writes it to the underlying writer.
+func (w *ZeroLogRedact) Write(p []byte) (int, error) {
+	bytes, err := w.RedactJSON(p)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := w.writer.Write(bytes); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
@@ -0,0 +1,231 @@
+package log_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"path"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/tkrop/go-testing/test"
+
+	"github.com/tkrop/go-config/log"
+)
+
+type testRedactParam struct {
+	formatter  log.Formatter
+	fields     []string
+	errors     bool
+	expectRus  string
+	expectZero string
+}
+
+// errRedact is the nested error containing secrets used for testing.
+var errRedact = fmt.Errorf("request failed: %w",
+	errors.New("invalid token=abc123 for user=alice"))
+
+// errPlain, errRedacted, and errRedactedAll are the plain and the redacted
+// nested error messages.
+const (
+	errPlain       = "request failed: invalid token=abc123 for user=alice"
+	errRedacted    = "request failed: invalid token=*** for user=alice"
+	errRedactedAll = "request failed: invalid token=*** for user=***"
+)
+
+var testRedactParams = map[string]testRedactParam{
+	"pretty exact": {
+		formatter: log.FormatterPretty,
+		fields:    []string{"password"},
+		expectRus: "INFO info message error=\"" + errPlain + "\" " +
+			"Password=\"***\" access_token=\"abc123\" user=\"alice\"\n",
+		expectZero: "INFO info message error=\"" + errPlain + "\" " +
+			"Password=\"***\" access_token=\"abc123\" user=\"alice\"\n",
+	},
+	"pretty glob": {
+		formatter: log.FormatterPretty,
+		fields:    []string{"*token*", "pass*"},
+		expectRus: "INFO info message error=\"" + errPlain + "\" " +
+			"Password=\"***\" access_token=\"***\" user=\"alice\"\n",
+		expectZero: "INFO info message error=\"" + errPlain + "\" " +
+			"Password=\"***\" access_token=\"***\" user=\"alice\"\n",
+	},
+	"pretty case-insensitive": {
+		formatter: log.FormatterPretty,
+		fields:    []string{"PassWord", "ACCESS_*"},
+		expectRus: "INFO info message error=\"" + errPlain + "\" " +
+			"Password=\"***\" access_token=\"***\" user=\"alice\"\n",
+		expectZero: "INFO info message error=\"" + errPlain + "\" " +
+			"Password=\"***\" access_token=\"***\" user=\"alice\"\n",
+	},
+	"pretty nested error": {
+		formatter: log.FormatterPretty,
+		fields:    []string{"*token*"},
+		errors:    true,
+		expectRus: "INFO info message error=\"" + errRedacted + "\" " +
+			"Password=\"secret\" access_token=\"***\" user=\"alice\"\n",
+		expectZero: "INFO info message error=\"" + errRedacted + "\" " +
+			"Password=\"secret\" access_token=\"***\" user=\"alice\"\n",
+	},
+	"json exact": {
+		formatter: log.FormatterJSON,
+		fields:    []string{"password"},
+		expectRus: `{"Password":"***","access_token":"abc123","error":"` + errPlain +
+			`","level":"info","msg":"info message","user":"alice"}` + "\n",
+		expectZero: `{"level":"info","error":"` + errPlain + `","user":"alice",` +
+			`"Password":"***","access_token":"abc123","message":"info message"}` + "\n",
+	},
+	"json glob": {
+		formatter: log.FormatterJSON,
+		fields:    []string{"*token*", "pass*"},
+		expectRus: `{"Password":"***","access_token":"***","error":"` + errPlain +
+			`","level":"info","msg":"info message","user":"alice"}` + "\n",
+		expectZero: `{"level":"info","error":"` + errPlain + `","user":"alice",` +
+			`"Password":"***","access_token":"***","message":"info message"}` + "\n",
+	},
+	"json case-insensitive": {
+		formatter: log.FormatterJSON,
+		fields:    []string{"PassWord", "ACCESS_*"},
+		expectRus: `{"Password":"***","access_token":"***","error":"` + errPlain +
+			`","level":"info","msg":"info message","user":"alice"}` + "\n",
+		expectZero: `{"level":"info","error":"` + errPlain + `","user":"alice",` +
+			`"Password":"***","access_token":"***","message":"info message"}` + "\n",
+	},
+	"json nested error": {
+		formatter: log.FormatterJSON,
+		fields:    []string{"*token*", "user"},
+		errors:    true,
+		expectRus: `{"Password":"secret","access_token":"***","error":"` + errRedactedAll +
+			`","level":"info","msg":"info message","user":"***"}` + "\n",
+		expectZero: `{"level":"info","error":"` + errRedactedAll + `","user":"***",` +
+			`"Password":"secret","access_token":"***","message":"info message"}` + "\n",
+	},
+	"json disabled": {
+		formatter: log.FormatterJSON,
+		expectRus: `{"Password":"secret","access_token":"abc123","error":"` + errPlain +
+			`","level":"info","msg":"info message","user":"alice"}` + "\n",
+		expectZero: `{"level":"info","error":"` + errPlain + `","user":"alice",` +
+			`"Password":"secret","access_token":"abc123","message":"info message"}` + "\n",
+	},
+	"text glob": {
+		formatter: log.FormatterText,
+		fields:    []string{"*token*", "pass*"},
+		errors:    true,
+		expectRus: "level=info msg=\"info message\" Password=\"***\" " +
+			"access_token=\"***\" error=\"" + errRedacted + "\" user=alice\n",
+		expectZero: "INF info message error=\"" + errRedacted + "\" " +
+			"Password=*** access_token=*** user=alice\n",
+	},
+	"ecs glob": {
+		formatter: log.FormatterECS,
+		fields:    []string{"*token*", "pass*"},
+		expectRus: `{"Password":"***","access_token":"***",` +
+			`"ecs":{"version":"8.11.0"},"error":{"message":"` + errPlain +
+			`"},"log":{"level":"info"},"message":"info message",` +
+			`"user":"alice"}` + "\n",
+		expectZero: `{"Password":"***","access_token":"***",` +
+			`"ecs":{"version":"8.11.0"},"error":{"message":"` + errPlain +
+			`"},"log":{"level":"info"},"message":"info message",` +
+			`"user":"alice"}` + "\n",
+	},
+}
+
+func TestRedact(t *testing.T) {
+	test.Map(t, testRedactParams).
+		Run(func(t test.Test, param testRedactParam) {
+			// Given
+			config := &log.Config{
+				Level:        log.LevelInfo,
+				Formatter:    param.formatter,
+				TimeFormat:   log.TimeFormatNone,
+				ColorMode:    log.ColorModeOff,
+				OrderMode:    log.OrderModeOn,
+				RedactFields: param.fields,
+				RedactErrors: param.errors,
+			}
+			rus, zero := &bytes.Buffer{}, &bytes.Buffer{}
+			rlogger := config.SetupRus(rus, logrus.New())
+			zlogger := config.SetupZero(zero).ZeroLogger()
+
+			// When
+			rlogger.WithError(errRedact).WithFields(logrus.Fields{
+				"user": "alice", "Password": "secret",
+				"access_token": "abc123",
+			}).Info("info message")
+			zlogger.Info().Err(errRedact).Str("user", "alice").
+				Str("Password", "secret").Str("access_token", "abc123").
+				Msg("info message")
+
+			// Then
+			assert.Equal(t, param.expectRus, rus.String())
+			assert.Equal(t, param.expectZero, zero.String())
+		})
+}
+
+func TestRedactSplit(t *testing.T) {
+	// Given
+	config := &log.Config{
+		Level:        log.LevelInfo,
+		Formatter:    log.FormatterJSON,
+		TimeFormat:   log.TimeFormatNone,
+		ColorMode:    log.ColorModeOff,
+		SplitLevel:   log.LevelWarn,
+		RedactFields: []string{"password"},
+	}
+	rlow, rhigh := &bytes.Buffer{}, &bytes.Buffer{}
+	zlow, zhigh := &bytes.Buffer{}, &bytes.Buffer{}
+	rlogger := config.SetupRus(log.NewSplitWriter(rlow, rhigh), logrus.New())
+	zlogger := config.SetupZero(log.NewSplitWriter(zlow, zhigh)).ZeroLogger()
+
+	// When
+	rlogger.WithField("password", "s3cret").Info("info message")
+	rlogger.WithField("password", "s3cret").Warn("warn message")
+	zlogger.Info().Str("password", "s3cret").Msg("info message")
+	zlogger.Warn().Str("password", "s3cret").Msg("warn message")
+
+	// Then
+	for _, output := range []string{
+		rlow.String(), rhigh.String(), zlow.String(), zhigh.String(),
+	} {
+		assert.Contains(t, output, `"password":"***"`)
+		assert.NotContains(t, output, "s3cret")
+	}
+}
+
+type testParseRedactFieldsParam struct {
+	fields      []string
+	expect      []string
+	expectError error
+}
+
+var testParseRedactFieldsParams = map[string]testParseRedactFieldsParam{
+	"redact fields empty": {
+		expect: []string{},
+	},
+	"redact fields valid": {
+		fields: []string{"Password", "*TOKEN*"},
+		expect: []string{"password", "*token*"},
+	},
+	"redact fields invalid": {
+		fields: []string{"secret[", "key"},
+		expect: []string{"key"},
+		expectError: errors.Join(
+			log.NewErrRedactField("secret[", path.ErrBadPattern)),
+	},
+}
+
+func TestParseRedactFields(t *testing.T) {
+	test.Map(t, testParseRedactFieldsParams).
+		Run(func(t test.Test, param testParseRedactFieldsParam) {
+			// Given
+			config := &log.Config{RedactFields: param.fields}
+
+			// When
+			fields, err := config.ParseRedactFields()
+
+			// Then
+			assert.Equal(t, param.expectError, err)
+			assert.Equal(t, param.expect, fields)
+		})
+}
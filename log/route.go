@@ -0,0 +1,217 @@
+package log
+
+import (
+	"errors"
+	"io"
+	"maps"
+	"slices"
+	"sync"
+
+	"github.com/rs/zerolog"
+	"github.com/sirupsen/logrus"
+)
+
+// LevelRouter is routing log entries by level to additional log files, e.g.
+// fatal and panic entries to a dedicated audit file. The file writers are
+// shared between levels routed to the same path and closed once.
+type LevelRouter struct {
+	// files are the file writers of the log levels.
+	files [FieldLevel][]*FileWriter
+	// writers are the distinct file writers in order of their paths.
+	writers []*FileWriter
+	// once ensures that the file writers are closed once.
+	once sync.Once
+}
+
+// NewLevelRouter creates a new level router opening the log files of the
+// given level to file path mapping. Unknown level names are ignored. If a log
+// file cannot be opened, all log files opened before are closed again.
+func NewLevelRouter(levels map[string]string) (*LevelRouter, error) {
+	router := &LevelRouter{}
+	paths := map[string]*FileWriter{}
+	for _, name := range slices.Sorted(maps.Keys(levels)) {
+		level, err := ParseLevelStrict(name)
+		if err != nil {
+			continue
+		}
+
+		path := levels[name]
+		writer, ok := paths[path]
+		if !ok {
+			if writer, err = NewFileWriter(path); err != nil {
+				return nil, errors.Join(err, router.Close())
+			}
+			paths[path] = writer
+			router.writers = append(router.writers, writer)
+		}
+		if !slices.Contains(router.files[level], writer) {
+			router.files[level] = append(router.files[level], writer)
+		}
+	}
+	return router, nil
+}
+
+// Writers returns the file writers the given log level is routed to.
+func (r *LevelRouter) Writers(level Level) []*FileWriter {
+	if level < 0 || int(level) >= len(r.files) {
+		return nil
+	}
+	return r.files[level]
+}
+
+// Levels returns the log levels routed to at least one log file.
+func (r *LevelRouter) Levels() []Level {
+	levels := []Level{}
+	for level, files := range r.files {
+		if len(files) > 0 {
+			levels = append(levels, Level(level))
+		}
+	}
+	return levels
+}
+
+// Close closes all file writers of the level router once.
+func (r *LevelRouter) Close() error {
+	var err error
+	r.once.Do(func() {
+		errs := []error{}
+		for _, writer := range r.writers {
+			errs = append(errs, writer.Close())
+		}
+		err = errors.Join(errs...)
+	})
+	return err
+}
+
+// setupRouter returns the level router of the configured level writers
+// creating it on first use. If no level writers are configured, `nil` is
+// returned. The loggers must be locked by the caller.
+func (l *loggers) setupRouter(c *Config) (*LevelRouter, error) {
+	if l.router != nil || len(c.LevelWriters) == 0 {
+		return l.router, nil
+	}
+
+	router, err := NewLevelRouter(c.LevelWriters)
+	if err != nil {
+		return nil, err
+	}
+	l.router = router
+	return router, nil
+}
+
+// CloseLevelWriters closes the log files of the level writers set up by the
+// config. The log files are closed once, even if shared by multiple levels
+// or loggers.
+func (c *Config) CloseLevelWriters() error {
+	loggers := c.lookupLoggers()
+	if loggers == nil {
+		return nil
+	}
+
+	loggers.mutex.RLock()
+	defer loggers.mutex.RUnlock()
+	if loggers.router == nil {
+		return nil
+	}
+	return loggers.router.Close()
+}
+
+// LogRusLevelHook is a hook writing log entries additionally to the log files
+// of the level router the level of the entry is routed to. Each log file is
+// using its own formatter.
+type LogRusLevelHook struct {
+	// router is the level router.
+	router *LevelRouter
+	// formatters are the formatters of the file writers.
+	formatters map[*FileWriter]logrus.Formatter
+}
+
+// NewLogRusLevelHook creates a new level hook for logrus using the given
+// config and level router.
+func NewLogRusLevelHook(c *Config, router *LevelRouter) *LogRusLevelHook {
+	formatters := make(map[*FileWriter]logrus.Formatter, len(router.writers))
+	for _, writer := range router.writers {
+		formatters[writer] = c.RusFormatter(writer)
+	}
+	return &LogRusLevelHook{router: router, formatters: formatters}
+}
+
+// Levels returns the log levels routed to at least one log file.
+func (h *LogRusLevelHook) Levels() []logrus.Level {
+	levels := []logrus.Level{}
+	for _, level := range h.router.Levels() {
+		// #nosec G115 // cannot happen.
+		levels = append(levels, logrus.Level(level))
+	}
+	return levels
+}
+
+// Fire formats the given log entry and writes it to the log files the level
+// of the entry is routed to.
+func (h *LogRusLevelHook) Fire(entry *logrus.Entry) error {
+	errs := []error{}
+	for _, writer := range h.router.Writers(Level(entry.Level)) {
+		bytes, err := h.formatters[writer].Format(entry)
+		if err != nil || len(bytes) == 0 {
+			errs = append(errs, err)
+			continue
+		}
+		_, err = writer.Write(bytes)
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
+// ZeroLogLevels is a level writer writing log events to the underlying writer
+// and additionally to the log files of the level router the level of the
+// event is routed to. Each log file is using its own formatting writer.
+type ZeroLogLevels struct {
+	// writer is the underlying writer.
+	writer io.Writer
+	// router is the level router.
+	router *LevelRouter
+	// outputs are the formatting writers of the file writers.
+	outputs map[*FileWriter]io.Writer
+}
+
+// NewZeroLogLevels creates a new level writer for zerolog using the given
+// config, underlying writer, and level router.
+func NewZeroLogLevels(
+	c *Config, writer io.Writer, router *LevelRouter,
+) *ZeroLogLevels {
+	outputs := make(map[*FileWriter]io.Writer, len(router.writers))
+	for _, file := range router.writers {
+		outputs[file] = c.ZeroWriter(file)
+	}
+	return &ZeroLogLevels{writer: writer, router: router, outputs: outputs}
+}
+
+// Write writes the given event to the underlying writer.
+func (w *ZeroLogLevels) Write(p []byte) (int, error) {
+	return w.writer.Write(p)
+}
+
+// WriteLevel writes the given event with the given level to the underlying
+// writer and the log files the level is routed to. Events without level are
+// not routed.
+func (w *ZeroLogLevels) WriteLevel(
+	level zerolog.Level, p []byte,
+) (int, error) {
+	var n int
+	var err error
+	if writer, ok := w.writer.(zerolog.LevelWriter); ok {
+		n, err = writer.WriteLevel(level, p)
+	} else {
+		n, err = w.writer.Write(p)
+	}
+	if level == zerolog.NoLevel || level == zerolog.Disabled {
+		return n, err
+	}
+
+	errs := []error{err}
+	for _, file := range w.router.Writers(ZeroLevel(level)) {
+		_, err := w.outputs[file].Write(p)
+		errs = append(errs, err)
+	}
+	return n, errors.Join(errs...)
+}
@@ -0,0 +1,137 @@
+package log_test
+
+import (
+	"bytes"
+	"io"
+	"path/filepath"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tkrop/go-testing/test"
+
+	"github.com/tkrop/go-config/log"
+)
+
+// auditWriters returns the level writers routing error and higher levels to
+// the audit file with the given path.
+func auditWriters(path string) map[string]string {
+	return map[string]string{
+		log.LevelError: path, log.LevelFatal: path, log.LevelPanic: path,
+	}
+}
+
+func TestLevelRouter(t *testing.T) {
+	// Given
+	dir := t.TempDir()
+	audit, debug := filepath.Join(dir, "audit.log"), filepath.Join(dir, "debug.log")
+	levels := auditWriters(audit)
+	levels[log.LevelDebug] = debug
+	levels["unknown"] = debug
+
+	// When
+	router, err := log.NewLevelRouter(levels)
+
+	// Then
+	require.NoError(t, err)
+	assert.Equal(t, []log.Level{
+		log.PanicLevel, log.FatalLevel, log.ErrorLevel, log.DebugLevel,
+	}, router.Levels())
+	assert.Len(t, router.Writers(log.ErrorLevel), 1)
+	assert.Same(t, router.Writers(log.ErrorLevel)[0],
+		router.Writers(log.PanicLevel)[0])
+	assert.NotSame(t, router.Writers(log.ErrorLevel)[0],
+		router.Writers(log.DebugLevel)[0])
+	assert.Empty(t, router.Writers(log.InfoLevel))
+	assert.Empty(t, router.Writers(log.FieldLevel))
+	assert.NoError(t, router.Close())
+	assert.NoError(t, router.Close())
+}
+
+func TestLevelRouterError(t *testing.T) {
+	// Given
+	levels := map[string]string{
+		log.LevelError: filepath.Join(t.TempDir(), "audit.log"),
+		log.LevelWarn:  filepath.Join(t.TempDir(), "missing", "warn.log"),
+	}
+
+	// When
+	router, err := log.NewLevelRouter(levels)
+
+	// Then
+	assert.ErrorIs(t, err, log.ErrFileOpen)
+	assert.Nil(t, router)
+}
+
+type testLevelWritersParam struct {
+	setup func(config *log.Config, output io.Writer)
+}
+
+var testLevelWritersParams = map[string]testLevelWritersParam{
+	"logrus": {
+		setup: func(config *log.Config, output io.Writer) {
+			logger := config.SetupRus(output, logrus.New())
+			logger.Info("info message")
+			logger.Error("error message")
+			logger.Warn("warn message")
+		},
+	},
+	"zerolog": {
+		setup: func(config *log.Config, output io.Writer) {
+			logger := config.SetupZeroLogger(zerolog.New(io.Discard), output)
+			logger.Info().Msg("info message")
+			logger.Error().Msg("error message")
+			logger.Warn().Msg("warn message")
+		},
+	},
+}
+
+func TestLevelWriters(t *testing.T) {
+	test.Map(t, testLevelWritersParams).
+		RunSeq(func(t test.Test, param testLevelWritersParam) {
+			// Given
+			path := filepath.Join(t.TempDir(), "audit.log")
+			output := &bytes.Buffer{}
+			config := &log.Config{
+				Level:        log.LevelInfo,
+				TimeFormat:   log.TimeFormatNone,
+				ColorMode:    log.ColorModeOff,
+				LevelWriters: auditWriters(path),
+			}
+
+			// When
+			param.setup(config, output)
+
+			// Then
+			assert.Equal(t, "INFO info message\n"+
+				"ERROR error message\n"+
+				"WARN warn message\n", output.String())
+			assert.Equal(t, "ERROR error message\n", readFile(t, path))
+			assert.NoError(t, config.CloseLevelWriters())
+			assert.NoError(t, config.CloseLevelWriters())
+		})
+}
+
+func TestLevelWritersShared(t *testing.T) {
+	// Given
+	path := filepath.Join(t.TempDir(), "audit.log")
+	config := &log.Config{
+		Level:        log.LevelInfo,
+		TimeFormat:   log.TimeFormatNone,
+		ColorMode:    log.ColorModeOff,
+		LevelWriters: auditWriters(path),
+	}
+	rus := config.SetupRus(io.Discard, logrus.New())
+	zero := config.SetupZeroLogger(zerolog.New(io.Discard), io.Discard)
+
+	// When
+	rus.Error("rus message")
+	zero.Error().Msg("zero message")
+
+	// Then
+	assert.Equal(t, "ERROR rus message\nERROR zero message\n",
+		readFile(t, path))
+	assert.NoError(t, config.CloseLevelWriters())
+}
@@ -0,0 +1,382 @@
+package log
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/sirupsen/logrus"
+)
+
+// FieldSuppressed is the field name of the number of identical log entries
+// suppressed by sampling since the last logged entry.
+const FieldSuppressed = "suppressed"
+
+// maxSamplerKeys is the maximum number of distinct entries tracked by the
+// sampler before expired counters are pruned.
+const maxSamplerKeys = 4096
+
+// Sampling is the configuration of the sampling of identical log entries, i.e.
+// entries with the same level and message. Per period the first `Initial`
+// entries are logged and thereafter only every `Thereafter` entry.
+type Sampling struct {
+	// Initial is defining the number of identical entries logged per period
+	// before sampling starts (default `0`, i.e. sampling disabled).
+	Initial int `default:"0"`
+	// Thereafter is defining that only every n-th identical entry is logged
+	// after the initial entries (default `0`, i.e. none).
+	Thereafter int `default:"0"`
+	// Period is defining the period after which the counting of identical
+	// entries is restarted (default `1s`).
+	Period time.Duration `default:"1s"`
+	// Level is defining the level at or below which entries are sampled, i.e.
+	// warnings and errors are never sampled by default (default `info`).
+	Level string `default:"info"`
+}
+
+// IsSamplingEnabled returns whether sampling of log entries is enabled.
+func (c *Config) IsSamplingEnabled() bool {
+	return c.Sampling.Initial > 0 || c.Sampling.Thereafter > 0
+}
+
+// samplerKey is the key of identical log entries.
+type samplerKey struct {
+	// level is the level of the entries.
+	level Level
+	// message is the message of the entries.
+	message string
+}
+
+// samplerCounter is counting identical log entries per period.
+type samplerCounter struct {
+	// start is the start of the current period.
+	start time.Time
+	// count is the number of entries in the current period.
+	count int
+	// suppressed is the number of entries suppressed since the last logged
+	// entry.
+	suppressed int
+}
+
+// Sampler is sampling identical log entries by level and message.
+type Sampler struct {
+	// initial is the number of entries logged per period before sampling.
+	initial int
+	// thereafter is the sampling rate after the initial entries.
+	thereafter int
+	// period is the period after which counting is restarted.
+	period time.Duration
+	// level is the level at or below which entries are sampled.
+	level Level
+
+	// mutex synchronizes the access to the counters.
+	mutex sync.Mutex
+	// counters are the counters of identical entries.
+	counters map[samplerKey]*samplerCounter
+	// now is the function returning the current time.
+	now func() time.Time
+}
+
+// NewSampler creates a new sampler using the given sampling config.
+func NewSampler(sampling *Sampling) *Sampler {
+	return &Sampler{
+		initial:    sampling.Initial,
+		thereafter: sampling.Thereafter,
+		period:     sampling.Period,
+		level:      ParseLevel(sampling.Level),
+		counters:   map[samplerKey]*samplerCounter{},
+		now:        time.Now,
+	}
+}
+
+// Sample returns whether the log entry with the given level and message is
+// logged and the number of identical entries suppressed since the last logged
+// entry. Entries above the sampling level are always logged.
+func (s *Sampler) Sample(level Level, message string) (bool, int) {
+	if level < s.level {
+		return true, 0
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	now := s.now()
+	key := samplerKey{level: level, message: message}
+	counter, ok := s.counters[key]
+	if !ok {
+		s.prune(now)
+		counter = &samplerCounter{start: now}
+		s.counters[key] = counter
+	} else if s.period > 0 && now.Sub(counter.start) >= s.period {
+		counter.start, counter.count = now, 0
+	}
+
+	counter.count++
+	if counter.count <= s.initial || (s.thereafter > 0 &&
+		(counter.count-s.initial)%s.thereafter == 0) {
+		suppressed := counter.suppressed
+		counter.suppressed = 0
+		return true, suppressed
+	}
+	counter.suppressed++
+	return false, 0
+}
+
+// prune removes the counters of expired periods without suppressed entries,
+// if the maximum number of tracked entries is reached.
+func (s *Sampler) prune(now time.Time) {
+	if len(s.counters) < maxSamplerKeys {
+		return
+	}
+	for key, counter := range s.counters {
+		if counter.suppressed == 0 && now.Sub(counter.start) >= s.period {
+			delete(s.counters, key)
+		}
+	}
+}
+
+// suppressedKey is the context key marking log entries suppressed by
+// sampling or deduplication.
+type suppressedKey struct{}
+
+// suppress marks the given log entry as suppressed in its context.
+func suppress(entry *logrus.Entry) {
+	ctx := entry.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	entry.Context = context.WithValue(ctx, suppressedKey{}, true)
+}
+
+// isSuppressed returns whether the given log entry is marked as suppressed.
+func isSuppressed(entry *logrus.Entry) bool {
+	return entry.Context != nil && entry.Context.Value(suppressedKey{}) != nil
+}
+
+// LogRusSampleHook is a hook sampling identical log entries. Since logrus
+// hooks cannot drop entries, suppressed entries are marked in the context of
+// the entry and dropped by the `LogRusSuppressed` formatter.
+type LogRusSampleHook struct {
+	// sampler is the sampler of the log entries.
+	sampler *Sampler
+}
+
+// NewLogRusSampleHook creates a new sampling hook for logrus using the given
+// config.
+func NewLogRusSampleHook(c *Config) *LogRusSampleHook {
+	return &LogRusSampleHook{sampler: NewSampler(&c.Sampling)}
+}
+
+// Levels returns the log levels at or below the sampling level.
+func (h *LogRusSampleHook) Levels() []logrus.Level {
+	return logrus.AllLevels[h.sampler.level:]
+}
+
+// Fire marks the given log entry as suppressed, if it is not sampled, and
+// else attaches the number of suppressed entries, if any.
+func (h *LogRusSampleHook) Fire(entry *logrus.Entry) error {
+	if ok, suppressed := h.sampler.Sample(
+		Level(entry.Level), entry.Message); !ok {
+		suppress(entry)
+	} else if suppressed > 0 {
+		entry.Data[FieldSuppressed] = suppressed
+	}
+	return nil
+}
+
+// LogRusSuppressed is a formatter dropping the log entries suppressed by the
+// sampling or the dedup hook.
+type LogRusSuppressed struct {
+	logrus.Formatter
+}
+
+// Format formats the log entry using the wrapped formatter, if the entry is
+// not suppressed.
+func (f *LogRusSuppressed) Format(entry *logrus.Entry) ([]byte, error) {
+	if isSuppressed(entry) {
+		return nil, nil
+	}
+	return f.Formatter.Format(entry)
+}
+
+// ZeroLogSampleHook is a hook sampling identical log events. A hook is used
+// instead of a zerolog sampler, since samplers cannot access the message.
+type ZeroLogSampleHook struct {
+	// sampler is the sampler of the log events.
+	sampler *Sampler
+}
+
+// NewZeroLogSampleHook creates a new sampling hook for zerolog using the
+// given config.
+func NewZeroLogSampleHook(c *Config) *ZeroLogSampleHook {
+	return &ZeroLogSampleHook{sampler: NewSampler(&c.Sampling)}
+}
+
+// Run discards the given log event, if it is not sampled, and else attaches
+// the number of suppressed events, if any. Events already discarded, e.g. by
+// module specific log levels, are not counted.
+func (h *ZeroLogSampleHook) Run(
+	event *zerolog.Event, level zerolog.Level, message string,
+) {
+	if level == zerolog.NoLevel || !event.Enabled() {
+		return
+	} else if ok, suppressed := h.sampler.Sample(
+		ZeroLevel(level), message); !ok {
+		event.Discard()
+	} else if suppressed > 0 {
+		event.Int(FieldSuppressed, suppressed)
+	}
+}
+
+// DefaultSamplingPeriod is the default period of level sampling, if no
+// period is configured.
+const DefaultSamplingPeriod = time.Second
+
+// IsLevelSamplingEnabled returns whether sampling of log entries by level is
+// enabled.
+func (c *Config) IsLevelSamplingEnabled() bool {
+	return len(c.LevelSampling) > 0
+}
+
+// ZeroSampler compiles the sampling config into a zerolog sampler logging
+// the first `Initial` entries per period and thereafter every n-th entry
+// independent of their message. If sampling is disabled, `nil` is returned.
+func (s *Sampling) ZeroSampler() zerolog.Sampler {
+	var next zerolog.Sampler
+	if s.Thereafter > 0 {
+		// #nosec G115 // cannot happen.
+		next = &zerolog.BasicSampler{N: uint32(s.Thereafter)}
+	}
+	if s.Initial <= 0 {
+		return next
+	}
+
+	period := s.Period
+	if period <= 0 {
+		period = DefaultSamplingPeriod
+	}
+	return &zerolog.BurstSampler{
+		// #nosec G115 // cannot happen.
+		Burst: uint32(s.Initial), Period: period, NextSampler: next,
+	}
+}
+
+// LevelSamplers is sampling log entries by level using the zerolog level
+// sampler compiled from the level sampling config. Levels without sampling
+// config are not sampled. The level sampler can be swapped atomically while
+// logging.
+type LevelSamplers struct {
+	// sampler is the current zerolog level sampler.
+	sampler atomic.Pointer[zerolog.LevelSampler]
+}
+
+// NewLevelSamplers creates new level samplers using the given level to
+// sampling config mapping.
+func NewLevelSamplers(levels map[string]Sampling) *LevelSamplers {
+	samplers := &LevelSamplers{}
+	samplers.Set(levels)
+	return samplers
+}
+
+// Set compiles the given level to sampling config mapping into a new zerolog
+// level sampler and swaps it atomically with the current one. Unknown level
+// names are ignored, while fatal and panic entries are never sampled.
+func (s *LevelSamplers) Set(levels map[string]Sampling) {
+	sampler := &zerolog.LevelSampler{}
+	for name, sampling := range levels {
+		level, err := ParseLevelStrict(name)
+		if err != nil {
+			continue
+		}
+
+		switch level {
+		case TraceLevel:
+			sampler.TraceSampler = sampling.ZeroSampler()
+		case DebugLevel:
+			sampler.DebugSampler = sampling.ZeroSampler()
+		case InfoLevel:
+			sampler.InfoSampler = sampling.ZeroSampler()
+		case WarnLevel:
+			sampler.WarnSampler = sampling.ZeroSampler()
+		case ErrorLevel:
+			sampler.ErrorSampler = sampling.ZeroSampler()
+		case PanicLevel, FatalLevel, FieldLevel:
+		}
+	}
+	s.sampler.Store(sampler)
+}
+
+// Sample returns whether the log entry with the given zerolog level is
+// logged using the current level sampler.
+func (s *LevelSamplers) Sample(level zerolog.Level) bool {
+	return s.sampler.Load().Sample(level)
+}
+
+// setupSamplers returns the level samplers of the configured level sampling
+// creating them on first use. If no level sampling is configured, `nil` is
+// returned. The loggers must be locked by the caller.
+func (l *loggers) setupSamplers(c *Config) *LevelSamplers {
+	if l.samplers == nil && c.IsLevelSamplingEnabled() {
+		l.samplers = NewLevelSamplers(c.LevelSampling)
+	}
+	return l.samplers
+}
+
+// SetLevelSampling sets the level sampling of the config and swaps the level
+// samplers of the loggers already set up by the config atomically. Loggers
+// set up without level sampling are not changed, but loggers set up
+// afterwards are using the new level sampling. The method is safe for
+// concurrent use with active logging.
+func (c *Config) SetLevelSampling(levels map[string]Sampling) error {
+	errs := []error{}
+	for _, level := range slices.Sorted(maps.Keys(levels)) {
+		if _, err := ParseLevelStrict(level); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		return err
+	}
+
+	loggers := c.setupLoggers()
+	loggers.mutex.Lock()
+	defer loggers.mutex.Unlock()
+
+	c.LevelSampling = levels
+	if loggers.samplers != nil {
+		loggers.samplers.Set(levels)
+	}
+	return nil
+}
+
+// LogRusLevelSampleHook is a hook sampling log entries by level. Since logrus
+// hooks cannot drop entries, suppressed entries are marked in the context of
+// the entry and dropped by the `LogRusSuppressed` formatter.
+type LogRusLevelSampleHook struct {
+	// samplers are the level samplers of the log entries.
+	samplers *LevelSamplers
+}
+
+// NewLogRusLevelSampleHook creates a new level sampling hook for logrus using
+// the given level samplers.
+func NewLogRusLevelSampleHook(samplers *LevelSamplers) *LogRusLevelSampleHook {
+	return &LogRusLevelSampleHook{samplers: samplers}
+}
+
+// Levels returns all log levels, since the level samplers can be swapped.
+func (*LogRusLevelSampleHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+// Fire marks the given log entry as suppressed, if it is not sampled.
+func (h *LogRusLevelSampleHook) Fire(entry *logrus.Entry) error {
+	if !h.samplers.Sample(ToZeroLevel(Level(entry.Level))) {
+		suppress(entry)
+	}
+	return nil
+}
@@ -0,0 +1,265 @@
+package log_test
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/tkrop/go-testing/test"
+
+	"github.com/tkrop/go-config/log"
+)
+
+// newSampleConfig creates a new pretty config with the given sampling.
+func newSampleConfig(sampling log.Sampling) *log.Config {
+	return &log.Config{
+		Level:      log.LevelDebug,
+		Formatter:  log.FormatterPretty,
+		TimeFormat: log.TimeFormatNone,
+		ColorMode:  log.ColorModeOff,
+		Sampling:   sampling,
+	}
+}
+
+// sampleLoggers returns the logging functions of both logging backends for
+// the given config writing to the given buffer.
+func sampleLoggers(
+	config *log.Config, buffer *bytes.Buffer,
+) map[string]func(level log.Level, msg string) {
+	rus := config.SetupRus(buffer, logrus.New())
+	zero := config.SetupZero(buffer).ZeroLogger()
+	return map[string]func(level log.Level, msg string){
+		"logrus": func(level log.Level, msg string) {
+			// #nosec G115 // cannot happen.
+			rus.Log(logrus.Level(level), msg)
+		},
+		"zerolog": func(level log.Level, msg string) {
+			zero.WithLevel(log.ToZeroLevel(level)).Msg(msg)
+		},
+	}
+}
+
+type testSampleParam struct {
+	backend  string
+	sampling log.Sampling
+	level    log.Level
+	count    int
+	expect   []string
+}
+
+var testSampleParams = map[string]testSampleParam{
+	"logrus debug sampled": {
+		backend: "logrus",
+		sampling: log.Sampling{
+			Initial: 10, Thereafter: 100, Period: time.Hour, Level: "debug",
+		},
+		level: log.DebugLevel,
+		count: 1000,
+		expect: append(repeat("DEBUG a", 10),
+			repeat("DEBUG a suppressed=99", 9)...),
+	},
+	"zerolog debug sampled": {
+		backend: "zerolog",
+		sampling: log.Sampling{
+			Initial: 10, Thereafter: 100, Period: time.Hour, Level: "debug",
+		},
+		level: log.DebugLevel,
+		count: 1000,
+		expect: append(repeat("DEBUG a", 10),
+			repeat("DEBUG a suppressed=99", 9)...),
+	},
+	"logrus warn not sampled": {
+		backend: "logrus",
+		sampling: log.Sampling{
+			Initial: 1, Thereafter: 0, Period: time.Hour, Level: "info",
+		},
+		level:  log.WarnLevel,
+		count:  3,
+		expect: repeat("WARN a", 3),
+	},
+	"zerolog warn not sampled": {
+		backend: "zerolog",
+		sampling: log.Sampling{
+			Initial: 1, Thereafter: 0, Period: time.Hour, Level: "info",
+		},
+		level:  log.WarnLevel,
+		count:  3,
+		expect: repeat("WARN a", 3),
+	},
+	"logrus info sampled": {
+		backend: "logrus",
+		sampling: log.Sampling{
+			Initial: 1, Thereafter: 0, Period: time.Hour, Level: "info",
+		},
+		level:  log.InfoLevel,
+		count:  3,
+		expect: repeat("INFO a", 1),
+	},
+	"zerolog info sampled": {
+		backend: "zerolog",
+		sampling: log.Sampling{
+			Initial: 1, Thereafter: 0, Period: time.Hour, Level: "info",
+		},
+		level:  log.InfoLevel,
+		count:  3,
+		expect: repeat("INFO a", 1),
+	},
+}
+
+func TestSample(t *testing.T) {
+	test.Map(t, testSampleParams).
+		RunSeq(func(t test.Test, param testSampleParam) {
+			// Given
+			buffer := &bytes.Buffer{}
+			config := newSampleConfig(param.sampling)
+			logf := sampleLoggers(config, buffer)[param.backend]
+
+			// When
+			for range param.count {
+				logf(param.level, "a")
+			}
+
+			// Then
+			assert.Equal(t, param.expect, lines(buffer))
+		})
+}
+
+func TestSamplePeriod(t *testing.T) {
+	for _, backend := range []string{"logrus", "zerolog"} {
+		t.Run(backend, func(t *testing.T) {
+			// Given
+			buffer := &bytes.Buffer{}
+			config := newSampleConfig(log.Sampling{
+				Initial: 1, Period: 10 * time.Millisecond, Level: "debug",
+			})
+			logf := sampleLoggers(config, buffer)[backend]
+			logf(log.DebugLevel, "a")
+			logf(log.DebugLevel, "a")
+			logf(log.DebugLevel, "b")
+
+			// When
+			time.Sleep(20 * time.Millisecond)
+			logf(log.DebugLevel, "a")
+
+			// Then
+			assert.Equal(t, []string{
+				"DEBUG a", "DEBUG b", "DEBUG a suppressed=1",
+			}, lines(buffer))
+		})
+	}
+}
+
+// repeat returns a list containing the given line count times.
+func repeat(line string, count int) []string {
+	lines := make([]string, 0, count)
+	for range count {
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// lines returns the trimmed lines written to the given buffer.
+func lines(buffer *bytes.Buffer) []string {
+	return strings.Split(strings.TrimSpace(buffer.String()), "\n")
+}
+
+// levelSampling is the level sampling config sampling debug entries only.
+var levelSampling = map[string]log.Sampling{
+	log.LevelDebug: {Initial: 2, Thereafter: 4, Period: time.Hour},
+}
+
+// countLines returns the number of lines written to the given buffer with
+// the given prefix.
+func countLines(buffer *bytes.Buffer, prefix string) int {
+	count := 0
+	for _, line := range lines(buffer) {
+		if strings.HasPrefix(line, prefix) {
+			count++
+		}
+	}
+	return count
+}
+
+type testLevelSamplingParam struct {
+	backend string
+	swap    map[string]log.Sampling
+	expect  int
+}
+
+var testLevelSamplingParams = map[string]testLevelSamplingParam{
+	"logrus": {
+		backend: "logrus",
+		expect:  4,
+	},
+	"zerolog": {
+		backend: "zerolog",
+		expect:  4,
+	},
+	"logrus swapped": {
+		backend: "logrus",
+		swap:    map[string]log.Sampling{},
+		expect:  10,
+	},
+	"zerolog swapped": {
+		backend: "zerolog",
+		swap:    map[string]log.Sampling{},
+		expect:  10,
+	},
+	"logrus swapped error": {
+		backend: "logrus",
+		swap: map[string]log.Sampling{
+			log.LevelDebug: {Thereafter: 5},
+		},
+		expect: 2,
+	},
+	"zerolog swapped error": {
+		backend: "zerolog",
+		swap: map[string]log.Sampling{
+			log.LevelDebug: {Thereafter: 5},
+		},
+		expect: 2,
+	},
+}
+
+func TestLevelSampling(t *testing.T) {
+	test.Map(t, testLevelSamplingParams).
+		Run(func(t test.Test, param testLevelSamplingParam) {
+			// Given
+			buffer := &bytes.Buffer{}
+			config := newSampleConfig(log.Sampling{})
+			config.LevelSampling = levelSampling
+			logf := sampleLoggers(config, buffer)[param.backend]
+			if param.swap != nil {
+				assert.NoError(t, config.SetLevelSampling(param.swap))
+			}
+
+			// When
+			for range 10 {
+				logf(log.DebugLevel, "debug")
+				logf(log.ErrorLevel, "error")
+			}
+
+			// Then
+			assert.Equal(t, param.expect, countLines(buffer, "DEBUG debug"))
+			assert.Equal(t, 10, countLines(buffer, "ERROR error"))
+		})
+}
+
+func TestSetLevelSamplingError(t *testing.T) {
+	// Given
+	config := newSampleConfig(log.Sampling{})
+	config.LevelSampling = levelSampling
+
+	// When
+	err := config.SetLevelSampling(map[string]log.Sampling{
+		"dbg": {Thereafter: 5},
+	})
+
+	// Then
+	assert.Equal(t, errors.Join(log.NewErrLevel("dbg")), err)
+	assert.Equal(t, levelSampling, config.LevelSampling)
+}
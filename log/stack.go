@@ -0,0 +1,160 @@
+package log
+
+import (
+	"bytes"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/rs/zerolog"
+	"github.com/sirupsen/logrus"
+)
+
+// FieldStack is the field name of the stack trace attached to log entries.
+const FieldStack = "stack"
+
+// DefaultStackDepth is the default maximum number of stack trace frames.
+const DefaultStackDepth = 32
+
+// stackPackages are the function prefixes of the logging packages trimmed
+// from the top of captured stack traces.
+var stackPackages = []string{
+	"runtime.",
+	"github.com/sirupsen/logrus.",
+	"github.com/rs/zerolog.",
+	"github.com/tkrop/go-config/log.",
+}
+
+// IsStacktraceEnabled returns whether stack traces are attached to log
+// entries.
+func (c *Config) IsStacktraceEnabled() bool {
+	return c.Stacktrace != ""
+}
+
+// ParseStackDepth returns the maximum number of stack trace frames falling
+// back to the default stack depth.
+func (c *Config) ParseStackDepth() int {
+	if c.StackDepth <= 0 {
+		return DefaultStackDepth
+	}
+	return c.StackDepth
+}
+
+// Stacktrace captures the stack trace of the caller as list of frames, i.e.
+// `function file:line`, trimming the frames of the logging packages at the
+// top. The number of frames is limited to the given depth.
+func Stacktrace(depth int) []string {
+	pcs := make([]uintptr, depth+32)
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(2, pcs)])
+
+	stack := make([]string, 0, depth)
+	trim := true
+	for len(stack) < depth {
+		frame, more := frames.Next()
+		if trim = trim && isStackPackage(frame.Function); !trim {
+			stack = append(stack, frame.Function+" "+
+				frame.File+":"+strconv.Itoa(frame.Line))
+		}
+		if !more {
+			break
+		}
+	}
+	return stack
+}
+
+// isStackPackage returns whether the given function belongs to one of the
+// logging packages.
+func isStackPackage(function string) bool {
+	for _, prefix := range stackPackages {
+		if strings.HasPrefix(function, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// WriteStack writes the given stack trace frames on continuation lines
+// indented under the log entry.
+func (b *Buffer) WriteStack(stack []string) *Buffer {
+	for _, frame := range stack {
+		b.WriteByte('\n').WriteString("    ").WriteString(b.pretty.Escape(frame))
+	}
+	return b
+}
+
+// FormatStack formats the stack trace of the given zerolog event on
+// continuation lines indented under the log entry.
+func (s *Setup) FormatStack(event map[string]any, buffer *bytes.Buffer) error {
+	frames, ok := event[FieldStack].([]any)
+	if !ok {
+		return nil
+	}
+
+	stack := make([]string, 0, len(frames))
+	for _, frame := range frames {
+		if frame, ok := frame.(string); ok {
+			stack = append(stack, frame)
+		}
+	}
+	_, err := NewBuffer(s, buffer).WriteStack(stack).Bytes()
+	return err
+}
+
+// LogRusStackHook is a hook attaching the stack trace to log entries at or
+// above the configured level.
+type LogRusStackHook struct {
+	// level is the level at or above which stack traces are attached.
+	level Level
+	// depth is the maximum number of stack trace frames.
+	depth int
+}
+
+// NewLogRusStackHook creates a new stack trace hook for logrus using the
+// given config.
+func NewLogRusStackHook(c *Config) *LogRusStackHook {
+	return &LogRusStackHook{
+		level: ParseLevel(c.Stacktrace),
+		depth: c.ParseStackDepth(),
+	}
+}
+
+// Levels returns the log levels at or above the configured level.
+func (h *LogRusStackHook) Levels() []logrus.Level {
+	return logrus.AllLevels[:h.level+1]
+}
+
+// Fire attaches the stack trace to the given log entry.
+func (h *LogRusStackHook) Fire(entry *logrus.Entry) error {
+	if _, ok := entry.Data[FieldStack]; !ok {
+		entry.Data[FieldStack] = Stacktrace(h.depth)
+	}
+	return nil
+}
+
+// ZeroLogStackHook is a hook attaching the stack trace to log events at or
+// above the configured level.
+type ZeroLogStackHook struct {
+	// level is the level at or above which stack traces are attached.
+	level Level
+	// depth is the maximum number of stack trace frames.
+	depth int
+}
+
+// NewZeroLogStackHook creates a new stack trace hook for zerolog using the
+// given config.
+func NewZeroLogStackHook(c *Config) *ZeroLogStackHook {
+	return &ZeroLogStackHook{
+		level: ParseLevel(c.Stacktrace),
+		depth: c.ParseStackDepth(),
+	}
+}
+
+// Run attaches the stack trace to the given log event, if the level is at or
+// above the configured level.
+func (h *ZeroLogStackHook) Run(
+	event *zerolog.Event, level zerolog.Level, _ string,
+) {
+	if level != zerolog.NoLevel && ZeroLevel(level) <= h.level {
+		event.Strs(FieldStack, Stacktrace(h.depth))
+	}
+}
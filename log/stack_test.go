@@ -0,0 +1,121 @@
+package log_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tkrop/go-testing/test"
+
+	"github.com/tkrop/go-config/log"
+)
+
+// stackCaller is the function expected as top frame of the stack traces.
+const stackCaller = "github.com/tkrop/go-config/log_test.logStack"
+
+// logStack logs a message using the given loggers and level.
+func logStack(rlogger *logrus.Logger, zlogger zerolog.Logger, level log.Level) {
+	// #nosec G115 // cannot happen.
+	rlogger.Log(logrus.Level(level), "message")
+	zlogger.WithLevel(log.ToZeroLevel(level)).Msg("message")
+}
+
+type testStackParam struct {
+	formatter log.Formatter
+	level     log.Level
+	depth     int
+	expect    bool
+}
+
+var testStackParams = map[string]testStackParam{
+	"pretty error": {
+		formatter: log.FormatterPretty,
+		level:     log.ErrorLevel,
+		expect:    true,
+	},
+	"pretty warn": {
+		formatter: log.FormatterPretty,
+		level:     log.WarnLevel,
+	},
+	"json error": {
+		formatter: log.FormatterJSON,
+		level:     log.ErrorLevel,
+		expect:    true,
+	},
+	"json error depth": {
+		formatter: log.FormatterJSON,
+		level:     log.ErrorLevel,
+		depth:     2,
+		expect:    true,
+	},
+	"json info": {
+		formatter: log.FormatterJSON,
+		level:     log.InfoLevel,
+	},
+}
+
+func TestStack(t *testing.T) {
+	test.Map(t, testStackParams).
+		Run(func(t test.Test, param testStackParam) {
+			// Given
+			config := &log.Config{
+				Level:      log.LevelInfo,
+				Formatter:  param.formatter,
+				TimeFormat: log.TimeFormatNone,
+				ColorMode:  log.ColorModeOff,
+				Stacktrace: log.LevelError,
+				StackDepth: param.depth,
+			}
+			rus, zero := &bytes.Buffer{}, &bytes.Buffer{}
+			rlogger := config.SetupRus(rus, logrus.New())
+			zlogger := config.SetupZero(zero).ZeroLogger()
+
+			// When
+			logStack(rlogger, zlogger, param.level)
+
+			// Then
+			for _, output := range []string{rus.String(), zero.String()} {
+				stack := parseStack(t, param.formatter, output)
+				if !param.expect {
+					assert.Empty(t, stack)
+					continue
+				}
+
+				require.NotEmpty(t, stack)
+				assert.True(t, strings.HasPrefix(stack[0], stackCaller+" "),
+					"unexpected top frame: %s", stack[0])
+				if param.depth > 0 {
+					assert.Len(t, stack, param.depth)
+				}
+				for _, frame := range stack {
+					assert.NotContains(t, frame, "github.com/sirupsen/logrus.")
+					assert.NotContains(t, frame, "github.com/rs/zerolog.")
+				}
+			}
+		})
+}
+
+// parseStack parses the stack trace frames from the given log output of the
+// given formatter.
+func parseStack(t test.Test, formatter log.Formatter, output string) []string {
+	if formatter != log.FormatterJSON {
+		lines := strings.Split(strings.TrimSuffix(output, "\n"), "\n")
+		stack := []string{}
+		for _, line := range lines[1:] {
+			assert.True(t, strings.HasPrefix(line, "    "))
+			stack = append(stack, strings.TrimPrefix(line, "    "))
+		}
+		return stack
+	}
+
+	entry := struct {
+		Stack []string `json:"stack"`
+	}{}
+	require.NoError(t, json.Unmarshal([]byte(output), &entry))
+	return entry.Stack
+}
@@ -0,0 +1,244 @@
+package log
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Syslog defaults.
+const (
+	// DefaultSyslogPort is the default port used for syslog endpoints.
+	DefaultSyslogPort = "514"
+	// DefaultSyslogFacility is the default facility used for syslog.
+	DefaultSyslogFacility = "user"
+)
+
+// Syslog severities.
+const (
+	// SyslogCrit is the syslog severity for critical conditions.
+	SyslogCrit = 2
+	// SyslogErr is the syslog severity for error conditions.
+	SyslogErr = 3
+	// SyslogWarning is the syslog severity for warning conditions.
+	SyslogWarning = 4
+	// SyslogInfo is the syslog severity for informational messages.
+	SyslogInfo = 6
+	// SyslogDebug is the syslog severity for debug messages.
+	SyslogDebug = 7
+)
+
+var (
+	// syslogSeverities maps the log levels to the syslog severities.
+	syslogSeverities = []int{
+		SyslogCrit, SyslogCrit, SyslogErr, SyslogWarning,
+		SyslogInfo, SyslogDebug, SyslogDebug,
+	}
+
+	// syslogFacilities maps the facility names to the syslog facilities.
+	syslogFacilities = map[string]int{
+		"kern": 0, "user": 1, "mail": 2, "daemon": 3,
+		"auth": 4, "syslog": 5, "lpr": 6, "news": 7,
+		"uucp": 8, "cron": 9, "authpriv": 10, "ftp": 11,
+		"local0": 16, "local1": 17, "local2": 18, "local3": 19,
+		"local4": 20, "local5": 21, "local6": 22, "local7": 23,
+	}
+
+	// syslogNetworks maps the syslog url schemes to the networks.
+	syslogNetworks = map[string]string{
+		"syslog": "udp", "syslog+udp": "udp", "syslog+tcp": "tcp",
+	}
+
+	// ansiRegex matches ANSI color escape sequences.
+	ansiRegex = regexp.MustCompile("\x1b\\[[0-9;]*m")
+)
+
+// ErrSyslog is a common error to indicate a syslog setup error.
+var ErrSyslog = errors.New("syslog")
+
+// NewErrSyslog is a convenience method to create a new syslog error with the
+// given message and endpoint wrapping the original error.
+func NewErrSyslog(message, endpoint string, err error) error {
+	return fmt.Errorf("%w - %s [%s]: %w", ErrSyslog, message, endpoint, err)
+}
+
+// IsSyslog checks whether the given file name is a syslog endpoint url.
+func IsSyslog(file string) bool {
+	scheme, _, ok := strings.Cut(file, "://")
+	_, found := syslogNetworks[scheme]
+	return ok && found
+}
+
+// SyslogWriter is a writer sending log entries to a syslog endpoint. The
+// writer translates log levels to syslog severities, strips ANSI colors, and
+// reconnects on failures of the network connection.
+type SyslogWriter struct {
+	// endpoint is the endpoint url of the writer.
+	endpoint string
+	// network is the network used for connecting the endpoint.
+	network string
+	// address is the address of the endpoint.
+	address string
+	// facility is the syslog facility.
+	facility int
+	// tag is the syslog tag.
+	tag string
+	// hostname is the hostname reported to syslog.
+	hostname string
+
+	// conn is the current network connection.
+	conn net.Conn
+	// mutex is the mutex to synchronize writes.
+	mutex sync.Mutex
+}
+
+// NewSyslogWriter creates a new syslog writer for the given endpoint url, e.g.
+// `syslog://localhost:514?facility=local0&tag=myapp`. The schemes `syslog`
+// and `syslog+udp` are using UDP, while `syslog+tcp` is using TCP. The
+// connection is established lazily on first write.
+func NewSyslogWriter(endpoint string) (*SyslogWriter, error) {
+	uri, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, NewErrSyslog("parsing url", endpoint, err)
+	}
+
+	network, ok := syslogNetworks[uri.Scheme]
+	if !ok {
+		return nil, NewErrSyslog("unknown scheme", endpoint,
+			errors.New(uri.Scheme))
+	}
+
+	query := uri.Query()
+	name := query.Get("facility")
+	if name == "" {
+		name = DefaultSyslogFacility
+	}
+	facility, ok := syslogFacilities[strings.ToLower(name)]
+	if !ok {
+		return nil, NewErrSyslog("unknown facility", endpoint,
+			errors.New(name))
+	}
+
+	tag := query.Get("tag")
+	if tag == "" {
+		tag = filepath.Base(os.Args[0])
+	}
+
+	address := uri.Host
+	if uri.Port() == "" {
+		address = net.JoinHostPort(uri.Hostname(), DefaultSyslogPort)
+	}
+
+	hostname, _ := os.Hostname()
+	return &SyslogWriter{
+		endpoint: endpoint,
+		network:  network,
+		address:  address,
+		facility: facility,
+		tag:      tag,
+		hostname: hostname,
+	}, nil
+}
+
+// Write writes the given entry with informational severity to syslog.
+func (w *SyslogWriter) Write(p []byte) (int, error) {
+	return w.WriteLevel(InfoLevel, p)
+}
+
+// WriteLevel writes the given entry with the syslog severity corresponding to
+// the given log level to syslog. On failure the connection is reestablished
+// and the write is retried once.
+func (w *SyslogWriter) WriteLevel(level Level, p []byte) (int, error) {
+	severity := SyslogDebug
+	if level >= PanicLevel && int(level) < len(syslogSeverities) {
+		severity = syslogSeverities[level]
+	}
+
+	message := ansiRegex.ReplaceAll(bytes.TrimRight(p, "\n"), nil)
+	line := "<" + strconv.Itoa(w.facility*8+severity) + ">" +
+		time.Now().Format(time.RFC3339) + " " + w.hostname + " " +
+		w.tag + "[" + strconv.Itoa(os.Getpid()) + "]: " +
+		string(message) + "\n"
+
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	if err := w.write(line); err != nil {
+		w.close()
+		if err := w.write(line); err != nil {
+			return 0, NewErrSyslog("writing entry", w.address, err)
+		}
+	}
+	return len(p), nil
+}
+
+// write writes the given line to the current connection. If no connection is
+// available, a new connection is established.
+func (w *SyslogWriter) write(line string) error {
+	if w.conn == nil {
+		conn, err := net.Dial(w.network, w.address)
+		if err != nil {
+			return err
+		}
+		w.conn = conn
+	}
+
+	_, err := io.WriteString(w.conn, line)
+	return err
+}
+
+// Close closes the current connection of the syslog writer.
+func (w *SyslogWriter) Close() error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	return w.close()
+}
+
+// close closes the current connection without locking.
+func (w *SyslogWriter) close() error {
+	if w.conn != nil {
+		err := w.conn.Close()
+		w.conn = nil
+		return err
+	}
+	return nil
+}
+
+// SetupSyslog creates the syslog writer, if the configured file is a syslog
+// endpoint url. Else `nil` is returned.
+func (c *Config) SetupSyslog() (*SyslogWriter, error) {
+	if !IsSyslog(c.File) {
+		return nil, nil
+	}
+	return NewSyslogWriter(c.File)
+}
+
+// setupSyslog returns the syslog writer for the configured syslog endpoint
+// reusing the syslog writer of a former setup. If the endpoint has changed,
+// the former syslog writer is closed. The loggers must be locked by the
+// caller.
+func (l *loggers) setupSyslog(c *Config) (*SyslogWriter, error) {
+	if l.syslog != nil {
+		if l.syslog.endpoint == c.File {
+			return l.syslog, nil
+		}
+		_ = l.syslog.Close()
+		l.syslog = nil
+	}
+
+	syslog, err := c.SetupSyslog()
+	if err != nil || syslog == nil {
+		return nil, err
+	}
+	l.syslog = syslog
+	return syslog, nil
+}
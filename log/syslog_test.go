@@ -0,0 +1,275 @@
+package log_test
+
+import (
+	"bytes"
+	"errors"
+	"net"
+	"os"
+	"regexp"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tkrop/go-testing/test"
+
+	"github.com/tkrop/go-config/log"
+)
+
+type testNewSyslogWriterParam struct {
+	endpoint       string
+	expectNetwork  string
+	expectAddress  string
+	expectFacility int
+	expectTag      string
+	expectError    error
+}
+
+var testNewSyslogWriterParams = map[string]testNewSyslogWriterParam{
+	"syslog default": {
+		endpoint:       "syslog://localhost?tag=app",
+		expectNetwork:  "udp",
+		expectAddress:  "localhost:514",
+		expectFacility: 1,
+		expectTag:      "app",
+	},
+	"syslog udp local0": {
+		endpoint:       "syslog+udp://localhost:1514?facility=local0&tag=app",
+		expectNetwork:  "udp",
+		expectAddress:  "localhost:1514",
+		expectFacility: 16,
+		expectTag:      "app",
+	},
+	"syslog tcp daemon": {
+		endpoint:       "syslog+tcp://localhost:1514?facility=DAEMON&tag=app",
+		expectNetwork:  "tcp",
+		expectAddress:  "localhost:1514",
+		expectFacility: 3,
+		expectTag:      "app",
+	},
+	"syslog unknown scheme": {
+		endpoint: "syslog+http://localhost",
+		expectError: log.NewErrSyslog("unknown scheme",
+			"syslog+http://localhost", errors.New("syslog+http")),
+	},
+	"syslog unknown facility": {
+		endpoint: "syslog://localhost?facility=any",
+		expectError: log.NewErrSyslog("unknown facility",
+			"syslog://localhost?facility=any", errors.New("any")),
+	},
+}
+
+func TestNewSyslogWriter(t *testing.T) {
+	test.Map(t, testNewSyslogWriterParams).
+		Run(func(t test.Test, param testNewSyslogWriterParam) {
+			// When
+			writer, err := log.NewSyslogWriter(param.endpoint)
+
+			// Then
+			if param.expectError != nil {
+				assert.ErrorIs(t, err, log.ErrSyslog)
+				assert.Equal(t, param.expectError.Error(), err.Error())
+				assert.Nil(t, writer)
+				return
+			}
+
+			require.NoError(t, err)
+			accessor := test.NewAccessor(writer)
+			assert.Equal(t, param.expectNetwork, accessor.Get("network"))
+			assert.Equal(t, param.expectAddress, accessor.Get("address"))
+			assert.Equal(t, param.expectFacility, accessor.Get("facility"))
+			assert.Equal(t, param.expectTag, accessor.Get("tag"))
+		})
+}
+
+type testSyslogParam struct {
+	config       log.Config
+	level        log.Level
+	expectPrefix string
+	expectSuffix string
+}
+
+var testSyslogParams = map[string]testSyslogParam{
+	"syslog error local0": {
+		config:       log.Config{File: "?facility=local0&tag=myapp"},
+		level:        log.ErrorLevel,
+		expectPrefix: "<131>",
+		expectSuffix: level(log.ErrorLevel) + " error message",
+	},
+	"syslog warn local0": {
+		config:       log.Config{File: "?facility=local0&tag=myapp"},
+		level:        log.WarnLevel,
+		expectPrefix: "<132>",
+		expectSuffix: level(log.WarnLevel) + " warn message",
+	},
+	"syslog info user": {
+		config:       log.Config{File: "?tag=myapp"},
+		level:        log.InfoLevel,
+		expectPrefix: "<14>",
+		expectSuffix: level(log.InfoLevel) + " info message",
+	},
+	"syslog debug user color-on": {
+		config: log.Config{
+			File:      "?tag=myapp",
+			ColorMode: log.ColorModeOn,
+		},
+		level:        log.DebugLevel,
+		expectPrefix: "<15>",
+		expectSuffix: level(log.DebugLevel) + " debug message",
+	},
+	"syslog info text color-on": {
+		config: log.Config{
+			File:      "?tag=myapp",
+			ColorMode: log.ColorModeOn,
+			Formatter: log.FormatterText,
+		},
+		level:        log.InfoLevel,
+		expectPrefix: "<14>",
+		expectSuffix: "info message",
+	},
+}
+
+// syslogHeader matches the syslog header after the priority.
+var syslogHeader = regexp.MustCompile(
+	`^<\d+>\S+ \S* myapp\[` + strconv.Itoa(os.Getpid()) + `\]: `)
+
+// setupSyslog sets up an in-process UDP syslog listener and returns the
+// listener and the endpoint url prefix.
+func setupSyslog(t test.Test) (net.PacketConn, string) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	return conn, "syslog://" + conn.LocalAddr().String()
+}
+
+// receive receives a single syslog packet from the given listener.
+func receive(t test.Test, conn net.PacketConn) string {
+	buffer := make([]byte, 4096)
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(time.Second)))
+	n, _, err := conn.ReadFrom(buffer)
+	require.NoError(t, err)
+	return string(buffer[:n])
+}
+
+// assertSyslog asserts the received syslog packet.
+func assertSyslog(t test.Test, param testSyslogParam, packet string) {
+	assert.Regexp(t, syslogHeader, packet)
+	assert.Regexp(t, "^"+regexp.QuoteMeta(param.expectPrefix), packet)
+	assert.NotContains(t, packet, "\x1b[")
+	assert.Regexp(t, regexp.QuoteMeta(param.expectSuffix)+`\s*\n$`, packet)
+}
+
+func TestSyslogRus(t *testing.T) {
+	test.Map(t, testSyslogParams).
+		Run(func(t test.Test, param testSyslogParam) {
+			// Given
+			conn, endpoint := setupSyslog(t)
+			defer conn.Close()
+			param.config.File = endpoint + param.config.File
+			param.config.Level = log.LevelTrace
+			logger := param.config.SetupRus(&bytes.Buffer{}, logrus.New())
+
+			// When
+			// #nosec G115 // cannot happen.
+			logger.Log(logrus.Level(param.level), message(param.level))
+
+			// Then
+			assertSyslog(t, param, receive(t, conn))
+		})
+}
+
+func TestSyslogZero(t *testing.T) {
+	test.Map(t, testSyslogParams).
+		Run(func(t test.Test, param testSyslogParam) {
+			// Given
+			conn, endpoint := setupSyslog(t)
+			defer conn.Close()
+			param.config.File = endpoint + param.config.File
+			param.config.Level = log.LevelTrace
+			logger := param.config.SetupZero(&bytes.Buffer{}).ZeroLogger()
+
+			// When
+			logger.WithLevel(zeroLevels[param.level]).
+				Msg(message(param.level))
+
+			// Then
+			assertSyslog(t, param, receive(t, conn))
+		})
+}
+
+func TestSyslogReconnect(t *testing.T) {
+	// Given
+	conn, endpoint := setupSyslog(t)
+	defer conn.Close()
+	writer, err := log.NewSyslogWriter(endpoint + "?tag=myapp")
+	require.NoError(t, err)
+	broken, err := net.Dial("udp", conn.LocalAddr().String())
+	require.NoError(t, err)
+	require.NoError(t, broken.Close())
+	test.NewAccessor(writer).Set("conn", broken)
+
+	// When
+	n, err := writer.WriteLevel(log.ErrorLevel, []byte("reconnect\n"))
+
+	// Then
+	require.NoError(t, err)
+	assert.Equal(t, len("reconnect\n"), n)
+	packet := receive(t, conn)
+	assert.Regexp(t, syslogHeader, packet)
+	assert.Regexp(t, `^<11>.*: reconnect\n$`, packet)
+	assert.NoError(t, writer.Close())
+}
+
+func TestSyslogInvalid(t *testing.T) {
+	// Given
+	buffer := &bytes.Buffer{}
+	config := log.Config{
+		File:      "syslog://localhost?facility=any",
+		ColorMode: log.ColorModeOff,
+	}
+
+	// When
+	config.SetupZero(buffer)
+	config.SetupRus(buffer, logrus.New())
+
+	// Then
+	assert.Contains(t, buffer.String(), "setting up syslog")
+	assert.Contains(t, buffer.String(), "unknown facility")
+}
+
+func TestSyslogSetupRepeated(t *testing.T) {
+	// Given
+	conn, endpoint := setupSyslog(t)
+	defer conn.Close()
+	config := &log.Config{
+		Level:     log.LevelInfo,
+		File:      endpoint + "?tag=first",
+		ColorMode: log.ColorModeOff,
+	}
+	config.SetupRus(&bytes.Buffer{}, logrus.New()).Info("first")
+	first := syslogWriter(config)
+	receive(t, conn)
+
+	// When
+	config.SetupZero(&bytes.Buffer{})
+	config.SetupRus(&bytes.Buffer{}, logrus.New())
+	reused := syslogWriter(config)
+	config.File = endpoint + "?tag=second"
+	config.SetupRus(&bytes.Buffer{}, logrus.New()).Info("second")
+	second := syslogWriter(config)
+
+	// Then
+	assert.Same(t, first, reused)
+	assert.NotSame(t, first, second)
+	assert.Nil(t, test.NewAccessor(first).Get("conn"))
+	assert.Contains(t, receive(t, conn), "second[")
+	assert.NoError(t, second.Close())
+}
+
+// syslogWriter returns the syslog writer tracked by the given config.
+func syslogWriter(config *log.Config) *log.SyslogWriter {
+	loggers := test.NewAccessor(config).Get("loggers")
+	writer, _ := test.NewAccessor(loggers).Get("syslog").(*log.SyslogWriter)
+	return writer
+}
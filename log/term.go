@@ -0,0 +1,83 @@
+package log
+
+import (
+	"io"
+	"os"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+// Environment variables controlling colors in automatic color mode.
+const (
+	// EnvNoColor is the environment variable to suppress colors.
+	EnvNoColor = "NO_COLOR"
+	// EnvForceColor is the environment variable to force colors.
+	EnvForceColor = "FORCE_COLOR"
+	// EnvCliColorForce is the alternative environment variable to force
+	// colors.
+	EnvCliColorForce = "CLICOLOR_FORCE"
+)
+
+// FdWriter is the interface of writers providing a file descriptor, e.g.
+// `os.File`.
+type FdWriter interface {
+	io.Writer
+	// Fd returns the file descriptor of the writer.
+	Fd() uintptr
+}
+
+// IsTerminal checks whether the given writer is a terminal. Writers without
+// file descriptor are never considered a terminal.
+func IsTerminal(writer io.Writer) bool {
+	if file, ok := writer.(FdWriter); ok {
+		// #nosec G115 // is a safe conversion for file descriptors.
+		return term.IsTerminal(int(file.Fd()))
+	}
+	return false
+}
+
+// IsColorized checks whether the given writer is colorized in automatic
+// color mode. It follows the common conventions of the environment variables,
+// i.e. if `NO_COLOR` is set colors are suppressed, if `FORCE_COLOR` or
+// `CLICOLOR_FORCE` is set to a non-zero value colors are forced. Only if none
+// of the variables is set, the terminal detection of the writer is used.
+func IsColorized(writer io.Writer) bool {
+	if os.Getenv(EnvNoColor) != "" {
+		return false
+	} else if isForced(EnvForceColor) || isForced(EnvCliColorForce) {
+		return true
+	}
+	return IsTerminal(writer)
+}
+
+// isForced checks whether the given environment variable is set to a
+// non-zero value.
+func isForced(name string) bool {
+	value := os.Getenv(name)
+	return value != "" && value != "0" && !strings.EqualFold(value, "false")
+}
+
+// EnableColors enables the processing of ANSI color escape sequences for
+// the given writer and returns whether colors are supported. On most
+// platforms terminals support colors natively, so that this is a no-op, while
+// on Windows the virtual terminal processing of the console is enabled.
+// Writers without file descriptor are not changed and always support colors.
+func EnableColors(writer io.Writer) bool {
+	if file, ok := writer.(FdWriter); ok && IsTerminal(file) {
+		return enableColors(file.Fd())
+	}
+	return true
+}
+
+// ParseColorMode parses the color mode of the config using the environment
+// and terminal detection of the given writer (see [IsColorized]). If the
+// resolved color mode requires colors, the color support of the writer is
+// enabled. If colors are not supported, the color mode is switched off.
+func (c *Config) ParseColorMode(writer io.Writer) ColorMode {
+	mode := c.ColorMode.Parse(IsColorized(writer))
+	if !mode.CheckFlag(ColorOff) && !EnableColors(writer) {
+		return ColorOff
+	}
+	return mode
+}
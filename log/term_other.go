@@ -0,0 +1,8 @@
+//go:build !windows
+
+package log
+
+// enableColors is a no-op, since terminals support colors natively.
+func enableColors(uintptr) bool {
+	return true
+}
@@ -0,0 +1,183 @@
+package log_test
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/tkrop/go-testing/test"
+
+	"github.com/tkrop/go-config/log"
+)
+
+// fdWriter is a fake writer with an arbitrary file descriptor.
+type fdWriter struct {
+	bytes.Buffer
+	fd uintptr
+}
+
+// Fd returns the fake file descriptor.
+func (w *fdWriter) Fd() uintptr {
+	return w.fd
+}
+
+type testTerminalParam struct {
+	writer          io.Writer
+	colorMode       log.ColorModeString
+	expectTerminal  bool
+	expectColors    bool
+	expectColorMode log.ColorMode
+}
+
+var testTerminalParams = map[string]testTerminalParam{
+	"buffer writer": {
+		writer:          &bytes.Buffer{},
+		expectColors:    true,
+		expectColorMode: log.ColorOff,
+	},
+	"buffer writer color-on": {
+		writer:          &bytes.Buffer{},
+		colorMode:       log.ColorModeOn,
+		expectColors:    true,
+		expectColorMode: log.ColorOn,
+	},
+	"nil writer": {
+		writer:          nil,
+		expectColors:    true,
+		expectColorMode: log.ColorOff,
+	},
+	"fake fd writer": {
+		writer:          &fdWriter{fd: 1 << 20},
+		expectColors:    true,
+		expectColorMode: log.ColorOff,
+	},
+	"fake fd writer color-levels": {
+		writer:          &fdWriter{fd: 1 << 20},
+		colorMode:       log.ColorModeLevels,
+		expectColors:    true,
+		expectColorMode: log.ColorLevels,
+	},
+	"file writer": {
+		writer:          os.NewFile(1<<20, "invalid"),
+		expectColors:    true,
+		expectColorMode: log.ColorOff,
+	},
+}
+
+func TestTerminal(t *testing.T) {
+	test.Map(t, testTerminalParams).
+		Run(func(t test.Test, param testTerminalParam) {
+			// Given
+			config := log.Config{ColorMode: param.colorMode}
+
+			// When
+			terminal := log.IsTerminal(param.writer)
+			colors := log.EnableColors(param.writer)
+			mode := config.ParseColorMode(param.writer)
+
+			// Then
+			assert.Equal(t, param.expectTerminal, terminal)
+			assert.Equal(t, param.expectColors, colors)
+			assert.Equal(t, param.expectColorMode, mode)
+		})
+}
+
+type testColorEnvParam struct {
+	env          map[string]string
+	colorMode    log.ColorModeString
+	expectResult string
+}
+
+var testColorEnvParams = map[string]testColorEnvParam{
+	"no env": {
+		expectResult: level(log.InfoLevel) + " info message\n",
+	},
+	"no color": {
+		env:          map[string]string{log.EnvNoColor: "1"},
+		expectResult: level(log.InfoLevel) + " info message\n",
+	},
+	"force color": {
+		env:          map[string]string{log.EnvForceColor: "1"},
+		expectResult: levelC(log.InfoLevel) + " info message\n",
+	},
+	"force color zero": {
+		env:          map[string]string{log.EnvForceColor: "0"},
+		expectResult: level(log.InfoLevel) + " info message\n",
+	},
+	"force color false": {
+		env:          map[string]string{log.EnvForceColor: "false"},
+		expectResult: level(log.InfoLevel) + " info message\n",
+	},
+	"clicolor force": {
+		env:          map[string]string{log.EnvCliColorForce: "1"},
+		expectResult: levelC(log.InfoLevel) + " info message\n",
+	},
+	"no color and force color": {
+		env: map[string]string{
+			log.EnvNoColor: "1", log.EnvForceColor: "1",
+		},
+		expectResult: level(log.InfoLevel) + " info message\n",
+	},
+	"force color color-off": {
+		env:          map[string]string{log.EnvForceColor: "1"},
+		colorMode:    log.ColorModeOff,
+		expectResult: level(log.InfoLevel) + " info message\n",
+	},
+	"no color color-on": {
+		env:          map[string]string{log.EnvNoColor: "1"},
+		colorMode:    log.ColorModeOn,
+		expectResult: levelC(log.InfoLevel) + " info message\n",
+	},
+}
+
+// setupColorEnv sets up the color environment variables for testing.
+func setupColorEnv(t test.Test, env map[string]string) {
+	for _, name := range []string{
+		log.EnvNoColor, log.EnvForceColor, log.EnvCliColorForce,
+	} {
+		t.Setenv(name, env[name])
+	}
+}
+
+func TestColorEnvRus(t *testing.T) {
+	test.Map(t, testColorEnvParams).
+		RunSeq(func(t test.Test, param testColorEnvParam) {
+			// Given
+			setupColorEnv(t, param.env)
+			buffer := &bytes.Buffer{}
+			config := log.Config{
+				TimeFormat: fixedTimeFormat,
+				ColorMode:  param.colorMode,
+			}
+			logger := config.SetupRus(buffer, logrus.New())
+
+			// When
+			logger.Info("info message")
+
+			// Then
+			assert.Equal(t, param.expectResult, trimTime(buffer.String()))
+		})
+}
+
+func TestColorEnvZero(t *testing.T) {
+	test.Map(t, testColorEnvParams).
+		RunSeq(func(t test.Test, param testColorEnvParam) {
+			// Given
+			setupColorEnv(t, param.env)
+			buffer := &bytes.Buffer{}
+			config := log.Config{
+				TimeFormat: fixedTimeFormat,
+				ColorMode:  param.colorMode,
+			}
+			logger := config.SetupZero(buffer).ZeroLogger()
+
+			// When
+			logger.Info().Msg("info message")
+
+			// Then
+			assert.Equal(t, param.expectResult, trimTime(buffer.String()))
+		})
+}
@@ -0,0 +1,20 @@
+//go:build windows
+
+package log
+
+import "golang.org/x/sys/windows"
+
+// enableColors enables the virtual terminal processing of the console with
+// the given file descriptor to render ANSI color escape sequences.
+func enableColors(fd uintptr) bool {
+	var mode uint32
+	handle := windows.Handle(fd)
+	if err := windows.GetConsoleMode(handle, &mode); err != nil {
+		return false
+	} else if mode&windows.ENABLE_VIRTUAL_TERMINAL_PROCESSING != 0 {
+		return true
+	}
+
+	mode |= windows.ENABLE_VIRTUAL_TERMINAL_PROCESSING
+	return windows.SetConsoleMode(handle, mode) == nil
+}
@@ -0,0 +1,26 @@
+package log
+
+// Text is the configuration fine-tuning the `text` formatter, i.e. the logrus
+// text formatter and the zerolog console writer. Options not supported by a
+// logging backend are ignored.
+type Text struct {
+	// DisableQuote is defining whether quoting of field values is disabled
+	// for logrus (default `false`).
+	DisableQuote bool `default:"false"`
+	// QuoteEmptyFields is defining whether empty field values are quoted for
+	// logrus (default `false`).
+	QuoteEmptyFields bool `default:"false"`
+	// PadLevelText is defining whether the level text is padded to the same
+	// width for logrus (default `false`).
+	PadLevelText bool `default:"false"`
+	// DisableLevelTruncation is defining whether the truncation of the level
+	// text to four characters is disabled for logrus (default `false`).
+	DisableLevelTruncation bool `default:"false"`
+	// PartsOrder is defining the order of the zerolog field names written as
+	// parts in front of the fields for zerolog (default ``, i.e. `time`,
+	// `level`, `caller`, and `message`).
+	PartsOrder []string
+	// PartsExclude is defining the zerolog field names of the parts excluded
+	// from the output for zerolog (default ``).
+	PartsExclude []string
+}
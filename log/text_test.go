@@ -0,0 +1,70 @@
+package log_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tkrop/go-testing/test"
+
+	"github.com/tkrop/go-config/log"
+)
+
+type testTextParam struct {
+	text               log.Text
+	expectPartsOrder   []string
+	expectPartsExclude []string
+}
+
+var testTextParams = map[string]testTextParam{
+	"default": {},
+	"tuned": {
+		text: log.Text{
+			DisableQuote:           true,
+			QuoteEmptyFields:       true,
+			PadLevelText:           true,
+			DisableLevelTruncation: true,
+			PartsOrder:             []string{"level", "message"},
+			PartsExclude:           []string{"caller"},
+		},
+		expectPartsOrder:   []string{"level", "message"},
+		expectPartsExclude: []string{"caller"},
+	},
+}
+
+func TestText(t *testing.T) {
+	test.Map(t, testTextParams).
+		Run(func(t test.Test, param testTextParam) {
+			// Given
+			config := &log.Config{
+				Level:     log.LevelInfo,
+				Formatter: log.FormatterText,
+				Text:      param.text,
+			}
+			logger := logrus.New()
+
+			// When
+			config.SetupRus(os.Stderr, logger)
+			zero := config.SetupZero(os.Stderr).ZeroLogger()
+
+			// Then
+			require.IsType(t, &logrus.TextFormatter{}, logger.Formatter)
+			format := logger.Formatter.(*logrus.TextFormatter)
+			assert.Equal(t, param.text.DisableQuote, format.DisableQuote)
+			assert.Equal(t, param.text.QuoteEmptyFields, format.QuoteEmptyFields)
+			assert.Equal(t, param.text.PadLevelText, format.PadLevelText)
+			assert.Equal(t, param.text.DisableLevelTruncation,
+				format.DisableLevelTruncation)
+
+			writer := test.NewAccessor(zero).Get("w")
+			require.IsType(t, zerolog.LevelWriterAdapter{}, writer)
+			console, ok := writer.(zerolog.LevelWriterAdapter).
+				Writer.(zerolog.ConsoleWriter)
+			require.True(t, ok)
+			assert.Equal(t, param.expectPartsOrder, console.PartsOrder)
+			assert.Equal(t, param.expectPartsExclude, console.PartsExclude)
+		})
+}
@@ -0,0 +1,8 @@
+package log
+
+// IsTraceEnabled returns whether the trace and span ids of the active span
+// context are attached to log entries, i.e. whether any field name is set.
+// The trace hooks are provided by the separate `log/otel` package.
+func (c *Config) IsTraceEnabled() bool {
+	return c.TraceIDName != "" || c.SpanIDName != ""
+}
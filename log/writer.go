@@ -0,0 +1,62 @@
+package log
+
+import (
+	"io"
+	"os"
+)
+
+// SplitWriter is a writer splitting the log output by level into a low level
+// writer for entries below the split level and a high level writer for entries
+// at or above the split level. Writes without level information are always
+// written to the low level writer.
+type SplitWriter struct {
+	// Low is the writer used for entries below the split level.
+	Low io.Writer
+	// High is the writer used for entries at or above the split level.
+	High io.Writer
+}
+
+// NewSplitWriter creates a new split writer using the given low level writer
+// for entries below the split level and the given high level writer for
+// entries at or above the split level.
+func NewSplitWriter(low, high io.Writer) *SplitWriter {
+	return &SplitWriter{Low: low, High: high}
+}
+
+// Write writes the given bytes to the low level writer.
+func (w *SplitWriter) Write(p []byte) (int, error) {
+	return w.Low.Write(p)
+}
+
+// Writer returns the low or high level writer for the given log level using
+// the given split level.
+func (w *SplitWriter) Writer(split, level Level) io.Writer {
+	if level <= split {
+		return w.High
+	}
+	return w.Low
+}
+
+// SetupSplit returns the split writer for the given writer. If the writer is
+// already a split writer, it is returned as is. If the split level is
+// configured, a new split writer is created writing entries below the split
+// level to `os.Stdout` and all other entries to the given writer. Else `nil`
+// is returned to signal that no split is required.
+func (c *Config) SetupSplit(writer io.Writer) *SplitWriter {
+	if split, ok := writer.(*SplitWriter); ok {
+		return split
+	} else if c.SplitLevel != "" {
+		return NewSplitWriter(os.Stdout, writer)
+	}
+	return nil
+}
+
+// ParseSplitLevel parses the split level of the config and returns the
+// corresponding level. If no split level is configured, the default split
+// level is returned.
+func (c *Config) ParseSplitLevel() Level {
+	if c.SplitLevel == "" {
+		return ParseLevel(DefaultSplitLevel)
+	}
+	return ParseLevel(c.SplitLevel)
+}
@@ -0,0 +1,161 @@
+package log_test
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/tkrop/go-testing/test"
+
+	"github.com/tkrop/go-config/log"
+)
+
+type testSplitWriterParam struct {
+	config     log.Config
+	level      log.Level
+	expectLow  string
+	expectHigh string
+}
+
+var testSplitWriterParams = map[string]testSplitWriterParam{
+	"split default error": {
+		config:    log.Config{ColorMode: log.ColorModeOff},
+		level:     log.ErrorLevel,
+		expectLow: level(log.ErrorLevel) + " error message\n",
+	},
+	"split warn error": {
+		config: log.Config{
+			SplitLevel: log.LevelWarn,
+			ColorMode:  log.ColorModeOff,
+		},
+		level:      log.ErrorLevel,
+		expectHigh: level(log.ErrorLevel) + " error message\n",
+	},
+	"split warn warn": {
+		config: log.Config{
+			SplitLevel: log.LevelWarn,
+			ColorMode:  log.ColorModeOff,
+		},
+		level:      log.WarnLevel,
+		expectHigh: level(log.WarnLevel) + " warn message\n",
+	},
+	"split warn info": {
+		config: log.Config{
+			SplitLevel: log.LevelWarn,
+			ColorMode:  log.ColorModeOff,
+		},
+		level:     log.InfoLevel,
+		expectLow: level(log.InfoLevel) + " info message\n",
+	},
+	"split warn debug": {
+		config: log.Config{
+			SplitLevel: log.LevelWarn,
+			ColorMode:  log.ColorModeOff,
+		},
+		level:     log.DebugLevel,
+		expectLow: level(log.DebugLevel) + " debug message\n",
+	},
+	"split error warn": {
+		config: log.Config{
+			SplitLevel: log.LevelError,
+			ColorMode:  log.ColorModeOff,
+		},
+		level:     log.WarnLevel,
+		expectLow: level(log.WarnLevel) + " warn message\n",
+	},
+	"split warn error color-on": {
+		config: log.Config{
+			SplitLevel: log.LevelWarn,
+			ColorMode:  log.ColorModeOn,
+		},
+		level:      log.ErrorLevel,
+		expectHigh: levelC(log.ErrorLevel) + " error message\n",
+	},
+	"split warn info color-on": {
+		config: log.Config{
+			SplitLevel: log.LevelWarn,
+			ColorMode:  log.ColorModeOn,
+		},
+		level:     log.InfoLevel,
+		expectLow: levelC(log.InfoLevel) + " info message\n",
+	},
+}
+
+// splitWriter returns a split writer for the given low and high writer, if a
+// split level is configured, else the low writer is returned.
+func splitWriter(config log.Config, low, high io.Writer) io.Writer {
+	if config.SplitLevel != "" {
+		return log.NewSplitWriter(low, high)
+	}
+	return low
+}
+
+func TestSplitWriterRus(t *testing.T) {
+	test.Map(t, testSplitWriterParams).
+		Run(func(t test.Test, param testSplitWriterParam) {
+			// Given
+			low, high := &bytes.Buffer{}, &bytes.Buffer{}
+			param.config.Level = log.LevelTrace
+			param.config.TimeFormat = fixedTimeFormat
+			logger := param.config.SetupRus(
+				splitWriter(param.config, low, high), logrus.New())
+
+			// When
+			// #nosec G115 // cannot happen.
+			logger.Log(logrus.Level(param.level), message(param.level))
+
+			// Then
+			assert.Equal(t, param.expectLow, trimTime(low.String()))
+			assert.Equal(t, param.expectHigh, trimTime(high.String()))
+		})
+}
+
+func TestSplitWriterZero(t *testing.T) {
+	test.Map(t, testSplitWriterParams).
+		Run(func(t test.Test, param testSplitWriterParam) {
+			// Given
+			low, high := &bytes.Buffer{}, &bytes.Buffer{}
+			param.config.Level = log.LevelTrace
+			param.config.TimeFormat = fixedTimeFormat
+			logger := param.config.SetupZero(
+				splitWriter(param.config, low, high)).ZeroLogger()
+
+			// When
+			logger.WithLevel(zeroLevels[param.level]).
+				Msg(message(param.level))
+
+			// Then
+			assert.Equal(t, param.expectLow, trimTime(low.String()))
+			assert.Equal(t, param.expectHigh, trimTime(high.String()))
+		})
+}
+
+func TestSplitWriterRusHooks(t *testing.T) {
+	// Given
+	low, high := &bytes.Buffer{}, &bytes.Buffer{}
+	config := log.Config{
+		Level:      log.LevelInfo,
+		Formatter:  log.FormatterJSON,
+		SplitLevel: log.LevelWarn,
+		ColorMode:  log.ColorModeOff,
+		Caller:     true,
+		Fields:     map[string]string{"app": "split"},
+	}
+	logger := config.SetupRus(log.NewSplitWriter(low, high), logrus.New())
+
+	// When
+	logger.Info("info message")
+	logger.Warn("warn message")
+
+	// Then
+	for _, output := range []string{low.String(), high.String()} {
+		assert.Equal(t, 1, strings.Count(output, "\n"), output)
+		assert.Contains(t, output, `"app":"split"`)
+		assert.Contains(t, output, "writer_test.go")
+	}
+	assert.Contains(t, low.String(), "info message")
+	assert.Contains(t, high.String(), "warn message")
+}
@@ -2,9 +2,15 @@ package log
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"io"
+	"os"
+	"slices"
+	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/rs/zerolog"
@@ -35,53 +41,509 @@ func (c *Config) ParseZeroLevel() zerolog.Level {
 
 // SetupZero sets up the zerolog logger. It particular it sets up the log
 // level, the report caller flag, as well as the formatter with color and order
-// mode.
+// mode. The setup is safe for concurrent use. Calling it again replaces the
+// zerolog logger of the config.
 func (c *Config) SetupZero(writer io.Writer) *Config {
-	logger := zerolog.New(writer).Level(c.ParseZeroLevel())
+	c.SetupZeroLogger(zerolog.New(writer), writer)
+	return c
+}
+
+// SetupZeroLogger sets up the given zerolog logger the same way as `SetupZero`
+// keeping the fields and hooks of the logger context, e.g. service fields.
+// The log level and the output are replaced. Since timestamp and caller are
+// added to the context as configured, the given logger should not set up
+// them to not duplicate them.
+func (c *Config) SetupZeroLogger(
+	logger zerolog.Logger, writer io.Writer,
+) zerolog.Logger {
+	loggers := c.setupLoggers()
+	loggers.mutex.Lock()
+	defer loggers.mutex.Unlock()
+	return c.setupZero(loggers, logger, writer)
+}
+
+// setupZero sets up the given zerolog logger as zerolog logger of the given
+// loggers. The loggers must be locked by the caller.
+func (c *Config) setupZero(
+	loggers *loggers, logger zerolog.Logger, writer io.Writer,
+) zerolog.Logger {
+	// Sets up the logger at the most verbose level to allow changing the log
+	// level at runtime via the level hook below, since zerolog loggers are
+	// values that cannot be changed after they have been handed out.
+	logger = logger.Level(zerolog.TraceLevel)
+	modules := loggers.modules
+	// #nosec G115 // cannot happen.
+	loggers.verbose.Store(int32(ParseLevel(c.Level)))
+
+	// Sets up the global field names and formats used by all zerolog loggers.
+	c.setupZeroGlobals()
+
+	// Flushes the pending repeated entries of a former setup before replacing.
+	if loggers.zeroFlush != nil {
+		loggers.zeroFlush()
+	}
+
+	// Sets up the log output split and format.
+	var output io.Writer
+	if split := c.SetupSplit(writer); split != nil {
+		output = &ZeroLogSplit{
+			level: c.ParseSplitLevel(),
+			low:   c.ZeroWriter(c.ZeroDiode(split.Low)),
+			high:  c.ZeroWriter(c.ZeroDiode(split.High)),
+		}
+	} else {
+		output = c.ZeroWriter(c.ZeroDiode(writer))
+	}
+
+	// Sets up the additional syslog output.
+	syslog, err := loggers.setupSyslog(c)
+	if syslog != nil {
+		output = zerolog.MultiLevelWriter(output,
+			NewZeroLogSyslog(c, syslog))
+	}
+	var oerr error
+	if c.IsFileOutputEnabled() {
+		var file *FileWriter
+		if file, oerr = loggers.setupFile(c); oerr == nil {
+			output = zerolog.MultiLevelWriter(output,
+				c.fileConfig().ZeroWriter(file))
+		}
+	}
+	router, rerr := loggers.setupRouter(c)
+	if router != nil {
+		output = NewZeroLogLevels(c, output, router)
+	}
+	loggers.zeroFlush = nil
+	if c.IsDedupEnabled() {
+		dedup := NewZeroLogDedup(c, output)
+		loggers.zeroFlush = dedup.Flush
+		output = dedup
+	}
+	if c.ExitFunc != nil {
+		output = &ZeroLogExit{writer: output, exit: c.ExitFunc}
+	}
+	logger = logger.Output(output)
+
+	context := logger.With()
+	fields, ferr := c.StaticFields()
+	if len(fields) > 0 {
+		context = context.Fields(fields)
+	}
+	if !c.IsTimeNone() {
+		context = context.Timestamp()
+	}
+	if c.Caller {
+		context = context.CallerWithSkipFrameCount(
+			zerolog.CallerSkipFrameCount + c.CallerSkip)
+	}
+
+	logger = context.Logger()
+	if samplers := loggers.setupSamplers(c); samplers != nil {
+		logger = logger.Sample(samplers)
+	}
+	if modules != nil {
+		logger = logger.Hook(&ZeroLogModules{
+			modules: modules, skip: c.CallerSkip,
+		})
+	} else {
+		logger = logger.Hook(&ZeroLogLevel{level: &loggers.verbose})
+	}
+	if c.IsSamplingEnabled() {
+		logger = logger.Hook(NewZeroLogSampleHook(c))
+	}
+	if c.IsCallerLevelEnabled() {
+		logger = logger.Hook(NewZeroLogCallerHook(c))
+	}
+	if len(c.ParseInfoFields()) > 0 {
+		logger = logger.Hook(NewZeroLogInfoHook(c))
+	}
+	if c.IsStacktraceEnabled() {
+		logger = logger.Hook(NewZeroLogStackHook(c))
+	}
+	logger = c.setupZeroHooks(logger)
+	loggers.zero = &logger
+	if err != nil {
+		logger.Warn().Err(err).Msg("setting up syslog")
+	}
+	if oerr != nil {
+		logger.Warn().Err(oerr).Msg("setting up file output")
+	}
+	if rerr != nil {
+		logger.Warn().Err(rerr).Msg("setting up level writers")
+	}
+	if _, err := c.ParseTimeLocation(); err != nil {
+		logger.Warn().Err(err).Msg("setting up time location")
+	}
+	if _, err := c.ParseJSONPretty(); err != nil {
+		logger.Warn().Err(err).Msg("setting up json pretty")
+	}
+	if _, err := c.ParseFieldMap(); err != nil {
+		logger.Warn().Err(err).Msg("setting up field map")
+	}
+	if _, err := c.ParseRedactFields(); err != nil {
+		logger.Warn().Err(err).Msg("setting up redact fields")
+	}
+	if ferr != nil {
+		logger.Debug().Err(ferr).Msg("omitting hostname field")
+	}
+
+	return logger
+}
 
+// ZeroWriter creates the zerolog output writer for the given writer. It sets
+// up the time format as well as the color and order mode of the formatter.
+func (c *Config) ZeroWriter(writer io.Writer) io.Writer {
 	switch c.Formatter {
 	case FormatterText:
-		color := c.ColorMode.Parse(IsTerminal(writer))
-		logger = logger.Output(zerolog.ConsoleWriter{
+		color := c.ParseColorMode(writer)
+		console := zerolog.ConsoleWriter{
 			Out:        writer,
-			NoColor:    color == ColorOff,
-			TimeFormat: c.TimeFormat,
-		})
+			NoColor:    color.CheckFlag(ColorOff),
+			TimeFormat: c.ParseTimeLayout(time.Kitchen),
+		}
+		if format := c.ParseTimeFormat(); format != console.TimeFormat {
+			console.FormatTimestamp = c.Setup(writer).FormatTimestamp
+		}
+		if c.IsRedactEnabled() {
+			console.FormatPrepare = c.Setup(writer).RedactEvent
+		}
+		console.FieldsExclude = c.ExcludeFields
+		if len(c.Text.PartsOrder) > 0 {
+			console.PartsOrder = c.Text.PartsOrder
+		}
+		console.PartsExclude = c.Text.PartsExclude
+		return console
 	case FormatterJSON:
-		logger = logger.Output(writer)
+		if pretty, _ := c.ParseJSONPretty(); pretty {
+			return c.zeroJSON(&ZeroLogIndent{writer: writer})
+		}
+		return c.zeroJSON(writer)
+	case FormatterECS:
+		return c.zeroJSON(NewZeroLogECS(c, writer))
+	case FormatterGCP:
+		return c.zeroJSON(NewZeroLogGCP(c, writer))
+	case FormatterGELF:
+		return c.zeroJSON(NewZeroLogGELF(c, writer))
 	case FormatterPretty:
 		fallthrough
 	default:
-		logger = logger.Output(NewZeroLogPretty(c, writer))
+		return NewZeroLogPretty(c, writer)
 	}
+}
 
-	context := logger.With().Timestamp()
-	if c.Caller {
-		context = context.Caller()
+// zeroJSON wraps the given zerolog JSON writer to omit the excluded and to
+// redact the sensitive fields as configured.
+func (c *Config) zeroJSON(writer io.Writer) io.Writer {
+	return c.ZeroExclude(c.ZeroRedact(writer))
+}
+
+// ZeroLogIndent is a zerolog writer re-indenting each JSON event using two
+// spaces for local debugging.
+type ZeroLogIndent struct {
+	// writer is the writer for the indented JSON events.
+	writer io.Writer
+}
+
+// Write re-indents the given JSON event and writes it to the underlying
+// writer.
+func (w *ZeroLogIndent) Write(p []byte) (int, error) {
+	buffer := &bytes.Buffer{}
+	if err := json.Indent(buffer, bytes.TrimRight(p, "\n"), "", "  "); err != nil {
+		return 0, fmt.Errorf("cannot indent event: %w", err)
 	}
+	buffer.WriteByte('\n')
 
-	c.logger = context.Logger()
+	if _, err := w.writer.Write(buffer.Bytes()); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
 
-	return c
+// setupZeroGlobals sets up the global field names, the global time field
+// format, and the global error marshal function of zerolog, since zerolog does
+// not support these settings per logger. The global settings are guarded by
+// the loggers mutex to synchronize concurrent setups of different configs,
+// but they are shared by all zerolog loggers of the process.
+func (c *Config) setupZeroGlobals() {
+	loggersMutex.Lock()
+	defer loggersMutex.Unlock()
+
+	// Sets up the global error field name used by `Err` consistently.
+	if name := c.ParseErrorName(); zerolog.ErrorFieldName != name {
+		zerolog.ErrorFieldName = name
+	}
+	// Sets up the global error marshal function expanding error causes.
+	c.setupZeroErrorCauses()
+	// Sets up the global field names renamed via the field map consistently.
+	names, _ := c.ParseFieldMap()
+	timeName := FieldName(names, FieldKeyTime, "time")
+	if zerolog.TimestampFieldName != timeName {
+		zerolog.TimestampFieldName = timeName
+	}
+	levelName := FieldName(names, FieldKeyLevel, "level")
+	if zerolog.LevelFieldName != levelName {
+		zerolog.LevelFieldName = levelName
+	}
+	messageName := FieldName(names, FieldKeyMessage, "message")
+	if zerolog.MessageFieldName != messageName {
+		zerolog.MessageFieldName = messageName
+	}
+	// Sets up the global time field format used by `Timestamp` consistently.
+	current := zerolog.TimeFieldFormat
+	if format := c.ZeroTimeFieldFormat(current); current != format {
+		zerolog.TimeFieldFormat = format
+	}
+}
+
+// ZeroLogSplit is a level writer routing log events by level to a low and a
+// high level writer. Events without level information are written to the low
+// level writer.
+type ZeroLogSplit struct {
+	// level is the split level.
+	level Level
+	// low is the writer for events below the split level.
+	low io.Writer
+	// high is the writer for events at or above the split level.
+	high io.Writer
+}
+
+// Write writes the given event to the low level writer.
+func (w *ZeroLogSplit) Write(p []byte) (int, error) {
+	return w.low.Write(p)
+}
+
+// WriteLevel writes the given event to the writer responsible for the given
+// zerolog level.
+func (w *ZeroLogSplit) WriteLevel(level zerolog.Level, p []byte) (int, error) {
+	if ZeroLevel(level) <= w.level {
+		return w.high.Write(p)
+	}
+	return w.low.Write(p)
+}
+
+// ZeroLogExit is a level writer calling the exit function after fatal events.
+// Since zerolog closes the writer of the logger after writing a fatal event
+// right before calling `os.Exit`, the exit function is called on closing the
+// writer. This way, the fatal event is written before the exit function is
+// called, and an exit function that does not return, e.g. by panicking,
+// prevents the exit. An exit function returning normally cannot prevent the
+// exit, since zerolog is calling `os.Exit` unconditionally afterwards.
+type ZeroLogExit struct {
+	// writer is the underlying writer.
+	writer io.Writer
+	// exit is the function called with the exit code after fatal events.
+	exit func(code int)
+	// fatal is defining whether a fatal event was written.
+	fatal atomic.Bool
+}
+
+// Write writes the given event to the underlying writer.
+func (w *ZeroLogExit) Write(p []byte) (int, error) {
+	return w.writer.Write(p)
+}
+
+// WriteLevel writes the given event with the given level to the underlying
+// writer remembering fatal events.
+func (w *ZeroLogExit) WriteLevel(level zerolog.Level, p []byte) (int, error) {
+	if level == zerolog.FatalLevel {
+		w.fatal.Store(true)
+	}
+	if writer, ok := w.writer.(zerolog.LevelWriter); ok {
+		return writer.WriteLevel(level, p)
+	}
+	return w.writer.Write(p)
+}
+
+// Close calls the exit function, if a fatal event was written before. The
+// underlying writer is not closed, since it is owned by the caller, e.g.
+// `os.Stderr` in the default setup, and must stay usable when the exit is
+// prevented.
+func (w *ZeroLogExit) Close() error {
+	if w.fatal.Swap(false) {
+		w.exit(1)
+	}
+	return nil
+}
+
+// ZeroLogSyslog is a level writer sending log events to a syslog endpoint.
+// The events are formatted using the configured formatter without colors.
+type ZeroLogSyslog struct {
+	// syslog is the syslog writer.
+	syslog *SyslogWriter
+	// writer is the formatting writer writing to syslog.
+	writer io.Writer
+	// level is the level of the event currently written.
+	level Level
+	// mutex is the mutex to synchronize writes.
+	mutex sync.Mutex
 }
 
-// ZeroLogger returns the zerolog logger.
+// NewZeroLogSyslog creates a new syslog level writer for zerolog using the
+// given config and syslog writer.
+func NewZeroLogSyslog(c *Config, syslog *SyslogWriter) *ZeroLogSyslog {
+	w := &ZeroLogSyslog{syslog: syslog}
+	w.writer = c.ZeroWriter(zeroSyslogWriter{w})
+	return w
+}
+
+// Write writes the given event with informational severity to syslog.
+func (w *ZeroLogSyslog) Write(p []byte) (int, error) {
+	return w.WriteLevel(zerolog.NoLevel, p)
+}
+
+// WriteLevel formats the given event and writes it with the syslog severity
+// corresponding to the given zerolog level to syslog.
+func (w *ZeroLogSyslog) WriteLevel(
+	level zerolog.Level, p []byte,
+) (int, error) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	w.level = ZeroLevel(level)
+	return w.writer.Write(p)
+}
+
+// zeroSyslogWriter is writing formatted events to syslog using the level of
+// the event currently written.
+type zeroSyslogWriter struct {
+	*ZeroLogSyslog
+}
+
+// Write writes the formatted event to syslog.
+func (w zeroSyslogWriter) Write(p []byte) (int, error) {
+	return w.syslog.WriteLevel(w.level, p)
+}
+
+// ZeroLogModules is a hook discarding log events below the module specific
+// log level of the caller package.
+type ZeroLogModules struct {
+	// modules are the module specific log levels.
+	modules *Modules
+	// skip is the number of additional caller frames to skip.
+	skip int
+}
+
+// Run discards the given log event, if the log level of the event is not
+// enabled for the caller package.
+func (h *ZeroLogModules) Run(event *zerolog.Event, level zerolog.Level, _ string) {
+	frame := CallerFrame(h.skip)
+	if !h.modules.Enabled(FramePackage(frame), ZeroLevel(level)) {
+		event.Discard()
+	}
+}
+
+// ZeroLogLevel is a zerolog hook discarding log events above the current log
+// level of the config, i.e. events that are more verbose. It allows to change
+// the log level of zerolog loggers that are already handed out.
+type ZeroLogLevel struct {
+	// level is the current log level.
+	level *atomic.Int32
+}
+
+// Run discards the given log event, if the log level of the event is not
+// enabled by the current log level.
+func (h *ZeroLogLevel) Run(event *zerolog.Event, level zerolog.Level, _ string) {
+	if ZeroLevel(level) > Level(h.level.Load()) {
+		event.Discard()
+	}
+}
+
+// ToZeroLevel converts the given log level to the corresponding zerolog level.
+func ToZeroLevel(level Level) zerolog.Level {
+	switch level {
+	case PanicLevel:
+		return zerolog.PanicLevel
+	case FatalLevel:
+		return zerolog.FatalLevel
+	case ErrorLevel:
+		return zerolog.ErrorLevel
+	case WarnLevel:
+		return zerolog.WarnLevel
+	case DebugLevel:
+		return zerolog.DebugLevel
+	case TraceLevel:
+		return zerolog.TraceLevel
+	case InfoLevel, FieldLevel:
+		fallthrough
+	default:
+		return zerolog.InfoLevel
+	}
+}
+
+// ZeroLevel converts the given zerolog level to the corresponding log level.
+// Events without level are mapped to the info level.
+func ZeroLevel(level zerolog.Level) Level {
+	switch level {
+	case zerolog.PanicLevel:
+		return PanicLevel
+	case zerolog.FatalLevel:
+		return FatalLevel
+	case zerolog.ErrorLevel:
+		return ErrorLevel
+	case zerolog.WarnLevel:
+		return WarnLevel
+	case zerolog.DebugLevel:
+		return DebugLevel
+	case zerolog.TraceLevel:
+		return TraceLevel
+	case zerolog.InfoLevel, zerolog.NoLevel, zerolog.Disabled:
+		fallthrough
+	default:
+		return InfoLevel
+	}
+}
+
+// ZeroLogger returns the zerolog logger set up by the config. If no zerolog
+// logger was set up, a default zerolog logger writing to `os.Stderr` is set
+// up first.
 func (c *Config) ZeroLogger() zerolog.Logger {
-	return c.logger.(zerolog.Logger)
+	if logger, ok := c.LookupZeroLogger(); ok {
+		return logger
+	}
+
+	loggers := c.setupLoggers()
+	loggers.mutex.Lock()
+	defer loggers.mutex.Unlock()
+	if loggers.zero != nil {
+		return *loggers.zero
+	}
+	return c.setupZero(loggers, zerolog.New(os.Stderr), os.Stderr)
 }
 
+// LookupZeroLogger returns the zerolog logger set up by the config and
+// whether a zerolog logger was set up.
+func (c *Config) LookupZeroLogger() (zerolog.Logger, bool) {
+	loggers := c.lookupLoggers()
+	if loggers == nil {
+		return zerolog.Nop(), false
+	}
+
+	loggers.mutex.RLock()
+	defer loggers.mutex.RUnlock()
+	if loggers.zero == nil {
+		return zerolog.Nop(), false
+	}
+	return *loggers.zero, true
+}
+
+// FieldDuplicate is the field name of the duplicate field key noted at debug
+// level by the pretty formatter when dropping duplicate values.
+const FieldDuplicate = "duplicate"
+
 // ZeroLogPretty formats logs into a pretty format.
 type ZeroLogPretty struct {
 	// Setup provides the setup for formatting logs.
 	*Setup
 	// ConsoleWriter is the console writer used for writing logs.
 	zerolog.ConsoleWriter
+	// level is the log level set up for noting dropped duplicate fields.
+	level zerolog.Level
 }
 
 func NewZeroLogPretty(c *Config, writer io.Writer) *ZeroLogPretty {
 	setup := c.Setup(writer)
 	return &ZeroLogPretty{
 		Setup: setup,
+		level: c.ParseZeroLevel(),
 		ConsoleWriter: zerolog.ConsoleWriter{
 			Out:                 writer,
 			TimeFormat:          setup.TimeFormat,
@@ -93,92 +555,504 @@ func NewZeroLogPretty(c *Config, writer io.Writer) *ZeroLogPretty {
 			FormatErrFieldValue: setup.FormatErrFieldValue,
 			FormatFieldName:     setup.FormatFieldName,
 			FormatFieldValue:    setup.FormatFieldValue,
+			FieldsOrder:         setup.FieldOrder,
+			FieldsExclude: slices.Concat(setup.TrailingFields(),
+				setup.ExcludeFields, []string{FieldStack}),
+			FormatExtra:   setup.FormatExtra,
+			FormatPrepare: setup.RedactEvent,
 		},
 	}
 }
 
+// Write writes the given JSON event printing the fields in the order of the
+// pretty formatter, i.e. in the order of the event if the order mode is off.
+// Duplicate field keys are collapsed to the last value, unless all values are
+// kept, noting the dropped values at debug level.
+func (w *ZeroLogPretty) Write(p []byte) (int, error) {
+	keys, values := []string{}, []json.RawMessage{}
+	var dups map[string][]json.RawMessage
+	level := ""
+	if _, err := rewriteJSON(p, func(
+		key string, value json.RawMessage,
+	) (json.RawMessage, bool) {
+		if index := slices.Index(keys, key); index >= 0 {
+			if dups == nil {
+				dups = map[string][]json.RawMessage{}
+			}
+			dups[key] = append(dups[key], values[index])
+			values[index] = value
+		} else {
+			keys, values = append(keys, key), append(values, value)
+		}
+		if key == zerolog.LevelFieldName {
+			_ = json.Unmarshal(value, &level)
+		}
+		return nil, false
+	}); err != nil {
+		return w.ConsoleWriter.Write(p)
+	}
+
+	console := w.ConsoleWriter
+	console.FieldsOrder = w.FieldKeys(keys)
+	keep := w.KeepDuplicates && len(dups) > 0
+	if keep || w.FieldSep() != DefaultFieldSeparator {
+		fields := slices.DeleteFunc(slices.Clone(console.FieldsOrder),
+			func(field string) bool {
+				return zeroPartField(field) ||
+					slices.Contains(console.FieldsExclude, field)
+			})
+		if !keep {
+			dups = nil
+		}
+		extra := console.FormatExtra
+		console.FieldsExclude = slices.Concat(console.FieldsExclude, fields)
+		console.FormatExtra = func(
+			event map[string]any, buffer *bytes.Buffer,
+		) error {
+			w.FormatFields(event, fields, dups, buffer)
+			return extra(event, buffer)
+		}
+	}
+	if level != "" && w.ColorMode.CheckFlag(ColorMessages) {
+		console.FormatMessage = w.FormatLevelMessage(ParseLevel(level))
+	}
+
+	n, err := console.Write(p)
+	if err == nil && !keep && len(dups) > 0 &&
+		w.level <= zerolog.DebugLevel {
+		err = w.noteDuplicates(keys, values, dups)
+	}
+	return n, err
+}
+
+// noteDuplicates writes a debug note for each field key of the event with
+// dropped duplicate values using the timestamp of the event.
+func (w *ZeroLogPretty) noteDuplicates(
+	keys []string, values []json.RawMessage,
+	dups map[string][]json.RawMessage,
+) error {
+	for _, key := range keys {
+		if _, ok := dups[key]; !ok || zeroPartField(key) {
+			continue
+		}
+
+		note := map[string]any{
+			zerolog.LevelFieldName: zerolog.LevelFieldMarshalFunc(
+				zerolog.DebugLevel),
+			zerolog.MessageFieldName: "duplicate field dropped",
+			FieldDuplicate:           key,
+		}
+		if index := slices.Index(keys, zerolog.TimestampFieldName); index >= 0 {
+			note[zerolog.TimestampFieldName] = values[index]
+		}
+		data, err := json.Marshal(note)
+		if err != nil {
+			return err
+		} else if _, err := w.ConsoleWriter.Write(data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// zeroValue decodes the given raw JSON value of a zerolog event the same way
+// as the zerolog console writer, i.e. keeping numbers as JSON numbers.
+func zeroValue(raw json.RawMessage) any {
+	var value any
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	if err := decoder.Decode(&value); err != nil {
+		return string(raw)
+	}
+	return value
+}
+
+// zeroPartField returns whether the given field is printed as part by the
+// zerolog console writer instead of as field.
+func zeroPartField(field string) bool {
+	switch field {
+	case zerolog.LevelFieldName, zerolog.TimestampFieldName,
+		zerolog.MessageFieldName, zerolog.CallerFieldName:
+		return true
+	}
+	return false
+}
+
+// FormatTimestamp formats the timestamp supporting RFC3339 strings, strings
+// using a custom zerolog time field format layout, as well as unix timestamps
+// in seconds, milliseconds, microseconds, and nanoseconds. If timestamps are
+// omitted, an empty string is returned.
 func (s *Setup) FormatTimestamp(i any) string {
-	if timestamp, ok := i.(string); ok {
-		if ttime, err := time.Parse(time.RFC3339, timestamp); err == nil {
-			return ttime.Format(s.TimeFormat)
+	if s.TimeFormat == TimeFormatNone {
+		return ""
+	}
+
+	switch timestamp := i.(type) {
+	case string:
+		if ttime, ok := ZeroParseTime(zerolog.TimeFieldFormat,
+			timestamp); ok {
+			return s.FormatTime(ttime)
 		}
 		return timestamp
+	case json.Number:
+		if epoch, err := timestamp.Int64(); err == nil {
+			return s.FormatTime(
+				ZeroEpochTime(zerolog.TimeFieldFormat, epoch))
+		}
+		return timestamp.String()
 	}
-	return fmt.Sprintf("%v", i)
+	return formatAny(i)
 }
 
-// Format formats the log entry.
+// ZeroParseTime parses the given timestamp string using the given zerolog
+// time field format layout falling back to RFC3339.
+func ZeroParseTime(format, timestamp string) (time.Time, bool) {
+	if format != "" && format != time.RFC3339 {
+		if ttime, err := time.Parse(format, timestamp); err == nil {
+			return ttime, true
+		}
+	}
+	if ttime, err := time.Parse(time.RFC3339, timestamp); err == nil {
+		return ttime, true
+	}
+	return time.Time{}, false
+}
+
+// ZeroEpochTime returns the time of the given unix timestamp using the unit
+// of the given zerolog time field format. If the format is not numeric, the
+// unit is derived from the magnitude of the timestamp.
+func ZeroEpochTime(format string, epoch int64) time.Time {
+	switch format {
+	case zerolog.TimeFormatUnix:
+		return time.Unix(epoch, 0)
+	case zerolog.TimeFormatUnixMs:
+		return time.UnixMilli(epoch)
+	case zerolog.TimeFormatUnixMicro:
+		return time.UnixMicro(epoch)
+	case zerolog.TimeFormatUnixNano:
+		return time.Unix(0, epoch)
+	}
+
+	switch magnitude := max(epoch, -epoch); {
+	case magnitude < 1e11:
+		return time.Unix(epoch, 0)
+	case magnitude < 1e14:
+		return time.UnixMilli(epoch)
+	case magnitude < 1e17:
+		return time.UnixMicro(epoch)
+	default:
+		return time.Unix(0, epoch)
+	}
+}
+
+// ZeroTimeFieldFormat returns the zerolog time field format for the config
+// based on the given current time field format. Numeric time formats are
+// mapped to the corresponding zerolog unix time formats, while for all other
+// time formats the current time field format is kept, if the pretty formatter
+// understands it, and else reset to RFC3339.
+func (c *Config) ZeroTimeFieldFormat(current string) string {
+	switch c.ParseTimeFormat() {
+	case TimeFormatUnix:
+		return zerolog.TimeFormatUnix
+	case TimeFormatUnixMs:
+		return zerolog.TimeFormatUnixMs
+	case TimeFormatUnixNs:
+		return zerolog.TimeFormatUnixNano
+	}
+
+	switch current {
+	case zerolog.TimeFormatUnix, zerolog.TimeFormatUnixMs,
+		zerolog.TimeFormatUnixMicro, zerolog.TimeFormatUnixNano:
+		return time.RFC3339
+	default:
+		return current
+	}
+}
+
+// FormatLevel formats the log level using the precomputed level strings, if
+// they match the current color mode.
 func (s *Setup) FormatLevel(i any) string {
-	if level, ok := i.(string); ok {
-		level := ParseLevel(level)
-		buffer := NewBuffer(s, &bytes.Buffer{})
-		if s.ColorMode.CheckFlag(ColorLevels) {
-			buffer.WriteColored(s.LevelColors[level], s.LevelNames[level])
-		} else {
-			buffer.WriteString(s.LevelNames[level])
+	if name, ok := i.(string); ok {
+		level := ParseLevel(name)
+		if int(level) < len(s.levels) && s.levelsMode == s.ColorMode {
+			return s.levels[level]
 		}
-		return buffer.String()
+		return s.format(func(buffer *Buffer) *Buffer {
+			return buffer.WriteLevel(level)
+		})
 	}
-	return fmt.Sprintf("%v", i)
+	return formatAny(i)
 }
 
-// FormatCaller formats the caller.
+// FormatCaller formats the caller provided by the global caller hook as well
+// as by explicit per event caller calls. The caller file is shortened to the
+// configured number of trailing path elements. Missing callers are omitted.
 func (s *Setup) FormatCaller(i any) string {
-	if !s.Caller {
+	switch caller := i.(type) {
+	case nil:
 		return ""
-	} else if caller, ok := i.(string); ok {
-		return `[` + caller + `]`
+	case string:
+		if caller == "" {
+			return ""
+		} else if index := strings.LastIndex(caller, ":"); index >= 0 {
+			return `[` + s.CallerFile(caller[:index]) + caller[index:] + `]`
+		}
+		return `[` + s.CallerFile(caller) + `]`
+	default:
+		return "[" + formatAny(i) + "]"
 	}
-	return fmt.Sprintf("[%v]", i)
 }
 
-// FormatMessage formats the message.
-func (*Setup) FormatMessage(i any) string {
+// FormatMessage formats the message collapsing newlines and truncating it to
+// the maximum message length as configured.
+func (s *Setup) FormatMessage(i any) string {
 	if message, ok := i.(string); ok {
-		return message
+		return s.Message(message)
+	}
+	return formatAny(i)
+}
+
+// FormatLevelMessage returns a message formatter formatting the message like
+// `FormatMessage` using the color of the given log level, if coloring of
+// messages is enabled.
+func (s *Setup) FormatLevelMessage(level Level) zerolog.Formatter {
+	return func(i any) string {
+		if message, ok := i.(string); ok {
+			return s.format(func(buffer *Buffer) *Buffer {
+				return buffer.WriteMessage(level, message)
+			})
+		}
+		return formatAny(i)
 	}
-	return fmt.Sprintf("%v", i)
 }
 
-// FormatErrFieldName formats the error field name.
+// FormatErrFieldName formats the error field name returning cached strings
+// for repeated names.
 func (s *Setup) FormatErrFieldName(i any) string {
 	if name, ok := i.(string); ok {
-		buffer := NewBuffer(s, &bytes.Buffer{})
+		return s.cachedName(s.errNames, name, s.formatErrFieldName)
+	}
+	return formatAny(i) + "="
+}
+
+// formatErrFieldName formats the given error field name.
+func (s *Setup) formatErrFieldName(name string) string {
+	name = s.Escape(name)
+	return s.format(func(buffer *Buffer) *Buffer {
 		if s.ColorMode.CheckFlag(ColorFields) {
-			buffer.WriteColored(ColorError, name)
+			buffer.WriteColored(s.LevelColors[ErrorLevel], name)
 		} else {
 			buffer.WriteString(name)
 		}
-		return buffer.WriteByte('=').String()
-	}
-	return fmt.Sprintf("%v=", i)
+		return buffer.WriteAssign(name)
+	})
 }
 
-// FormatErrFieldValue formats the error field value.
-func (*Setup) FormatErrFieldValue(i any) string {
-	if value, ok := i.(string); ok {
-		return value
+// FormatErrFieldValue formats the error field value truncated to the maximum
+// field length. Error chains are formatted with their causes, while numbers,
+// booleans, and nulls are formatted unquoted.
+func (s *Setup) FormatErrFieldValue(i any) string {
+	switch value := i.(type) {
+	case string:
+		if unquoted := zeroUnquote(value); unquoted != value {
+			return strconv.Quote(s.Truncate(unquoted))
+		}
+		return s.Truncate(value)
+	case json.Number:
+		return value.String()
+	case []byte:
+		if chain, ok := s.FormatErrorChain(value); ok {
+			return chain
+		}
+		return s.formatRawValue(value)
 	}
-	return fmt.Sprintf("%v", i)
+	return s.format(func(buffer *Buffer) *Buffer {
+		return buffer.WriteValue(i)
+	})
 }
 
-// FormatFieldName formats the field name.
+// FormatFieldName formats the field name returning cached strings for
+// repeated names.
 func (s *Setup) FormatFieldName(i any) string {
 	if field, ok := i.(string); ok {
-		buffer := NewBuffer(s, &bytes.Buffer{})
+		return s.cachedName(s.fieldNames, field, s.formatFieldName)
+	}
+	return formatAny(i) + "="
+}
+
+// formatFieldName formats the given field name.
+func (s *Setup) formatFieldName(field string) string {
+	field = s.Escape(field)
+	return s.format(func(buffer *Buffer) *Buffer {
 		if s.ColorMode.CheckFlag(ColorFields) {
-			buffer.WriteColored(ColorField, field)
+			buffer.WriteColored(s.LevelColors[FieldLevel], field)
 		} else {
 			buffer.WriteString(field)
 		}
-		return buffer.WriteByte('=').String()
+		return buffer.WriteAssign(field)
+	})
+}
+
+// maxNameCache is the maximum number of formatted names cached per cache to
+// not grow unbounded on dynamic field names.
+const maxNameCache = 1024
+
+// nameCache is a bounded cache of formatted field names.
+type nameCache struct {
+	// names are the formatted names by name.
+	names sync.Map
+	// size is the number of cached names.
+	size atomic.Int32
+}
+
+// cachedName returns the formatted name of the given name from the given
+// cache, formatting and caching it on first use. If the color mode changed
+// since setup or the cache is full, the name is formatted as is.
+func (s *Setup) cachedName(
+	cache *nameCache, name string, format func(string) string,
+) string {
+	if cache == nil || s.levelsMode != s.ColorMode {
+		return format(name)
+	} else if formatted, ok := cache.names.Load(name); ok {
+		return formatted.(string)
+	}
+
+	formatted := format(name)
+	if cache.size.Load() < maxNameCache {
+		if _, loaded := cache.names.LoadOrStore(name, formatted); !loaded {
+			cache.size.Add(1)
+		}
+	}
+	return formatted
+}
+
+// formatAny formats the given value the same way as `fmt.Sprint` using fast
+// paths for the values passed by the zerolog console writer.
+func formatAny(i any) string {
+	switch value := i.(type) {
+	case nil:
+		return NilValue
+	case string:
+		return value
+	case json.Number:
+		return value.String()
+	case bool:
+		return strconv.FormatBool(value)
+	case int:
+		return strconv.Itoa(value)
+	case float64:
+		return strconv.FormatFloat(value, 'g', -1, 64)
 	}
-	return fmt.Sprintf("%v=", i)
+	return fmt.Sprint(i)
 }
 
-func (*Setup) FormatFieldValue(i any) string {
-	if value, ok := i.(string); ok {
-		return `"` + value + `"`
+// FormatFieldValue formats the field value matching the pretty formatting of
+// `Buffer.WriteValue`. String values and raw JSON values, i.e. objects and
+// arrays, are truncated to the maximum field length, while numbers and
+// booleans are kept as is unquoted. Nil values are formatted as `<nil>`.
+func (s *Setup) FormatFieldValue(i any) string {
+	switch value := i.(type) {
+	case string:
+		return s.Quote(s.Truncate(zeroUnquote(value)))
+	case json.Number:
+		return value.String()
+	case []byte:
+		return s.formatRawValue(value)
+	}
+	return s.format(func(buffer *Buffer) *Buffer {
+		return buffer.WriteValue(i)
+	})
+}
+
+// formatRawValue formats the given raw JSON value of the zerolog console
+// writer. Nulls are formatted as `<nil>` while booleans are kept as is and
+// objects and arrays are truncated to the maximum field length.
+func (s *Setup) formatRawValue(value []byte) string {
+	switch raw := string(value); raw {
+	case "null":
+		return NilValue
+	case "true", "false":
+		return raw
+	default:
+		return s.Truncate(raw)
+	}
+}
+
+// zeroUnquote returns the given string value unquoted, if it was quoted by
+// the zerolog console writer, since it contained special characters.
+func zeroUnquote(value string) string {
+	if strings.HasPrefix(value, `"`) {
+		if unquoted, err := strconv.Unquote(value); err == nil {
+			return unquoted
+		}
+	}
+	return value
+}
+
+// FormatExtra formats the trailing build info fields followed by the stack
+// trace of the given event.
+func (s *Setup) FormatExtra(event map[string]any, buffer *bytes.Buffer) error {
+	if err := s.FormatInfoFields(event, buffer); err != nil {
+		return err
+	}
+	return s.FormatStack(event, buffer)
+}
+
+// FormatInfoFields formats the trailing build info fields of the given event
+// placing them after the user fields in the configured order.
+func (s *Setup) FormatInfoFields(event map[string]any, buffer *bytes.Buffer) error {
+	for _, field := range s.TrailingFields() {
+		if value, ok := event[field]; ok {
+			buffer.WriteString(s.FieldSep())
+			buffer.WriteString(s.FormatFieldName(field))
+			buffer.WriteString(s.FormatFieldValue(value))
+		}
+	}
+	return nil
+}
+
+// FormatFields formats the given fields of the given event in the given order
+// using the field separator, since the zerolog console writer only supports
+// spaces. The given duplicate raw values, if any, are formatted redacted in
+// front of the event value of a field.
+func (s *Setup) FormatFields(
+	event map[string]any, fields []string,
+	dups map[string][]json.RawMessage, buffer *bytes.Buffer,
+) {
+	for _, field := range fields {
+		for _, raw := range dups[field] {
+			s.FormatField(field, s.Redact(field, zeroValue(raw)), buffer)
+		}
+		if value, ok := event[field]; ok {
+			s.FormatField(field, value, buffer)
+		}
+	}
+}
+
+// FormatField formats the given field with the given value using the field
+// separator. String values are passed quoted when needed and other values as
+// raw JSON to the field value formatters matching the zerolog console writer.
+func (s *Setup) FormatField(field string, value any, buffer *bytes.Buffer) {
+	name, format := s.FormatFieldName, s.FormatFieldValue
+	if field == zerolog.ErrorFieldName {
+		name, format = s.FormatErrFieldName, s.FormatErrFieldValue
+	}
+
+	buffer.WriteString(s.FieldSep())
+	buffer.WriteString(name(field))
+	switch value := value.(type) {
+	case string:
+		if s.needsQuote(value) {
+			value = strconv.Quote(value)
+		}
+		buffer.WriteString(format(value))
+	case json.Number:
+		buffer.WriteString(format(value))
+	default:
+		if raw, err := zerolog.InterfaceMarshalFunc(value); err == nil {
+			buffer.WriteString(format(raw))
+		} else {
+			buffer.WriteString(format(fmt.Sprintf("[error: %v]", err)))
+		}
 	}
-	return fmt.Sprintf("\"%v\"", i)
 }
@@ -2,11 +2,19 @@ package log_test
 
 import (
 	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
 	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strconv"
 	"testing"
 	"time"
 
 	"github.com/rs/zerolog"
+	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/tkrop/go-testing/mock"
@@ -28,8 +36,9 @@ func TestSetupZero(t *testing.T) {
 			logger := config.Log.SetupZero(os.Stderr).ZeroLogger()
 
 			// Then
+			assert.Equal(t, zerolog.TraceLevel, logger.GetLevel())
 			assert.Equal(t, log.ParseLevel(param.expectLogLevel),
-				log.ParseLevel(logger.GetLevel().String()))
+				log.ParseLevel(config.Log.GetLevel()))
 
 			// Check if the writer is set up correctly.
 			writer := test.NewAccessor(logger).Get("w")
@@ -65,19 +74,122 @@ func TestSetupZero(t *testing.T) {
 				assert.Equal(t, param.expectOrderMode, writer.Setup.OrderMode)
 			}
 
-			// Check if the hooks are set up with caller hook.
+			// Check if the hooks are set up with level and caller hook.
 			hooks := test.NewAccessor(logger).Get("hooks")
 			require.IsType(t, []zerolog.Hook{}, hooks)
 			hookSlice, ok := hooks.([]zerolog.Hook)
 			require.True(t, ok)
 			if param.expectLogCaller {
-				assert.Len(t, hookSlice, 2)
+				assert.Len(t, hookSlice, 3)
 			} else {
-				assert.Len(t, hookSlice, 1)
+				assert.Len(t, hookSlice, 2)
 			}
 		})
 }
 
+func TestSetupZeroLogger(t *testing.T) {
+	// Given
+	buffer := &bytes.Buffer{}
+	record := []string{}
+	config := &log.Config{
+		Level:      log.LevelInfo,
+		TimeFormat: log.TimeFormatNone,
+		ColorMode:  log.ColorModeOff,
+	}
+	logger := zerolog.New(io.Discard).Level(zerolog.ErrorLevel).
+		With().Str("service", "test").Logger().
+		Hook(&recordZeroHook{name: "preset", record: &record})
+
+	// When
+	logger = config.SetupZeroLogger(logger, buffer)
+	logger.Info().Msg("message")
+
+	// Then
+	writer := test.NewAccessor(logger).Get("w")
+	require.IsType(t, zerolog.LevelWriterAdapter{}, writer)
+	assert.IsType(t, &log.ZeroLogPretty{},
+		writer.(zerolog.LevelWriterAdapter).Writer)
+	assert.Equal(t, zerolog.TraceLevel, logger.GetLevel())
+	assert.Equal(t, []string{"preset"}, record)
+	assert.Equal(t, "INFO message service=\"test\"\n", buffer.String())
+	assert.Equal(t, logger, config.ZeroLogger())
+}
+
+type testLookupLoggerParam struct {
+	setup      func(*log.Config)
+	expectZero bool
+	expectRus  bool
+}
+
+var testLookupLoggerParams = map[string]testLookupLoggerParam{
+	"unset": {
+		setup: func(*log.Config) {},
+	},
+	"zerolog": {
+		setup: func(config *log.Config) {
+			config.SetupZero(io.Discard)
+		},
+		expectZero: true,
+	},
+	"logrus": {
+		setup: func(config *log.Config) {
+			config.SetupRus(io.Discard, logrus.New())
+		},
+		expectRus: true,
+	},
+	"both": {
+		setup: func(config *log.Config) {
+			config.SetupRus(io.Discard, logrus.New())
+			config.SetupZero(io.Discard)
+		},
+		expectZero: true,
+		expectRus:  true,
+	},
+}
+
+func TestLookupLogger(t *testing.T) {
+	test.Map(t, testLookupLoggerParams).
+		Run(func(t test.Test, param testLookupLoggerParam) {
+			// Given
+			config := &log.Config{Level: log.LevelInfo}
+			param.setup(config)
+
+			// When
+			zero, zok := config.LookupZeroLogger()
+			rus, rok := config.LookupRusLogger()
+
+			// Then
+			assert.Equal(t, param.expectZero, zok)
+			assert.Equal(t, param.expectRus, rok)
+			if !param.expectZero {
+				assert.Equal(t, zerolog.Disabled, zero.GetLevel())
+			}
+			if !param.expectRus {
+				assert.Nil(t, rus)
+			}
+		})
+}
+
+func TestZeroLoggerLazy(t *testing.T) {
+	// Given
+	config := &log.Config{Level: log.LevelWarn}
+
+	// When
+	logger := config.ZeroLogger()
+
+	// Then
+	assert.Equal(t, zerolog.TraceLevel, logger.GetLevel())
+	assert.Equal(t, log.LevelWarn, config.GetLevel())
+	writer := test.NewAccessor(logger).Get("w")
+	require.IsType(t, zerolog.LevelWriterAdapter{}, writer)
+	pretty, ok := writer.(zerolog.LevelWriterAdapter).Writer.(*log.ZeroLogPretty)
+	require.True(t, ok)
+	assert.Equal(t, os.Stderr, pretty.Out)
+	lookup, ok := config.LookupZeroLogger()
+	assert.True(t, ok)
+	assert.Equal(t, logger, lookup)
+}
+
 type testZeroLogParam struct {
 	config       log.Config
 	noTerminal   bool
@@ -97,17 +209,15 @@ var testZeroLogParams = map[string]testZeroLogParam{
 		expectResult: otime[0:26] + " " +
 			levelC(log.PanicLevel) + " panic message\n",
 	},
-	// Fatal is not testable this way since it is calling `os.Exit``. It needs
-	// to be tested in spawned process instead.
-	// "level fatal default": {
-	// 	config: log.Config{Level: "fatal"},
-	// 	setup: func(logger zerolog.Logger) {
-	// 		logger.Fatal().Msg("fatal message")
-	// 	},
-	// 	expect: test.Panic("fatal message"),
-	// 	expectResult: otime[0:26] + " " +
-	// 		levelC(log.FatalLevel) + " fatal message\n",
-	// },
+	"level fatal default": {
+		config: log.Config{Level: "fatal"},
+		setup: func(logger zerolog.Logger) {
+			logger.Fatal().Msg("fatal message")
+		},
+		expect: test.Panic("exit 1"),
+		expectResult: otime[0:26] + " " +
+			levelC(log.FatalLevel) + " fatal message\n",
+	},
 	"level error default": {
 		config: log.Config{Level: "error"},
 		setup: func(logger zerolog.Logger) {
@@ -159,17 +269,15 @@ var testZeroLogParams = map[string]testZeroLogParam{
 		expectResult: otime[0:26] + " " +
 			levelC(log.PanicLevel) + " panic message\n",
 	},
-	// Fatal is not testable this way since it is calling `os.Exit``. It needs
-	// to be tested in spawned process instead.
-	// "level fatal color-on": {
-	// 	config: log.Config{Level: "fatal", ColorMode: log.ColorModeOn},
-	// 	setup: func(logger zerolog.Logger) {
-	// 		logger.Fatal().Msg("fatal message")
-	// 	},
-	// 	expect: test.Panic("fatal message"),
-	// 	expectResult: otime[0:26] + " " +
-	// 		levelC(log.FatalLevel) + " fatal message\n",
-	// },
+	"level fatal color-on": {
+		config: log.Config{Level: "fatal", ColorMode: log.ColorModeOn},
+		setup: func(logger zerolog.Logger) {
+			logger.Fatal().Msg("fatal message")
+		},
+		expect: test.Panic("exit 1"),
+		expectResult: otime[0:26] + " " +
+			levelC(log.FatalLevel) + " fatal message\n",
+	},
 	"level error color-on": {
 		config: log.Config{Level: "error", ColorMode: log.ColorModeOn},
 		setup: func(logger zerolog.Logger) {
@@ -425,7 +533,8 @@ var testZeroLogParams = map[string]testZeroLogParam{
 			logger.Info().Caller(0).Msg("caller message")
 		},
 		expectResult: otime[0:26] + " " +
-			levelC(log.InfoLevel) + " " + "caller message\n",
+			levelC(log.InfoLevel) + " " +
+			"[" + caller(-4) + "] caller message\n",
 	},
 	"caller report": {
 		config: log.Config{Caller: true},
@@ -466,6 +575,12 @@ var testZeroLogParams = map[string]testZeroLogParam{
 	},
 }
 
+// exitPanic is an exit function panicking with the exit code to assert fatal
+// log entries in-process.
+func exitPanic(code int) {
+	panic(fmt.Sprintf("exit %d", code))
+}
+
 func TestZeroLog(t *testing.T) {
 	assert.NoError(t, terr)
 	zerolog.TimeFieldFormat = time.RFC3339Nano
@@ -481,9 +596,10 @@ func TestZeroLog(t *testing.T) {
 				SetDefaults(func(r *config.Reader[config.Config]) {
 					r.SetDefault("log.level", "trace")
 				}).GetConfig("zerolog")
+			config.Log.ExitFunc = exitPanic
 			logger := config.Log.SetupZero(buffer).ZeroLogger()
-			pretty := test.NewAccessor(logger).Get("w").(zerolog.LevelWriterAdapter).
-				Writer.(*log.ZeroLogPretty)
+			exit := test.NewAccessor(logger).Get("w").(*log.ZeroLogExit)
+			pretty := test.NewAccessor(exit).Get("writer").(*log.ZeroLogPretty)
 			pretty.Setup.ColorMode = param.config.ColorMode.Parse(!param.noTerminal)
 
 			if param.expect != nil {
@@ -506,6 +622,39 @@ func TestZeroLog(t *testing.T) {
 		})
 }
 
+// closeWriter is a writer recording whether it was closed.
+type closeWriter struct {
+	bytes.Buffer
+	closed bool
+}
+
+func (w *closeWriter) Close() error {
+	w.closed = true
+	return nil
+}
+
+func TestZeroLogExit(t *testing.T) {
+	// Given
+	writer := &closeWriter{}
+	config := &log.Config{
+		Level:     "info",
+		Formatter: log.FormatterJSON,
+		ExitFunc:  exitPanic,
+	}
+	logger := config.SetupZero(writer).ZeroLogger()
+
+	// When
+	assert.PanicsWithValue(t, "exit 1", func() {
+		logger.Fatal().Msg("fatal message")
+	})
+	logger.Info().Msg("info message")
+
+	// Then
+	assert.Contains(t, writer.String(), "fatal message")
+	assert.Contains(t, writer.String(), "info message")
+	assert.False(t, writer.closed)
+}
+
 type testSetupFormatParam struct {
 	config *log.Config
 	call   func(*log.Setup) string
@@ -748,8 +897,29 @@ var testSetupFormatParams = map[string]testSetupFormatParam{
 		call: func(s *log.Setup) string {
 			return s.FormatCaller("caller")
 		},
+		expect: "[caller]",
+	},
+	"caller missing": {
+		config: &log.Config{Caller: true},
+		call: func(s *log.Setup) string {
+			return s.FormatCaller(nil)
+		},
 		expect: "",
 	},
+	"caller empty": {
+		config: &log.Config{Caller: true},
+		call: func(s *log.Setup) string {
+			return s.FormatCaller("")
+		},
+		expect: "",
+	},
+	"caller shortened": {
+		config: &log.Config{Caller: true, CallerPaths: 2},
+		call: func(s *log.Setup) string {
+			return s.FormatCaller("/path/to/pkg/file.go:123")
+		},
+		expect: "[pkg/file.go:123]",
+	},
 	"caller report on": {
 		config: &log.Config{Caller: true},
 		call: func(s *log.Setup) string {
@@ -912,7 +1082,35 @@ var testSetupFormatParams = map[string]testSetupFormatParam{
 		call: func(s *log.Setup) string {
 			return s.FormatFieldValue(1)
 		},
-		expect: `"1"`,
+		expect: "1",
+	},
+	"field value number": {
+		config: &log.Config{},
+		call: func(s *log.Setup) string {
+			return s.FormatFieldValue(json.Number("1.5"))
+		},
+		expect: "1.5",
+	},
+	"field value bool": {
+		config: &log.Config{},
+		call: func(s *log.Setup) string {
+			return s.FormatFieldValue([]byte("true"))
+		},
+		expect: "true",
+	},
+	"field value null": {
+		config: &log.Config{},
+		call: func(s *log.Setup) string {
+			return s.FormatFieldValue([]byte("null"))
+		},
+		expect: log.NilValue,
+	},
+	"error field value number": {
+		config: &log.Config{},
+		call: func(s *log.Setup) string {
+			return s.FormatErrFieldValue(json.Number("42"))
+		},
+		expect: "42",
 	},
 }
 
@@ -930,3 +1128,404 @@ func TestSetupFormat(t *testing.T) {
 			assert.Equal(t, param.expect, result)
 		})
 }
+
+type testFormatTimestampParam struct {
+	format    string
+	timestamp any
+	expect    string
+}
+
+var testFormatTimestampParams = map[string]testFormatTimestampParam{
+	"string rfc3339": {
+		format:    time.RFC3339,
+		timestamp: itime,
+		expect:    otime[0:29],
+	},
+	"string custom layout": {
+		format:    "02.01.2006 15:04:05.000000000",
+		timestamp: "01.10.2024 23:07:13.891012345",
+		expect:    otime[0:29],
+	},
+	"string custom layout rfc3339": {
+		format:    "02.01.2006 15:04:05",
+		timestamp: itime,
+		expect:    otime[0:29],
+	},
+	"string invalid": {
+		format:    "02.01.2006 15:04:05",
+		timestamp: "2024-12-31 23:59:59",
+		expect:    "2024-12-31 23:59:59",
+	},
+
+	"number seconds": {
+		format:    zerolog.TimeFormatUnix,
+		timestamp: json.Number("1727824033"),
+		expect:    otime[0:19] + ".000000000",
+	},
+	"number millis": {
+		format:    zerolog.TimeFormatUnixMs,
+		timestamp: json.Number("1727824033891"),
+		expect:    otime[0:23] + "000000",
+	},
+	"number micros": {
+		format:    zerolog.TimeFormatUnixMicro,
+		timestamp: json.Number("1727824033891012"),
+		expect:    otime[0:26] + "000",
+	},
+	"number nanos": {
+		format:    zerolog.TimeFormatUnixNano,
+		timestamp: json.Number("1727824033891012345"),
+		expect:    otime[0:29],
+	},
+	"number seconds by magnitude": {
+		format:    time.RFC3339,
+		timestamp: json.Number("1727824033"),
+		expect:    otime[0:19] + ".000000000",
+	},
+	"number millis by magnitude": {
+		format:    time.RFC3339,
+		timestamp: json.Number("1727824033891"),
+		expect:    otime[0:23] + "000000",
+	},
+	"number micros by magnitude": {
+		format:    time.RFC3339,
+		timestamp: json.Number("1727824033891012"),
+		expect:    otime[0:26] + "000",
+	},
+	"number nanos by magnitude": {
+		format:    time.RFC3339,
+		timestamp: json.Number("1727824033891012345"),
+		expect:    otime[0:29],
+	},
+	"number invalid": {
+		format:    zerolog.TimeFormatUnix,
+		timestamp: json.Number("1727824033.5"),
+		expect:    "1727824033.5",
+	},
+}
+
+func TestFormatTimestamp(t *testing.T) {
+	test.Map(t, testFormatTimestampParams).
+		RunSeq(func(t test.Test, param testFormatTimestampParam) {
+			// Given
+			format := zerolog.TimeFieldFormat
+			t.Cleanup(func() { zerolog.TimeFieldFormat = format })
+			zerolog.TimeFieldFormat = param.format
+			s := (&log.Config{
+				TimeFormat:   "2006-01-02 15:04:05.000000000",
+				TimeLocation: "UTC",
+			}).Setup(os.Stderr)
+
+			// When
+			result := s.FormatTimestamp(param.timestamp)
+
+			// Then
+			assert.Equal(t, param.expect, result)
+		})
+}
+
+type testZeroLogCallerParam struct {
+	config log.Config
+	call   func(zerolog.Logger) string
+}
+
+var testZeroLogCallerParams = map[string]testZeroLogCallerParam{
+	"caller explicit": {
+		call: func(logger zerolog.Logger) string {
+			logger.Info().Caller().Msg("caller message")
+			return caller(-1)
+		},
+	},
+	"caller explicit skip": {
+		call: func(logger zerolog.Logger) string {
+			return logZeroCaller(logger)
+		},
+	},
+	"caller explicit spawned": {
+		call: func(logger zerolog.Logger) string {
+			spawned := logger.With().Str("key", "value").Logger()
+			spawned.Info().Caller().Msg("caller message")
+			return caller(-1)
+		},
+	},
+	"caller hook": {
+		config: log.Config{Caller: true},
+		call: func(logger zerolog.Logger) string {
+			logger.Info().Msg("caller message")
+			return caller(-1)
+		},
+	},
+	"caller hook spawned": {
+		config: log.Config{Caller: true},
+		call: func(logger zerolog.Logger) string {
+			spawned := logger.With().Str("key", "value").Logger()
+			spawned.Info().Msg("caller message")
+			return caller(-1)
+		},
+	},
+}
+
+// logZeroCaller logs a message with explicit caller skipping this helper and
+// returns the caller of this helper.
+func logZeroCaller(logger zerolog.Logger) string {
+	logger.Info().Caller(1).Msg("caller message")
+	if _, file, line, ok := runtime.Caller(1); ok {
+		return file + ":" + strconv.Itoa(line)
+	}
+	return "unknown"
+}
+
+func TestZeroLogCaller(t *testing.T) {
+	test.Map(t, testZeroLogCallerParams).
+		Run(func(t test.Test, param testZeroLogCallerParam) {
+			// Given
+			param.config.TimeFormat = "none"
+			param.config.ColorMode = log.ColorModeOff
+			param.config.CallerPaths = 1
+			buffer := &bytes.Buffer{}
+			logger := param.config.SetupZero(buffer).ZeroLogger()
+
+			// When
+			file := param.call(logger)
+
+			// Then
+			assert.Regexp(t, "^INFO \\["+
+				regexp.QuoteMeta(filepath.Base(file))+
+				"\\] caller message( key=\"value\")?\n$", buffer.String())
+		})
+}
+
+type testZeroLogPrettyOrderParam struct {
+	order  log.OrderModeString
+	fields []string
+	call   func(zerolog.Logger)
+	expect string
+}
+
+var testZeroLogPrettyOrderParams = map[string]testZeroLogPrettyOrderParam{
+	"order on reverse": {
+		order: log.OrderModeOn,
+		call: func(logger zerolog.Logger) {
+			logger.Info().Str("zone", "eu").Str("user", "alice").
+				Int("count", 1).Msg("info message")
+		},
+		expect: "INFO info message count=1 user=\"alice\" zone=\"eu\"\n",
+	},
+	"order off reverse": {
+		order: log.OrderModeOff,
+		call: func(logger zerolog.Logger) {
+			logger.Info().Str("zone", "eu").Str("user", "alice").
+				Int("count", 1).Msg("info message")
+		},
+		expect: "INFO info message zone=\"eu\" user=\"alice\" count=1\n",
+	},
+	"order on error": {
+		order: log.OrderModeOn,
+		call: func(logger zerolog.Logger) {
+			logger.Info().Str("zone", "eu").Err(assert.AnError).
+				Str("alpha", "a").Msg("info message")
+		},
+		expect: "INFO info message error=\"" + assert.AnError.Error() +
+			"\" alpha=\"a\" zone=\"eu\"\n",
+	},
+	"order off error": {
+		order: log.OrderModeOff,
+		call: func(logger zerolog.Logger) {
+			logger.Info().Str("zone", "eu").Err(assert.AnError).
+				Str("alpha", "a").Msg("info message")
+		},
+		expect: "INFO info message error=\"" + assert.AnError.Error() +
+			"\" zone=\"eu\" alpha=\"a\"\n",
+	},
+	"order on field order": {
+		order:  log.OrderModeOn,
+		fields: []string{"user", "zone"},
+		call: func(logger zerolog.Logger) {
+			logger.Info().Str("zone", "eu").Str("beta", "b").
+				Str("user", "alice").Str("alpha", "a").Msg("info message")
+		},
+		expect: "INFO info message user=\"alice\" zone=\"eu\" " +
+			"alpha=\"a\" beta=\"b\"\n",
+	},
+	"order off field order": {
+		order:  log.OrderModeOff,
+		fields: []string{"user", "zone"},
+		call: func(logger zerolog.Logger) {
+			logger.Info().Str("zone", "eu").Str("beta", "b").
+				Str("user", "alice").Str("alpha", "a").Msg("info message")
+		},
+		expect: "INFO info message user=\"alice\" zone=\"eu\" " +
+			"beta=\"b\" alpha=\"a\"\n",
+	},
+	"order off duplicate": {
+		order: log.OrderModeOff,
+		call: func(logger zerolog.Logger) {
+			logger.Info().Str("zone", "eu").Str("user", "alice").
+				Str("zone", "us").Msg("info message")
+		},
+		expect: "INFO info message zone=\"us\" user=\"alice\"\n",
+	},
+}
+
+func TestZeroLogPrettyOrder(t *testing.T) {
+	test.Map(t, testZeroLogPrettyOrderParams).
+		Run(func(t test.Test, param testZeroLogPrettyOrderParam) {
+			// Given
+			buffer := &bytes.Buffer{}
+			logger := (&log.Config{
+				Level:      log.LevelInfo,
+				TimeFormat: log.TimeFormatNone,
+				ColorMode:  log.ColorModeOff,
+				OrderMode:  param.order,
+				FieldOrder: param.fields,
+			}).SetupZero(buffer).ZeroLogger()
+
+			// When
+			param.call(logger)
+
+			// Then
+			assert.Equal(t, param.expect, buffer.String())
+		})
+}
+
+type testPrettyDuplicatesParam struct {
+	level      string
+	keep       bool
+	expectRus  string
+	expectZero string
+}
+
+var testPrettyDuplicatesParams = map[string]testPrettyDuplicatesParam{
+	"last wins": {
+		level:      log.LevelInfo,
+		expectRus:  "INFO info message user=\"bob\" zone=\"eu\"\n",
+		expectZero: "INFO info message user=\"bob\" zone=\"eu\"\n",
+	},
+	"last wins noted": {
+		level:     log.LevelDebug,
+		expectRus: "INFO info message user=\"bob\" zone=\"eu\"\n",
+		expectZero: "INFO info message user=\"bob\" zone=\"eu\"\n" +
+			"DEBUG duplicate field dropped duplicate=\"user\"\n",
+	},
+	"keep duplicates": {
+		level:     log.LevelDebug,
+		keep:      true,
+		expectRus: "INFO info message user=\"bob\" zone=\"eu\"\n",
+		expectZero: "INFO info message user=\"alice\" user=\"bob\" " +
+			"zone=\"eu\"\n",
+	},
+}
+
+func TestPrettyDuplicates(t *testing.T) {
+	test.Map(t, testPrettyDuplicatesParams).
+		RunSeq(func(t test.Test, param testPrettyDuplicatesParam) {
+			// Given
+			rusBuffer, zeroBuffer := &bytes.Buffer{}, &bytes.Buffer{}
+			config := &log.Config{
+				Level:          param.level,
+				TimeFormat:     log.TimeFormatNone,
+				ColorMode:      log.ColorModeOff,
+				KeepDuplicates: param.keep,
+			}
+			rus := config.SetupRus(rusBuffer, logrus.New()).
+				WithField("user", "alice")
+			zero := config.SetupZeroLogger(zerolog.New(io.Discard).
+				With().Str("user", "alice").Logger(), zeroBuffer)
+
+			// When
+			rus.WithField("user", "bob").WithField("zone", "eu").
+				Info("info message")
+			zero.Info().Str("user", "bob").Str("zone", "eu").
+				Msg("info message")
+
+			// Then
+			assert.Equal(t, param.expectRus, rusBuffer.String())
+			assert.Equal(t, param.expectZero, zeroBuffer.String())
+		})
+}
+
+func BenchmarkZeroPrettyFormat(b *testing.B) {
+	logger := (&log.Config{
+		Level:      log.LevelInfo,
+		TimeFormat: log.DefaultTimeFormat,
+		ColorMode:  log.ColorModeOn,
+	}).SetupZero(io.Discard).ZeroLogger()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for range b.N {
+		logger.Info().Str("user", "alice").Int("count", 42).
+			Bool("flag", true).Str("zone", "eu").Msg("info message")
+	}
+}
+
+type testFormatFallbackParam struct {
+	value any
+}
+
+var testFormatFallbackParams = map[string]testFormatFallbackParam{
+	"nil":     {value: nil},
+	"bool":    {value: true},
+	"int":     {value: 42},
+	"float":   {value: 1.5},
+	"float-e": {value: 1e21},
+	"number":  {value: json.Number("42")},
+	"bytes":   {value: []byte("raw")},
+	"struct":  {value: struct{ A int }{A: 1}},
+}
+
+func TestFormatFallback(t *testing.T) {
+	test.Map(t, testFormatFallbackParams).
+		Run(func(t test.Test, param testFormatFallbackParam) {
+			// Given
+			setup := (&log.Config{ColorMode: log.ColorModeOff}).Setup(io.Discard)
+
+			// When
+			level := setup.FormatLevel(param.value)
+			message := setup.FormatMessage(param.value)
+			name := setup.FormatFieldName(param.value)
+			errName := setup.FormatErrFieldName(param.value)
+
+			// Then
+			assert.Equal(t, fmt.Sprintf("%v", param.value), level)
+			assert.Equal(t, fmt.Sprintf("%v", param.value), message)
+			assert.Equal(t, fmt.Sprintf("%v=", param.value), name)
+			assert.Equal(t, fmt.Sprintf("%v=", param.value), errName)
+		})
+}
+
+func TestFormatFieldNameCached(t *testing.T) {
+	// Given
+	setup := (&log.Config{ColorMode: log.ColorModeOn}).Setup(io.Discard)
+
+	// When
+	first := setup.FormatFieldName("user")
+	second := setup.FormatFieldName("user")
+	errName := setup.FormatErrFieldName("error")
+	setup.ColorMode = log.ColorOff
+	plain := setup.FormatFieldName("user")
+
+	// Then
+	assert.Equal(t, fieldC("user")+"=", first)
+	assert.Equal(t, first, second)
+	assert.Equal(t, colored(log.ColorError, "error")+"=", errName)
+	assert.Equal(t, "user=", plain)
+}
+
+func BenchmarkZeroFormatCallbacks(b *testing.B) {
+	setup := (&log.Config{
+		TimeFormat: log.DefaultTimeFormat,
+		ColorMode:  log.ColorModeOn,
+	}).Setup(io.Discard)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for range b.N {
+		_ = setup.FormatLevel("info")
+		_ = setup.FormatErrFieldName("error")
+		_ = setup.FormatFieldName("user")
+		_ = setup.FormatFieldName("count")
+		_ = setup.FormatFieldName("flag")
+		_ = setup.FormatFieldName("zone")
+	}
+}